@@ -0,0 +1,29 @@
+// Command report-engine builds and runs reports from configuration files.
+//
+// Usage:
+//
+//	report-engine run -c config.yaml
+//	report-engine run -c config.yaml --set provider.params.query="SELECT * FROM sales"
+//	report-engine validate -c config.yaml
+//	report-engine dry-run -c config.yaml
+//	report-engine list-plugins
+//
+// Exit codes are mapped from the failure's error classification; see the
+// cli package for the full table.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/AshishBagdane/go-report-engine/internal/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -183,7 +183,6 @@ func example5() {
 	// Update formatter
 	cfg = config.ConfigWithFormatterParams(cfg, map[string]string{
 		"indent": "4",
-		"pretty": "true",
 	})
 
 	// Use the customized config
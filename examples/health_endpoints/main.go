@@ -2,17 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
-	"time"
+	"os"
+	"os/signal"
 
 	"github.com/AshishBagdane/go-report-engine/internal/engine"
 	"github.com/AshishBagdane/go-report-engine/internal/formatter"
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/server"
 )
 
 func main() {
@@ -31,25 +31,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Setup HTTP Server
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
+	// The engine implements health.Checker by aggregating its components
+	srv := server.New(server.WithChecker("engine", eng))
 
-		healthStatus := eng.Health(ctx)
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(healthStatus); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	port := ":8080"
 	fmt.Printf("Starting server on %s\n", port)
-	fmt.Println("Try: curl http://localhost:8080/health")
+	fmt.Println("Try: curl http://localhost:8080/healthz")
+	fmt.Println("     curl http://localhost:8080/readyz")
 
-	// Start server (blocking)
-	if err := http.ListenAndServe(port, nil); err != nil {
+	// Serve until interrupted, then shut down gracefully
+	if err := srv.ListenAndServe(ctx, port); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,129 @@
+// Package broker defines a minimal message broker abstraction used by the
+// queue provider and queue output to participate in event pipelines.
+//
+// The interface models the common subset of Kafka- and NATS-style systems:
+// topics hold an ordered log of messages, and consumer groups track how far
+// they have read. Adapters for real brokers implement Broker and are made
+// available to configuration by name with Register. An in-memory broker is
+// registered as "memory" for tests and local development.
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by brokers and subscriptions after Close.
+var ErrClosed = errors.New("broker: closed")
+
+// Message is a single message on a topic.
+type Message struct {
+	// Topic is the topic the message was published to.
+	Topic string
+
+	// Key is an optional partitioning/routing key.
+	Key string
+
+	// Value is the message payload.
+	Value []byte
+
+	// Headers carries optional metadata.
+	Headers map[string]string
+
+	// Offset is the position of the message in its topic. It is assigned
+	// by the broker on publish and ignored when publishing.
+	Offset int64
+
+	// Timestamp is the publish time, assigned by the broker.
+	Timestamp time.Time
+}
+
+// Broker publishes messages to topics and creates subscriptions.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Broker interface {
+	// Publish appends messages to the topic in order.
+	Publish(ctx context.Context, topic string, msgs ...Message) error
+
+	// Subscribe starts consuming the topic as a member of the consumer
+	// group. Consumption resumes after the group's last committed message.
+	Subscribe(ctx context.Context, topic, group string) (Subscription, error)
+
+	// Close releases broker resources. Subsequent calls fail with ErrClosed.
+	Close() error
+}
+
+// Subscription receives messages for a single topic and consumer group.
+type Subscription interface {
+	// Receive blocks until the next message is available or ctx is done.
+	Receive(ctx context.Context) (Message, error)
+
+	// Commit marks msg, and every message before it, as processed for the
+	// consumer group.
+	Commit(ctx context.Context, msg Message) error
+
+	// Close ends the subscription. Uncommitted messages are redelivered to
+	// the next subscription for the same group.
+	Close() error
+}
+
+// DefaultBroker is the name of the broker used when none is configured.
+const DefaultBroker = "memory"
+
+var (
+	brokers   = map[string]Broker{DefaultBroker: NewMemoryBroker()}
+	brokersMu sync.RWMutex
+)
+
+// Register makes a broker available by name to queue providers and outputs.
+// Registering an existing name replaces the previous broker.
+//
+// Panics if name is empty or b is nil.
+func Register(name string, b Broker) {
+	if name == "" {
+		panic("broker: name cannot be empty")
+	}
+	if b == nil {
+		panic("broker: broker cannot be nil")
+	}
+
+	brokersMu.Lock()
+	defer brokersMu.Unlock()
+	brokers[name] = b
+}
+
+// Get returns the broker registered under name.
+func Get(name string) (Broker, error) {
+	brokersMu.RLock()
+	b, ok := brokers[name]
+	brokersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("broker: %q not registered (available: %v)", name, List())
+	}
+	return b, nil
+}
+
+// List returns the sorted names of all registered brokers.
+func List() []string {
+	brokersMu.RLock()
+	defer brokersMu.RUnlock()
+
+	names := make([]string, 0, len(brokers))
+	for name := range brokers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unregister removes a broker by name. It is primarily useful in tests.
+func Unregister(name string) {
+	brokersMu.Lock()
+	defer brokersMu.Unlock()
+	delete(brokers, name)
+}
@@ -0,0 +1,206 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryBroker_PublishSubscribe(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+
+	if err := b.Publish(ctx, "orders", Message{Key: "a", Value: []byte("1")}, Message{Value: []byte("2")}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	sub, err := b.Subscribe(ctx, "orders", "reports")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	for i, want := range []string{"1", "2"} {
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		if string(msg.Value) != want {
+			t.Errorf("message %d value = %q, want %q", i, msg.Value, want)
+		}
+		if msg.Offset != int64(i) || msg.Topic != "orders" {
+			t.Errorf("message %d offset/topic = %d/%s", i, msg.Offset, msg.Topic)
+		}
+		if msg.Timestamp.IsZero() {
+			t.Error("timestamp should be set on publish")
+		}
+	}
+
+	if b.Len("orders") != 2 {
+		t.Errorf("Len() = %d, want 2", b.Len("orders"))
+	}
+}
+
+func TestMemoryBroker_GroupOffsets(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_ = b.Publish(ctx, "events", Message{Value: []byte("a")}, Message{Value: []byte("b")}, Message{Value: []byte("c")})
+
+	sub, _ := b.Subscribe(ctx, "events", "g1")
+	first, _ := sub.Receive(ctx)
+	_, _ = sub.Receive(ctx)
+	if err := sub.Commit(ctx, first); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	_ = sub.Close()
+
+	if got := b.Committed("events", "g1"); got != 1 {
+		t.Errorf("Committed() = %d, want 1", got)
+	}
+
+	// Uncommitted messages are redelivered to the same group
+	sub, _ = b.Subscribe(ctx, "events", "g1")
+	msg, _ := sub.Receive(ctx)
+	if string(msg.Value) != "b" {
+		t.Errorf("redelivered value = %q, want %q", msg.Value, "b")
+	}
+	_ = sub.Close()
+
+	// Other groups start from the beginning
+	other, _ := b.Subscribe(ctx, "events", "g2")
+	msg, _ = other.Receive(ctx)
+	if string(msg.Value) != "a" {
+		t.Errorf("new group value = %q, want %q", msg.Value, "a")
+	}
+	_ = other.Close()
+}
+
+func TestMemoryBroker_CommitNeverMovesBackwards(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_ = b.Publish(ctx, "t", Message{}, Message{}, Message{})
+
+	sub, _ := b.Subscribe(ctx, "t", "g")
+	_ = sub.Commit(ctx, Message{Offset: 2})
+	_ = sub.Commit(ctx, Message{Offset: 0})
+
+	if got := b.Committed("t", "g"); got != 3 {
+		t.Errorf("Committed() = %d, want 3", got)
+	}
+}
+
+func TestMemoryBroker_ReceiveWaitsForPublish(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+
+	sub, _ := b.Subscribe(ctx, "live", "g")
+	defer func() { _ = sub.Close() }()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = b.Publish(ctx, "live", Message{Value: []byte("late")})
+	}()
+
+	recvCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	msg, err := sub.Receive(recvCtx)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if string(msg.Value) != "late" {
+		t.Errorf("value = %q, want %q", msg.Value, "late")
+	}
+}
+
+func TestMemoryBroker_ReceiveContextDone(t *testing.T) {
+	b := NewMemoryBroker()
+	sub, _ := b.Subscribe(context.Background(), "empty", "g")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := sub.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Receive() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestMemoryBroker_Closed(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	sub, _ := b.Subscribe(ctx, "t", "g")
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := b.Publish(ctx, "t", Message{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrClosed", err)
+	}
+	if _, err := b.Subscribe(ctx, "t", "g"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe() after Close error = %v, want ErrClosed", err)
+	}
+	if _, err := sub.Receive(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Receive() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestMemoryBroker_EmptyTopic(t *testing.T) {
+	b := NewMemoryBroker()
+	if err := b.Publish(context.Background(), "", Message{}); err == nil {
+		t.Error("expected error for empty topic on Publish")
+	}
+	if _, err := b.Subscribe(context.Background(), "", "g"); err == nil {
+		t.Error("expected error for empty topic on Subscribe")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if _, err := Get(DefaultBroker); err != nil {
+		t.Fatalf("default broker should be registered: %v", err)
+	}
+
+	custom := NewMemoryBroker()
+	Register("custom", custom)
+	defer Unregister("custom")
+
+	got, err := Get("custom")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != custom {
+		t.Error("Get() returned a different broker")
+	}
+
+	names := List()
+	if len(names) < 2 || names[0] != "custom" || names[1] != "memory" {
+		t.Errorf("List() = %v", names)
+	}
+
+	if _, err := Get("missing"); err == nil {
+		t.Error("expected error for unregistered broker")
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		broker string
+		b      Broker
+	}{
+		{name: "empty name", broker: "", b: NewMemoryBroker()},
+		{name: "nil broker", broker: "x", b: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() should panic")
+				}
+			}()
+			Register(tt.broker, tt.b)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryBroker is an in-process Broker that keeps every topic as an
+// unbounded, append-only log. Committed offsets are tracked per consumer
+// group, so separate groups each see every message while members of one
+// group resume where the last committed read stopped.
+//
+// It is intended for tests, examples and single-process pipelines; nothing
+// is persisted.
+//
+// Thread-safe: Yes.
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+	closed bool
+}
+
+// memoryTopic holds the message log and group offsets for one topic.
+type memoryTopic struct {
+	messages  []Message
+	committed map[string]int64
+	// notify is closed and replaced on every publish to wake receivers
+	notify chan struct{}
+}
+
+// NewMemoryBroker creates an empty in-memory broker.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{topics: make(map[string]*memoryTopic)}
+}
+
+// topic returns the named topic, creating it if needed. Caller holds mu.
+func (b *MemoryBroker) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{
+			committed: make(map[string]int64),
+			notify:    make(chan struct{}),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// Publish appends messages to the topic and wakes waiting subscribers.
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, msgs ...Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if topic == "" {
+		return fmt.Errorf("broker: topic cannot be empty")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	t := b.topic(topic)
+	now := time.Now()
+	for _, msg := range msgs {
+		msg.Topic = topic
+		msg.Offset = int64(len(t.messages))
+		msg.Timestamp = now
+		t.messages = append(t.messages, msg)
+	}
+
+	if len(msgs) > 0 {
+		close(t.notify)
+		t.notify = make(chan struct{})
+	}
+	return nil
+}
+
+// Subscribe starts reading topic from the group's committed offset.
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic, group string) (Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("broker: topic cannot be empty")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	t := b.topic(topic)
+	return &memorySubscription{
+		broker:   b,
+		topic:    t,
+		group:    group,
+		position: t.committed[group],
+	}, nil
+}
+
+// Len returns the number of messages ever published to topic.
+func (b *MemoryBroker) Len(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if t, ok := b.topics[topic]; ok {
+		return len(t.messages)
+	}
+	return 0
+}
+
+// Committed returns the next offset the group will read from topic.
+func (b *MemoryBroker) Committed(topic, group string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if t, ok := b.topics[topic]; ok {
+		return t.committed[group]
+	}
+	return 0
+}
+
+// Close shuts the broker down and wakes all waiting subscribers.
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	for _, t := range b.topics {
+		close(t.notify)
+		t.notify = make(chan struct{})
+	}
+	return nil
+}
+
+// memorySubscription reads a memoryTopic from its own position.
+type memorySubscription struct {
+	broker   *MemoryBroker
+	topic    *memoryTopic
+	group    string
+	position int64
+	closed   bool
+}
+
+// Receive returns the next message, waiting for a publish if none is ready.
+func (s *memorySubscription) Receive(ctx context.Context) (Message, error) {
+	for {
+		s.broker.mu.Lock()
+		if s.closed || s.broker.closed {
+			s.broker.mu.Unlock()
+			return Message{}, ErrClosed
+		}
+		if s.position < int64(len(s.topic.messages)) {
+			msg := s.topic.messages[s.position]
+			s.position++
+			s.broker.mu.Unlock()
+			return msg, nil
+		}
+		notify := s.topic.notify
+		s.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// Commit advances the group's committed offset past msg.
+func (s *memorySubscription) Commit(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed || s.broker.closed {
+		return ErrClosed
+	}
+	if next := msg.Offset + 1; next > s.topic.committed[s.group] {
+		s.topic.committed[s.group] = next
+	}
+	return nil
+}
+
+// Close ends the subscription.
+func (s *memorySubscription) Close() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,457 @@
+// Package cli implements the report-engine command-line interface.
+//
+// The binary in cmd/report-engine is a thin wrapper around Run so that the
+// commands, flag handling and exit codes can be tested in-process.
+//
+// Usage:
+//
+//	report-engine run -c config.yaml [--report name] [--set path=value ...] [--result result.json] [--history runs.jsonl]
+//	report-engine validate -c config.yaml [--report name]
+//	report-engine dry-run -c config.yaml
+//	report-engine history --store runs.jsonl [--report name] [--status failed] [--since 24h]
+//	report-engine spool list --dir /var/spool/report-engine [--json]
+//	report-engine spool replay -c config.yaml [--report name] [--id entry-id]
+//	report-engine list-plugins [--json]
+//	report-engine schema [--out report-engine.schema.json]
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/config"
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/factory"
+	"github.com/AshishBagdane/go-report-engine/internal/history"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+)
+
+// command is a single report-engine subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+// commands lists the subcommands in the order shown by usage.
+var commands = []command{
+	{name: "run", summary: "Run the report defined by a config file", run: runCommand},
+	{name: "validate", summary: "Validate a config file and its components without running", run: validateCommand},
+	{name: "dry-run", summary: "Run the pipeline but print the report instead of delivering it", run: dryRunCommand},
+	{name: "history", summary: "Query the run history recorded with run --history", run: historyCommand},
+	{name: "spool", summary: "List or replay reports waiting in an output spool", run: spoolCommand},
+	{name: "list-plugins", summary: "List registered providers, processors, formatters and outputs", run: listPluginsCommand},
+	{name: "schema", summary: "Print the config file JSON Schema for editor validation", run: schemaCommand},
+}
+
+// Run executes the CLI with args (excluding the program name) and returns
+// the process exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(ctx, args[1:], stdout, stderr)
+		if err == nil || errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		_, _ = fmt.Fprintf(stderr, "report-engine %s: %v\n", name, err)
+		return ExitCode(err)
+	}
+
+	_, _ = fmt.Fprintf(stderr, "report-engine: unknown command %q\n\n", name)
+	printUsage(stderr)
+	return ExitUsage
+}
+
+// printUsage writes the top-level help text.
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: report-engine <command> [flags]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Run 'report-engine <command> -h' for command flags.")
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// pipelineFlags are shared by commands that load a config file.
+type pipelineFlags struct {
+	configPath string
+	report     string
+	overrides  stringList
+	env        bool
+	vaultPath  string
+	logLevel   string
+	logFormat  string
+	timeout    time.Duration
+}
+
+// newFlagSet creates a flag set whose usage and errors go to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("report-engine "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// register adds the shared pipeline flags to fs.
+func (p *pipelineFlags) register(fs *flag.FlagSet, withTimeout bool) {
+	fs.StringVar(&p.configPath, "c", "", "path to the config file (shorthand for --config)")
+	fs.StringVar(&p.configPath, "config", "", "path to the config file (.yaml, .yml, .json, .toml or .hcl)")
+	fs.StringVar(&p.report, "report", "", "report to use from a multi-report config file")
+	fs.Var(&p.overrides, "set", "override a config value, e.g. provider.params.query=... (repeatable)")
+	fs.BoolVar(&p.env, "env", true, "apply ENGINE_* environment variable overrides")
+	fs.StringVar(&p.vaultPath, "vault", "", "resolve ${vault:path#key} references from this local vault file")
+	fs.StringVar(&p.logLevel, "log-level", "info", "log level: debug, info, warn or error")
+	fs.StringVar(&p.logFormat, "log-format", "text", "log format: text or json")
+	if withTimeout {
+		fs.DurationVar(&p.timeout, "timeout", 0, "abort the run after this duration (0 for no limit)")
+	}
+}
+
+// parse parses args and checks required flags.
+func (p *pipelineFlags) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageErrorf("%v", err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if p.configPath == "" {
+		return usageErrorf("missing required flag -c/--config")
+	}
+	return nil
+}
+
+// logger builds the logger for engine runs. Logs always go to stderr so
+// that reports written to stdout stay clean.
+func (p *pipelineFlags) logger(stderr io.Writer) (*logging.Logger, error) {
+	var level logging.Level
+	switch strings.ToLower(p.logLevel) {
+	case "debug":
+		level = logging.LevelDebug
+	case "info":
+		level = logging.LevelInfo
+	case "warn", "warning":
+		level = logging.LevelWarn
+	case "error":
+		level = logging.LevelError
+	default:
+		return nil, usageErrorf("invalid --log-level %q", p.logLevel)
+	}
+
+	var format logging.Format
+	switch strings.ToLower(p.logFormat) {
+	case "text":
+		format = logging.FormatText
+	case "json":
+		format = logging.FormatJSON
+	default:
+		return nil, usageErrorf("invalid --log-format %q", p.logFormat)
+	}
+
+	return logging.NewLogger(logging.Config{
+		Level:     level,
+		Format:    format,
+		Output:    stderr,
+		Component: "engine",
+	}), nil
+}
+
+// loadConfig reads the config file, resolving variables and secrets, and
+// applies environment and --set overrides. With --report, the report is
+// selected from a multi-report config file.
+func (p *pipelineFlags) loadConfig() (*engine.Config, error) {
+	loader := p.loader()
+
+	var cfg *engine.Config
+	var err error
+	if p.report == "" {
+		cfg, err = loader.LoadFromFile(p.configPath)
+		if errors.Is(err, config.ErrMultipleReports) {
+			err = fmt.Errorf("%w; select one with --report", err)
+		}
+	} else {
+		cfg, err = p.selectReport(loader)
+	}
+	if err != nil {
+		return nil, engerrors.WrapWithType(engerrors.ComponentFactory, "load_config", engerrors.ErrorTypeConfiguration, err)
+	}
+	return cfg, nil
+}
+
+// loader returns a config loader for the --set, --env and --vault flags.
+func (p *pipelineFlags) loader() *config.Loader {
+	loader := config.NewLoader().WithOverrides(p.overrides...)
+	if p.env {
+		loader = loader.WithEnvOverrides()
+	}
+	if p.vaultPath != "" {
+		loader = loader.WithSecretResolver("vault", config.NewLocalVault(p.vaultPath))
+	}
+	return loader
+}
+
+// selectReport loads a multi-report config file and returns the --report one.
+func (p *pipelineFlags) selectReport(loader *config.Loader) (*engine.Config, error) {
+	reports, err := loader.LoadReportsFromFile(p.configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := reports[p.report]
+	if !ok {
+		names := make([]string, 0, len(reports))
+		for name := range reports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("report %q not found in %s (available: %s)", p.report, p.configPath, strings.Join(names, ", "))
+	}
+	return cfg, nil
+}
+
+// buildEngine loads the config and constructs the engine.
+func (p *pipelineFlags) buildEngine(stderr io.Writer) (*engine.Config, *engine.ReportEngine, error) {
+	logger, err := p.logger(stderr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := p.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	eng, err := factory.NewEngineFromConfig(*cfg)
+	if err != nil {
+		return nil, nil, engerrors.WrapWithType(engerrors.ComponentFactory, "build_engine", engerrors.ErrorTypeConfiguration, err)
+	}
+	return cfg, eng.WithLogger(logger), nil
+}
+
+// withTimeout applies the --timeout flag to ctx.
+func (p *pipelineFlags) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.timeout > 0 {
+		return context.WithTimeout(ctx, p.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// runCommand implements "report-engine run".
+func runCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		flags       pipelineFlags
+		resultPath  string
+		historyPath string
+		reportName  string
+	)
+	fs := newFlagSet("run", stderr)
+	flags.register(fs, true)
+	fs.StringVar(&resultPath, "result", "", "write the run result as JSON to this file (- for stdout)")
+	fs.StringVar(&historyPath, "history", "", "record the run in this history file (JSON Lines)")
+	fs.StringVar(&reportName, "name", "", "report name recorded in history (default: config file name)")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, eng, err := flags.buildEngine(stderr)
+	if err != nil {
+		return err
+	}
+	defer closeEngine(eng, stderr)
+
+	if historyPath != "" {
+		if reportName == "" {
+			reportName = flags.report
+		}
+		if reportName == "" {
+			reportName = defaultReportName(flags.configPath)
+		}
+		recorder := history.NewRecorder(history.NewFileHistory(historyPath), reportName, *cfg)
+		recorder.Trigger = "cli"
+		eng.WithResultRecorder(recorder)
+	}
+
+	ctx, cancel := flags.withTimeout(ctx)
+	defer cancel()
+
+	res, runErr := eng.Execute(ctx)
+	if resultPath != "" {
+		if err := writeResult(resultPath, res, stdout); err != nil {
+			_, _ = fmt.Fprintf(stderr, "warning: failed to write run result: %v\n", err)
+		}
+	}
+	return runErr
+}
+
+// defaultReportName derives a report name from the config file name.
+func defaultReportName(configPath string) string {
+	base := filepath.Base(configPath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// writeResult writes the run result as indented JSON to path, or to
+// stdout when path is "-". The result is written for failed runs too.
+func writeResult(path string, res *engine.RunResult, stdout io.Writer) error {
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// validateCommand implements "report-engine validate".
+func validateCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var flags pipelineFlags
+	fs := newFlagSet("validate", stderr)
+	flags.register(fs, false)
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	// Without --report, every report of a multi-report file is checked
+	reports := []string{flags.report}
+	if flags.report == "" {
+		if all, err := flags.loader().LoadReportsFromFile(flags.configPath); err == nil {
+			reports = reports[:0]
+			for name := range all {
+				reports = append(reports, name)
+			}
+			sort.Strings(reports)
+		}
+	}
+
+	for _, name := range reports {
+		flags.report = name
+
+		// Building the engine checks that every component is registered and
+		// accepts its params, not just that the file is well-formed
+		cfg, eng, err := flags.buildEngine(stderr)
+		if err != nil {
+			return err
+		}
+		closeEngine(eng, stderr)
+
+		processors := make([]string, 0, len(cfg.Processors))
+		for _, p := range cfg.Processors {
+			processors = append(processors, p.Type)
+		}
+
+		label := flags.configPath
+		if name != "" {
+			label += " [" + name + "]"
+		}
+		_, _ = fmt.Fprintf(stdout, "%s: configuration is valid\n", label)
+		_, _ = fmt.Fprintf(stdout, "  provider:   %s\n", cfg.Provider.Type)
+		_, _ = fmt.Fprintf(stdout, "  processors: %s\n", listOrNone(processors))
+		_, _ = fmt.Fprintf(stdout, "  formatter:  %s\n", cfg.Formatter.Type)
+		_, _ = fmt.Fprintf(stdout, "  output:     %s\n", cfg.Output.Type)
+	}
+	return nil
+}
+
+// dryRunCommand implements "report-engine dry-run".
+func dryRunCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var flags pipelineFlags
+	fs := newFlagSet("dry-run", stderr)
+	flags.register(fs, true)
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, eng, err := flags.buildEngine(stderr)
+	if err != nil {
+		return err
+	}
+
+	// Deliver to stdout instead of the configured output. The configured
+	// output has only been constructed and configured, never written to,
+	// and watermarks and checkpoints are left as they were.
+	eng.Output = &writerOutput{w: stdout}
+	eng.WithReadOnlyState()
+	defer closeEngine(eng, stderr)
+
+	ctx, cancel := flags.withTimeout(ctx)
+	defer cancel()
+
+	if err := eng.RunWithContext(ctx); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stderr, "dry run complete: report was not delivered to output %q\n", cfg.Output.Type)
+	return nil
+}
+
+// closeEngine releases engine resources, reporting (not returning) failures.
+func closeEngine(eng *engine.ReportEngine, stderr io.Writer) {
+	if err := eng.Close(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "warning: cleanup failed: %v\n", err)
+	}
+}
+
+// listOrNone joins names or returns "(none)".
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "(none)"
+	}
+	return strings.Join(names, ", ")
+}
+
+// writerOutput writes formatted reports to an io.Writer.
+type writerOutput struct {
+	w io.Writer
+}
+
+// Send writes data followed by a newline.
+func (o *writerOutput) Send(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := o.w.Write(data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(o.w, "\n")
+	return err
+}
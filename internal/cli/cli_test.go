@@ -0,0 +1,345 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFixture creates a CSV source and a config that turns it into a JSON
+// file report, returning the config path and report path.
+func writeFixture(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "sales.csv")
+	if err := os.WriteFile(csvPath, []byte("region,total\neast,100\nwest,250\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reportPath := filepath.Join(dir, "report.json")
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "provider:\n" +
+		"  type: csv\n" +
+		"  params:\n" +
+		"    file_path: " + csvPath + "\n" +
+		"formatter:\n" +
+		"  type: json\n" +
+		"  params:\n" +
+		"    indent: \"0\"\n" +
+		"output:\n" +
+		"  type: file\n" +
+		"  params:\n" +
+		"    path: " + reportPath + "\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return configPath, reportPath
+}
+
+// run invokes the CLI and returns the exit code and captured output.
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Run(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+
+	code, _, stderr := run("run", "-c", configPath, "--log-level", "error")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("report is not JSON: %v (%s)", err, data)
+	}
+	if len(records) != 2 {
+		t.Errorf("report has %d records, want 2", len(records))
+	}
+}
+
+func TestRun_RunWithSet(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+	altPath := filepath.Join(filepath.Dir(reportPath), "alt.json")
+
+	code, _, stderr := run("run", "--config", configPath, "--log-level", "error",
+		"--set", "output.params.path="+altPath)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+
+	if _, err := os.Stat(altPath); err != nil {
+		t.Errorf("override path not used: %v", err)
+	}
+	if _, err := os.Stat(reportPath); err == nil {
+		t.Error("original path should not be written when overridden")
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+
+	code, stdout, stderr := run("validate", "-c", configPath)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "configuration is valid") || !strings.Contains(stdout, "provider:   csv") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+	if _, err := os.Stat(reportPath); err == nil {
+		t.Error("validate must not run the report")
+	}
+}
+
+func TestRun_Vault(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+	vaultPath := filepath.Join(filepath.Dir(configPath), "vault.yaml")
+	if err := os.WriteFile(vaultPath, []byte("reports/out:\n  path: "+reportPath+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Take the output path from the vault instead
+	data, _ := os.ReadFile(configPath)
+	templated := strings.Replace(string(data), "path: "+reportPath, `path: "${vault:reports/out#path}"`, 1)
+	if err := os.WriteFile(configPath, []byte(templated), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, _, _ := run("validate", "-c", configPath); code != ExitConfiguration {
+		t.Errorf("validate without --vault: exit code = %d, want %d", code, ExitConfiguration)
+	}
+	code, _, stderr := run("run", "-c", configPath, "--vault", vaultPath, "--log-level", "error")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("report not written to the vault path: %v", err)
+	}
+}
+
+func TestRun_MultiReport(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "sales.csv")
+	if err := os.WriteFile(csvPath, []byte("region,total\neast,100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "reports.yaml")
+	content := "defaults:\n" +
+		"  provider: {type: csv, params: {file_path: " + csvPath + "}}\n" +
+		"  formatter: {type: json}\n" +
+		"  output: {type: file}\n" +
+		"reports:\n" +
+		"  east: {output: {params: {path: " + filepath.Join(dir, "east.json") + "}}}\n" +
+		"  west: {output: {params: {path: " + filepath.Join(dir, "west.json") + "}}}\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := run("validate", "-c", configPath)
+	if code != ExitOK || !strings.Contains(stdout, "[east]: configuration is valid") || !strings.Contains(stdout, "[west]") {
+		t.Fatalf("validate: exit code = %d, stdout = %s, stderr = %s", code, stdout, stderr)
+	}
+
+	if code, _, stderr := run("run", "-c", configPath, "--report", "west", "--log-level", "error"); code != ExitOK {
+		t.Fatalf("run --report: exit code = %d, stderr = %s", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "west.json")); err != nil {
+		t.Errorf("west report not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "east.json")); err == nil {
+		t.Error("only the selected report should run")
+	}
+
+	if code, _, stderr := run("run", "-c", configPath); code != ExitConfiguration || !strings.Contains(stderr, "--report") {
+		t.Errorf("run without --report: exit code = %d, stderr = %s", code, stderr)
+	}
+	if code, _, stderr := run("run", "-c", configPath, "--report", "north"); code != ExitConfiguration || !strings.Contains(stderr, "available: east, west") {
+		t.Errorf("unknown report: exit code = %d, stderr = %s", code, stderr)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+
+	code, stdout, stderr := run("dry-run", "-c", configPath, "--log-level", "error")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"region":"east"`) {
+		t.Errorf("report not printed to stdout: %s", stdout)
+	}
+	if !strings.Contains(stderr, "not delivered") {
+		t.Errorf("expected dry run notice on stderr: %s", stderr)
+	}
+	if _, err := os.Stat(reportPath); err == nil {
+		t.Error("dry-run must not deliver to the configured output")
+	}
+}
+
+func TestRun_DryRunKeepsWatermark(t *testing.T) {
+	configPath, _ := writeFixture(t)
+	statePath := filepath.Join(filepath.Dir(configPath), "state.json")
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content = bytes.Replace(content, []byte("    file_path:"), []byte("    watermark_field: total\n    file_path:"), 1)
+	content = append(content, []byte("watermark:\n  store: "+statePath+"\n  key: sales\n")...)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := run("dry-run", "-c", configPath, "--log-level", "error")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"region":"west"`) {
+		t.Errorf("report not printed to stdout: %s", stdout)
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Error("dry-run must not save the watermark")
+	}
+}
+
+func TestRun_ListPlugins(t *testing.T) {
+	code, stdout, _ := run("list-plugins")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+	for _, want := range []string{"Providers:", "  csv", "Formatters:", "  json", "Outputs:", "  file"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	code, stdout, _ = run("list-plugins", "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+	var plugins pluginList
+	if err := json.Unmarshal([]byte(stdout), &plugins); err != nil {
+		t.Fatalf("--json output is not JSON: %v", err)
+	}
+	if len(plugins.Providers) == 0 || len(plugins.Outputs) == 0 {
+		t.Errorf("plugins = %+v", plugins)
+	}
+}
+
+func TestRun_Schema(t *testing.T) {
+	code, stdout, _ := run("schema")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &schema); err != nil {
+		t.Fatalf("schema output is not JSON: %v", err)
+	}
+	if !strings.Contains(stdout, `"file_path"`) || !strings.Contains(stdout, `"remote_path"`) {
+		t.Error("schema should describe the registered components' params")
+	}
+
+	path := filepath.Join(t.TempDir(), "report-engine.schema.json")
+	if code, _, stderr := run("schema", "--out", path); code != ExitOK {
+		t.Fatalf("exit code = %d: %s", code, stderr)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != stdout {
+		t.Errorf("--out wrote %d bytes, %v; want the printed schema", len(data), err)
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	configPath, _ := writeFixture(t)
+	dir := t.TempDir()
+
+	badType := filepath.Join(dir, "bad_type.yaml")
+	_ = os.WriteFile(badType, []byte("provider:\n  type: nope\nformatter:\n  type: json\noutput:\n  type: console\n"), 0644)
+
+	missingSource := filepath.Join(dir, "missing_source.yaml")
+	_ = os.WriteFile(missingSource, []byte("provider:\n  type: csv\n  params:\n    file_path: "+
+		filepath.Join(dir, "nope.csv")+"\nformatter:\n  type: json\noutput:\n  type: console\n"), 0644)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no args", args: nil, want: ExitUsage},
+		{name: "unknown command", args: []string{"explode"}, want: ExitUsage},
+		{name: "help", args: []string{"help"}, want: ExitOK},
+		{name: "command help", args: []string{"run", "-h"}, want: ExitOK},
+		{name: "missing config flag", args: []string{"run"}, want: ExitUsage},
+		{name: "unknown flag", args: []string{"run", "-c", configPath, "--nope"}, want: ExitUsage},
+		{name: "extra args", args: []string{"validate", "-c", configPath, "extra"}, want: ExitUsage},
+		{name: "bad log level", args: []string{"run", "-c", configPath, "--log-level", "loud"}, want: ExitUsage},
+		{name: "missing file", args: []string{"run", "-c", filepath.Join(dir, "none.yaml")}, want: ExitConfiguration},
+		{name: "bad override", args: []string{"validate", "-c", configPath, "--set", "nonsense"}, want: ExitConfiguration},
+		{name: "unregistered type", args: []string{"validate", "-c", badType}, want: ExitConfiguration},
+		{name: "runtime failure", args: []string{"run", "-c", missingSource, "--log-level", "error"}, want: ExitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, stderr := run(tt.args...); code != tt.want {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	configPath, _ := writeFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var stdout, stderr bytes.Buffer
+	code := Run(ctx, []string{"run", "-c", configPath, "--log-level", "error"}, &stdout, &stderr)
+	if code != ExitInterrupted {
+		t.Errorf("exit code = %d, want %d (stderr: %s)", code, ExitInterrupted, stderr.String())
+	}
+}
+
+func TestRun_RunResult(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+	resultPath := filepath.Join(filepath.Dir(reportPath), "result.json")
+
+	code, _, stderr := run("run", "-c", configPath, "--log-level", "error", "--result", resultPath)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr = %s", code, stderr)
+	}
+
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("result not written: %v", err)
+	}
+	var result struct {
+		RunID      string `json:"run_id"`
+		Status     string `json:"status"`
+		RecordsIn  int    `json:"records_in"`
+		RecordsOut int    `json:"records_out"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if result.RunID == "" || result.Status != "succeeded" || result.RecordsIn != 2 || result.RecordsOut != 2 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The result is also written for failed runs, here to stdout
+	code, stdout, _ := run("run", "-c", configPath, "--log-level", "error", "--result", "-",
+		"--set", "provider.params.file_path="+filepath.Join(t.TempDir(), "missing.csv"))
+	if code == ExitOK || !strings.Contains(stdout, `"status": "failed"`) {
+		t.Errorf("exit code = %d, stdout = %s", code, stdout)
+	}
+}
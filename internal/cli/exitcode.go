@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+)
+
+// Exit codes returned by Run. Pipeline failures map from the error's
+// errors.ErrorType so wrappers and schedulers can decide whether to retry.
+const (
+	ExitOK            = 0   // Success
+	ExitFailure       = 1   // Unclassified failure
+	ExitUsage         = 2   // Bad command line
+	ExitConfiguration = 3   // Config file, override or component setup problem
+	ExitValidation    = 4   // Input data failed validation
+	ExitTransient     = 5   // Temporary failure; retrying may succeed
+	ExitPermanent     = 6   // Permanent failure; retrying will not help
+	ExitResource      = 7   // Resource exhaustion (disk, memory, connections)
+	ExitInterrupted   = 130 // Canceled by signal
+)
+
+// usageError reports invalid command-line usage.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf creates a usage error.
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ExitCode maps an error to a process exit code.
+//
+// The most specific classification in the error chain wins: the engine
+// wraps stage errors in an unclassified EngineError, so the chain is
+// searched for the first EngineError with a known type.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return ExitUsage
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+
+	switch engerrors.Classify(err) {
+	case engerrors.ErrorTypeConfiguration:
+		return ExitConfiguration
+	case engerrors.ErrorTypeValidation:
+		return ExitValidation
+	case engerrors.ErrorTypeTransient:
+		return ExitTransient
+	case engerrors.ErrorTypePermanent:
+		return ExitPermanent
+	case engerrors.ErrorTypeResource:
+		return ExitResource
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTransient
+	}
+	return ExitFailure
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+)
+
+func TestExitCode(t *testing.T) {
+	classified := func(t engerrors.ErrorType) error {
+		return engerrors.WrapWithType(engerrors.ComponentProvider, "fetch", t, fmt.Errorf("boom"))
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "plain error", err: fmt.Errorf("boom"), want: ExitFailure},
+		{name: "usage", err: usageErrorf("bad flag"), want: ExitUsage},
+		{name: "configuration", err: classified(engerrors.ErrorTypeConfiguration), want: ExitConfiguration},
+		{name: "validation", err: classified(engerrors.ErrorTypeValidation), want: ExitValidation},
+		{name: "transient", err: classified(engerrors.ErrorTypeTransient), want: ExitTransient},
+		{name: "permanent", err: classified(engerrors.ErrorTypePermanent), want: ExitPermanent},
+		{name: "resource", err: classified(engerrors.ErrorTypeResource), want: ExitResource},
+		{name: "unknown type", err: classified(engerrors.ErrorTypeUnknown), want: ExitFailure},
+		{
+			// The engine wraps stage errors in an unclassified EngineError
+			name: "classified cause under unknown wrapper",
+			err:  engerrors.NewErrorContext(engerrors.ComponentEngine, "run").Wrap(classified(engerrors.ErrorTypeTransient)),
+			want: ExitTransient,
+		},
+		{
+			name: "fmt wrapped",
+			err:  fmt.Errorf("stage failed: %w", classified(engerrors.ErrorTypeValidation)),
+			want: ExitValidation,
+		},
+		{name: "canceled", err: fmt.Errorf("run: %w", context.Canceled), want: ExitInterrupted},
+		{name: "deadline", err: engerrors.Wrap(engerrors.ComponentEngine, "run", context.DeadlineExceeded), want: ExitTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
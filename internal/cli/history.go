@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/history"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+// historyCommand implements "report-engine history".
+func historyCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		storePath string
+		runID     string
+		status    string
+		since     time.Duration
+		asJSON    bool
+		query     history.Query
+	)
+	fs := newFlagSet("history", stderr)
+	fs.StringVar(&storePath, "store", "", "path to the history file written by run --history")
+	fs.StringVar(&query.Job, "report", "", "only show runs of this report")
+	fs.StringVar(&status, "status", "", "only show runs with this status: succeeded, failed, canceled or skipped")
+	fs.DurationVar(&since, "since", 0, "only show runs started within this duration, e.g. 24h")
+	fs.IntVar(&query.Limit, "limit", 20, "maximum number of runs to show (0 for all)")
+	fs.StringVar(&runID, "id", "", "show the full record of one run as JSON")
+	fs.BoolVar(&asJSON, "json", false, "print runs as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageErrorf("%v", err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	if storePath == "" {
+		return usageErrorf("missing required flag --store")
+	}
+
+	switch scheduler.RunStatus(status) {
+	case "", scheduler.RunStatusSucceeded, scheduler.RunStatusFailed, scheduler.RunStatusCanceled, scheduler.RunStatusSkipped:
+		query.Status = scheduler.RunStatus(status)
+	default:
+		return usageErrorf("invalid --status %q", status)
+	}
+	if since > 0 {
+		query.Since = time.Now().Add(-since)
+	}
+
+	h := history.NewFileHistory(storePath)
+
+	if runID != "" {
+		run, err := h.Get(runID)
+		if err != nil {
+			return fmt.Errorf("run %q: %w", runID, err)
+		}
+		return writeJSON(stdout, run)
+	}
+
+	runs, err := history.Find(h, query)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if runs == nil {
+			runs = []scheduler.Run{}
+		}
+		return writeJSON(stdout, runs)
+	}
+
+	if len(runs) == 0 {
+		_, _ = fmt.Fprintln(stdout, "no runs recorded")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RUN ID\tREPORT\tSTATUS\tSTARTED\tDURATION\tRECORDS\tERROR")
+	for _, run := range runs {
+		records := "-"
+		if run.Result != nil {
+			records = fmt.Sprintf("%d/%d", run.Result.RecordsIn, run.Result.RecordsOut)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID, run.Job, run.Status,
+			run.StartedAt.Local().Format(time.DateTime),
+			run.Duration.Round(time.Millisecond),
+			records, errorSummary(run))
+	}
+	return tw.Flush()
+}
+
+// errorSummary describes a failed run as "<type> in <component>".
+func errorSummary(run scheduler.Run) string {
+	switch {
+	case run.Result != nil && run.Result.ErrorType != "" && run.Result.ErrorComponent != "":
+		return run.Result.ErrorType + " in " + run.Result.ErrorComponent
+	case run.Result != nil && run.Result.ErrorType != "":
+		return run.Result.ErrorType
+	case run.Error != "":
+		return run.Error
+	default:
+		return "-"
+	}
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
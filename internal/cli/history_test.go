@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/history"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+func TestRun_History(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+	storePath := filepath.Join(t.TempDir(), "runs.jsonl")
+
+	if code, _, stderr := run("run", "-c", configPath, "--log-level", "error", "--history", storePath); code != ExitOK {
+		t.Fatalf("run exit code = %d, stderr = %s", code, stderr)
+	}
+	code, _, _ := run("run", "-c", configPath, "--log-level", "error", "--history", storePath, "--name", "finance",
+		"--set", "provider.params.file_path="+filepath.Join(t.TempDir(), "missing.csv"))
+	if code == ExitOK {
+		t.Fatal("run with a missing input should fail")
+	}
+
+	// The default report name is the config file name
+	last, err := history.Latest(history.NewFileHistory(storePath), "config", "")
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if last.Trigger != "cli" || last.ConfigHash == "" || last.Result.RecordsOut != 2 {
+		t.Errorf("run = %+v", last)
+	}
+	if last.Result.OutputLocation != reportPath {
+		t.Errorf("OutputLocation = %q, want %q", last.Result.OutputLocation, reportPath)
+	}
+
+	code, stdout, stderr := run("history", "--store", storePath)
+	if code != ExitOK {
+		t.Fatalf("history exit code = %d, stderr = %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "RUN ID") {
+		t.Fatalf("history output = %q", stdout)
+	}
+	if !strings.Contains(lines[1], "finance") || !strings.Contains(lines[1], "failed") {
+		t.Errorf("newest run should be the failed finance run: %q", lines[1])
+	}
+
+	code, stdout, _ = run("history", "--store", storePath, "--report", "finance", "--status", "failed", "--json")
+	var runs []scheduler.Run
+	if code != ExitOK || json.Unmarshal([]byte(stdout), &runs) != nil || len(runs) != 1 {
+		t.Fatalf("history --json exit code = %d, stdout = %s", code, stdout)
+	}
+	if runs[0].Result == nil || runs[0].Result.ErrorComponent == "" || runs[0].Result.FailedStage == "" {
+		t.Errorf("failed run = %+v", runs[0])
+	}
+
+	code, stdout, _ = run("history", "--store", storePath, "--id", last.ID)
+	if code != ExitOK || !strings.Contains(stdout, `"id": "`+last.ID+`"`) {
+		t.Errorf("history --id exit code = %d, stdout = %s", code, stdout)
+	}
+}
+
+func TestRun_HistoryUsage(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "runs.jsonl")
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"missing store", []string{"history"}, ExitUsage},
+		{"bad status", []string{"history", "--store", store, "--status", "running"}, ExitUsage},
+		{"unknown run", []string{"history", "--store", store, "--id", "nope"}, ExitFailure},
+		{"empty store", []string{"history", "--store", store}, ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := run(tt.args...); code != tt.want {
+				t.Errorf("exit code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AshishBagdane/go-report-engine/internal/config"
+	"github.com/AshishBagdane/go-report-engine/internal/registry"
+)
+
+// pluginList is the list-plugins output, also used for --json.
+type pluginList struct {
+	Providers  []string `json:"providers"`
+	Processors []string `json:"processors"`
+	Formatters []string `json:"formatters"`
+	Outputs    []string `json:"outputs"`
+}
+
+// listPluginsCommand implements "report-engine list-plugins".
+func listPluginsCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var asJSON bool
+	fs := newFlagSet("list-plugins", stderr)
+	fs.BoolVar(&asJSON, "json", false, "print the plugin list as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageErrorf("%v", err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+
+	plugins := pluginList{
+		Providers:  registry.ListProviders(),
+		Processors: registry.ListProcessors(),
+		Formatters: registry.ListFormatters(),
+		Outputs:    registry.ListOutputs(),
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plugins)
+	}
+
+	for _, section := range []struct {
+		title string
+		names []string
+	}{
+		{"Providers", plugins.Providers},
+		{"Processors", plugins.Processors},
+		{"Formatters", plugins.Formatters},
+		{"Outputs", plugins.Outputs},
+	} {
+		_, _ = fmt.Fprintf(stdout, "%s:\n", section.title)
+		if len(section.names) == 0 {
+			_, _ = fmt.Fprintln(stdout, "  (none)")
+		}
+		for _, name := range section.names {
+			_, _ = fmt.Fprintf(stdout, "  %s\n", name)
+		}
+	}
+	return nil
+}
+
+// schemaCommand implements "report-engine schema". The schema covers the
+// components registered in this binary, including their declared params.
+func schemaCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var outPath string
+	fs := newFlagSet("schema", stderr)
+	fs.StringVar(&outPath, "out", "", "write the schema to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageErrorf("%v", err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+
+	data, err := config.JSONSchema()
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if outPath == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
+)
+
+// spoolCommand implements "report-engine spool".
+func spoolCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("missing subcommand: list or replay")
+	}
+	switch args[0] {
+	case "list":
+		return spoolListCommand(args[1:], stdout, stderr)
+	case "replay":
+		return spoolReplayCommand(ctx, args[1:], stdout, stderr)
+	case "-h", "--help", "help":
+		_, _ = fmt.Fprintln(stderr, "Usage: report-engine spool <list|replay> [flags]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "  list    List the reports waiting in a spool directory")
+		_, _ = fmt.Fprintln(stderr, "  replay  Deliver the spooled reports of a config's output")
+		return flag.ErrHelp
+	}
+	return usageErrorf("unknown spool subcommand %q (want list or replay)", args[0])
+}
+
+// spoolListCommand implements "report-engine spool list".
+func spoolListCommand(args []string, stdout, stderr io.Writer) error {
+	var (
+		dir    string
+		asJSON bool
+	)
+	fs := newFlagSet("spool list", stderr)
+	fs.StringVar(&dir, "dir", "", "spool directory (the spool.dir config setting)")
+	fs.BoolVar(&asJSON, "json", false, "print entries as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageErrorf("%v", err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	if dir == "" {
+		return usageErrorf("missing required flag --dir")
+	}
+
+	entries, err := spool.New(dir).List()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if entries == nil {
+			entries = []spool.Entry{}
+		}
+		return writeJSON(stdout, entries)
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(stdout, "no spooled reports")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tOUTPUT\tRUN ID\tCREATED\tSIZE\tATTEMPTS\tLAST ERROR")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			e.ID, e.Output, orDash(e.RunID),
+			e.CreatedAt.Local().Format(time.DateTime),
+			e.Size, e.Attempts, orDash(e.LastError))
+	}
+	return tw.Flush()
+}
+
+// spoolReplayCommand implements "report-engine spool replay". The output
+// is built from the config file, so reports are delivered with its
+// current settings.
+func spoolReplayCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		flags pipelineFlags
+		id    string
+	)
+	fs := newFlagSet("spool replay", stderr)
+	flags.register(fs, true)
+	fs.StringVar(&id, "id", "", "replay only the spooled report with this ID")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	_, eng, err := flags.buildEngine(stderr)
+	if err != nil {
+		return err
+	}
+	defer closeEngine(eng, stderr)
+
+	out, ok := eng.Output.(*spool.Output)
+	if !ok {
+		return engerrors.WrapWithType(engerrors.ComponentFactory, "spool_replay", engerrors.ErrorTypeConfiguration,
+			fmt.Errorf("%s has no spool section", flags.configPath))
+	}
+	logger, err := flags.logger(stderr)
+	if err != nil {
+		return err
+	}
+	out.WithLogger(logger)
+
+	ctx, cancel := flags.withTimeout(ctx)
+	defer cancel()
+
+	if id != "" {
+		if err := out.ReplayEntry(ctx, id); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "delivered spooled report %s\n", id)
+		return nil
+	}
+
+	delivered, err := out.Replay(ctx)
+	_, _ = fmt.Fprintf(stdout, "delivered %d spooled report(s) from %s\n", delivered, out.Spool().Dir())
+	return err
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
+)
+
+func TestRun_Spool(t *testing.T) {
+	configPath, reportPath := writeFixture(t)
+	spoolDir := filepath.Join(t.TempDir(), "spool")
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config = append(config, []byte("spool:\n  dir: "+spoolDir+"\n  max_retries: 1\n  base_delay: 1ms\n  max_delay: 1ms\n")...)
+	if err := os.WriteFile(configPath, config, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The report's directory is a file, so delivery fails and the report
+	// stays spooled while the run still succeeds
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	unreachable := "output.params.path=" + filepath.Join(blocker, "report.json")
+	if code, _, stderr := run("run", "-c", configPath, "--log-level", "error", "--set", unreachable); code != ExitOK {
+		t.Fatalf("run exit code = %d, stderr = %s", code, stderr)
+	}
+
+	code, stdout, stderr := run("spool", "list", "--dir", spoolDir)
+	if code != ExitOK {
+		t.Fatalf("spool list exit code = %d, stderr = %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "file") {
+		t.Fatalf("spool list output = %q", stdout)
+	}
+
+	code, stdout, _ = run("spool", "list", "--dir", spoolDir, "--json")
+	var entries []spool.Entry
+	if code != ExitOK || json.Unmarshal([]byte(stdout), &entries) != nil || len(entries) != 1 {
+		t.Fatalf("spool list --json exit code = %d, stdout = %s", code, stdout)
+	}
+	if entries[0].Attempts != 1 || entries[0].LastError == "" {
+		t.Errorf("entry = %+v", entries[0])
+	}
+
+	code, _, _ = run("spool", "replay", "-c", configPath, "--log-level", "error", "--set", unreachable)
+	if code == ExitOK {
+		t.Error("replay to an unreachable output should fail")
+	}
+
+	code, stdout, stderr = run("spool", "replay", "-c", configPath, "--log-level", "error", "--id", entries[0].ID)
+	if code != ExitOK || !strings.Contains(stdout, entries[0].ID) {
+		t.Fatalf("spool replay exit code = %d, stdout = %s, stderr = %s", code, stdout, stderr)
+	}
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("replay should write the report: %v", err)
+	}
+
+	code, stdout, _ = run("spool", "list", "--dir", spoolDir)
+	if code != ExitOK || strings.TrimSpace(stdout) != "no spooled reports" {
+		t.Errorf("spool list after replay exit code = %d, stdout = %q", code, stdout)
+	}
+}
+
+func TestRun_SpoolUsage(t *testing.T) {
+	configPath, _ := writeFixture(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no subcommand", []string{"spool"}, ExitUsage},
+		{"unknown subcommand", []string{"spool", "purge"}, ExitUsage},
+		{"list without dir", []string{"spool", "list"}, ExitUsage},
+		{"replay without spool section", []string{"spool", "replay", "-c", configPath, "--log-level", "error"}, ExitConfiguration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, stderr := run(tt.args...); code != tt.want {
+				t.Errorf("exit code = %d, want %d (stderr = %s)", code, tt.want, stderr)
+			}
+		})
+	}
+}
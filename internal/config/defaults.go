@@ -305,7 +305,6 @@ func ConfigWithProviderParams(base engine.Config, params map[string]string) engi
 //	config := config.DefaultConfig()
 //	config = config.ConfigWithFormatterParams(config, map[string]string{
 //	    "indent": "4",
-//	    "pretty": "true",
 //	})
 func ConfigWithFormatterParams(base engine.Config, params map[string]string) engine.Config {
 	newConfig := base
@@ -0,0 +1,299 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
+)
+
+// hclListBlocks maps repeatable HCL block types to the list they append
+// to, so each processor block adds one processor.
+var hclListBlocks = map[string]string{
+	"processor": "processors",
+}
+
+// parseHCL parses HCL data into a node tree that records the line and
+// column of every key and value, so HCL configs share includes,
+// interpolation and strict field checks with YAML.
+//
+// Blocks become mappings, and a block's labels nest it under the block
+// type, so reports "daily" { ... } declares the report "daily" of a
+// multi-report file. Each processor block appends one processor:
+//
+//	provider {
+//	  type   = "sql"
+//	  params = { query = "SELECT * FROM sales" }
+//	}
+//
+//	processor {
+//	  type = "deduplicate"
+//	}
+//
+// Expressions are evaluated without variables or functions. HCL reads
+// "${" as the start of a template, so config references are written with
+// HCL's escape, "$${DB_HOST}", and are resolved like those of YAML files.
+func parseHCL(data []byte) (*yaml.Node, error) {
+	file, diags := hclsyntax.ParseConfig(data, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, hclError(diags)
+	}
+
+	body := file.Body.(*hclsyntax.Body)
+	root, err := hclBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1}
+	if len(root.Content) > 0 {
+		doc.Content = []*yaml.Node{root}
+	}
+	return doc, nil
+}
+
+// hclBody converts the attributes and blocks of a body, in source order.
+func hclBody(body *hclsyntax.Body) (*yaml.Node, error) {
+	m := hclNode(yaml.MappingNode, "!!map", body.SrcRange)
+
+	type item struct {
+		start int
+		attr  *hclsyntax.Attribute
+		block *hclsyntax.Block
+	}
+	items := make([]item, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, item{start: attr.SrcRange.Start.Byte, attr: attr})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, item{start: block.TypeRange.Start.Byte, block: block})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].start < items[j].start })
+
+	for _, it := range items {
+		var err error
+		if it.attr != nil {
+			err = hclAttribute(m, it.attr)
+		} else {
+			err = hclBlock(m, it.block)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// hclAttribute adds an attribute to m.
+func hclAttribute(m *yaml.Node, attr *hclsyntax.Attribute) error {
+	if existing := mappingValue(m, attr.Name); existing != nil {
+		return hclDuplicate(attr.Name, attr.NameRange, existing)
+	}
+	value, err := hclExpression(attr.Expr)
+	if err != nil {
+		return err
+	}
+	m.Content = append(m.Content, hclKey(attr.Name, attr.NameRange), value)
+	return nil
+}
+
+// hclBlock adds a block to m, nested under its type and labels.
+func hclBlock(m *yaml.Node, block *hclsyntax.Block) error {
+	body, err := hclBody(block.Body)
+	if err != nil {
+		return err
+	}
+	body.Line, body.Column = block.TypeRange.Start.Line, block.TypeRange.Start.Column
+
+	if list, ok := hclListBlocks[block.Type]; ok && len(block.Labels) == 0 {
+		seq := mappingValue(m, list)
+		if seq == nil {
+			seq = hclNode(yaml.SequenceNode, "!!seq", block.TypeRange)
+			m.Content = append(m.Content, hclKey(list, block.TypeRange), seq)
+		}
+		if seq.Kind != yaml.SequenceNode {
+			return hclDuplicate(list, block.TypeRange, seq)
+		}
+		seq.Content = append(seq.Content, body)
+		return nil
+	}
+
+	keys := append([]string{block.Type}, block.Labels...)
+	ranges := append([]hcl.Range{block.TypeRange}, block.LabelRanges...)
+	for i, key := range keys[:len(keys)-1] {
+		next := mappingValue(m, key)
+		if next == nil {
+			next = hclNode(yaml.MappingNode, "!!map", ranges[i])
+			m.Content = append(m.Content, hclKey(key, ranges[i]), next)
+		}
+		if next.Kind != yaml.MappingNode {
+			return hclDuplicate(key, ranges[i], next)
+		}
+		m = next
+	}
+
+	last := len(keys) - 1
+	if existing := mappingValue(m, keys[last]); existing != nil {
+		return hclDuplicate(keys[last], ranges[last], existing)
+	}
+	m.Content = append(m.Content, hclKey(keys[last], ranges[last]), body)
+	return nil
+}
+
+// hclExpression converts an expression. Object and tuple constructors are
+// walked so their items keep their own positions.
+func hclExpression(expr hclsyntax.Expression) (*yaml.Node, error) {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		m := hclNode(yaml.MappingNode, "!!map", e.SrcRange)
+		for _, item := range e.Items {
+			key, diags := item.KeyExpr.Value(nil)
+			if diags.HasErrors() {
+				return nil, hclError(diags)
+			}
+			if key.IsNull() || !key.IsKnown() || key.Type() != cty.String {
+				return nil, hclErrorAt(item.KeyExpr.Range(), "object keys must be strings")
+			}
+			name := key.AsString()
+			if existing := mappingValue(m, name); existing != nil {
+				return nil, hclDuplicate(name, item.KeyExpr.Range(), existing)
+			}
+			value, err := hclExpression(item.ValueExpr)
+			if err != nil {
+				return nil, err
+			}
+			m.Content = append(m.Content, hclKey(name, item.KeyExpr.Range()), value)
+		}
+		return m, nil
+
+	case *hclsyntax.TupleConsExpr:
+		seq := hclNode(yaml.SequenceNode, "!!seq", e.SrcRange)
+		for _, item := range e.Exprs {
+			value, err := hclExpression(item)
+			if err != nil {
+				return nil, err
+			}
+			seq.Content = append(seq.Content, value)
+		}
+		return seq, nil
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, hclError(diags)
+	}
+	return hclValue(value, expr.Range())
+}
+
+// hclValue converts an evaluated value found at r.
+func hclValue(value cty.Value, r hcl.Range) (*yaml.Node, error) {
+	if value.IsNull() {
+		return hclNode(yaml.ScalarNode, "!!null", r), nil
+	}
+	if !value.IsWhollyKnown() {
+		return nil, hclErrorAt(r, "value is not known")
+	}
+
+	t := value.Type()
+	switch {
+	case t == cty.String:
+		s := hclScalar("!!str", value.AsString(), r)
+		s.Style = yaml.DoubleQuotedStyle
+		return s, nil
+
+	case t == cty.Bool:
+		if value.True() {
+			return hclScalar("!!bool", "true", r), nil
+		}
+		return hclScalar("!!bool", "false", r), nil
+
+	case t == cty.Number:
+		f := value.AsBigFloat()
+		if f.IsInt() {
+			return hclScalar("!!int", f.Text('f', 0), r), nil
+		}
+		return hclScalar("!!float", f.Text('g', -1), r), nil
+
+	case t.IsObjectType() || t.IsMapType():
+		m := hclNode(yaml.MappingNode, "!!map", r)
+		keys := make([]string, 0, value.LengthInt())
+		values := value.AsValueMap()
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item, err := hclValue(values[key], r)
+			if err != nil {
+				return nil, err
+			}
+			m.Content = append(m.Content, hclKey(key, r), item)
+		}
+		return m, nil
+
+	case t.IsTupleType() || t.IsListType() || t.IsSetType():
+		seq := hclNode(yaml.SequenceNode, "!!seq", r)
+		for _, item := range value.AsValueSlice() {
+			node, err := hclValue(item, r)
+			if err != nil {
+				return nil, err
+			}
+			seq.Content = append(seq.Content, node)
+		}
+		return seq, nil
+
+	default:
+		return nil, hclErrorAt(r, fmt.Sprintf("unsupported value of type %s", t.FriendlyName()))
+	}
+}
+
+// hclKey creates a mapping key at r.
+func hclKey(name string, r hcl.Range) *yaml.Node {
+	return hclScalar("!!str", name, r)
+}
+
+// hclScalar creates a scalar node at r.
+func hclScalar(tag, value string, r hcl.Range) *yaml.Node {
+	s := hclNode(yaml.ScalarNode, tag, r)
+	s.Value = value
+	return s
+}
+
+// hclNode creates a node at the start of r.
+func hclNode(kind yaml.Kind, tag string, r hcl.Range) *yaml.Node {
+	return &yaml.Node{Kind: kind, Tag: tag, Line: r.Start.Line, Column: r.Start.Column}
+}
+
+// hclDuplicate reports a key defined twice.
+func hclDuplicate(name string, r hcl.Range, first *yaml.Node) error {
+	return hclErrorAt(r, fmt.Sprintf("duplicate %q; first defined at line %d, column %d", name, first.Line, first.Column))
+}
+
+// hclErrorAt reports a problem at r.
+func hclErrorAt(r hcl.Range, message string) error {
+	return fmt.Errorf("failed to parse HCL config: line %d, column %d: %s", r.Start.Line, r.Start.Column, message)
+}
+
+// hclError reports the first error in diags.
+func hclError(diags hcl.Diagnostics) error {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		message := diag.Summary
+		if diag.Detail != "" {
+			message += "; " + diag.Detail
+		}
+		if diag.Summary == "Variables not allowed" {
+			message += ` Write "$${NAME}" to reference a config variable or secret.`
+		}
+		if diag.Subject == nil {
+			return fmt.Errorf("failed to parse HCL config: %s", message)
+		}
+		return hclErrorAt(*diag.Subject, message)
+	}
+	return fmt.Errorf("failed to parse HCL config: %s", diags.Error())
+}
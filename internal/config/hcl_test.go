@@ -0,0 +1,145 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const hclConfig = `
+provider {
+  type   = "mock"
+  params = {
+    query      = "SELECT * FROM sales"
+    batch_size = 500
+  }
+}
+
+processor {
+  type = "deduplicate"
+}
+
+processor {
+  type   = "aggregate"
+  params = { group_by = "region" }
+}
+
+formatter {
+  type = "json"
+}
+
+output {
+  type   = "file"
+  params = { path = "$${REPORT_DIR:-/tmp}/sales.json" }
+}
+
+retry {
+  max_retries = 3
+  base_delay  = "1s"
+  max_delay   = "30s"
+  factor      = 2.5
+  jitter      = true
+}
+`
+
+// TestLoadFromFileHCL tests blocks, processor lists and value types
+func TestLoadFromFileHCL(t *testing.T) {
+	t.Setenv("REPORT_DIR", "/data")
+	path := writeFile(t, t.TempDir(), "config.hcl", hclConfig)
+
+	config, err := NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if config.Provider.Params["query"] != "SELECT * FROM sales" || config.Provider.Params["batch_size"] != "500" {
+		t.Errorf("provider params = %v", config.Provider.Params)
+	}
+	if len(config.Processors) != 2 || config.Processors[0].Type != "deduplicate" || config.Processors[1].Params["group_by"] != "region" {
+		t.Errorf("processors = %+v", config.Processors)
+	}
+	if config.Output.Params["path"] != "/data/sales.json" {
+		t.Errorf("output path = %q, want the interpolated path", config.Output.Params["path"])
+	}
+	if r := config.Retry; r == nil || r.MaxRetries != 3 || r.Factor != 2.5 || !r.Jitter || r.MaxDelay != "30s" {
+		t.Errorf("retry = %+v", r)
+	}
+}
+
+// TestLoadFromBytesHCLEnvOverrides tests that env overrides apply to HCL
+func TestLoadFromBytesHCLEnvOverrides(t *testing.T) {
+	t.Setenv("REPORT_DIR", "/data")
+	t.Setenv("ENGINE_FORMATTER_TYPE", "csv")
+	t.Setenv("ENGINE_OUTPUT_PARAM_PATH", "/tmp/out.csv")
+
+	config, err := NewLoader().WithEnvOverrides().LoadFromBytes([]byte(hclConfig), "HCL")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if config.Formatter.Type != "csv" || config.Output.Params["path"] != "/tmp/out.csv" {
+		t.Errorf("formatter = %q, path = %q, want env overrides", config.Formatter.Type, config.Output.Params["path"])
+	}
+}
+
+// TestLoadHCLErrors tests that errors carry their line and column
+func TestLoadHCLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"syntax", "provider {\n  type = \n}\n", "failed to parse HCL config: line 2, column 10"},
+		{"template variable", "provider {\n  type = \"${TYPE}\"\n}\n", `line 2, column 13: Variables not allowed; Variables may not be used here. Write "$${NAME}"`},
+		{"duplicate block", "provider { type = \"mock\" }\nprovider { type = \"sql\" }\n", `line 2, column 1: duplicate "provider"; first defined at line 1, column 1`},
+		{
+			"unknown field",
+			"provider {\n  type  = \"mock\"\n  parms = {}\n}\nformatter { type = \"json\" }\noutput { type = \"console\" }\n",
+			`provider.parms (line 3, column 3) (did you mean "params"?)`,
+		},
+		{
+			"wrong type",
+			"provider { type = \"mock\" }\nformatter { type = \"json\" }\noutput { type = \"console\" }\nretry {\n  max_retries = \"three\"\n}\n",
+			"line 5",
+		},
+		{
+			"invalid field",
+			"provider { type = \"mock\" }\nformatter { type = \"json\" }\noutput { type = \"console\" }\ntimeouts {\n  fetch = \"soon\"\n}\n",
+			`timeouts.fetch must be a positive duration, got "soon" (line 5, column 3)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromBytes([]byte(tt.content), "hcl")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFromBytes() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadReportsHCL tests labeled report blocks
+func TestLoadReportsHCL(t *testing.T) {
+	content := `
+defaults {
+  formatter { type = "json" }
+  output { type = "console" }
+}
+
+reports "daily" {
+  provider { type = "mock" }
+}
+
+reports "weekly" {
+  provider { type = "mock" }
+  processor { type = "deduplicate" }
+}
+`
+	reports, err := NewLoader().LoadReportsFromBytes([]byte(content), "hcl")
+	if err != nil {
+		t.Fatalf("LoadReportsFromBytes() error = %v", err)
+	}
+	if len(reports) != 2 || reports["daily"].Formatter.Type != "json" {
+		t.Errorf("reports = %v", reports)
+	}
+	if weekly := reports["weekly"]; len(weekly.Processors) != 1 || weekly.Output.Type != "console" {
+		t.Errorf("weekly = %+v", weekly)
+	}
+}
@@ -13,7 +13,7 @@ import (
 // This is a convenience function that combines LoadFromFile and NewEngineFromConfig.
 //
 // Parameters:
-//   - path: Path to the configuration file (YAML or JSON)
+//   - path: Path to the configuration file (YAML, JSON, TOML or HCL)
 //
 // Returns:
 //   - *engine.ReportEngine: Fully configured engine ready to run
@@ -49,7 +49,7 @@ func LoadAndBuild(path string) (*engine.ReportEngine, error) {
 // Environment variables will override values from the configuration file.
 //
 // Parameters:
-//   - path: Path to the configuration file (YAML or JSON)
+//   - path: Path to the configuration file (YAML, JSON, TOML or HCL)
 //
 // Returns:
 //   - *engine.ReportEngine: Fully configured engine ready to run
@@ -458,3 +458,71 @@ func BuildFromConfigOrFile(cfg *engine.Config, path string) (*engine.ReportEngin
 
 	return eng, nil
 }
+
+// LoadAndBuildReports loads a multi-report configuration file and builds
+// an engine for each report, keyed by report name. See
+// Loader.LoadReportsFromFile for the file layout.
+//
+// Parameters:
+//   - path: Path to the multi-report configuration file (YAML, JSON, TOML or HCL)
+//
+// Returns:
+//   - map[string]*engine.ReportEngine: Engines by report name
+//   - error: nil on success, error naming the first report that failed
+//
+// Example:
+//
+//	engines, err := config.LoadAndBuildReports("reports.yaml")
+//	if err != nil {
+//	    log.Fatalf("Failed to create engines: %v", err)
+//	}
+//	res, err := engines["daily-sales"].Execute(ctx)
+func LoadAndBuildReports(path string) (map[string]*engine.ReportEngine, error) {
+	cfgs, err := LoadReportsFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return buildReports(cfgs)
+}
+
+// LoadAndBuildReportsWithEnv is like LoadAndBuildReports but applies
+// environment overrides to every report.
+func LoadAndBuildReportsWithEnv(path string) (map[string]*engine.ReportEngine, error) {
+	cfgs, err := LoadReportsFromFileWithEnv(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return buildReports(cfgs)
+}
+
+// BuildReportsFromBytes loads a multi-report configuration from raw bytes
+// ("yaml" or "json") and builds an engine for each report.
+func BuildReportsFromBytes(data []byte, format string) (map[string]*engine.ReportEngine, error) {
+	cfgs, err := NewLoader().LoadReportsFromBytes(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return buildReports(cfgs)
+}
+
+// MustLoadAndBuildReports is like LoadAndBuildReports but panics on error.
+func MustLoadAndBuildReports(path string) map[string]*engine.ReportEngine {
+	engines, err := LoadAndBuildReports(path)
+	if err != nil {
+		panic(fmt.Sprintf("MustLoadAndBuildReports failed: %v", err))
+	}
+	return engines
+}
+
+// buildReports builds an engine for each report config.
+func buildReports(cfgs map[string]*engine.Config) (map[string]*engine.ReportEngine, error) {
+	engines := make(map[string]*engine.ReportEngine, len(cfgs))
+	for name, cfg := range cfgs {
+		eng, err := factory.NewEngineFromConfig(*cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build report %q: %w", name, err)
+		}
+		engines[name] = eng
+	}
+	return engines, nil
+}
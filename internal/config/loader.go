@@ -1,9 +1,11 @@
 // Package config provides configuration loading capabilities for the report engine.
-// It supports loading from YAML and JSON files with environment variable overrides,
+// It supports loading from YAML, JSON, TOML and HCL files with environment variable overrides,
 // validation, and default values.
 //
 // The package is designed to handle production-grade configuration needs:
-//   - Multiple file formats (YAML, JSON)
+//   - Multiple file formats (YAML, JSON, TOML, HCL)
+//   - ${VAR} interpolation, includes and secret references
+//   - Strict field checking with "did you mean" hints
 //   - Environment variable overrides
 //   - Comprehensive validation
 //   - Clear error messages
@@ -25,7 +27,6 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -45,12 +46,29 @@ const (
 type Loader struct {
 	// applyEnvOverrides determines if environment variables should override file config.
 	applyEnvOverrides bool
+
+	// overrides are "path=value" overrides applied after environment overrides.
+	overrides []string
+
+	// secrets are the resolvers for ${scheme:ref} references, by scheme.
+	secrets map[string]SecretResolver
+
+	// allowUnknownFields disables the unknown field check.
+	allowUnknownFields bool
+
+	// onInclude, if set, is called with every included file read.
+	onInclude func(file string)
 }
 
-// NewLoader creates a new configuration loader.
+// NewLoader creates a new configuration loader. The "env" and "file"
+// secret resolvers are registered by default.
 func NewLoader() *Loader {
 	return &Loader{
 		applyEnvOverrides: false,
+		secrets: map[string]SecretResolver{
+			"env":  EnvSecrets,
+			"file": FileSecrets,
+		},
 	}
 }
 
@@ -68,8 +86,49 @@ func (l *Loader) WithEnvOverrides() *Loader {
 	return l
 }
 
-// LoadFromFile loads configuration from a file (YAML or JSON).
-// The file format is determined by the file extension (.yaml, .yml, or .json).
+// WithOverrides adds "path=value" overrides (see ApplyOverrides) that are
+// applied after environment overrides and before validation.
+//
+// Example:
+//
+//	loader := config.NewLoader().WithOverrides("output.params.path=/tmp/report.json")
+func (l *Loader) WithOverrides(overrides ...string) *Loader {
+	l.overrides = append(l.overrides, overrides...)
+	return l
+}
+
+// WithSecretResolver registers a resolver for "${scheme:ref}" references,
+// replacing any resolver already registered for scheme.
+//
+// Example:
+//
+//	loader := config.NewLoader().
+//	    WithSecretResolver("vault", config.NewLocalVault("vault.yaml"))
+//
+//	# config.yaml
+//	provider:
+//	  params:
+//	    dsn: "postgres://reports:${vault:db/reporting#password}@db/sales"
+func (l *Loader) WithSecretResolver(scheme string, resolver SecretResolver) *Loader {
+	l.secrets[scheme] = resolver
+	return l
+}
+
+// AllowUnknownFields disables strict field checking. By default a key that
+// matches no config field, such as "parms:" instead of "params:", fails the
+// load; with this option such keys are silently ignored.
+func (l *Loader) AllowUnknownFields() *Loader {
+	l.allowUnknownFields = true
+	return l
+}
+
+// LoadFromFile loads configuration from a file (YAML, JSON, TOML or HCL).
+// The file format is determined by the file extension (.yaml, .yml, .json,
+// .toml or .hcl). Parse errors and unknown fields are reported with their
+// line and column in every format.
+//
+// Before validation, includes and ${...} references are resolved (see
+// documentResolver), then environment and explicit overrides are applied.
 //
 // Parameters:
 //   - path: Path to the configuration file
@@ -85,84 +144,157 @@ func (l *Loader) WithEnvOverrides() *Loader {
 //	    log.Fatalf("Failed to load config: %v", err)
 //	}
 func (l *Loader) LoadFromFile(path string) (*engine.Config, error) {
+	data, format, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := l.decode(data, format, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.finish(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// LoadFromBytes loads configuration from raw bytes (YAML, JSON, TOML or HCL).
+//
+// Parameters:
+//   - data: Raw configuration data
+//   - format: "yaml", "json", "toml" or "hcl"
+//
+// Returns:
+//   - *engine.Config: Loaded and validated configuration
+//   - error: nil on success, error describing the failure otherwise
+func (l *Loader) LoadFromBytes(data []byte, format string) (*engine.Config, error) {
+	format, err := normalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Includes are resolved relative to the working directory
+	config, err := l.decode(data, format, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := l.finish(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// readConfigFile reads a config file and determines its format from the
+// extension (.yaml, .yml, .json, .toml or .hcl).
+func readConfigFile(path string) ([]byte, string, error) {
 	// Validate file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file not found: %s", path)
+		return nil, "", fmt.Errorf("config file not found: %s", path)
 	}
 
 	// Read file contents
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, "", fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	// Determine format from extension
 	ext := strings.ToLower(filepath.Ext(path))
-	var config engine.Config
-
 	switch ext {
-	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
-		}
-	case ".json":
-		if err := json.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
-		}
+	case ".yaml", ".yml", ".json", ".toml", ".hcl":
+		return data, formatOf(path), nil
 	default:
-		return nil, fmt.Errorf("unsupported config file format: %s (use .yaml, .yml, or .json)", ext)
+		return nil, "", fmt.Errorf("unsupported config file format: %s (use .yaml, .yml, .json, .toml or .hcl)", ext)
 	}
+}
 
-	// Apply environment overrides if enabled
-	if l.applyEnvOverrides {
-		applyEnvironmentOverrides(&config)
+// normalizeFormat checks a format name and returns "yaml", "json", "toml"
+// or "hcl".
+func normalizeFormat(format string) (string, error) {
+	switch f := strings.ToLower(format); f {
+	case "yaml", "yml":
+		return "yaml", nil
+	case "json", "toml", "hcl":
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s (use 'yaml', 'json', 'toml' or 'hcl')", format)
 	}
+}
 
-	// Validate configuration
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// resolveDocument parses config data and resolves includes, variables and
+// secret references. file is the config file path, or "" for data that
+// did not come from a file. It returns nil for an empty document.
+func (l *Loader) resolveDocument(data []byte, format, file string) (*yaml.Node, error) {
+	doc, err := parseDocument(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
 	}
 
-	return &config, nil
+	r := &documentResolver{secrets: l.secrets, onInclude: l.onInclude}
+	dir := "."
+	if file != "" {
+		dir = filepath.Dir(file)
+		if abs, err := filepath.Abs(file); err == nil {
+			r.files = []string{abs}
+		}
+	}
+	if err := r.resolve(doc, dir, ""); err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+	return doc, nil
 }
 
-// LoadFromBytes loads configuration from raw bytes (YAML or JSON).
-// The format is automatically detected based on the content.
-//
-// Parameters:
-//   - data: Raw configuration data
-//   - format: "yaml" or "json"
-//
-// Returns:
-//   - *engine.Config: Loaded and validated configuration
-//   - error: nil on success, error describing the failure otherwise
-func (l *Loader) LoadFromBytes(data []byte, format string) (*engine.Config, error) {
+// decode resolves config data and decodes it as a single report.
+func (l *Loader) decode(data []byte, format, file string) (engine.Config, error) {
 	var config engine.Config
 
-	switch strings.ToLower(format) {
-	case "yaml", "yml":
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
-		}
-	case "json":
-		if err := json.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported format: %s (use 'yaml' or 'json')", format)
+	doc, err := l.resolveDocument(data, format, file)
+	if err != nil || doc == nil {
+		return config, err
+	}
+	if root := doc.Content[0]; root.Kind == yaml.MappingNode && mappingValue(root, "reports") != nil {
+		return config, ErrMultipleReports
 	}
+	if err := l.checkFields(doc); err != nil {
+		return config, err
+	}
+
+	if err := doc.Decode(&config); err != nil {
+		return config, fmt.Errorf("failed to parse %s config: %w", strings.ToUpper(format), err)
+	}
+	config.Positions = fieldPositions(doc)
+	return config, nil
+}
 
+// checkFields rejects unknown fields unless they are allowed.
+func (l *Loader) checkFields(node *yaml.Node) error {
+	if l.allowUnknownFields {
+		return nil
+	}
+	return checkFields(node)
+}
+
+// finish applies environment and explicit overrides, then validates.
+func (l *Loader) finish(config *engine.Config) error {
 	// Apply environment overrides if enabled
 	if l.applyEnvOverrides {
-		applyEnvironmentOverrides(&config)
+		applyEnvironmentOverrides(config)
+	}
+
+	// Apply explicit overrides
+	if err := ApplyOverrides(config, l.overrides); err != nil {
+		return err
 	}
 
 	// Validate configuration
 	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
-
-	return &config, nil
+	return nil
 }
 
 // applyEnvironmentOverrides applies environment variable overrides to the config.
@@ -282,6 +282,9 @@ output:
 	if !strings.Contains(err.Error(), "invalid configuration") {
 		t.Errorf("Error should mention invalid configuration, got: %v", err)
 	}
+	if !strings.Contains(err.Error(), "provider.type is required (line 3, column 3)") {
+		t.Errorf("Error should locate the field, got: %v", err)
+	}
 }
 
 // TestLoadFromBytesYAML tests loading from YAML bytes
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+)
+
+// ApplyOverrides applies "path=value" overrides to a configuration, in order.
+// Overrides are how command-line flags such as
+// --set provider.params.query=... reach the config.
+//
+// Supported paths:
+//   - provider.type, formatter.type, output.type
+//   - provider.params.<key>, formatter.params.<key>, output.params.<key>
+//   - processors.<index>.type, processors.<index>.params.<key>
+//
+// A processor index equal to the current number of processors appends a
+// new processor. Param keys may themselves contain dots.
+//
+// Example:
+//
+//	err := config.ApplyOverrides(cfg, []string{
+//	    "provider.params.query=SELECT * FROM sales WHERE day = CURRENT_DATE",
+//	    "output.params.path=/tmp/sales.json",
+//	})
+func ApplyOverrides(cfg *engine.Config, overrides []string) error {
+	for _, override := range overrides {
+		if err := applyOverride(cfg, override); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyOverride applies a single "path=value" override.
+func applyOverride(cfg *engine.Config, override string) error {
+	path, value, ok := strings.Cut(override, "=")
+	path = strings.TrimSpace(path)
+	if !ok || path == "" {
+		return fmt.Errorf("invalid override %q: expected path=value", override)
+	}
+
+	section, rest, _ := strings.Cut(path, ".")
+	switch section {
+	case "provider":
+		return setComponentField(&cfg.Provider.Type, &cfg.Provider.Params, rest, value, path)
+	case "formatter":
+		return setComponentField(&cfg.Formatter.Type, &cfg.Formatter.Params, rest, value, path)
+	case "output":
+		return setComponentField(&cfg.Output.Type, &cfg.Output.Params, rest, value, path)
+	case "processors":
+		indexStr, field, _ := strings.Cut(rest, ".")
+		index, err := strconv.Atoi(indexStr)
+		if err != nil || index < 0 || index > len(cfg.Processors) {
+			return fmt.Errorf("invalid override %q: processor index must be between 0 and %d", path, len(cfg.Processors))
+		}
+		if index == len(cfg.Processors) {
+			cfg.Processors = append(cfg.Processors, engine.ProcessorConfig{})
+		}
+		p := &cfg.Processors[index]
+		return setComponentField(&p.Type, &p.Params, field, value, path)
+	default:
+		return fmt.Errorf("invalid override %q: unknown section %q (use provider, processors, formatter or output)", path, section)
+	}
+}
+
+// setComponentField sets "type" or "params.<key>" on a component config.
+func setComponentField(typ *string, params *map[string]string, field, value, path string) error {
+	if field == "type" {
+		*typ = value
+		return nil
+	}
+
+	key, ok := strings.CutPrefix(field, "params.")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid override %q: expected type or params.<key>", path)
+	}
+	if *params == nil {
+		*params = make(map[string]string)
+	}
+	(*params)[key] = value
+	return nil
+}
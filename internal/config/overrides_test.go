@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	cfg := engine.Config{
+		Provider:   engine.ProviderConfig{Type: "mock"},
+		Processors: []engine.ProcessorConfig{{Type: "filter"}},
+		Formatter:  engine.FormatterConfig{Type: "json"},
+		Output:     engine.OutputConfig{Type: "console"},
+	}
+
+	err := ApplyOverrides(&cfg, []string{
+		"provider.type=sql",
+		"provider.params.query=SELECT a = 1 FROM t",
+		"formatter.params.indent=4",
+		"output.type=file",
+		"output.params.path=/tmp/out.json",
+		"processors.0.params.min=10",
+		"processors.1.type=deduplicate",
+		"processors.1.params.fields.key=id",
+	})
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+
+	if cfg.Provider.Type != "sql" {
+		t.Errorf("Provider.Type = %q, want sql", cfg.Provider.Type)
+	}
+	if cfg.Provider.Params["query"] != "SELECT a = 1 FROM t" {
+		t.Errorf("query = %q (values may contain '=')", cfg.Provider.Params["query"])
+	}
+	if cfg.Formatter.Params["indent"] != "4" {
+		t.Errorf("indent = %q, want 4", cfg.Formatter.Params["indent"])
+	}
+	if cfg.Output.Type != "file" || cfg.Output.Params["path"] != "/tmp/out.json" {
+		t.Errorf("Output = %+v", cfg.Output)
+	}
+	if len(cfg.Processors) != 2 {
+		t.Fatalf("len(Processors) = %d, want 2", len(cfg.Processors))
+	}
+	if cfg.Processors[0].Params["min"] != "10" {
+		t.Errorf("processor 0 params = %v", cfg.Processors[0].Params)
+	}
+	if cfg.Processors[1].Type != "deduplicate" || cfg.Processors[1].Params["fields.key"] != "id" {
+		t.Errorf("processor 1 = %+v", cfg.Processors[1])
+	}
+}
+
+func TestApplyOverridesInvalid(t *testing.T) {
+	tests := []string{
+		"provider.type",
+		"=value",
+		"retry.max_retries=3",
+		"provider.name=x",
+		"provider.params.=x",
+		"provider.params=x",
+		"processors.x.type=filter",
+		"processors.-1.type=filter",
+		"processors.5.type=filter",
+	}
+
+	for _, override := range tests {
+		t.Run(override, func(t *testing.T) {
+			cfg := engine.Config{}
+			if err := ApplyOverrides(&cfg, []string{override}); err == nil {
+				t.Errorf("ApplyOverrides(%q) should fail", override)
+			}
+		})
+	}
+}
+
+func TestLoaderWithOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	// The file alone is invalid (no output type); the override fixes it
+	content := "provider:\n  type: mock\nformatter:\n  type: json\noutput:\n  params:\n    path: a.json\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader().WithOverrides("output.type=file", "output.params.path=b.json").LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Output.Type != "file" || cfg.Output.Params["path"] != "b.json" {
+		t.Errorf("Output = %+v", cfg.Output)
+	}
+
+	if _, err := NewLoader().WithOverrides("bogus").LoadFromFile(path); err == nil {
+		t.Error("LoadFromFile() should fail on an invalid override")
+	}
+}
+
+func TestLoaderOverridesAfterEnv(t *testing.T) {
+	t.Setenv("ENGINE_OUTPUT_TYPE", "console")
+
+	data := []byte(`{"provider": {"type": "mock"}, "formatter": {"type": "json"}, "output": {"type": "file"}}`)
+	cfg, err := NewLoader().WithEnvOverrides().WithOverrides("output.type=queue").LoadFromBytes(data, "json")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if cfg.Output.Type != "queue" {
+		t.Errorf("Output.Type = %q, want explicit override to win over env", cfg.Output.Type)
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrMultipleReports is returned when a single-report load meets a
+// document with a top-level "reports" section. Such documents are loaded
+// with LoadReportsFromFile.
+var ErrMultipleReports = errors.New("config defines multiple reports")
+
+// presets are the built-in configurations a profile can name.
+var presets = map[string]func() engine.Config{
+	"default":     DefaultConfig,
+	"production":  ProductionConfig,
+	"development": DevelopmentConfig,
+	"testing":     TestingConfig,
+	"csv":         CSVConfig,
+}
+
+// LoadReportsFromFile loads a multi-report configuration file and returns
+// the validated configuration of each report by name.
+//
+// A multi-report document has three top-level sections:
+//   - defaults: settings shared by every report
+//   - profiles: named partial configs, e.g. one per delivery channel
+//   - reports: the reports by name (required)
+//
+// A report, profile or the defaults may name a profile to build on with
+// "profile". A profile can in turn name another profile or one of the
+// built-in presets (default, production, development, testing, csv). A
+// report without its own profile uses the one named in defaults.
+//
+// Each report is assembled from, lowest priority first: the preset its
+// profile chain ends in, defaults, the profiles from the most general to
+// the most specific, and the report itself. Mappings such as params are
+// merged key by key; anything else, including the processors list, is
+// replaced. Environment and explicit overrides then apply to every report.
+//
+// Example:
+//
+//	defaults:
+//	  retry: {max_retries: 3, base_delay: 1s, max_delay: 30s, factor: 2}
+//	profiles:
+//	  archive:
+//	    profile: production
+//	    output: {type: file, params: {mode: "0640"}}
+//	reports:
+//	  daily-sales:
+//	    profile: archive
+//	    provider: {type: postgres, params: {query: "SELECT * FROM sales"}}
+//	    output: {params: {path: /data/daily-sales.json}}
+func (l *Loader) LoadReportsFromFile(path string) (map[string]*engine.Config, error) {
+	data, format, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.loadReports(data, format, path)
+}
+
+// LoadReportsFromBytes loads a multi-report configuration from raw bytes
+// ("yaml" or "json"). See LoadReportsFromFile for the document layout.
+func (l *Loader) LoadReportsFromBytes(data []byte, format string) (map[string]*engine.Config, error) {
+	format, err := normalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return l.loadReports(data, format, "")
+}
+
+// loadReports resolves a multi-report document and builds each report.
+func (l *Loader) loadReports(data []byte, format, file string) (map[string]*engine.Config, error) {
+	doc, err := l.resolveDocument(data, format, file)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("multi-report config must be a mapping with a reports section")
+	}
+
+	b := &reportBuilder{}
+	var reports *yaml.Node
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		switch key {
+		case "defaults":
+			b.defaults = value
+		case "profiles":
+			b.profiles = value
+		case "reports":
+			reports = value
+		default:
+			return nil, fmt.Errorf("unknown top-level key %q in multi-report config (use defaults, profiles or reports)", key)
+		}
+	}
+	for name, section := range map[string]*yaml.Node{"defaults": b.defaults, "profiles": b.profiles, "reports": reports} {
+		if section != nil && section.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s must be a mapping", name)
+		}
+	}
+	if reports == nil || len(reports.Content) == 0 {
+		return nil, fmt.Errorf("multi-report config defines no reports")
+	}
+
+	configs := make(map[string]*engine.Config, len(reports.Content)/2)
+	for i := 0; i+1 < len(reports.Content); i += 2 {
+		name, body := reports.Content[i].Value, reports.Content[i+1]
+		config, err := l.buildReport(b, body, format)
+		if err != nil {
+			return nil, fmt.Errorf("report %q: %w", name, err)
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// buildReport merges a report with its profiles and defaults, decodes it
+// and validates it.
+func (l *Loader) buildReport(b *reportBuilder, body *yaml.Node, format string) (*engine.Config, error) {
+	if body.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("report must be a mapping")
+	}
+
+	profile := profileOf(body)
+	if profile == "" && b.defaults != nil {
+		profile = profileOf(b.defaults)
+	}
+	preset, profiles, err := b.profileChain(profile, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, layer := range slices.Concat([]*yaml.Node{preset, b.defaults}, profiles, []*yaml.Node{body}) {
+		if layer != nil {
+			mergeMappings(merged, withoutProfile(layer))
+		}
+	}
+
+	if err := l.checkFields(merged); err != nil {
+		return nil, err
+	}
+
+	var config engine.Config
+	if err := merged.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s config: %w", strings.ToUpper(format), err)
+	}
+	config.Positions = fieldPositions(merged)
+	if err := l.finish(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// reportBuilder holds the shared sections of a multi-report document.
+type reportBuilder struct {
+	defaults *yaml.Node
+	profiles *yaml.Node
+}
+
+// profileChain returns the preset that the named profile builds on (or
+// nil) and its profiles from the most general to name itself.
+func (b *reportBuilder) profileChain(name string, seen []string) (*yaml.Node, []*yaml.Node, error) {
+	if name == "" {
+		return nil, nil, nil
+	}
+	if slices.Contains(seen, name) {
+		return nil, nil, fmt.Errorf("profile cycle: %s -> %s", strings.Join(seen, " -> "), name)
+	}
+
+	if b.profiles != nil {
+		if profile := mappingValue(b.profiles, name); profile != nil {
+			if profile.Kind != yaml.MappingNode {
+				return nil, nil, fmt.Errorf("profile %q must be a mapping", name)
+			}
+			preset, chain, err := b.profileChain(profileOf(profile), append(seen, name))
+			return preset, append(chain, profile), err
+		}
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown profile %q", name)
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(preset()); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode preset %q: %w", name, err)
+	}
+	return node, nil, nil
+}
+
+// profileOf returns the profile a mapping builds on, or "".
+func profileOf(node *yaml.Node) string {
+	if value := mappingValue(node, "profile"); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// withoutProfile returns a deep copy of a mapping without its "profile"
+// key, safe to merge into another mapping.
+func withoutProfile(node *yaml.Node) *yaml.Node {
+	out := &yaml.Node{Kind: node.Kind, Tag: node.Tag}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "profile" {
+			out.Content = append(out.Content, copyNode(node.Content[i]), copyNode(node.Content[i+1]))
+		}
+	}
+	return out
+}
+
+// copyNode returns a deep copy of node.
+func copyNode(node *yaml.Node) *yaml.Node {
+	out := *node
+	out.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		out.Content[i] = copyNode(child)
+	}
+	return &out
+}
+
+// LoadReportsFromFile is a convenience function that creates a loader and
+// loads a multi-report config file.
+func LoadReportsFromFile(path string) (map[string]*engine.Config, error) {
+	return NewLoader().LoadReportsFromFile(path)
+}
+
+// LoadReportsFromFileWithEnv is a convenience function that creates a
+// loader with env overrides and loads a multi-report config file.
+func LoadReportsFromFileWithEnv(path string) (map[string]*engine.Config, error) {
+	return NewLoader().WithEnvOverrides().LoadReportsFromFile(path)
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const multiReportYAML = `
+defaults:
+  retry:
+    max_retries: 3
+    base_delay: 1s
+    max_delay: 30s
+    factor: 2
+  formatter:
+    type: json
+profiles:
+  archive:
+    profile: production
+    output:
+      type: file
+      params:
+        mode: "0640"
+  audited:
+    profile: archive
+    processors:
+      - type: validator
+reports:
+  daily-sales:
+    profile: audited
+    provider:
+      type: mock
+      params:
+        query: SELECT * FROM sales
+    output:
+      params:
+        path: /data/daily-sales.json
+  adhoc:
+    provider:
+      type: mock
+    output:
+      type: console
+`
+
+// TestLoadReports tests defaults, profile chains and presets
+func TestLoadReports(t *testing.T) {
+	reports, err := NewLoader().LoadReportsFromBytes([]byte(multiReportYAML), "yaml")
+	if err != nil {
+		t.Fatalf("LoadReportsFromBytes() error = %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reports))
+	}
+
+	daily := reports["daily-sales"]
+	if daily.Output.Type != "file" || daily.Output.Params["path"] != "/data/daily-sales.json" || daily.Output.Params["mode"] != "0640" {
+		t.Errorf("daily output = %+v, want profile params merged with the report's path", daily.Output)
+	}
+	if len(daily.Processors) != 1 || daily.Processors[0].Type != "validator" || daily.Processors[0].Params["strict"] != "" {
+		t.Errorf("daily processors = %+v, want the profile's list replacing the preset's", daily.Processors)
+	}
+	if daily.Formatter.Type != "json" || daily.Formatter.Params["indent"] != "" {
+		t.Errorf("daily formatter = %+v, want the production preset", daily.Formatter)
+	}
+	if daily.Retry == nil || daily.Retry.MaxRetries != 3 || daily.Provider.Params["query"] != "SELECT * FROM sales" {
+		t.Errorf("daily retry %+v, provider %+v", daily.Retry, daily.Provider)
+	}
+
+	adhoc := reports["adhoc"]
+	if adhoc.Output.Type != "console" || adhoc.Retry == nil || len(adhoc.Processors) != 0 {
+		t.Errorf("adhoc = %+v, want defaults without a profile", adhoc)
+	}
+}
+
+// TestLoadReportsOverrides tests that overrides apply to every report
+func TestLoadReportsOverrides(t *testing.T) {
+	loader := NewLoader().WithOverrides("formatter.params.indent=4")
+	reports, err := loader.LoadReportsFromBytes([]byte(multiReportYAML), "yaml")
+	if err != nil {
+		t.Fatalf("LoadReportsFromBytes() error = %v", err)
+	}
+	for name, cfg := range reports {
+		if cfg.Formatter.Params["indent"] != "4" {
+			t.Errorf("report %q formatter params = %v", name, cfg.Formatter.Params)
+		}
+	}
+}
+
+// TestLoadReportsErrors tests invalid multi-report documents
+func TestLoadReportsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no reports", "defaults:\n  formatter:\n    type: json\n", "defines no reports"},
+		{"unknown section", "reports:\n  a: {}\nprovider:\n  type: mock\n", `unknown top-level key "provider"`},
+		{"unknown profile", "reports:\n  a:\n    profile: nope\n", `report "a": unknown profile "nope"`},
+		{"profile cycle", "profiles:\n  x: {profile: y}\n  y: {profile: x}\nreports:\n  a: {profile: x}\n", "profile cycle: x -> y -> x"},
+		{"invalid report", "reports:\n  a:\n    provider: {type: mock}\n", `report "a": invalid configuration`},
+		{"not a mapping", "- a\n", "must be a mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoader().LoadReportsFromBytes([]byte(tt.content), "yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadSingleReportFromMultiReportFile tests the single-report loader
+// refuses a multi-report document
+func TestLoadSingleReportFromMultiReportFile(t *testing.T) {
+	if _, err := LoadFromBytes([]byte(multiReportYAML), "yaml"); !errors.Is(err, ErrMultipleReports) {
+		t.Errorf("LoadFromBytes() error = %v, want ErrMultipleReports", err)
+	}
+}
+
+// TestLoadAndBuildReports tests building an engine per report
+func TestLoadAndBuildReports(t *testing.T) {
+	setupTestRegistries()
+	path := writeFile(t, t.TempDir(), "reports.yaml", `
+defaults:
+  formatter: {type: json}
+  output: {type: console}
+reports:
+  first: {provider: {type: mock}}
+  second: {provider: {type: mock}, profile: testing}
+`)
+
+	engines, err := LoadAndBuildReports(path)
+	if err != nil {
+		t.Fatalf("LoadAndBuildReports() error = %v", err)
+	}
+	if len(engines) != 2 || engines["first"] == nil || engines["second"] == nil {
+		t.Errorf("engines = %v", engines)
+	}
+
+	if _, err := BuildReportsFromBytes([]byte("reports:\n  bad: {provider: {type: unregistered}, formatter: {type: json}, output: {type: console}}\n"), "yaml"); err == nil ||
+		!strings.Contains(err.Error(), `failed to build report "bad"`) {
+		t.Errorf("BuildReportsFromBytes() error = %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth limits how deeply config files may include each other.
+const maxIncludeDepth = 16
+
+// documentResolver expands includes, variables and secret references in a
+// parsed config document before it is decoded into engine.Config.
+//
+// Supported syntax:
+//   - ${VAR}: environment variable VAR; an error if it is not set
+//   - ${VAR:-default}: VAR, or default if VAR is unset or empty
+//   - ${scheme:ref}: a secret from the resolver registered for scheme
+//   - $${: a literal "${"
+//   - !include file.yaml: replaces the node with the contents of the file
+//   - $ref: file.yaml#/pointer: like !include, with sibling keys merged over
+//     the referenced mapping
+//
+// Included paths are relative to the including file.
+type documentResolver struct {
+	secrets map[string]SecretResolver
+
+	// files are the files being resolved, outermost first
+	files []string
+
+	// onInclude, if set, is called with every included file read
+	onInclude func(file string)
+}
+
+// parseDocument parses YAML, JSON, TOML or HCL data into a node tree.
+// JSON is parsed as YAML once it is known to be valid, and TOML and HCL
+// are converted, so all formats share includes and interpolation.
+func parseDocument(data []byte, format string) (*yaml.Node, error) {
+	var doc yaml.Node
+	switch format {
+	case "json":
+		var raw interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+	case "toml":
+		return parseTOML(data)
+	case "hcl":
+		return parseHCL(data)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+	}
+	return &doc, nil
+}
+
+// formatOf returns the document format for a file extension.
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	case ".hcl":
+		return "hcl"
+	default:
+		return "yaml"
+	}
+}
+
+// resolve expands node in place. dir is the directory of the file node
+// came from and path its location in the config, for error messages.
+func (r *documentResolver) resolve(node *yaml.Node, dir, path string) error {
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := r.resolve(child, dir, path); err != nil {
+				return err
+			}
+		}
+
+	case node.Tag == "!include":
+		if node.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s: !include expects a file path", displayPath(path))
+		}
+		included, err := r.include(node.Value, dir, path)
+		if err != nil {
+			return err
+		}
+		*node = *included
+
+	case node.Kind == yaml.MappingNode:
+		if ref := mappingValue(node, "$ref"); ref != nil {
+			return r.resolveRef(node, ref, dir, path)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := r.resolve(node.Content[i+1], dir, joinPath(path, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+
+	case node.Kind == yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := r.resolve(child, dir, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case node.Kind == yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		value, err := r.interpolate(node.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		node.Value = value
+		// Let the new value determine the type, so "${RETRIES}" can fill
+		// an integer field; quoted scalars stay strings.
+		node.Tag = ""
+		if node.ShortTag() == "!!null" {
+			node.Tag = "!!str"
+		}
+	}
+	return nil
+}
+
+// resolveRef replaces a mapping containing "$ref" with the referenced
+// node, merging the mapping's other keys over it.
+func (r *documentResolver) resolveRef(node, ref *yaml.Node, dir, path string) error {
+	if ref.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s: $ref expects a file path", displayPath(path))
+	}
+	target, err := r.include(ref.Value, dir, path)
+	if err != nil {
+		return err
+	}
+
+	siblings := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "$ref" {
+			siblings.Content = append(siblings.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	if len(siblings.Content) == 0 {
+		*node = *target
+		return nil
+	}
+	if target.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: $ref %s must reference a mapping to be combined with other keys", displayPath(path), ref.Value)
+	}
+	if err := r.resolve(siblings, dir, path); err != nil {
+		return err
+	}
+	mergeMappings(target, siblings)
+	*node = *target
+	return nil
+}
+
+// include loads, resolves and returns the node referenced by "file" or
+// "file#/json/pointer".
+func (r *documentResolver) include(ref, dir, path string) (*yaml.Node, error) {
+	file, pointer, _ := strings.Cut(ref, "#")
+	if file == "" {
+		return nil, fmt.Errorf("%s: include %q has no file", displayPath(path), ref)
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+
+	if slices.Contains(r.files, file) {
+		return nil, fmt.Errorf("%s: include cycle: %s -> %s", displayPath(path), strings.Join(r.files, " -> "), file)
+	}
+	if len(r.files) >= maxIncludeDepth {
+		return nil, fmt.Errorf("%s: includes nested more than %d deep", displayPath(path), maxIncludeDepth)
+	}
+
+	if r.onInclude != nil {
+		r.onInclude(file)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read include: %w", displayPath(path), err)
+	}
+	doc, err := parseDocument(data, formatOf(file))
+	if err != nil {
+		return nil, fmt.Errorf("%s: include %s: %w", displayPath(path), file, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("%s: include %s is empty", displayPath(path), file)
+	}
+
+	r.files = append(r.files, file)
+	err = r.resolve(doc, filepath.Dir(file), path)
+	r.files = r.files[:len(r.files)-1]
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := lookupPointer(doc.Content[0], pointer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: include %s: %w", displayPath(path), ref, err)
+	}
+	return node, nil
+}
+
+// interpolate expands ${...} references in s.
+func (r *documentResolver) interpolate(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			b.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end := closingBrace(s, i+1)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated ${ in %q", s)
+			}
+			value, err := r.expand(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end + 1
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// expand resolves the contents of one ${...} reference.
+func (r *documentResolver) expand(expr string) (string, error) {
+	name, fallback, hasDefault := strings.Cut(expr, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty variable name in ${%s}", expr)
+	}
+
+	if scheme, ref, isSecret := strings.Cut(name, ":"); isSecret {
+		resolver, ok := r.secrets[scheme]
+		if !ok {
+			return "", fmt.Errorf("unknown secret resolver %q in ${%s}", scheme, name)
+		}
+		value, err := resolver.Resolve(ref)
+		if err == nil {
+			return value, nil
+		}
+		if !hasDefault || !errors.Is(err, ErrSecretNotFound) {
+			return "", fmt.Errorf("failed to resolve ${%s}: %w", name, err)
+		}
+		return r.interpolate(fallback)
+	}
+
+	value, ok := os.LookupEnv(name)
+	switch {
+	case hasDefault && value == "":
+		return r.interpolate(fallback)
+	case !ok:
+		return "", fmt.Errorf("variable %s is not set", name)
+	default:
+		return value, nil
+	}
+}
+
+// closingBrace returns the index of the brace closing the one at open,
+// allowing nested ${...} in defaults, or -1.
+func closingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mergeMappings merges over into base. Nested mappings are merged; any
+// other value in over replaces the one in base.
+func mergeMappings(base, over *yaml.Node) {
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		key, value := over.Content[i], over.Content[i+1]
+		existing := mappingValue(base, key.Value)
+		switch {
+		case existing == nil:
+			base.Content = append(base.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeMappings(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+// lookupPointer returns the node at a JSON pointer such as
+// "/providers/postgres".
+func lookupPointer(node *yaml.Node, pointer string) (*yaml.Node, error) {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return node, nil
+	}
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			next = mappingValue(node, token)
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("pointer /%s: %q not found", pointer, token)
+		}
+		node = next
+	}
+	return node, nil
+}
+
+// joinPath appends key to a dotted config path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath names the config root in error messages.
+func displayPath(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
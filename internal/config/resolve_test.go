@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestInterpolate tests ${...} expansion
+func TestInterpolate(t *testing.T) {
+	t.Setenv("REPORT_HOST", "db.internal")
+	t.Setenv("REPORT_EMPTY", "")
+
+	r := &documentResolver{secrets: NewLoader().secrets}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "no variables", "no variables"},
+		{"variable", "postgres://${REPORT_HOST}/sales", "postgres://db.internal/sales"},
+		{"default unused", "${REPORT_HOST:-localhost}", "db.internal"},
+		{"default for unset", "${REPORT_UNSET:-localhost}", "localhost"},
+		{"default for empty", "${REPORT_EMPTY:-localhost}", "localhost"},
+		{"empty without default", "[${REPORT_EMPTY}]", "[]"},
+		{"nested default", "${REPORT_UNSET:-${REPORT_HOST}}", "db.internal"},
+		{"env secret", "${env:REPORT_HOST}", "db.internal"},
+		{"secret default", "${env:REPORT_UNSET:-none}", "none"},
+		{"escaped", "$${REPORT_HOST}", "${REPORT_HOST}"},
+		{"sql placeholders", "WHERE id > $1 AND x = $$", "WHERE id > $1 AND x = $$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.interpolate(tt.input)
+			if err != nil || got != tt.want {
+				t.Errorf("interpolate(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"${REPORT_UNSET}", "${nope:x}", "${REPORT_HOST", "${}", "${env:REPORT_UNSET}"} {
+		if _, err := r.interpolate(input); err == nil {
+			t.Errorf("interpolate(%q) should fail", input)
+		}
+	}
+}
+
+// TestLoadWithVariables tests that variables fill string and typed fields
+func TestLoadWithVariables(t *testing.T) {
+	t.Setenv("REPORT_DSN", "postgres://reports@db/sales")
+	t.Setenv("REPORT_RETRIES", "5")
+
+	yamlConfig := `
+provider:
+  type: postgres
+  params:
+    dsn: ${REPORT_DSN}
+    quoted: "${REPORT_RETRIES}"
+formatter:
+  type: json
+output:
+  type: ${REPORT_OUTPUT:-console}
+retry:
+  max_retries: ${REPORT_RETRIES}
+`
+	cfg, err := LoadFromBytes([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if cfg.Provider.Params["dsn"] != "postgres://reports@db/sales" || cfg.Provider.Params["quoted"] != "5" {
+		t.Errorf("provider params = %v", cfg.Provider.Params)
+	}
+	if cfg.Output.Type != "console" || cfg.Retry.MaxRetries != 5 {
+		t.Errorf("output type %q, max retries %d", cfg.Output.Type, cfg.Retry.MaxRetries)
+	}
+
+	jsonConfig := `{"provider": {"type": "${REPORT_PROVIDER:-mock}"}, "formatter": {"type": "json"}, "output": {"type": "console"}}`
+	if cfg, err := LoadFromBytes([]byte(jsonConfig), "json"); err != nil || cfg.Provider.Type != "mock" {
+		t.Errorf("JSON LoadFromBytes() = %+v, %v", cfg, err)
+	}
+
+	_, err = LoadFromBytes([]byte("provider:\n  type: mock\n  params:\n    dsn: ${REPORT_MISSING}\n"), "yaml")
+	if err == nil || !strings.Contains(err.Error(), "provider.params.dsn: variable REPORT_MISSING is not set") {
+		t.Errorf("unset variable error = %v", err)
+	}
+}
+
+// TestLoadWithIncludes tests !include and $ref
+func TestLoadWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "shared/postgres.yaml", `
+type: postgres
+params:
+  dsn: postgres://db/sales
+  query: SELECT 1
+`)
+	writeFile(t, dir, "shared/outputs.json", `{"outputs": {"archive": {"type": "file", "params": {"path": "${REPORT_DIR:-/tmp}/out.json"}}}}`)
+	writeFile(t, dir, "shared/processors.yaml", "- type: sanitize\n- type: dedupe\n")
+
+	path := writeFile(t, dir, "reports/daily.yaml", `
+provider:
+  $ref: ../shared/postgres.yaml
+  params:
+    query: SELECT * FROM orders
+processors: !include ../shared/processors.yaml
+formatter:
+  type: json
+output:
+  $ref: ../shared/outputs.json#/outputs/archive
+`)
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Provider.Type != "postgres" || cfg.Provider.Params["dsn"] != "postgres://db/sales" ||
+		cfg.Provider.Params["query"] != "SELECT * FROM orders" {
+		t.Errorf("provider = %+v, want shared block with query overridden", cfg.Provider)
+	}
+	if len(cfg.Processors) != 2 || cfg.Processors[1].Type != "dedupe" {
+		t.Errorf("processors = %+v", cfg.Processors)
+	}
+	if cfg.Output.Type != "file" || cfg.Output.Params["path"] != "/tmp/out.json" {
+		t.Errorf("output = %+v", cfg.Output)
+	}
+}
+
+// TestLoadIncludeErrors tests include failures
+func TestLoadIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", "provider: !include b.yaml\n")
+	writeFile(t, dir, "b.yaml", "nested: !include a.yaml\n")
+	writeFile(t, dir, "list.yaml", "- one\n")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing file", "provider: !include nope.yaml\n", "provider: failed to read include"},
+		{"cycle", "provider: !include a.yaml\n", "include cycle"},
+		{"bad pointer", "provider:\n  $ref: list.yaml#/nope\n", `"nope" not found`},
+		{"merge into list", "provider:\n  $ref: list.yaml\n  type: mock\n", "must reference a mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, dir, "config.yaml", tt.content)
+			_, err := LoadFromFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFromFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
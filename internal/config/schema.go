@@ -0,0 +1,299 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/registry"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// schemaDraft is the JSON Schema dialect of the generated schema. Draft-07
+// is the newest draft supported by most editors.
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// interpolated matches values containing a ${...} reference, which are
+// only resolved at load time.
+const interpolated = `\$\{`
+
+// fieldDescriptions documents config fields in the generated schema, by
+// dotted path from the config root.
+var fieldDescriptions = map[string]string{
+	"provider":                          "Data source the report is fetched from",
+	"processors":                        "Processing chain applied to the fetched records, in order",
+	"formatter":                         "Format the processed records are rendered in",
+	"output":                            "Destination the formatted report is delivered to",
+	"retry":                             "Retry policy for the provider and output",
+	"retry.max_retries":                 "Maximum attempts after the first failure",
+	"retry.base_delay":                  "Delay before the first retry, e.g. 1s",
+	"retry.max_delay":                   "Upper bound for the backoff delay, e.g. 30s",
+	"retry.factor":                      "Backoff multiplier applied after each attempt",
+	"retry.jitter":                      "Randomize delays to spread retries",
+	"circuit_breaker":                   "Circuit breaker around the provider and output",
+	"circuit_breaker.failure_threshold": "Consecutive failures that open the circuit (without a window)",
+	"circuit_breaker.reset_timeout":     "Time before a half-open trial call, e.g. 1m",
+	"circuit_breaker.name":              "Breaker name in metrics, logs and health (default: provider type)",
+	"circuit_breaker.window":            "Sliding window for failure-rate mode: count or time",
+	"circuit_breaker.window_size":       "Number of calls in a count window",
+	"circuit_breaker.window_duration":   "Length of a time window, e.g. 1m",
+	"circuit_breaker.failure_rate":      "Fraction of failed calls in the window (0-1) that opens the circuit",
+	"circuit_breaker.min_calls":         "Calls the window must hold before the failure rate can open the circuit",
+	"circuit_breaker.half_open_calls":   "Trial calls let through after the reset timeout",
+	"rate_limit":                        "Token-bucket rate limits, shared by name across reports",
+	"rate_limit.provider":               "Rate limit for provider calls",
+	"rate_limit.output":                 "Rate limit for output calls",
+	"rate_limit.provider.name":          "Shared limiter name, e.g. partner-api",
+	"rate_limit.provider.rate":          "Calls per second",
+	"rate_limit.provider.burst":         "Calls allowed at once after idling",
+	"rate_limit.output.name":            "Shared limiter name, e.g. partner-api",
+	"rate_limit.output.rate":            "Calls per second",
+	"rate_limit.output.burst":           "Calls allowed at once after idling",
+	"bulkhead":                          "Concurrency limits, shared by name across reports",
+	"bulkhead.provider":                 "Concurrency limit for provider calls",
+	"bulkhead.output":                   "Concurrency limit for output calls",
+	"bulkhead.provider.name":            "Shared bulkhead name, e.g. warehouse-db",
+	"bulkhead.provider.max_concurrent":  "Calls running at once",
+	"bulkhead.provider.max_wait":        "Time a call queues for a slot before failing, e.g. 30s",
+	"bulkhead.output.name":              "Shared bulkhead name, e.g. warehouse-db",
+	"bulkhead.output.max_concurrent":    "Calls running at once",
+	"bulkhead.output.max_wait":          "Time a call queues for a slot before failing, e.g. 30s",
+	"watermark":                         "Incremental runs: only fetch records newer than the last run",
+	"watermark.store":                   "Path to the JSON state file",
+	"watermark.key":                     "State key for this report's watermark",
+	"checkpoint":                        "Resumable streaming runs: save progress after every chunk",
+	"checkpoint.store":                  "Path to the JSON state file",
+	"checkpoint.key":                    "State key for this report's checkpoint",
+	"timeouts":                          "Run and stage timeouts; a timed-out run fails with a retryable error",
+	"timeouts.run":                      "Time the whole run may take, e.g. 15m",
+	"timeouts.fetch":                    "Time the fetch stage may take, over all chunks of a streaming run, e.g. 10m",
+	"timeouts.process":                  "Time the process stage may take, over all chunks of a streaming run",
+	"timeouts.format":                   "Time the format stage may take, over all chunks of a streaming run",
+	"timeouts.output":                   "Time the output stage may take, over all chunks of a streaming run, e.g. 2m",
+	"timeouts.warn_at":                  "Fraction (0-1) of a timeout after which a soft-deadline warning is logged, e.g. 0.8",
+	"fallback":                          "Alternatives used when the provider or output fails or its circuit is open",
+	"fallback.providers":                "Providers to fetch from, in order, when the provider fails",
+	"fallback.outputs":                  "Outputs to send to, in order, when the output fails",
+	"spool":                             "Durable output: spool reports to disk and deliver them in the background",
+	"spool.dir":                         "Directory reports are spooled in until delivered",
+	"spool.max_retries":                 "Delivery retries before a report is left for replay (default: 5)",
+	"spool.base_delay":                  "Delay before the first delivery retry, e.g. 1s",
+	"spool.max_delay":                   "Upper bound for the delivery backoff delay, e.g. 1m",
+	"spool.factor":                      "Backoff multiplier applied after each delivery attempt (default: 2)",
+}
+
+// component describes a pluggable config section and its registry.
+type component struct {
+	names func() []string
+	get   func(name string) (interface{}, error)
+}
+
+// components maps the config section types to their registries.
+var components = map[reflect.Type]component{
+	reflect.TypeOf(engine.ProviderConfig{}): {
+		names: registry.ListProviders,
+		get:   func(name string) (interface{}, error) { return registry.GetProvider(name) },
+	},
+	reflect.TypeOf(engine.ProcessorConfig{}): {
+		names: registry.ListProcessors,
+		get:   func(name string) (interface{}, error) { return registry.GetProcessor(name) },
+	},
+	reflect.TypeOf(engine.FormatterConfig{}): {
+		names: registry.ListFormatters,
+		get:   func(name string) (interface{}, error) { return registry.GetFormatter(name) },
+	},
+	reflect.TypeOf(engine.OutputConfig{}): {
+		names: registry.ListOutputs,
+		get:   func(name string) (interface{}, error) { return registry.GetOutput(name) },
+	},
+}
+
+// schema is a JSON Schema object.
+type schema = map[string]interface{}
+
+// JSONSchema returns a JSON Schema for config files, for editor validation
+// and autocompletion. It is generated from engine.Config and the
+// components registered when it is called: the "type" of each provider,
+// processor, formatter and output is limited to the registered names, and
+// the params of components that declare them (see api.ParamDescriber) are
+// described with their types, defaults and allowed values.
+//
+// Both layouts are covered: a single report, and a multi-report document
+// with defaults, profiles and reports (see LoadReportsFromFile). Values
+// containing ${...} references are accepted for typed fields, since they
+// are only resolved at load time.
+//
+// Example:
+//
+//	data, err := config.JSONSchema()
+//	// # yaml-language-server: $schema=report-engine.schema.json
+func JSONSchema() ([]byte, error) {
+	report := structSchema(reflect.TypeOf(engine.Config{}), "")
+	report["properties"].(schema)["profile"] = schema{
+		"type":        "string",
+		"description": "Profile or built-in preset (default, production, development, testing, csv) to build on",
+	}
+
+	root := schema{
+		"$schema":     schemaDraft,
+		"title":       "report-engine configuration",
+		"definitions": schema{"report": report},
+		"if":          schema{"required": []string{"reports"}},
+		"then": schema{
+			"type": "object",
+			"properties": schema{
+				"defaults": schema{"$ref": "#/definitions/report", "description": "Settings shared by every report"},
+				"profiles": schema{
+					"type":                 "object",
+					"description":          "Named partial configs that reports build on",
+					"additionalProperties": schema{"$ref": "#/definitions/report"},
+				},
+				"reports": schema{
+					"type":                 "object",
+					"description":          "Reports by name",
+					"additionalProperties": schema{"$ref": "#/definitions/report"},
+				},
+			},
+			"additionalProperties": false,
+		},
+		"else": structSchema(reflect.TypeOf(engine.Config{}), ""),
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// typeSchema returns the schema for values of type t at path.
+func typeSchema(t reflect.Type, path string) schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var s schema
+	switch t.Kind() {
+	case reflect.Struct:
+		s = structSchema(t, path)
+	case reflect.Slice:
+		s = schema{"type": "array", "items": typeSchema(t.Elem(), path+"[]")}
+	case reflect.Map:
+		s = schema{"type": "object", "additionalProperties": schema{"type": []string{"string", "number", "boolean"}}}
+	case reflect.Bool:
+		s = typedOrInterpolated("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = typedOrInterpolated("integer")
+	case reflect.Float32, reflect.Float64:
+		s = typedOrInterpolated("number")
+	default:
+		s = schema{"type": "string"}
+	}
+	if description, ok := fieldDescriptions[path]; ok {
+		s["description"] = description
+	}
+	return s
+}
+
+// typedOrInterpolated accepts a JSON type or a string with a ${...} reference.
+func typedOrInterpolated(jsonType string) schema {
+	return schema{"type": []string{jsonType, "string"}, "pattern": interpolated}
+}
+
+// structSchema returns the schema for struct type t at path. Component
+// sections get their type and params from the registry.
+func structSchema(t reflect.Type, path string) schema {
+	properties := schema{
+		"$ref": schema{"type": "string", "description": "Merge the mapping at file or file#/pointer under this one"},
+	}
+	for name, field := range yamlFields(t) {
+		properties[name] = typeSchema(field.Type, joinPath(path, name))
+	}
+	s := schema{"type": "object", "properties": properties, "additionalProperties": false}
+
+	if c, ok := components[t]; ok {
+		addComponent(s, c)
+	}
+	return s
+}
+
+// addComponent limits a component section's type to the registered names
+// and adds the declared params of each.
+func addComponent(s schema, c component) {
+	names := c.names()
+	properties := s["properties"].(schema)
+	properties["type"] = schema{
+		"type":        "string",
+		"description": "Registered component name",
+		"anyOf":       []schema{{"enum": names}, {"pattern": interpolated}},
+	}
+
+	var cases []schema
+	for _, name := range names {
+		instance, err := c.get(name)
+		if err != nil {
+			continue
+		}
+		describer, ok := instance.(api.ParamDescriber)
+		if !ok || describer.ParamSpecs() == nil {
+			continue
+		}
+		cases = append(cases, schema{
+			"if":   schema{"properties": schema{"type": schema{"const": name}}, "required": []string{"type"}},
+			"then": schema{"properties": schema{"params": paramsSchema(describer.ParamSpecs())}},
+		})
+	}
+	if len(cases) > 0 {
+		s["allOf"] = cases
+	}
+}
+
+// paramsSchema returns the schema for a params mapping declared by specs.
+func paramsSchema(specs []api.ParamSpec) schema {
+	properties := schema{}
+	patterns := schema{}
+	for _, spec := range specs {
+		if prefix, ok := strings.CutSuffix(spec.Name, "*"); ok {
+			patterns["^"+prefix+".+"] = paramSchema(spec)
+		} else {
+			properties[spec.Name] = paramSchema(spec)
+		}
+	}
+
+	s := schema{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(patterns) > 0 {
+		s["patternProperties"] = patterns
+	}
+	return s
+}
+
+// paramSchema returns the schema for a single param. Param values are
+// strings, but YAML and JSON scalars such as true or 5 are accepted too.
+func paramSchema(spec api.ParamSpec) schema {
+	var s schema
+	switch {
+	case len(spec.Enum) > 0:
+		s = schema{"type": "string", "anyOf": []schema{{"enum": spec.Enum}, {"pattern": interpolated}}}
+	case spec.Type == api.ParamInt:
+		s = schema{"type": []string{"integer", "string"}, "pattern": `^-?[0-9]+$|` + interpolated}
+	case spec.Type == api.ParamFloat:
+		s = schema{"type": []string{"number", "string"}, "pattern": `^-?[0-9.]+([eE][-+]?[0-9]+)?$|` + interpolated}
+	case spec.Type == api.ParamBool:
+		s = schema{"type": []string{"boolean", "string"}, "pattern": `^(true|false|TRUE|FALSE|True|False|1|0|t|f|T|F)$|` + interpolated}
+	case spec.Type == api.ParamDuration:
+		s = schema{"type": "string", "pattern": `^([0-9.]+(ns|us|µs|ms|s|m|h))+$|` + interpolated}
+	default:
+		s = schema{"type": []string{"string", "number", "boolean"}}
+	}
+
+	description := spec.Description
+	if spec.Required {
+		description += " (required)"
+	}
+	if spec.Deprecated != "" {
+		description += " (deprecated: " + spec.Deprecated + ")"
+		s["deprecated"] = true
+	}
+	s["description"] = description
+	if spec.Default != "" {
+		s["default"] = spec.Default
+	}
+	return s
+}
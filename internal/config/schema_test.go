@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/registry"
+)
+
+// TestJSONSchema tests the generated schema layout and component params
+func TestJSONSchema(t *testing.T) {
+	setupTestRegistries()
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+	defer registry.UnregisterOutput("file")
+
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if root["$schema"] != schemaDraft {
+		t.Errorf("$schema = %v", root["$schema"])
+	}
+
+	single := root["else"].(map[string]interface{})
+	if single["additionalProperties"] != false {
+		t.Error("config should not allow unknown fields")
+	}
+	properties := single["properties"].(map[string]interface{})
+	for _, key := range []string{"provider", "processors", "formatter", "output", "retry", "watermark", "checkpoint"} {
+		if properties[key] == nil {
+			t.Errorf("schema is missing %q", key)
+		}
+	}
+
+	outputSchema := properties["output"].(map[string]interface{})
+	outputType := outputSchema["properties"].(map[string]interface{})["type"].(map[string]interface{})
+	names := outputType["anyOf"].([]interface{})[0].(map[string]interface{})["enum"].([]interface{})
+	if len(names) != 2 || names[0] != "console" || names[1] != "file" {
+		t.Errorf("output type enum = %v, want the registered outputs", names)
+	}
+
+	cases := outputSchema["allOf"].([]interface{})
+	if len(cases) != 1 {
+		t.Fatalf("got %d output param cases, want 1 for file", len(cases))
+	}
+	fileCase := cases[0].(map[string]interface{})
+	if c := fileCase["if"].(map[string]interface{})["properties"].(map[string]interface{})["type"].(map[string]interface{})["const"]; c != "file" {
+		t.Errorf("case const = %v", c)
+	}
+	params := fileCase["then"].(map[string]interface{})["properties"].(map[string]interface{})["params"].(map[string]interface{})
+	mode := params["properties"].(map[string]interface{})["mode"].(map[string]interface{})
+	if mode["default"] != "0644" || mode["description"] == "" {
+		t.Errorf("file mode param schema = %v", mode)
+	}
+	if params["additionalProperties"] != false {
+		t.Error("declared params should not allow unknown keys")
+	}
+
+	report := root["definitions"].(map[string]interface{})["report"].(map[string]interface{})
+	if report["properties"].(map[string]interface{})["profile"] == nil {
+		t.Error("report definition should allow profile")
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSecretNotFound is returned by a SecretResolver when a secret does not
+// exist. A reference with a default (${vault:db#password:-dev}) falls back
+// to the default only on this error.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretResolver resolves "${scheme:ref}" references in config values.
+// Resolvers are registered on a Loader under their scheme.
+//
+// Example:
+//
+//	loader := config.NewLoader().
+//	    WithSecretResolver("vault", config.NewLocalVault("/etc/reports/vault.yaml"))
+type SecretResolver interface {
+	// Resolve returns the secret named by ref.
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface.
+type SecretResolverFunc func(ref string) (string, error)
+
+// Resolve calls f(ref).
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// EnvSecrets resolves "${env:NAME}" to the environment variable NAME.
+var EnvSecrets SecretResolver = SecretResolverFunc(func(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s: %w", name, ErrSecretNotFound)
+	}
+	return value, nil
+})
+
+// FileSecrets resolves "${file:path}" to the contents of a file, without
+// its trailing newline. This suits Docker and Kubernetes secret mounts.
+// Relative paths are resolved against the working directory.
+var FileSecrets SecretResolver = SecretResolverFunc(func(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("file %s: %w", path, ErrSecretNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+})
+
+// LocalVault is a file-backed stand-in for a secrets vault, for local
+// development and tests. The file (YAML or JSON) maps secret paths to
+// key/value pairs:
+//
+//	db/reporting:
+//	  username: reports
+//	  password: s3cret
+//
+// References have the form "path#key", e.g. "${vault:db/reporting#password}".
+// A secret path that holds a single string may be referenced without a key.
+type LocalVault struct {
+	// Path is the vault file.
+	Path string
+
+	once    sync.Once
+	secrets map[string]interface{}
+	err     error
+}
+
+// NewLocalVault creates a LocalVault reading secrets from path. The file
+// is read on first use.
+func NewLocalVault(path string) *LocalVault {
+	return &LocalVault{Path: path}
+}
+
+// Resolve returns the secret for a "path#key" reference.
+func (v *LocalVault) Resolve(ref string) (string, error) {
+	v.once.Do(v.load)
+	if v.err != nil {
+		return "", v.err
+	}
+
+	path, key, hasKey := strings.Cut(ref, "#")
+	entry, ok := v.secrets[path]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s: %w", path, ErrSecretNotFound)
+	}
+
+	switch secret := entry.(type) {
+	case map[string]interface{}:
+		if !hasKey {
+			return "", fmt.Errorf("vault secret %s has several keys, use %s#<key>", path, path)
+		}
+		value, ok := secret[key]
+		if !ok {
+			return "", fmt.Errorf("vault secret %s#%s: %w", path, key, ErrSecretNotFound)
+		}
+		return fmt.Sprint(value), nil
+	default:
+		if hasKey {
+			return "", fmt.Errorf("vault secret %s#%s: %w", path, key, ErrSecretNotFound)
+		}
+		return fmt.Sprint(secret), nil
+	}
+}
+
+// load reads the vault file.
+func (v *LocalVault) load() {
+	data, err := os.ReadFile(v.Path)
+	if err != nil {
+		v.err = fmt.Errorf("failed to read vault file: %w", err)
+		return
+	}
+	if strings.ToLower(filepath.Ext(v.Path)) == ".json" {
+		err = json.Unmarshal(data, &v.secrets)
+	} else {
+		err = yaml.Unmarshal(data, &v.secrets)
+	}
+	if err != nil {
+		v.err = fmt.Errorf("failed to parse vault file %s: %w", v.Path, err)
+	}
+}
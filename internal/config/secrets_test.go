@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEnvSecrets tests the env resolver
+func TestEnvSecrets(t *testing.T) {
+	t.Setenv("REPORT_SECRET", "s3cret")
+
+	if got, err := EnvSecrets.Resolve("REPORT_SECRET"); err != nil || got != "s3cret" {
+		t.Errorf("Resolve() = %q, %v", got, err)
+	}
+	if _, err := EnvSecrets.Resolve("REPORT_NO_SECRET"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Resolve() of unset variable = %v, want ErrSecretNotFound", err)
+	}
+}
+
+// TestFileSecrets tests the file resolver
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "db_password", "s3cret\n")
+
+	if got, err := FileSecrets.Resolve(path); err != nil || got != "s3cret" {
+		t.Errorf("Resolve() = %q, %v; want the file without its newline", got, err)
+	}
+	if _, err := FileSecrets.Resolve(filepath.Join(dir, "missing")); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Resolve() of missing file = %v, want ErrSecretNotFound", err)
+	}
+}
+
+// TestLocalVault tests the local vault stand-in
+func TestLocalVault(t *testing.T) {
+	dir := t.TempDir()
+	vault := NewLocalVault(writeFile(t, dir, "vault.yaml", `
+db/reporting:
+  username: reports
+  password: s3cret
+api/token: abc123
+`))
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"db/reporting#password", "s3cret"},
+		{"db/reporting#username", "reports"},
+		{"api/token", "abc123"},
+	}
+	for _, tt := range tests {
+		if got, err := vault.Resolve(tt.ref); err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"db/other#password", "db/reporting#port", "api/token#key"} {
+		if _, err := vault.Resolve(ref); !errors.Is(err, ErrSecretNotFound) {
+			t.Errorf("Resolve(%q) = %v, want ErrSecretNotFound", ref, err)
+		}
+	}
+	if _, err := vault.Resolve("db/reporting"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Resolve() without a key = %v, want an ambiguity error", err)
+	}
+
+	missing := NewLocalVault(filepath.Join(dir, "none.json"))
+	if _, err := missing.Resolve("x"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Resolve() with missing vault file = %v", err)
+	}
+}
+
+// TestLoaderSecretResolver tests custom resolvers and that secret values
+// never appear in errors
+func TestLoaderSecretResolver(t *testing.T) {
+	resolver := SecretResolverFunc(func(ref string) (string, error) {
+		if ref == "db" {
+			return "hunter2", nil
+		}
+		return "", ErrSecretNotFound
+	})
+	loader := NewLoader().WithSecretResolver("test", resolver)
+
+	content := "provider:\n  type: mock\n  params:\n    dsn: \"postgres://u:${test:db}@db/x\"\nformatter:\n  type: json\noutput:\n  type: console\n"
+	cfg, err := loader.LoadFromBytes([]byte(content), "yaml")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if cfg.Provider.Params["dsn"] != "postgres://u:hunter2@db/x" {
+		t.Errorf("dsn = %q", cfg.Provider.Params["dsn"])
+	}
+
+	_, err = loader.LoadFromBytes([]byte(strings.Replace(content, "${test:db}", "${test:other}", 1)), "yaml")
+	if err == nil || !errors.Is(err, ErrSecretNotFound) || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("missing secret error = %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// configType is the type unknown fields are checked against.
+var configType = reflect.TypeOf(engine.Config{})
+
+// checkFields reports mapping keys in node that have no matching field in
+// engine.Config, such as a misspelled "parms:". Params maps are free-form
+// here; their keys are checked against each component's declared
+// parameters when the engine is built.
+func checkFields(node *yaml.Node) error {
+	var problems []string
+	collectUnknownFields(node, configType, "", &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("unknown config fields: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// fieldPositions returns where every mapping key and sequence item in node
+// is set, by dotted path, so validation and build errors can name the line
+// and column of the field at fault.
+func fieldPositions(node *yaml.Node) engine.Positions {
+	positions := engine.Positions{}
+	collectPositions(node, "", positions)
+	return positions
+}
+
+// collectPositions adds the positions of node's keys and items below path.
+func collectPositions(node *yaml.Node, path string, positions engine.Positions) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collectPositions(child, path, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := joinPath(path, key.Value)
+			positions[keyPath] = engine.Position{Line: key.Line, Column: key.Column}
+			collectPositions(node.Content[i+1], keyPath, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			positions[itemPath] = engine.Position{Line: item.Line, Column: item.Column}
+			collectPositions(item, itemPath, positions)
+		}
+	}
+}
+
+// collectUnknownFields walks node alongside t and appends a problem for
+// every mapping key that t does not declare.
+func collectUnknownFields(node *yaml.Node, t reflect.Type, path string, problems *[]string) {
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			collectUnknownFields(child, t, path, problems)
+		}
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				collectUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, ok := fields[key.Value]
+			if !ok {
+				*problems = append(*problems, unknownField(key, joinPath(path, key.Value), fields))
+				continue
+			}
+			collectUnknownFields(node.Content[i+1], field.Type, joinPath(path, key.Value), problems)
+		}
+	}
+}
+
+// unknownField describes an unknown key with its position and, when the key
+// looks like a typo, the field it was probably meant to be.
+func unknownField(key *yaml.Node, path string, fields map[string]reflect.StructField) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	problem := fmt.Sprintf("%s (line %d, column %d)", path, key.Line, key.Column)
+	if suggestion := api.ClosestName(key.Value, names); suggestion != "" {
+		problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return problem
+}
+
+// yamlFields returns the fields of struct type t by their yaml key.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadUnknownFields tests that misspelled config keys are rejected
+func TestLoadUnknownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"misspelled params",
+			"provider:\n  type: mock\n  parms:\n    query: x\nformatter: {type: json}\noutput: {type: console}\n",
+			`provider.parms (line 3, column 3) (did you mean "params"?)`,
+		},
+		{
+			"unknown section",
+			"provider: {type: mock}\nformatter: {type: json}\noutput: {type: console}\nnotify: {}\n",
+			"notify (line 4, column 1)",
+		},
+		{
+			"in processor list",
+			"provider: {type: mock}\nprocessors:\n  - tpye: dedupe\nformatter: {type: json}\noutput: {type: console}\n",
+			`processors[0].tpye (line 3, column 5) (did you mean "type"?)`,
+		},
+		{
+			"in retry",
+			"provider: {type: mock}\nformatter: {type: json}\noutput: {type: console}\nretry:\n  max_retry: 3\n",
+			`retry.max_retry (line 5, column 3) (did you mean "max_retries"?)`,
+		},
+		{
+			"in rate limit rule",
+			"provider: {type: mock}\nformatter: {type: json}\noutput: {type: console}\nrate_limit:\n  provider: {name: api, rate: 5, brust: 10}\n",
+			`rate_limit.provider.brust (line 5, column 34) (did you mean "burst"?)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromBytes([]byte(tt.content), "yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFromBytes() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// JSON goes through the same check
+	_, err := LoadFromBytes([]byte(`{"provider": {"type": "mock", "parms": {}}, "formatter": {"type": "json"}, "output": {"type": "console"}}`), "json")
+	if err == nil || !strings.Contains(err.Error(), "provider.parms") {
+		t.Errorf("JSON LoadFromBytes() error = %v", err)
+	}
+}
+
+// TestLoadAllowUnknownFields tests the strict check opt-out
+func TestLoadAllowUnknownFields(t *testing.T) {
+	content := "provider:\n  type: mock\n  parms: {query: x}\nformatter: {type: json}\noutput: {type: console}\n"
+	cfg, err := NewLoader().AllowUnknownFields().LoadFromBytes([]byte(content), "yaml")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if len(cfg.Provider.Params) != 0 {
+		t.Errorf("provider params = %v, want the unknown key ignored", cfg.Provider.Params)
+	}
+}
+
+// TestLoadReportsUnknownFields tests the check on merged multi-report configs
+func TestLoadReportsUnknownFields(t *testing.T) {
+	content := `
+profiles:
+  archive:
+    output: {type: file, parms: {mode: "0640"}}
+reports:
+  daily:
+    profile: archive
+    provider: {type: mock}
+    formatter: {type: json}
+`
+	_, err := NewLoader().LoadReportsFromBytes([]byte(content), "yaml")
+	if err == nil || !strings.Contains(err.Error(), `report "daily": unknown config fields: output.parms (line 4, column 26)`) {
+		t.Errorf("LoadReportsFromBytes() error = %v", err)
+	}
+}
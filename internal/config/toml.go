@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// parseTOML parses TOML data into a node tree that records the line and
+// column of every key and value, so TOML configs share includes,
+// interpolation and strict field checks with YAML.
+//
+// Tables and dotted keys become mappings and arrays of tables become
+// sequences, so [[processors]] declares one processor:
+//
+//	[provider]
+//	type = "sql"
+//	params = { query = "SELECT * FROM sales" }
+//
+//	[[processors]]
+//	type = "deduplicate"
+//
+// Dates and times are kept as strings.
+func parseTOML(data []byte) (*yaml.Node, error) {
+	// Decoding first reports every syntax error and redefined key with its
+	// position, so the tree below can be built from a valid document
+	var check map[string]interface{}
+	if err := toml.Unmarshal(data, &check); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, column := decodeErr.Position()
+			return nil, fmt.Errorf("failed to parse TOML config: line %d, column %d: %s",
+				line, column, strings.TrimPrefix(decodeErr.Error(), "toml: "))
+		}
+		return nil, fmt.Errorf("failed to parse TOML config: %w", err)
+	}
+
+	b := &tomlBuilder{}
+	b.parser.Reset(data)
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}
+	current := root
+	for b.parser.NextExpression() {
+		expr := b.parser.Expression()
+		switch expr.Kind {
+		case unstable.KeyValue:
+			b.keyValue(current, expr)
+		case unstable.Table:
+			current = b.table(root, expr)
+		case unstable.ArrayTable:
+			current = b.arrayTable(root, expr)
+		}
+	}
+	if err := b.parser.Error(); err != nil {
+		return nil, fmt.Errorf("failed to parse TOML config: %w", err)
+	}
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1}
+	if len(root.Content) > 0 {
+		doc.Content = []*yaml.Node{root}
+	}
+	return doc, nil
+}
+
+// tomlBuilder converts TOML expressions into yaml nodes.
+type tomlBuilder struct {
+	parser unstable.Parser
+}
+
+// table returns the mapping a [table] header opens, creating it and its
+// parents as needed.
+func (b *tomlBuilder) table(root *yaml.Node, expr *unstable.Node) *yaml.Node {
+	m := root
+	for _, key := range tomlKey(expr) {
+		m = b.child(m, key)
+	}
+	return m
+}
+
+// arrayTable appends a new mapping to the sequence a [[table]] header
+// names and returns it.
+func (b *tomlBuilder) arrayTable(root *yaml.Node, expr *unstable.Node) *yaml.Node {
+	keys := tomlKey(expr)
+	m := root
+	for _, key := range keys[:len(keys)-1] {
+		m = b.child(m, key)
+	}
+
+	last := keys[len(keys)-1]
+	seq := mappingValue(m, string(last.Data))
+	if seq == nil {
+		seq = b.node(yaml.SequenceNode, "!!seq", last)
+		m.Content = append(m.Content, b.key(last), seq)
+	}
+	item := b.node(yaml.MappingNode, "!!map", last)
+	seq.Content = append(seq.Content, item)
+	return item
+}
+
+// keyValue adds a possibly dotted key and its value to m.
+func (b *tomlBuilder) keyValue(m *yaml.Node, expr *unstable.Node) {
+	keys := tomlKey(expr)
+	for _, key := range keys[:len(keys)-1] {
+		m = b.child(m, key)
+	}
+	m.Content = append(m.Content, b.key(keys[len(keys)-1]), b.value(expr.Value()))
+}
+
+// child returns the mapping under key in m, creating it if needed. For an
+// array of tables it returns the last table.
+func (b *tomlBuilder) child(m *yaml.Node, key *unstable.Node) *yaml.Node {
+	existing := mappingValue(m, string(key.Data))
+	switch {
+	case existing == nil:
+		existing = b.node(yaml.MappingNode, "!!map", key)
+		m.Content = append(m.Content, b.key(key), existing)
+	case existing.Kind == yaml.SequenceNode && len(existing.Content) > 0:
+		existing = existing.Content[len(existing.Content)-1]
+	}
+	return existing
+}
+
+// value converts a TOML value.
+func (b *tomlBuilder) value(n *unstable.Node) *yaml.Node {
+	switch n.Kind {
+	case unstable.Array:
+		seq := b.node(yaml.SequenceNode, "!!seq", n)
+		it := n.Children()
+		for it.Next() {
+			seq.Content = append(seq.Content, b.value(it.Node()))
+		}
+		if seq.Line == 0 && len(seq.Content) > 0 {
+			seq.Line, seq.Column = seq.Content[0].Line, seq.Content[0].Column
+		}
+		return seq
+
+	case unstable.InlineTable:
+		m := b.node(yaml.MappingNode, "!!map", n)
+		it := n.Children()
+		for it.Next() {
+			b.keyValue(m, it.Node())
+		}
+		return m
+
+	case unstable.Bool:
+		return b.scalar("!!bool", string(n.Data), n)
+
+	case unstable.Integer:
+		text := strings.ReplaceAll(string(n.Data), "_", "")
+		if v, err := strconv.ParseInt(text, 0, 64); err == nil {
+			text = strconv.FormatInt(v, 10)
+		}
+		return b.scalar("!!int", text, n)
+
+	case unstable.Float:
+		text := strings.ReplaceAll(string(n.Data), "_", "")
+		switch strings.TrimPrefix(text, "+") {
+		case "inf":
+			text = ".inf"
+		case "-inf":
+			text = "-.inf"
+		case "nan", "-nan":
+			text = ".nan"
+		}
+		return b.scalar("!!float", text, n)
+
+	default:
+		// Strings, dates and times
+		s := b.scalar("!!str", string(n.Data), n)
+		s.Style = yaml.DoubleQuotedStyle
+		return s
+	}
+}
+
+// key converts a key part into a mapping key.
+func (b *tomlBuilder) key(n *unstable.Node) *yaml.Node {
+	return b.scalar("!!str", string(n.Data), n)
+}
+
+// scalar creates a scalar node at the position of n.
+func (b *tomlBuilder) scalar(tag, value string, n *unstable.Node) *yaml.Node {
+	s := b.node(yaml.ScalarNode, tag, n)
+	s.Value = value
+	return s
+}
+
+// node creates a node at the position of n.
+func (b *tomlBuilder) node(kind yaml.Kind, tag string, n *unstable.Node) *yaml.Node {
+	node := &yaml.Node{Kind: kind, Tag: tag}
+	if n.Raw.Length > 0 {
+		start := b.parser.Shape(n.Raw).Start
+		node.Line, node.Column = start.Line, start.Column
+	}
+	return node
+}
+
+// tomlKey returns the parts of the key of a key-value or table header.
+func tomlKey(expr *unstable.Node) []*unstable.Node {
+	var keys []*unstable.Node
+	it := expr.Key()
+	for it.Next() {
+		keys = append(keys, it.Node())
+	}
+	return keys
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const tomlConfig = `
+[provider]
+type = "mock"
+params = { query = "SELECT * FROM sales", batch_size = 500 }
+
+[[processors]]
+type = "deduplicate"
+
+[[processors]]
+type = "aggregate"
+params.group_by = "region"
+
+[formatter]
+type = "json"
+
+[output]
+type = "file"
+params = { path = "${REPORT_DIR:-/tmp}/sales.json" }
+
+[retry]
+max_retries = 3
+base_delay = "1s"
+max_delay = "30s"
+factor = 2.0
+jitter = true
+`
+
+// TestLoadFromFileTOML tests tables, arrays of tables and value types
+func TestLoadFromFileTOML(t *testing.T) {
+	t.Setenv("REPORT_DIR", "/data")
+	path := writeFile(t, t.TempDir(), "config.toml", tomlConfig)
+
+	config, err := NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if config.Provider.Params["query"] != "SELECT * FROM sales" || config.Provider.Params["batch_size"] != "500" {
+		t.Errorf("provider params = %v", config.Provider.Params)
+	}
+	if len(config.Processors) != 2 || config.Processors[1].Type != "aggregate" || config.Processors[1].Params["group_by"] != "region" {
+		t.Errorf("processors = %+v", config.Processors)
+	}
+	if config.Output.Params["path"] != "/data/sales.json" {
+		t.Errorf("output path = %q, want the interpolated path", config.Output.Params["path"])
+	}
+	if r := config.Retry; r == nil || r.MaxRetries != 3 || r.Factor != 2 || !r.Jitter || r.BaseDelay != "1s" {
+		t.Errorf("retry = %+v", r)
+	}
+}
+
+// TestLoadFromBytesTOMLEnvOverrides tests that env overrides apply to TOML
+func TestLoadFromBytesTOMLEnvOverrides(t *testing.T) {
+	t.Setenv("ENGINE_OUTPUT_TYPE", "console")
+	t.Setenv("ENGINE_PROVIDER_PARAM_QUERY", "SELECT 1")
+
+	config, err := NewLoader().WithEnvOverrides().LoadFromBytes([]byte(tomlConfig), "toml")
+	if err != nil {
+		t.Fatalf("LoadFromBytes() error = %v", err)
+	}
+	if config.Output.Type != "console" || config.Provider.Params["query"] != "SELECT 1" {
+		t.Errorf("output type = %q, query = %q, want env overrides", config.Output.Type, config.Provider.Params["query"])
+	}
+}
+
+// TestLoadTOMLErrors tests that errors carry their line and column
+func TestLoadTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"syntax", "[provider\ntype = \"mock\"\n", "failed to parse TOML config: line 1, column 10"},
+		{"redefined key", "[provider]\ntype = \"mock\"\ntype = \"sql\"\n", "line 3, column 1"},
+		{
+			"unknown field",
+			"[provider]\ntype = \"mock\"\n  parms = { query = \"x\" }\n[formatter]\ntype = \"json\"\n[output]\ntype = \"console\"\n",
+			`provider.parms (line 3, column 3) (did you mean "params"?)`,
+		},
+		{
+			"wrong type",
+			"[provider]\ntype = \"mock\"\n[formatter]\ntype = \"json\"\n[output]\ntype = \"console\"\n[retry]\nmax_retries = \"three\"\n",
+			"line 8",
+		},
+		{
+			"invalid field",
+			"[provider]\ntype = \"mock\"\n[formatter]\ntype = \"json\"\n[output]\ntype = \"console\"\n[spool]\nmax_retries = -1\n",
+			"spool.dir is required (line 7, column 2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromBytes([]byte(tt.content), "toml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFromBytes() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadReportsTOML tests multi-report TOML files and TOML includes
+func TestLoadReportsTOML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "shared.toml", "[archive]\ntype = \"file\"\nparams = { mode = \"0640\" }\n")
+	path := writeFile(t, dir, "reports.toml", `
+[defaults.formatter]
+type = "json"
+
+[defaults.output]
+type = "console"
+
+[reports.daily.provider]
+type = "mock"
+
+[reports.archive.provider]
+type = "mock"
+
+[reports.archive.output]
+"$ref" = "shared.toml#/archive"
+params.path = "/data/archive.json"
+`)
+
+	reports, err := NewLoader().LoadReportsFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadReportsFromFile() error = %v", err)
+	}
+	if len(reports) != 2 || reports["daily"].Output.Type != "console" {
+		t.Errorf("reports = %v", reports)
+	}
+	archive := reports["archive"].Output
+	if archive.Type != "file" || archive.Params["mode"] != "0640" || archive.Params["path"] != "/data/archive.json" {
+		t.Errorf("archive output = %+v, want the included output with path merged", archive)
+	}
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/factory"
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+)
+
+const watchedConfig = "provider:\n  type: mock\n  params: {query: %s}\nformatter: {type: json}\noutput: {type: console}\n"
+
+// recordingApply collects applied reloads.
+type recordingApply struct {
+	mu      sync.Mutex
+	applied []map[string]*engine.Config
+	err     error
+}
+
+func (r *recordingApply) apply(configs map[string]*engine.Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.applied = append(r.applied, configs)
+	return nil
+}
+
+func (r *recordingApply) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.applied)
+}
+
+func (r *recordingApply) last() map[string]*engine.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.applied[len(r.applied)-1]
+}
+
+func quietWatcherLogger() WatcherOption {
+	return WithWatcherLogger(logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText}))
+}
+
+// buildsEngine validates a reloaded report by building its engine.
+func buildsEngine() WatcherOption {
+	return WithConfigValidator(func(name string, cfg *engine.Config) error {
+		return factory.ValidateConfig(*cfg)
+	})
+}
+
+func watchedContent(query string) string {
+	return fmt.Sprintf(watchedConfig, query)
+}
+
+// TestWatcherReload tests change detection, rejection and recovery
+func TestWatcherReload(t *testing.T) {
+	setupTestRegistries()
+	dir := t.TempDir()
+	path := writeFile(t, dir, "sales.yaml", watchedContent("v1"))
+
+	rec := &recordingApply{}
+	w := NewWatcher(path, NewLoader(), rec.apply, buildsEngine(), quietWatcherLogger())
+
+	configs, err := w.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if configs["sales"] == nil || configs["sales"].Provider.Params["query"] != "v1" {
+		t.Fatalf("Load() = %v, want the file's report named sales", configs)
+	}
+	initial := w.Status().Version
+
+	// Unchanged files are not reloaded
+	if err := w.reloadIfChanged(); err != nil || rec.count() != 0 {
+		t.Fatalf("reloadIfChanged() error = %v, applied %d", err, rec.count())
+	}
+
+	writeFile(t, dir, "sales.yaml", watchedContent("v2"))
+	if err := w.reloadIfChanged(); err != nil {
+		t.Fatalf("reloadIfChanged() error = %v", err)
+	}
+	if rec.count() != 1 || rec.last()["sales"].Provider.Params["query"] != "v2" {
+		t.Fatalf("applied = %v, want v2", rec.applied)
+	}
+	status := w.Status()
+	if status.Reloads != 1 || status.Version == initial {
+		t.Errorf("Status() = %+v", status)
+	}
+
+	// A report that cannot build is rejected and the previous config stays
+	writeFile(t, dir, "sales.yaml", strings.Replace(watchedContent("v3"), "type: mock", "type: nope", 1))
+	if err := w.reloadIfChanged(); err == nil || !strings.Contains(err.Error(), `report "sales"`) {
+		t.Errorf("reloadIfChanged() error = %v, want the report named", err)
+	}
+	if rec.count() != 1 {
+		t.Error("rejected reload should not be applied")
+	}
+	res, _ := w.CheckHealth(context.Background())
+	if res.Status != health.StatusDegraded || !strings.Contains(res.Error, "config reload rejected") {
+		t.Errorf("health = %+v, want DEGRADED", res)
+	}
+	if w.Status().Version != status.Version {
+		t.Error("rejected reload should not change the applied version")
+	}
+
+	// The broken version is not retried until it changes again
+	if err := w.reloadIfChanged(); err != nil || w.Status().Rejected != 1 {
+		t.Errorf("reloadIfChanged() error = %v, rejected = %d", err, w.Status().Rejected)
+	}
+
+	writeFile(t, dir, "sales.yaml", watchedContent("v4"))
+	if err := w.reloadIfChanged(); err != nil {
+		t.Fatalf("reloadIfChanged() error = %v", err)
+	}
+	res, _ = w.CheckHealth(context.Background())
+	if res.Status != health.StatusUp || w.Status().LastError != "" {
+		t.Errorf("health = %+v, want UP after a good reload", res)
+	}
+}
+
+// TestWatcherDefaultValidation tests that without a validator, reloads
+// are only checked by the loader
+func TestWatcherDefaultValidation(t *testing.T) {
+	setupTestRegistries()
+	dir := t.TempDir()
+	path := writeFile(t, dir, "sales.yaml", watchedContent("v1"))
+
+	rec := &recordingApply{}
+	w := NewWatcher(path, NewLoader(), rec.apply, quietWatcherLogger())
+	if _, err := w.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// An unregistered type is only caught when an engine is built
+	writeFile(t, dir, "sales.yaml", strings.Replace(watchedContent("v2"), "type: mock", "type: nope", 1))
+	if err := w.reloadIfChanged(); err != nil || rec.count() != 1 {
+		t.Fatalf("reloadIfChanged() error = %v, applied %d", err, rec.count())
+	}
+
+	writeFile(t, dir, "sales.yaml", strings.Replace(watchedContent("v3"), "type: mock", "type: ' '", 1))
+	if err := w.reloadIfChanged(); err == nil || !strings.Contains(err.Error(), "provider.type") {
+		t.Errorf("reloadIfChanged() error = %v, want the invalid field named", err)
+	}
+	if rec.count() != 1 {
+		t.Error("invalid reload should not be applied")
+	}
+}
+
+// TestWatcherApplyError tests that a failed apply is a rejected reload
+func TestWatcherApplyError(t *testing.T) {
+	setupTestRegistries()
+	path := writeFile(t, t.TempDir(), "sales.yaml", watchedContent("v1"))
+
+	rec := &recordingApply{err: errors.New("scheduler stopped")}
+	w := NewWatcher(path, NewLoader(), rec.apply, quietWatcherLogger())
+	err := w.Reload()
+	if err == nil || !strings.Contains(err.Error(), "failed to apply config: scheduler stopped") {
+		t.Errorf("Reload() error = %v", err)
+	}
+	if status := w.Status(); status.Rejected != 1 || status.Reloads != 0 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+// TestWatcherIncludes tests that included files are watched
+func TestWatcherIncludes(t *testing.T) {
+	setupTestRegistries()
+	dir := t.TempDir()
+	writeFile(t, dir, "shared/output.yaml", "type: console\n")
+	path := writeFile(t, dir, "sales.yaml", "provider: {type: mock}\nformatter: {type: json}\noutput: !include shared/output.yaml\n")
+
+	rec := &recordingApply{}
+	w := NewWatcher(path, NewLoader(), rec.apply, quietWatcherLogger())
+	if _, err := w.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	writeFile(t, dir, "shared/output.yaml", "type: console\nparams: {}\n")
+	if err := w.reloadIfChanged(); err != nil {
+		t.Fatalf("reloadIfChanged() error = %v", err)
+	}
+	if rec.count() != 1 {
+		t.Errorf("change to an included file applied %d reloads, want 1", rec.count())
+	}
+}
+
+// TestWatcherMultiReport tests reloading a multi-report file
+func TestWatcherMultiReport(t *testing.T) {
+	setupTestRegistries()
+	content := "defaults:\n  formatter: {type: json}\n  output: {type: console}\nreports:\n  daily:\n    provider: {type: mock}\n  weekly:\n    provider: {type: mock}\n"
+	path := writeFile(t, t.TempDir(), "reports.yaml", content)
+
+	w := NewWatcher(path, NewLoader(), nil, quietWatcherLogger())
+	configs, err := w.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(configs) != 2 || configs["daily"] == nil || configs["weekly"] == nil {
+		t.Errorf("Load() = %v, want daily and weekly", configs)
+	}
+}
+
+// TestWatcherRun tests polling for changes
+func TestWatcherRun(t *testing.T) {
+	setupTestRegistries()
+	dir := t.TempDir()
+	path := writeFile(t, dir, "sales.yaml", watchedContent("v1"))
+
+	rec := &recordingApply{}
+	w := NewWatcher(path, NewLoader(), rec.apply, WithPollInterval(10*time.Millisecond), quietWatcherLogger())
+	if _, err := w.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	writeFile(t, dir, "sales.yaml", watchedContent("v2"))
+	deadline := time.Now().Add(2 * time.Second)
+	for rec.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rec.count() != 1 {
+		t.Errorf("applied %d reloads, want 1", rec.count())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
 	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
 )
 
 // EngineBuilder provides a fluent interface for constructing a ReportEngine.
@@ -21,8 +22,25 @@ type EngineBuilder struct {
 	retry     *resilience.RetryPolicy
 	breaker   *resilience.CircuitBreaker
 
+	providerLimiter  *resilience.RateLimiter
+	outputLimiter    *resilience.RateLimiter
+	providerBulkhead *resilience.Bulkhead
+	outputBulkhead   *resilience.Bulkhead
+
+	providerFallbacks []resilience.ProviderAlternative
+	outputFallbacks   []resilience.OutputAlternative
+	providerName      string
+	outputName        string
+
+	spool       *spool.Spool
+	spoolName   string
+	spoolPolicy resilience.RetryPolicy
+
 	tracer  observability.Tracer
 	metrics observability.MetricsCollector
+
+	timeouts     Timeouts
+	deadlineHook DeadlineHook
 }
 
 // NewEngineBuilder creates a new EngineBuilder with default values.
@@ -65,7 +83,65 @@ func (b *EngineBuilder) WithCircuitBreaker(cb *resilience.CircuitBreaker) *Engin
 	return b
 }
 
-// WithTracer sets the tracer for the engine.
+// WithProviderRateLimiter limits how often the provider is called. The
+// limiter may be shared with other engines (see resilience.LimiterRegistry).
+func (b *EngineBuilder) WithProviderRateLimiter(l *resilience.RateLimiter) *EngineBuilder {
+	b.providerLimiter = l
+	return b
+}
+
+// WithOutputRateLimiter limits how often the output is called. The
+// limiter may be shared with other engines.
+func (b *EngineBuilder) WithOutputRateLimiter(l *resilience.RateLimiter) *EngineBuilder {
+	b.outputLimiter = l
+	return b
+}
+
+// WithProviderBulkhead limits how many provider calls run at once. The
+// bulkhead may be shared with other engines.
+func (b *EngineBuilder) WithProviderBulkhead(bh *resilience.Bulkhead) *EngineBuilder {
+	b.providerBulkhead = bh
+	return b
+}
+
+// WithOutputBulkhead limits how many output calls run at once. The
+// bulkhead may be shared with other engines.
+func (b *EngineBuilder) WithOutputBulkhead(bh *resilience.Bulkhead) *EngineBuilder {
+	b.outputBulkhead = bh
+	return b
+}
+
+// WithProviderFallbacks sets providers to fetch from, in order, when the
+// provider fails or its circuit is open. primary names the provider in
+// logs and RunResult.Sources.
+func (b *EngineBuilder) WithProviderFallbacks(primary string, fallbacks ...resilience.ProviderAlternative) *EngineBuilder {
+	b.providerName = primary
+	b.providerFallbacks = fallbacks
+	return b
+}
+
+// WithOutputFallbacks sets outputs to send to, in order, when the output
+// fails or its circuit is open. primary names the output in logs and
+// RunResult.Sources.
+func (b *EngineBuilder) WithOutputFallbacks(primary string, fallbacks ...resilience.OutputAlternative) *EngineBuilder {
+	b.outputName = primary
+	b.outputFallbacks = fallbacks
+	return b
+}
+
+// WithSpool makes the output durable: every report is written to s before
+// it is delivered in the background, retried with policy, and reports
+// that cannot be delivered stay in s for replay. name identifies the
+// output in spool entries.
+func (b *EngineBuilder) WithSpool(name string, s *spool.Spool, policy resilience.RetryPolicy) *EngineBuilder {
+	b.spool = s
+	b.spoolName = name
+	b.spoolPolicy = policy
+	return b
+}
+
+// WithTracer sets the tracer for the engine. Each component call gets a
+// span, nested under a "report.run" span for the whole run.
 func (b *EngineBuilder) WithTracer(tracer observability.Tracer) *EngineBuilder {
 	b.tracer = tracer
 	return b
@@ -77,6 +153,19 @@ func (b *EngineBuilder) WithMetrics(collector observability.MetricsCollector) *E
 	return b
 }
 
+// WithTimeouts sets the run and stage timeouts (see ReportEngine.WithTimeouts).
+func (b *EngineBuilder) WithTimeouts(timeouts Timeouts) *EngineBuilder {
+	b.timeouts = timeouts
+	return b
+}
+
+// WithDeadlineHook sets the hook called when a run or stage passes its
+// soft deadline (see Timeouts.WarnAt).
+func (b *EngineBuilder) WithDeadlineHook(hook DeadlineHook) *EngineBuilder {
+	b.deadlineHook = hook
+	return b
+}
+
 // Build validates all components and constructs the ReportEngine.
 // It returns an error if any required component is missing or invalid.
 //
@@ -124,6 +213,7 @@ func (b *EngineBuilder) Build() (*ReportEngine, error) {
 
 	prov := b.provider
 	proc := b.processor
+	fmttr := b.formatter
 	out := b.output
 
 	// Apply Metrics Decorators if collector is present
@@ -134,9 +224,27 @@ func (b *EngineBuilder) Build() (*ReportEngine, error) {
 		// processor.ProcessorHandler is an interface, so it should match.
 		// Wait, NewProcessorWithMetrics takes processor.ProcessorHandler.
 		proc = observability.NewProcessorWithMetrics(proc, b.metrics)
+		fmttr = observability.NewFormatterWithMetrics(fmttr, b.metrics)
 		out = observability.NewOutputWithMetrics(out, b.metrics)
 	}
 
+	// Apply Rate Limit and Bulkhead Decorators if present
+	// Both sit inside the circuit breaker, so rejected calls take no token
+	// or slot, and inside retry, so every attempt is limited. A call holds
+	// its bulkhead slot while it waits for a token.
+	if b.providerLimiter != nil {
+		prov = resilience.NewProviderWithRateLimit(prov, b.providerLimiter)
+	}
+	if b.outputLimiter != nil {
+		out = resilience.NewOutputWithRateLimit(out, b.outputLimiter)
+	}
+	if b.providerBulkhead != nil {
+		prov = resilience.NewProviderWithBulkhead(prov, b.providerBulkhead)
+	}
+	if b.outputBulkhead != nil {
+		out = resilience.NewOutputWithBulkhead(out, b.outputBulkhead)
+	}
+
 	// Apply CircuitBreaker Decorators if present
 	// Applies BEFORE Retry so that CB protects downstream.
 	// If wrapped inside Retry: Retry calls CB (success) -> CB calls Prov (fail) -> CB records fail.
@@ -174,6 +282,7 @@ func (b *EngineBuilder) Build() (*ReportEngine, error) {
 	if b.tracer != nil {
 		prov = observability.NewProviderWithTracing(prov, b.tracer)
 		proc = observability.NewProcessorWithTracing(proc, b.tracer)
+		fmttr = observability.NewFormatterWithTracing(fmttr, b.tracer)
 		out = observability.NewOutputWithTracing(out, b.tracer)
 	}
 
@@ -185,12 +294,40 @@ func (b *EngineBuilder) Build() (*ReportEngine, error) {
 		out = resilience.NewOutputWithRetry(out, retrier)
 	}
 
-	return &ReportEngine{
+	// Apply Fallback Decorators if present
+	// Fallbacks wrap everything else, so an alternative is only tried once
+	// the primary has exhausted its retries or its circuit is open.
+	if len(b.providerFallbacks) > 0 {
+		alternatives := append([]resilience.ProviderAlternative{{Name: b.providerName, Provider: prov}}, b.providerFallbacks...)
+		prov = resilience.NewProviderWithFallback(alternatives...)
+	}
+	if len(b.outputFallbacks) > 0 {
+		alternatives := append([]resilience.OutputAlternative{{Name: b.outputName, Output: out}}, b.outputFallbacks...)
+		out = resilience.NewOutputWithFallback(alternatives...)
+	}
+
+	// Apply Spool Decorator if present
+	// The spool is outermost: a report is on disk before any delivery
+	// attempt, and every decorator above runs again for each attempt.
+	if b.spool != nil {
+		out = spool.NewOutput(b.spoolName, out, b.spool, b.spoolPolicy)
+	}
+
+	eng := &ReportEngine{
 		Provider:  prov,
 		Processor: proc,
-		Formatter: b.formatter,
+		Formatter: fmttr,
 		Output:    out,
-	}, nil
+		tracer:    b.tracer,
+		metrics:   b.metrics,
+
+		timeouts:     b.timeouts,
+		deadlineHook: b.deadlineHook,
+	}
+	if b.breaker != nil {
+		eng.breaker = b.breaker
+	}
+	return eng, nil
 }
 
 // Validate checks if all required components are set without building.
@@ -2,13 +2,19 @@ package engine
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
 )
 
 // Mock implementations for builder tests
@@ -455,3 +461,146 @@ func BenchmarkEngineBuilderReuseWithReset(b *testing.B) {
 			Build()
 	}
 }
+
+// TestEngineBuilderFallbacks tests that fallbacks serve the run and are
+// reported in the result
+func TestEngineBuilderFallbacks(t *testing.T) {
+	primaryErr := fmt.Errorf("sftp unreachable")
+	spool := &mockOutput{}
+
+	eng, err := NewEngineBuilder().
+		WithProvider(&mockProvider{shouldErr: true, err: fmt.Errorf("rest down")}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(&mockFormatter{}).
+		WithOutput(&mockOutput{shouldErr: true, err: primaryErr}).
+		WithProviderFallbacks("rest", resilience.ProviderAlternative{Name: "snapshot", Provider: &builderMockProvider{}}).
+		WithOutputFallbacks("sftp", resilience.OutputAlternative{Name: "spool", Output: spool}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if string(spool.received) != "formatted" {
+		t.Errorf("spool received %q", spool.received)
+	}
+
+	if len(res.Sources) != 2 {
+		t.Fatalf("Sources = %+v, want provider and output", res.Sources)
+	}
+	if s := res.Sources[0]; s.Component != "provider" || s.Source != "snapshot" || !s.Fallback {
+		t.Errorf("provider source = %+v", s)
+	}
+	if s := res.Sources[1]; s.Component != "output" || s.Source != "spool" || !s.Fallback {
+		t.Errorf("output source = %+v", s)
+	}
+	if len(res.Warnings) != 2 || !strings.Contains(res.Warnings[1], `output served by fallback "spool" after: sftp: sftp unreachable`) {
+		t.Errorf("Warnings = %v", res.Warnings)
+	}
+}
+
+// TestEngineBuilderInstrumentationKeepsStreaming tests that metrics and
+// tracing decorators leave the pipeline mode to the components
+func TestEngineBuilderInstrumentationKeepsStreaming(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "orders.csv")
+	if err := os.WriteFile(csvPath, []byte("id,total\n1,10\n2,20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	csv := provider.NewCSVProvider()
+	if err := csv.Configure(map[string]string{"file_path": csvPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		provider provider.ProviderStrategy
+		mode     string
+	}{
+		{"streaming provider", csv, ModeStreaming},
+		{"batch provider", &builderMockProvider{}, ModeBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := output.NewFileOutput()
+			if err := out.Configure(map[string]string{"path": filepath.Join(t.TempDir(), "report.json")}); err != nil {
+				t.Fatal(err)
+			}
+			eng, err := NewEngineBuilder().
+				WithProvider(tt.provider).
+				WithProcessor(&processor.BaseProcessor{}).
+				WithFormatter(formatter.NewJSONFormatter("")).
+				WithOutput(out).
+				WithMetrics(observability.NewNoopCollector()).
+				WithTracer(observability.NewNoopTracer()).
+				Build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+
+			res, err := eng.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if res.Mode != tt.mode {
+				t.Errorf("Mode = %s, want %s", res.Mode, tt.mode)
+			}
+			if res.OutputLocation != out.Location() {
+				t.Errorf("OutputLocation = %q, want %q", res.OutputLocation, out.Location())
+			}
+		})
+	}
+}
+
+// builderRecordOutput is a record output that counts how it was called.
+type builderRecordOutput struct {
+	sends   int
+	records []map[string]interface{}
+}
+
+func (o *builderRecordOutput) Send(ctx context.Context, data []byte) error {
+	o.sends++
+	return nil
+}
+
+func (o *builderRecordOutput) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	o.records = append(o.records, records...)
+	return nil
+}
+
+// TestEngineBuilderDecoratorsForwardRecords tests that a record output
+// still receives records when every output decorator wraps it
+func TestEngineBuilderDecoratorsForwardRecords(t *testing.T) {
+	out := &builderRecordOutput{}
+	eng, err := NewEngineBuilder().
+		WithProvider(&builderMockProvider{}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(&builderMockFormatter{}).
+		WithOutput(out).
+		WithMetrics(observability.NewNoopCollector()).
+		WithTracer(observability.NewNoopTracer()).
+		WithOutputRateLimiter(resilience.NewRateLimiter("test", 100, 1)).
+		WithOutputBulkhead(resilience.NewBulkhead("test", 1, 0)).
+		WithCircuitBreaker(resilience.NewCircuitBreaker("test", 3, time.Second)).
+		WithRetry(resilience.DefaultRetryPolicy).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, ok := eng.Output.(output.RecordOutputStrategy); !ok {
+		t.Fatalf("expected the decorated output to accept records, got %T", eng.Output)
+	}
+
+	if err := eng.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out.sends != 0 {
+		t.Errorf("expected no formatted sends, got %d", out.sends)
+	}
+	if len(out.records) != 1 || out.records[0]["id"] != 1 {
+		t.Errorf("records = %v, want the provider's record", out.records)
+	}
+}
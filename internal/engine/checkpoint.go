@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
+)
+
+// Checkpoint records how far a streaming run got. It is saved after every
+// chunk the output has accepted, so a failed run can be resumed from the
+// last committed chunk.
+type Checkpoint struct {
+	// RunID is the run that started the stream.
+	RunID string `json:"run_id"`
+
+	// Records is the number of provider records consumed and committed.
+	Records int64 `json:"records"`
+
+	// RecordsOut is the number of records written to the output.
+	RecordsOut int64 `json:"records_out"`
+
+	// Chunks is the number of chunks committed.
+	Chunks int `json:"chunks"`
+
+	// Bytes is the number of output bytes committed.
+	Bytes int64 `json:"bytes"`
+
+	// Watermark is the highest watermark fetched so far, for incremental
+	// runs.
+	Watermark string `json:"watermark,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WithCheckpoints enables resumable streaming runs. When the provider
+// implements provider.ResumableProvider and the output implements
+// output.ResumableOutput, the engine saves a Checkpoint under key after
+// every committed chunk. If a streaming run fails, the partial output is
+// kept and the next run with the same key resumes after the last
+// committed chunk instead of starting from zero. The checkpoint is deleted
+// when a run completes.
+//
+// Example:
+//
+//	eng.WithCheckpoints(state.NewFileStore("state.json"), "nightly-export")
+func (r *ReportEngine) WithCheckpoints(store state.Store, key string) *ReportEngine {
+	r.checkpoints = store
+	r.checkpointKey = key
+	return r
+}
+
+// checkpointer saves the progress of one streaming run.
+type checkpointer struct {
+	store state.Store
+	key   string
+
+	// base is the progress made by earlier attempts
+	base Checkpoint
+
+	// resuming is true until the stream has been reopened from base
+	resuming bool
+}
+
+// newCheckpointer returns nil unless checkpoints are configured, the state
+// is writable and both the provider and output can resume; configured
+// checkpoints that cannot be kept are reported as a warning. Any stored checkpoint is loaded;
+// one that cannot be read is discarded with a warning, as starting over
+// is always safe.
+func (r *ReportEngine) newCheckpointer(ctx context.Context, res *RunResult, prov provider.StreamingProviderStrategy, out output.StreamingOutputStrategy) *checkpointer {
+	if r.checkpoints == nil || r.readOnlyState {
+		return nil
+	}
+	_, okProvider := prov.(provider.ResumableProvider)
+	_, okOutput := out.(output.ResumableOutput)
+	if !okProvider || !okOutput {
+		r.getLogger().WarnContext(ctx, "checkpoints configured but the pipeline cannot resume",
+			"checkpoint_key", r.checkpointKey,
+			"provider_resumable", okProvider,
+			"output_resumable", okOutput,
+		)
+		res.warn("checkpoint %q not kept: the %s cannot resume, so a failed run will start over", r.checkpointKey, notResumable(okProvider, okOutput))
+		return nil
+	}
+
+	ck := &checkpointer{store: r.checkpoints, key: r.checkpointKey, base: Checkpoint{RunID: res.RunID}}
+
+	value, err := r.checkpoints.Get(r.checkpointKey)
+	if stderrors.Is(err, state.ErrNotFound) {
+		return ck
+	}
+	var saved Checkpoint
+	if err == nil {
+		err = json.Unmarshal([]byte(value), &saved)
+	}
+	if err != nil {
+		r.getLogger().WarnContext(ctx, "ignoring unreadable checkpoint", "checkpoint_key", r.checkpointKey, "error", err)
+		res.warn("checkpoint %q ignored, starting from the beginning: %v", r.checkpointKey, err)
+		return ck
+	}
+
+	ck.base = saved
+	ck.resuming = true
+	return ck
+}
+
+// notResumable names the components that cannot resume.
+func notResumable(okProvider, okOutput bool) string {
+	switch {
+	case !okProvider && !okOutput:
+		return "provider and output"
+	case !okProvider:
+		return "provider"
+	}
+	return "output"
+}
+
+// openOutput resumes the output from the checkpoint, or initializes it.
+// If the output cannot resume, the run starts over.
+func (ck *checkpointer) openOutput(ctx context.Context, r *ReportEngine, res *RunResult, out output.StreamingOutputStrategy) error {
+	if ck == nil || !ck.resuming {
+		return out.Initialize(ctx)
+	}
+
+	err := out.(output.ResumableOutput).Resume(ctx, ck.base.Bytes)
+	if err == nil {
+		return nil
+	}
+	r.getLogger().WarnContext(ctx, "cannot resume output, starting from the beginning",
+		"checkpoint_key", ck.key, "error", err)
+	res.warn("resume failed, starting from the beginning: %v", err)
+	ck.base = Checkpoint{RunID: res.RunID}
+	ck.resuming = false
+	return out.Initialize(ctx)
+}
+
+// openStream resumes the provider stream after the committed records, or
+// starts it from the beginning.
+func (ck *checkpointer) openStream(ctx context.Context, res *RunResult, prov provider.StreamingProviderStrategy) (provider.Iterator, error) {
+	if ck == nil || !ck.resuming {
+		return prov.Stream(ctx)
+	}
+
+	it, err := prov.(provider.ResumableProvider).StreamFrom(ctx, ck.base.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	resumed := ck.base
+	res.ResumedFrom = &resumed
+	res.Watermark.raise(ck.base.Watermark)
+	return it, nil
+}
+
+// resumed reports whether the run continues an earlier stream.
+func (ck *checkpointer) resumed() bool {
+	return ck != nil && ck.resuming
+}
+
+// save records the progress after a committed chunk. Failures are only
+// logged: they cost the ability to resume, not the run.
+func (ck *checkpointer) save(ctx context.Context, r *ReportEngine, res *RunResult, totals *streamTotals) {
+	if ck == nil {
+		return
+	}
+
+	cp := Checkpoint{
+		RunID:      ck.base.RunID,
+		Records:    ck.base.Records + int64(totals.fetch.RecordsOut),
+		RecordsOut: ck.base.RecordsOut + int64(totals.output.RecordsIn),
+		Chunks:     ck.base.Chunks + res.Chunks,
+		Bytes:      ck.base.Bytes + totals.output.Bytes,
+		UpdatedAt:  time.Now(),
+	}
+	if res.Watermark != nil {
+		cp.Watermark = res.Watermark.Next
+	}
+
+	data, err := json.Marshal(cp)
+	if err == nil {
+		err = ck.store.Set(ck.key, string(data))
+	}
+	if err != nil {
+		r.getLogger().WarnContext(ctx, "failed to save checkpoint", "checkpoint_key", ck.key, "error", err)
+	}
+}
+
+// clear deletes the checkpoint once the stream is complete.
+func (ck *checkpointer) clear(ctx context.Context, r *ReportEngine, res *RunResult) {
+	if ck == nil {
+		return
+	}
+	if err := ck.store.Delete(ck.key); err != nil {
+		r.getLogger().WarnContext(ctx, "failed to delete checkpoint", "checkpoint_key", ck.key, "error", err)
+		res.warn("checkpoint %q not deleted, the next run may resume a completed stream: %v", ck.key, err)
+	}
+}
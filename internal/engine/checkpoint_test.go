@@ -0,0 +1,266 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
+)
+
+// flakyFileOutput writes half of its failAt-th chunk and then fails, like
+// a disk filling up mid-write.
+type flakyFileOutput struct {
+	*output.FileOutput
+	writes int
+	failAt int
+}
+
+func (f *flakyFileOutput) WriteChunk(ctx context.Context, data []byte) error {
+	f.writes++
+	if f.writes == f.failAt {
+		_ = f.FileOutput.WriteChunk(ctx, data[:len(data)/2])
+		return errors.New("disk full")
+	}
+	return f.FileOutput.WriteChunk(ctx, data)
+}
+
+// streamOnlyProvider hides the CSV provider's StreamFrom.
+type streamOnlyProvider struct {
+	csv *provider.CSVProvider
+}
+
+func (p streamOnlyProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	return p.csv.Fetch(ctx)
+}
+
+func (p streamOnlyProvider) Stream(ctx context.Context) (provider.Iterator, error) {
+	return p.csv.Stream(ctx)
+}
+
+// checkpointFixture is a CSV source with ids 1..5 and a JSON file output.
+type checkpointFixture struct {
+	t       *testing.T
+	csvPath string
+	outPath string
+	store   state.Store
+}
+
+func newCheckpointFixture(t *testing.T) *checkpointFixture {
+	dir := t.TempDir()
+	f := &checkpointFixture{
+		t:       t,
+		csvPath: filepath.Join(dir, "orders.csv"),
+		outPath: filepath.Join(dir, "out.json"),
+		store:   state.NewFileStore(filepath.Join(dir, "state.json")),
+	}
+	if err := os.WriteFile(f.csvPath, []byte("id\n1\n2\n3\n4\n5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func (f *checkpointFixture) provider() *provider.CSVProvider {
+	f.t.Helper()
+	p := provider.NewCSVProvider()
+	if err := p.Configure(map[string]string{"file_path": f.csvPath}); err != nil {
+		f.t.Fatal(err)
+	}
+	return p
+}
+
+func (f *checkpointFixture) output() *output.FileOutput {
+	f.t.Helper()
+	out := output.NewFileOutput()
+	if err := out.Configure(map[string]string{"path": f.outPath}); err != nil {
+		f.t.Fatal(err)
+	}
+	return out
+}
+
+func (f *checkpointFixture) run(prov provider.ProviderStrategy, out output.OutputStrategy) (*engine.RunResult, error) {
+	return (&engine.ReportEngine{
+		Provider:  prov,
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    out,
+	}).WithLogger(quietLogger()).WithChunkSize(2).WithCheckpoints(f.store, "export").Execute(context.Background())
+}
+
+// ids returns the ids in the output file, failing if it is not valid JSON.
+func (f *checkpointFixture) ids() []string {
+	f.t.Helper()
+	data, err := os.ReadFile(f.outPath)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	var records []map[string]string
+	if err := json.Unmarshal(data, &records); err != nil {
+		f.t.Fatalf("output is not valid JSON: %v\n%s", err, data)
+	}
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r["id"]
+	}
+	return ids
+}
+
+func TestCheckpoint_ResumeAfterFailure(t *testing.T) {
+	f := newCheckpointFixture(t)
+
+	// Writes: "[" then chunk [1 2], then chunk [3 4] fails half written
+	res, err := f.run(f.provider(), &flakyFileOutput{FileOutput: f.output(), failAt: 3})
+	if err == nil {
+		t.Fatal("first run should fail")
+	}
+	if res.ResumedFrom != nil {
+		t.Errorf("first run ResumedFrom = %+v", res.ResumedFrom)
+	}
+	saved, err := f.store.Get("export")
+	if err != nil {
+		t.Fatalf("checkpoint not saved: %v", err)
+	}
+	var cp engine.Checkpoint
+	if err := json.Unmarshal([]byte(saved), &cp); err != nil {
+		t.Fatal(err)
+	}
+	if cp.RunID != res.RunID || cp.Records != 2 || cp.RecordsOut != 2 || cp.Chunks != 1 {
+		t.Errorf("checkpoint = %+v", cp)
+	}
+
+	// The retry continues after the committed chunk
+	res, err = f.run(f.provider(), f.output())
+	if err != nil {
+		t.Fatalf("retry error = %v", err)
+	}
+	if res.ResumedFrom == nil || res.ResumedFrom.Records != 2 || res.ResumedFrom.RunID == res.RunID {
+		t.Errorf("ResumedFrom = %+v", res.ResumedFrom)
+	}
+	if res.RecordsIn != 3 || res.Chunks != 2 {
+		t.Errorf("retry: %d records in %d chunks, want 3 in 2", res.RecordsIn, res.Chunks)
+	}
+	if got := f.ids(); len(got) != 5 || got[0] != "1" || got[2] != "3" || got[4] != "5" {
+		t.Errorf("output ids = %v, want 1..5 once each", got)
+	}
+	if _, err := f.store.Get("export"); !errors.Is(err, state.ErrNotFound) {
+		t.Errorf("checkpoint not deleted after success: %v", err)
+	}
+
+	// A completed run starts over
+	res, err = f.run(f.provider(), f.output())
+	if err != nil || res.ResumedFrom != nil || res.RecordsIn != 5 {
+		t.Errorf("next run: %v, resumed %+v, %d records", err, res.ResumedFrom, res.RecordsIn)
+	}
+}
+
+func TestCheckpoint_FailureBeforeFirstChunk(t *testing.T) {
+	f := newCheckpointFixture(t)
+
+	if _, err := f.run(f.provider(), &flakyFileOutput{FileOutput: f.output(), failAt: 2}); err == nil {
+		t.Fatal("first run should fail")
+	}
+	if _, err := f.store.Get("export"); !errors.Is(err, state.ErrNotFound) {
+		t.Errorf("no chunk was committed, checkpoint = %v", err)
+	}
+
+	res, err := f.run(f.provider(), f.output())
+	if err != nil || res.ResumedFrom != nil || len(f.ids()) != 5 {
+		t.Errorf("retry: %v, resumed %+v", err, res.ResumedFrom)
+	}
+}
+
+func TestCheckpoint_UnreadableCheckpoint(t *testing.T) {
+	f := newCheckpointFixture(t)
+	_ = f.store.Set("export", "not json")
+
+	res, err := f.run(f.provider(), f.output())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.ResumedFrom != nil || len(res.Warnings) != 1 || len(f.ids()) != 5 {
+		t.Errorf("resumed %+v, warnings %v", res.ResumedFrom, res.Warnings)
+	}
+}
+
+func TestCheckpoint_OutputCannotResume(t *testing.T) {
+	f := newCheckpointFixture(t)
+	_ = f.store.Set("export", `{"run_id":"old","records":2,"records_out":2,"chunks":1,"bytes":4096}`)
+
+	// The output file is shorter than the checkpoint, so the run starts over
+	res, err := f.run(f.provider(), f.output())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.ResumedFrom != nil || len(res.Warnings) != 1 || len(f.ids()) != 5 {
+		t.Errorf("resumed %+v, warnings %v", res.ResumedFrom, res.Warnings)
+	}
+}
+
+func TestCheckpoint_NotResumable(t *testing.T) {
+	f := newCheckpointFixture(t)
+
+	// The provider streams but cannot resume, so no checkpoints are kept
+	prov := streamOnlyProvider{f.provider()}
+	if _, err := f.run(prov, &flakyFileOutput{FileOutput: f.output(), failAt: 3}); err == nil {
+		t.Fatal("run should fail")
+	}
+	if _, err := f.store.Get("export"); !errors.Is(err, state.ErrNotFound) {
+		t.Errorf("checkpoint saved for a provider that cannot resume: %v", err)
+	}
+
+	res, err := f.run(prov, f.output())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "provider cannot resume") {
+		t.Errorf("Warnings = %v, want the checkpoint warning", res.Warnings)
+	}
+}
+
+func TestCheckpoint_BatchRunWarns(t *testing.T) {
+	f := newCheckpointFixture(t)
+
+	res, err := f.run(provider.NewMockProvider([]map[string]interface{}{{"id": 1}}), f.output())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.Mode != engine.ModeBatch || len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "streaming runs") {
+		t.Errorf("mode %s, Warnings = %v, want the checkpoint warning", res.Mode, res.Warnings)
+	}
+}
+
+func TestCheckpoint_ThroughDecorators(t *testing.T) {
+	f := newCheckpointFixture(t)
+
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(f.provider()).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&flakyFileOutput{FileOutput: f.output(), failAt: 3}).
+		WithMetrics(observability.NewNoopCollector()).
+		WithTracer(observability.NewNoopTracer()).
+		WithOutputBulkhead(resilience.NewBulkhead("test", 1, 0)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	eng.WithLogger(quietLogger()).WithChunkSize(2).WithCheckpoints(f.store, "export")
+
+	if _, err := eng.Execute(context.Background()); err == nil {
+		t.Fatal("run should fail")
+	}
+	if _, err := f.store.Get("export"); err != nil {
+		t.Errorf("expected a checkpoint through the decorators, got %v", err)
+	}
+}
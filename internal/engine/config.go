@@ -3,6 +3,9 @@ package engine
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
 )
 
 // Config is the top-level configuration for the Report Engine.
@@ -15,6 +18,18 @@ type Config struct {
 	Output         OutputConfig          `json:"output" yaml:"output"`
 	Retry          *RetryConfig          `json:"retry,omitempty" yaml:"retry,omitempty"`
 	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker,omitempty" yaml:"circuit_breaker,omitempty"`
+	RateLimit      *RateLimitConfig      `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	Bulkhead       *BulkheadConfig       `json:"bulkhead,omitempty" yaml:"bulkhead,omitempty"`
+	Watermark      *WatermarkConfig      `json:"watermark,omitempty" yaml:"watermark,omitempty"`
+	Checkpoint     *CheckpointConfig     `json:"checkpoint,omitempty" yaml:"checkpoint,omitempty"`
+	Timeouts       *TimeoutConfig        `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	Fallback       *FallbackConfig       `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	Spool          *SpoolConfig          `json:"spool,omitempty" yaml:"spool,omitempty"`
+
+	// Positions locates the fields in the config file the config was
+	// loaded from, if any, so validation and build errors can name the
+	// line and column of the field at fault.
+	Positions Positions `json:"-" yaml:"-"`
 }
 
 // RetryConfig defines the retry policy settings.
@@ -26,10 +41,137 @@ type RetryConfig struct {
 	Jitter     bool    `json:"jitter" yaml:"jitter"`
 }
 
-// CircuitBreakerConfig defines circuit breaker settings.
+// CircuitBreakerConfig defines circuit breaker settings. Without a window
+// the breaker opens after FailureThreshold consecutive failures; with a
+// "count" or "time" window it opens when FailureRate of the calls in the
+// window failed, once the window holds MinCalls calls.
 type CircuitBreakerConfig struct {
-	FailureThreshold uint   `json:"failure_threshold" yaml:"failure_threshold"`
-	ResetTimeout     string `json:"reset_timeout" yaml:"reset_timeout"` // Parsed to time.Duration
+	Name             string  `json:"name,omitempty" yaml:"name,omitempty"` // Used in metrics, logs and health (default: provider type)
+	FailureThreshold uint    `json:"failure_threshold" yaml:"failure_threshold"`
+	ResetTimeout     string  `json:"reset_timeout" yaml:"reset_timeout"`                         // Parsed to time.Duration
+	Window           string  `json:"window,omitempty" yaml:"window,omitempty"`                   // "count" or "time"
+	WindowSize       int     `json:"window_size,omitempty" yaml:"window_size,omitempty"`         // Calls in a count window
+	WindowDuration   string  `json:"window_duration,omitempty" yaml:"window_duration,omitempty"` // Parsed to time.Duration
+	FailureRate      float64 `json:"failure_rate,omitempty" yaml:"failure_rate,omitempty"`       // 0-1
+	MinCalls         int     `json:"min_calls,omitempty" yaml:"min_calls,omitempty"`
+	HalfOpenCalls    int     `json:"half_open_calls,omitempty" yaml:"half_open_calls,omitempty"` // Trial calls after the reset timeout (default: 1)
+}
+
+// RateLimitConfig defines token-bucket rate limits for the provider and
+// output. Limits with the same name are shared by every engine in the
+// process, e.g. to keep several reports within one partner API quota.
+type RateLimitConfig struct {
+	Provider *RateLimitRule `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Output   *RateLimitRule `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// RateLimitRule defines one shared rate limit.
+type RateLimitRule struct {
+	Name  string  `json:"name" yaml:"name"`   // e.g., "partner-api"
+	Rate  float64 `json:"rate" yaml:"rate"`   // Calls per second
+	Burst int     `json:"burst" yaml:"burst"` // Calls allowed at once after idling (default: 1)
+}
+
+// BulkheadConfig defines concurrency limits for the provider and output.
+// Bulkheads with the same name are shared by every engine in the process.
+type BulkheadConfig struct {
+	Provider *BulkheadRule `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Output   *BulkheadRule `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// BulkheadRule defines one shared concurrency limit.
+type BulkheadRule struct {
+	Name          string `json:"name" yaml:"name"`                             // e.g., "warehouse-db"
+	MaxConcurrent int    `json:"max_concurrent" yaml:"max_concurrent"`         // Calls running at once
+	MaxWait       string `json:"max_wait,omitempty" yaml:"max_wait,omitempty"` // Parsed to time.Duration; empty waits for the run's context
+}
+
+// WatermarkConfig enables incremental runs. The provider declares the
+// watermark field through its "watermark_field" param; the highest value
+// fetched by each successful run is saved under Key in the state file.
+type WatermarkConfig struct {
+	Store string `json:"store" yaml:"store"` // Path to the JSON state file
+	Key   string `json:"key" yaml:"key"`     // e.g., "daily-orders"
+}
+
+// CheckpointConfig enables resumable streaming runs. Progress is saved
+// under Key in the state file after every chunk, so a failed run resumes
+// where it stopped when the provider and output support it.
+type CheckpointConfig struct {
+	Store string `json:"store" yaml:"store"` // Path to the JSON state file
+	Key   string `json:"key" yaml:"key"`     // e.g., "nightly-export"
+}
+
+// TimeoutConfig bounds how long a run and each of its stages may take.
+// Durations are parsed with time.ParseDuration; empty means no limit.
+// In a streaming run, a stage timeout bounds the stage over all chunks.
+type TimeoutConfig struct {
+	Run     string  `json:"run,omitempty" yaml:"run,omitempty"`
+	Fetch   string  `json:"fetch,omitempty" yaml:"fetch,omitempty"`
+	Process string  `json:"process,omitempty" yaml:"process,omitempty"`
+	Format  string  `json:"format,omitempty" yaml:"format,omitempty"`
+	Output  string  `json:"output,omitempty" yaml:"output,omitempty"`
+	WarnAt  float64 `json:"warn_at,omitempty" yaml:"warn_at,omitempty"` // Fraction (0-1) of a timeout after which a warning is logged
+}
+
+// ToTimeouts converts the validated settings to Timeouts.
+func (c TimeoutConfig) ToTimeouts() Timeouts {
+	parse := func(s string) time.Duration {
+		d, _ := time.ParseDuration(s)
+		return d
+	}
+	return Timeouts{
+		Run:     parse(c.Run),
+		Fetch:   parse(c.Fetch),
+		Process: parse(c.Process),
+		Format:  parse(c.Format),
+		Output:  parse(c.Output),
+		WarnAt:  c.WarnAt,
+	}
+}
+
+// FallbackConfig lists providers and outputs to use, in order, when the
+// provider or output fails or its circuit is open, e.g. a cached snapshot
+// when the REST source is down.
+type FallbackConfig struct {
+	Providers []ProviderConfig `json:"providers,omitempty" yaml:"providers,omitempty"`
+	Outputs   []OutputConfig   `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+}
+
+// SpoolConfig makes the output durable: reports are written to Dir before
+// they are delivered in the background, and reports that cannot be
+// delivered stay there until replayed with "report-engine spool replay".
+type SpoolConfig struct {
+	Dir        string  `json:"dir" yaml:"dir"`                                     // e.g., "/var/spool/report-engine/orders"
+	MaxRetries int     `json:"max_retries,omitempty" yaml:"max_retries,omitempty"` // Delivery retries (default: 5)
+	BaseDelay  string  `json:"base_delay,omitempty" yaml:"base_delay,omitempty"`   // Parsed to time.Duration (default: 1s)
+	MaxDelay   string  `json:"max_delay,omitempty" yaml:"max_delay,omitempty"`     // Parsed to time.Duration (default: 1m)
+	Factor     float64 `json:"factor,omitempty" yaml:"factor,omitempty"`           // Backoff multiplier (default: 2)
+}
+
+// RetryPolicy returns the delivery retry policy of the validated
+// settings, with defaults for unset fields.
+func (c SpoolConfig) RetryPolicy() resilience.RetryPolicy {
+	policy := resilience.RetryPolicy{
+		MaxRetries: c.MaxRetries,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+		Factor:     c.Factor,
+		Jitter:     true,
+	}
+	if policy.MaxRetries == 0 {
+		policy.MaxRetries = 5
+	}
+	if d, err := time.ParseDuration(c.BaseDelay); err == nil {
+		policy.BaseDelay = d
+	}
+	if d, err := time.ParseDuration(c.MaxDelay); err == nil {
+		policy.MaxDelay = d
+	}
+	if policy.Factor == 0 {
+		policy.Factor = 2
+	}
+	return policy
 }
 
 // ProviderConfig represents the selected provider and its parameters.
@@ -56,30 +198,88 @@ type OutputConfig struct {
 	Params map[string]string `json:"params" yaml:"params"`
 }
 
-// Validate performs comprehensive validation of the configuration.
-// It checks all required fields and validates parameter structures.
-//
-// Returns nil if valid, or a detailed error describing what is invalid.
-func (c Config) Validate() error {
-	var errors []string
+// Position is the line and column, both 1-based, of a field in a config
+// file.
+type Position struct {
+	Line   int
+	Column int
+}
 
-	// Validate Provider
-	if err := c.validateProvider(); err != nil {
-		errors = append(errors, err.Error())
+// Positions maps the dotted paths of config fields, such as
+// "processors[1].params.field", to where they are set in the config file.
+type Positions map[string]Position
+
+// Locate returns the position of the field at path or, if it is not set
+// in the file, of its nearest enclosing field, e.g. of "output" for a
+// missing "output.type". ok is false if none of them is known.
+func (p Positions) Locate(path string) (pos Position, ok bool) {
+	for path != "" {
+		if pos, ok := p[path]; ok && pos.Line > 0 {
+			return pos, true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
 	}
+	return Position{}, false
+}
 
-	// Validate Processors
-	if err := c.validateProcessors(); err != nil {
-		errors = append(errors, err.Error())
-	}
+// FieldError is a validation problem with the config field at Path. Its
+// message names the field; Position, when known, is where the field is
+// set in the config file.
+type FieldError struct {
+	Path     string
+	Err      error
+	Position Position
+}
 
-	// Validate Formatter
-	if err := c.validateFormatter(); err != nil {
-		errors = append(errors, err.Error())
+// fieldError returns err as a problem with the field at path.
+func fieldError(path string, err error) *FieldError {
+	return &FieldError{Path: path, Err: err}
+}
+
+func (e *FieldError) Error() string {
+	if e.Position.Line == 0 {
+		return e.Err.Error()
 	}
+	return fmt.Sprintf("%v (line %d, column %d)", e.Err, e.Position.Line, e.Position.Column)
+}
 
-	// Validate Output
-	if err := c.validateOutput(); err != nil {
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate performs comprehensive validation of the configuration.
+// It checks all required fields and validates parameter structures.
+// Problems are reported with the position of their field when the config
+// was loaded from a file (see Positions).
+//
+// Returns nil if valid, or a detailed error describing what is invalid.
+func (c Config) Validate() error {
+	var errors []string
+	for _, validate := range []func() error{
+		c.validateProvider,
+		c.validateProcessors,
+		c.validateFormatter,
+		c.validateOutput,
+		c.validateWatermark,
+		c.validateCheckpoint,
+		c.validateCircuitBreaker,
+		c.validateRateLimit,
+		c.validateBulkhead,
+		c.validateTimeouts,
+		c.validateFallback,
+		c.validateSpool,
+	} {
+		err := validate()
+		if err == nil {
+			continue
+		}
+		if fe, ok := err.(*FieldError); ok {
+			fe.Position, _ = c.Positions.Locate(fe.Path)
+		}
 		errors = append(errors, err.Error())
 	}
 
@@ -97,16 +297,16 @@ func (c Config) Validate() error {
 // validateProvider validates the provider configuration
 func (c Config) validateProvider() error {
 	if c.Provider.Type == "" {
-		return ErrMissingProvider
+		return fieldError("provider.type", ErrMissingProvider)
 	}
 
 	// Validate type is not just whitespace
 	if strings.TrimSpace(c.Provider.Type) == "" {
-		return fmt.Errorf("provider.type cannot be empty or whitespace")
+		return fieldError("provider.type", fmt.Errorf("provider.type cannot be empty or whitespace"))
 	}
 
 	// Validate parameters if present
-	if err := validateParams(c.Provider.Params, "provider"); err != nil {
+	if err := validateParams(c.Provider.Params, "provider", "provider"); err != nil {
 		return err
 	}
 
@@ -121,17 +321,18 @@ func (c Config) validateProcessors() error {
 	}
 
 	for i, proc := range c.Processors {
+		path := fmt.Sprintf("processors[%d]", i)
 		if proc.Type == "" {
-			return fmt.Errorf("processor[%d].type is required", i)
+			return fieldError(path+".type", fmt.Errorf("processor[%d].type is required", i))
 		}
 
 		// Validate type is not just whitespace
 		if strings.TrimSpace(proc.Type) == "" {
-			return fmt.Errorf("processor[%d].type cannot be empty or whitespace", i)
+			return fieldError(path+".type", fmt.Errorf("processor[%d].type cannot be empty or whitespace", i))
 		}
 
 		// Validate parameters if present
-		if err := validateParams(proc.Params, fmt.Sprintf("processor[%d]", i)); err != nil {
+		if err := validateParams(proc.Params, fmt.Sprintf("processor[%d]", i), path); err != nil {
 			return err
 		}
 	}
@@ -142,16 +343,16 @@ func (c Config) validateProcessors() error {
 // validateFormatter validates the formatter configuration
 func (c Config) validateFormatter() error {
 	if c.Formatter.Type == "" {
-		return ErrMissingFormatter
+		return fieldError("formatter.type", ErrMissingFormatter)
 	}
 
 	// Validate type is not just whitespace
 	if strings.TrimSpace(c.Formatter.Type) == "" {
-		return fmt.Errorf("formatter.type cannot be empty or whitespace")
+		return fieldError("formatter.type", fmt.Errorf("formatter.type cannot be empty or whitespace"))
 	}
 
 	// Validate parameters if present
-	if err := validateParams(c.Formatter.Params, "formatter"); err != nil {
+	if err := validateParams(c.Formatter.Params, "formatter", "formatter"); err != nil {
 		return err
 	}
 
@@ -161,24 +362,230 @@ func (c Config) validateFormatter() error {
 // validateOutput validates the output configuration
 func (c Config) validateOutput() error {
 	if c.Output.Type == "" {
-		return ErrMissingOutput
+		return fieldError("output.type", ErrMissingOutput)
 	}
 
 	// Validate type is not just whitespace
 	if strings.TrimSpace(c.Output.Type) == "" {
-		return fmt.Errorf("output.type cannot be empty or whitespace")
+		return fieldError("output.type", fmt.Errorf("output.type cannot be empty or whitespace"))
 	}
 
 	// Validate parameters if present
-	if err := validateParams(c.Output.Params, "output"); err != nil {
+	if err := validateParams(c.Output.Params, "output", "output"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateWatermark validates the incremental-run settings, if present
+func (c Config) validateWatermark() error {
+	if c.Watermark == nil {
+		return nil
+	}
+	if strings.TrimSpace(c.Watermark.Store) == "" {
+		return fieldError("watermark.store", fmt.Errorf("watermark.store is required"))
+	}
+	if strings.TrimSpace(c.Watermark.Key) == "" {
+		return fieldError("watermark.key", fmt.Errorf("watermark.key is required"))
+	}
+	return nil
+}
+
+// validateCheckpoint validates the resumable-run settings, if present
+func (c Config) validateCheckpoint() error {
+	if c.Checkpoint == nil {
+		return nil
+	}
+	if strings.TrimSpace(c.Checkpoint.Store) == "" {
+		return fieldError("checkpoint.store", fmt.Errorf("checkpoint.store is required"))
+	}
+	if strings.TrimSpace(c.Checkpoint.Key) == "" {
+		return fieldError("checkpoint.key", fmt.Errorf("checkpoint.key is required"))
+	}
+	return nil
+}
+
+// validateCircuitBreaker validates the circuit breaker settings, if present
+func (c Config) validateCircuitBreaker() error {
+	cb := c.CircuitBreaker
+	if cb == nil {
+		return nil
+	}
+	if cb.ResetTimeout != "" {
+		if _, err := time.ParseDuration(cb.ResetTimeout); err != nil {
+			return fieldError("circuit_breaker.reset_timeout", fmt.Errorf("circuit_breaker.reset_timeout is invalid: %w", err))
+		}
+	}
+	if cb.HalfOpenCalls < 0 {
+		return fieldError("circuit_breaker.half_open_calls", fmt.Errorf("circuit_breaker.half_open_calls cannot be negative"))
+	}
+
+	switch cb.Window {
+	case "":
+		return nil
+	case "count":
+		if cb.WindowSize <= 0 {
+			return fieldError("circuit_breaker.window_size", fmt.Errorf("circuit_breaker.window_size must be positive for a count window"))
+		}
+	case "time":
+		d, err := time.ParseDuration(cb.WindowDuration)
+		if err != nil || d <= 0 {
+			return fieldError("circuit_breaker.window_duration", fmt.Errorf("circuit_breaker.window_duration must be a positive duration for a time window"))
+		}
+	default:
+		return fieldError("circuit_breaker.window", fmt.Errorf("circuit_breaker.window must be \"count\" or \"time\", got %q", cb.Window))
+	}
+	if cb.FailureRate <= 0 || cb.FailureRate > 1 {
+		return fieldError("circuit_breaker.failure_rate", fmt.Errorf("circuit_breaker.failure_rate must be between 0 and 1"))
+	}
+	if cb.MinCalls < 0 {
+		return fieldError("circuit_breaker.min_calls", fmt.Errorf("circuit_breaker.min_calls cannot be negative"))
+	}
+	return nil
+}
+
+// validateRateLimit validates the shared rate limits, if present
+func (c Config) validateRateLimit() error {
+	if c.RateLimit == nil {
+		return nil
+	}
+	if err := validateRateLimitRule(c.RateLimit.Provider, "rate_limit.provider"); err != nil {
 		return err
 	}
+	return validateRateLimitRule(c.RateLimit.Output, "rate_limit.output")
+}
+
+// validateRateLimitRule validates one rate limit, if present
+func validateRateLimitRule(rule *RateLimitRule, context string) error {
+	if rule == nil {
+		return nil
+	}
+	if strings.TrimSpace(rule.Name) == "" {
+		return fieldError(context+".name", fmt.Errorf("%s.name is required", context))
+	}
+	if rule.Rate <= 0 {
+		return fieldError(context+".rate", fmt.Errorf("%s.rate must be positive", context))
+	}
+	if rule.Burst < 0 {
+		return fieldError(context+".burst", fmt.Errorf("%s.burst cannot be negative", context))
+	}
+	return nil
+}
+
+// validateBulkhead validates the shared concurrency limits, if present
+func (c Config) validateBulkhead() error {
+	if c.Bulkhead == nil {
+		return nil
+	}
+	if err := validateBulkheadRule(c.Bulkhead.Provider, "bulkhead.provider"); err != nil {
+		return err
+	}
+	return validateBulkheadRule(c.Bulkhead.Output, "bulkhead.output")
+}
+
+// validateBulkheadRule validates one concurrency limit, if present
+func validateBulkheadRule(rule *BulkheadRule, context string) error {
+	if rule == nil {
+		return nil
+	}
+	if strings.TrimSpace(rule.Name) == "" {
+		return fieldError(context+".name", fmt.Errorf("%s.name is required", context))
+	}
+	if rule.MaxConcurrent <= 0 {
+		return fieldError(context+".max_concurrent", fmt.Errorf("%s.max_concurrent must be positive", context))
+	}
+	if rule.MaxWait != "" {
+		if _, err := time.ParseDuration(rule.MaxWait); err != nil {
+			return fieldError(context+".max_wait", fmt.Errorf("%s.max_wait is invalid: %w", context, err))
+		}
+	}
+	return nil
+}
+
+// validateTimeouts validates the run and stage timeouts, if present
+func (c Config) validateTimeouts() error {
+	t := c.Timeouts
+	if t == nil {
+		return nil
+	}
+	for _, field := range []struct{ name, value string }{
+		{"run", t.Run},
+		{"fetch", t.Fetch},
+		{"process", t.Process},
+		{"format", t.Format},
+		{"output", t.Output},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(field.value); err != nil || d <= 0 {
+			return fieldError("timeouts."+field.name, fmt.Errorf("timeouts.%s must be a positive duration, got %q", field.name, field.value))
+		}
+	}
+	if t.WarnAt < 0 || t.WarnAt >= 1 {
+		return fieldError("timeouts.warn_at", fmt.Errorf("timeouts.warn_at must be at least 0 and less than 1"))
+	}
+	return nil
+}
+
+// validateFallback validates the fallback providers and outputs, if present
+func (c Config) validateFallback() error {
+	if c.Fallback == nil {
+		return nil
+	}
+	for i, prov := range c.Fallback.Providers {
+		context := fmt.Sprintf("fallback.providers[%d]", i)
+		if strings.TrimSpace(prov.Type) == "" {
+			return fieldError(context+".type", fmt.Errorf("%s.type is required", context))
+		}
+		if err := validateParams(prov.Params, context, context); err != nil {
+			return err
+		}
+	}
+	for i, out := range c.Fallback.Outputs {
+		context := fmt.Sprintf("fallback.outputs[%d]", i)
+		if strings.TrimSpace(out.Type) == "" {
+			return fieldError(context+".type", fmt.Errorf("%s.type is required", context))
+		}
+		if err := validateParams(out.Params, context, context); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// validateSpool validates the output spool settings, if present
+func (c Config) validateSpool() error {
+	sp := c.Spool
+	if sp == nil {
+		return nil
+	}
+	if strings.TrimSpace(sp.Dir) == "" {
+		return fieldError("spool.dir", fmt.Errorf("spool.dir is required"))
+	}
+	if sp.MaxRetries < 0 {
+		return fieldError("spool.max_retries", fmt.Errorf("spool.max_retries cannot be negative"))
+	}
+	if sp.BaseDelay != "" {
+		if _, err := time.ParseDuration(sp.BaseDelay); err != nil {
+			return fieldError("spool.base_delay", fmt.Errorf("spool.base_delay is invalid: %w", err))
+		}
+	}
+	if sp.MaxDelay != "" {
+		if _, err := time.ParseDuration(sp.MaxDelay); err != nil {
+			return fieldError("spool.max_delay", fmt.Errorf("spool.max_delay is invalid: %w", err))
+		}
+	}
+	if sp.Factor != 0 && sp.Factor < 1 {
+		return fieldError("spool.factor", fmt.Errorf("spool.factor must be at least 1"))
+	}
 	return nil
 }
 
-// validateParams validates parameter map for empty keys or values
-func validateParams(params map[string]string, context string) error {
+// validateParams validates parameter map for empty keys or values.
+// context names the component in messages; path is its config path.
+func validateParams(params map[string]string, context, path string) error {
 	if params == nil {
 		return nil
 	}
@@ -186,12 +593,12 @@ func validateParams(params map[string]string, context string) error {
 	for key, value := range params {
 		// Check for empty keys
 		if key == "" {
-			return fmt.Errorf("%s.params contains empty key", context)
+			return fieldError(path+".params", fmt.Errorf("%s.params contains empty key", context))
 		}
 
 		// Check for whitespace-only keys
 		if strings.TrimSpace(key) == "" {
-			return fmt.Errorf("%s.params contains whitespace-only key", context)
+			return fieldError(path+".params", fmt.Errorf("%s.params contains whitespace-only key", context))
 		}
 
 		// Check for empty values (warn but don't fail - might be intentional)
@@ -224,7 +631,7 @@ func ValidateProviderConfig(cfg ProviderConfig) error {
 	if cfg.Type == "" || strings.TrimSpace(cfg.Type) == "" {
 		return fmt.Errorf("provider type is required and cannot be empty")
 	}
-	return validateParams(cfg.Params, "provider")
+	return validateParams(cfg.Params, "provider", "provider")
 }
 
 // ValidateProcessorConfig validates a processor config independently
@@ -232,7 +639,7 @@ func ValidateProcessorConfig(cfg ProcessorConfig) error {
 	if cfg.Type == "" || strings.TrimSpace(cfg.Type) == "" {
 		return fmt.Errorf("processor type is required and cannot be empty")
 	}
-	return validateParams(cfg.Params, "processor")
+	return validateParams(cfg.Params, "processor", "processor")
 }
 
 // ValidateFormatterConfig validates a formatter config independently
@@ -240,7 +647,7 @@ func ValidateFormatterConfig(cfg FormatterConfig) error {
 	if cfg.Type == "" || strings.TrimSpace(cfg.Type) == "" {
 		return fmt.Errorf("formatter type is required and cannot be empty")
 	}
-	return validateParams(cfg.Params, "formatter")
+	return validateParams(cfg.Params, "formatter", "formatter")
 }
 
 // ValidateOutputConfig validates an output config independently
@@ -248,5 +655,5 @@ func ValidateOutputConfig(cfg OutputConfig) error {
 	if cfg.Type == "" || strings.TrimSpace(cfg.Type) == "" {
 		return fmt.Errorf("output type is required and cannot be empty")
 	}
-	return validateParams(cfg.Params, "output")
+	return validateParams(cfg.Params, "output", "output")
 }
@@ -3,6 +3,7 @@ package engine
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestConfigValidateSuccess tests successful validation
@@ -53,6 +54,57 @@ func TestConfigValidateSuccess(t *testing.T) {
 				Output:    OutputConfig{Type: "console"},
 			},
 		},
+		{
+			name: "config with failure-rate circuit breaker",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				CircuitBreaker: &CircuitBreakerConfig{
+					ResetTimeout: "30s", Window: "count", WindowSize: 20, FailureRate: 0.5, MinCalls: 10, HalfOpenCalls: 3,
+				},
+			},
+		},
+		{
+			name: "config with rate limit and bulkhead",
+			config: Config{
+				Provider:  ProviderConfig{Type: "rest"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				RateLimit: &RateLimitConfig{Provider: &RateLimitRule{Name: "partner-api", Rate: 5, Burst: 10}},
+				Bulkhead:  &BulkheadConfig{Provider: &BulkheadRule{Name: "partner-api", MaxConcurrent: 2, MaxWait: "30s"}},
+			},
+		},
+		{
+			name: "config with timeouts",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Timeouts:  &TimeoutConfig{Run: "15m", Fetch: "10m", Output: "2m", WarnAt: 0.8},
+			},
+		},
+		{
+			name: "config with fallbacks",
+			config: Config{
+				Provider:  ProviderConfig{Type: "rest"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "sftp"},
+				Fallback: &FallbackConfig{
+					Providers: []ProviderConfig{{Type: "csv", Params: map[string]string{"file_path": "snapshot.csv"}}},
+					Outputs:   []OutputConfig{{Type: "file", Params: map[string]string{"path": "spool/report.json"}}},
+				},
+			},
+		},
+		{
+			name: "config with spool",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "sftp"},
+				Spool:     &SpoolConfig{Dir: "/var/spool/report-engine", MaxRetries: 3, BaseDelay: "5s", MaxDelay: "5m"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,6 +144,46 @@ func TestConfigValidateFailures(t *testing.T) {
 			},
 			expectError: "whitespace",
 		},
+		{
+			name: "watermark without store",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Watermark: &WatermarkConfig{Key: "orders"},
+			},
+			expectError: "watermark.store is required",
+		},
+		{
+			name: "watermark without key",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Watermark: &WatermarkConfig{Store: "state.json"},
+			},
+			expectError: "watermark.key is required",
+		},
+		{
+			name: "checkpoint without store",
+			config: Config{
+				Provider:   ProviderConfig{Type: "mock"},
+				Formatter:  FormatterConfig{Type: "json"},
+				Output:     OutputConfig{Type: "console"},
+				Checkpoint: &CheckpointConfig{Key: "export"},
+			},
+			expectError: "checkpoint.store is required",
+		},
+		{
+			name: "checkpoint without key",
+			config: Config{
+				Provider:   ProviderConfig{Type: "mock"},
+				Formatter:  FormatterConfig{Type: "json"},
+				Output:     OutputConfig{Type: "console"},
+				Checkpoint: &CheckpointConfig{Store: "state.json"},
+			},
+			expectError: "checkpoint.key is required",
+		},
 		{
 			name: "missing formatter type",
 			config: Config{
@@ -182,6 +274,126 @@ func TestConfigValidateFailures(t *testing.T) {
 			},
 			expectError: "formatter.params contains whitespace-only key",
 		},
+		{
+			name: "circuit breaker with unknown window",
+			config: Config{
+				Provider:       ProviderConfig{Type: "mock"},
+				Formatter:      FormatterConfig{Type: "json"},
+				Output:         OutputConfig{Type: "console"},
+				CircuitBreaker: &CircuitBreakerConfig{Window: "sliding"},
+			},
+			expectError: `circuit_breaker.window must be "count" or "time"`,
+		},
+		{
+			name: "circuit breaker count window without size",
+			config: Config{
+				Provider:       ProviderConfig{Type: "mock"},
+				Formatter:      FormatterConfig{Type: "json"},
+				Output:         OutputConfig{Type: "console"},
+				CircuitBreaker: &CircuitBreakerConfig{Window: "count", FailureRate: 0.5},
+			},
+			expectError: "circuit_breaker.window_size must be positive",
+		},
+		{
+			name: "circuit breaker failure rate out of range",
+			config: Config{
+				Provider:       ProviderConfig{Type: "mock"},
+				Formatter:      FormatterConfig{Type: "json"},
+				Output:         OutputConfig{Type: "console"},
+				CircuitBreaker: &CircuitBreakerConfig{Window: "time", WindowDuration: "1m", FailureRate: 50},
+			},
+			expectError: "circuit_breaker.failure_rate must be between 0 and 1",
+		},
+		{
+			name: "rate limit without name",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				RateLimit: &RateLimitConfig{Provider: &RateLimitRule{Rate: 5}},
+			},
+			expectError: "rate_limit.provider.name is required",
+		},
+		{
+			name: "rate limit without rate",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				RateLimit: &RateLimitConfig{Output: &RateLimitRule{Name: "partner-api"}},
+			},
+			expectError: "rate_limit.output.rate must be positive",
+		},
+		{
+			name: "bulkhead without limit",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Bulkhead:  &BulkheadConfig{Provider: &BulkheadRule{Name: "warehouse-db"}},
+			},
+			expectError: "bulkhead.provider.max_concurrent must be positive",
+		},
+		{
+			name: "bulkhead with invalid max wait",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Bulkhead:  &BulkheadConfig{Output: &BulkheadRule{Name: "s3", MaxConcurrent: 2, MaxWait: "soon"}},
+			},
+			expectError: "bulkhead.output.max_wait is invalid",
+		},
+		{
+			name: "timeout with invalid duration",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Timeouts:  &TimeoutConfig{Fetch: "-1m"},
+			},
+			expectError: "timeouts.fetch must be a positive duration",
+		},
+		{
+			name: "timeout warning fraction out of range",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Timeouts:  &TimeoutConfig{Run: "15m", WarnAt: 1.5},
+			},
+			expectError: "timeouts.warn_at must be at least 0 and less than 1",
+		},
+		{
+			name: "fallback output without type",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Fallback:  &FallbackConfig{Outputs: []OutputConfig{{Type: " "}}},
+			},
+			expectError: "fallback.outputs[0].type is required",
+		},
+		{
+			name: "spool without dir",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Spool:     &SpoolConfig{MaxRetries: 3},
+			},
+			expectError: "spool.dir is required",
+		},
+		{
+			name: "spool with invalid delay",
+			config: Config{
+				Provider:  ProviderConfig{Type: "mock"},
+				Formatter: FormatterConfig{Type: "json"},
+				Output:    OutputConfig{Type: "console"},
+				Spool:     &SpoolConfig{Dir: "spool", BaseDelay: "soon"},
+			},
+			expectError: "spool.base_delay",
+		},
 		{
 			name: "multiple validation errors",
 			config: Config{
@@ -426,3 +638,71 @@ func BenchmarkConfigValidate(b *testing.B) {
 		_ = config.Validate()
 	}
 }
+
+// TestSpoolConfigRetryPolicy tests spool retry defaults and overrides
+func TestSpoolConfigRetryPolicy(t *testing.T) {
+	got := SpoolConfig{Dir: "spool"}.RetryPolicy()
+	if got.MaxRetries != 5 || got.BaseDelay != time.Second || got.MaxDelay != time.Minute || got.Factor != 2 || !got.Jitter {
+		t.Errorf("default RetryPolicy() = %+v", got)
+	}
+
+	got = SpoolConfig{Dir: "spool", MaxRetries: 2, BaseDelay: "5s", MaxDelay: "5m", Factor: 3}.RetryPolicy()
+	if got.MaxRetries != 2 || got.BaseDelay != 5*time.Second || got.MaxDelay != 5*time.Minute || got.Factor != 3 {
+		t.Errorf("RetryPolicy() = %+v", got)
+	}
+}
+
+// TestValidatePositions tests that problems name the position of their field
+func TestValidatePositions(t *testing.T) {
+	config := Config{
+		Provider:   ProviderConfig{Type: "mock"},
+		Processors: []ProcessorConfig{{Type: "filter"}, {Type: " "}},
+		Formatter:  FormatterConfig{Type: "json"},
+		Positions: Positions{
+			"provider":           {Line: 1, Column: 1},
+			"processors":         {Line: 3, Column: 1},
+			"processors[1]":      {Line: 5, Column: 5},
+			"processors[1].type": {Line: 5, Column: 7},
+			"formatter":          {Line: 6, Column: 1},
+			"formatter.type":     {Line: 7, Column: 3},
+		},
+	}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate() should fail")
+	}
+	for _, want := range []string{
+		"processor[1].type cannot be empty or whitespace (line 5, column 7)",
+		// A missing field has no position of its own, nor an enclosing one
+		"output.type is required;",
+	} {
+		if !strings.Contains(err.Error()+";", want) {
+			t.Errorf("Validate() error = %v, want %q", err, want)
+		}
+	}
+}
+
+// TestPositionsLocate tests that missing fields fall back to their parents
+func TestPositionsLocate(t *testing.T) {
+	positions := Positions{
+		"output":             {Line: 4, Column: 1},
+		"processors[0]":      {Line: 2, Column: 5},
+		"processors[0].type": {}, // a generated node without a position
+	}
+	tests := []struct {
+		path string
+		want Position
+		ok   bool
+	}{
+		{"output", Position{Line: 4, Column: 1}, true},
+		{"output.params.path", Position{Line: 4, Column: 1}, true},
+		{"processors[0].type", Position{Line: 2, Column: 5}, true},
+		{"spool.dir", Position{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := positions.Locate(tt.path); got != tt.want || ok != tt.ok {
+			t.Errorf("Locate(%q) = %v, %v; want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
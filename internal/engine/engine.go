@@ -15,9 +15,12 @@ import (
 	"github.com/AshishBagdane/go-report-engine/internal/health"
 	"github.com/AshishBagdane/go-report-engine/internal/logging"
 	"github.com/AshishBagdane/go-report-engine/internal/memory"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
 )
 
 // ReportEngine orchestrates the report generation pipeline with structured logging.
@@ -48,6 +51,35 @@ type ReportEngine struct {
 	// Default: 1000
 	ChunkSize int
 
+	// recorder receives the result of every Execute, if set
+	recorder ResultRecorder
+
+	// tracer wraps every Execute in a run span, if set
+	tracer observability.Tracer
+
+	// metrics receives streaming chunk and throughput metrics, if set
+	metrics observability.MetricsCollector
+
+	// breaker protects the provider and output, if set; Health reports
+	// its state
+	breaker health.Checker
+
+	// watermarks stores incremental-run watermarks under watermarkKey
+	watermarks   state.Store
+	watermarkKey string
+
+	// checkpoints stores streaming progress under checkpointKey
+	checkpoints   state.Store
+	checkpointKey string
+
+	// readOnlyState keeps runs from saving watermarks and checkpoints
+	readOnlyState bool
+
+	// timeouts bounds the run and its stages; deadlineHook is called when
+	// one passes its soft deadline
+	timeouts     Timeouts
+	deadlineHook DeadlineHook
+
 	// closeOnce ensures cleanup is performed exactly once
 	closeOnce sync.Once
 }
@@ -81,6 +113,14 @@ func (r *ReportEngine) WithLogger(logger *logging.Logger) *ReportEngine {
 	return r
 }
 
+// WithResultRecorder sets a recorder that receives the RunResult of every
+// run, for example to persist it for dashboards. Recorder errors are
+// logged and do not fail the run.
+func (r *ReportEngine) WithResultRecorder(recorder ResultRecorder) *ReportEngine {
+	r.recorder = recorder
+	return r
+}
+
 // getLogger returns the engine's logger, creating a default one if needed.
 func (r *ReportEngine) getLogger() *logging.Logger {
 	if r.logger == nil {
@@ -138,8 +178,88 @@ func (r *ReportEngine) Run() error {
 //	    logger.ErrorContext(ctx, "pipeline failed", "error", err)
 //	}
 func (r *ReportEngine) RunWithContext(ctx context.Context) error {
+	_, err := r.Execute(ctx)
+	return err
+}
+
+// Execute runs the pipeline like RunWithContext and returns a RunResult
+// describing what the run did: per-stage timings, records in and out of
+// every processor, bytes emitted, warnings and the final status. The
+// result is returned on failure too, with the error details filled in.
+//
+// The run ID is the context request ID if set; otherwise one is generated
+// and added to the context so log lines can be correlated with the result.
+// If a ResultRecorder is configured, the result is passed to it before
+// Execute returns. If a tracer is configured, the run is wrapped in a
+// "report.run" span (see WithTracer).
+//
+// Example:
+//
+//	res, err := engine.Execute(ctx)
+//	fmt.Printf("run %s %s: %d in, %d out, %d bytes\n",
+//	    res.RunID, res.Status, res.RecordsIn, res.RecordsOut, res.BytesWritten)
+func (r *ReportEngine) Execute(ctx context.Context) (*RunResult, error) {
+	res := &RunResult{
+		RunID:     logging.GetRequestID(ctx),
+		Mode:      ModeBatch,
+		StartedAt: time.Now(),
+	}
+	if res.RunID == "" {
+		res.RunID = newRunID()
+		ctx = logging.WithRequestID(ctx, res.RunID)
+	}
+	if located, ok := r.Output.(output.LocatedOutput); ok {
+		res.OutputLocation = located.Location()
+	}
+	stats := processor.NewChainStats()
+	ctx = processor.WithChainStats(ctx, stats)
+	sources := resilience.NewFallbackRecorder()
+	ctx = resilience.WithFallbackRecorder(ctx, sources)
+	ctx, span := r.startRunSpan(ctx)
+
+	runCtx, endRun := r.withDeadline(ctx, DeadlineRun, r.timeouts.Run)
+	stage, err := r.execute(runCtx, res)
+	err = endRun(stage, err)
+	res.addSources(sources.Uses())
+	res.finish(ctx, stage, err, stats)
+	endRunSpan(span, res, err)
+
+	if r.recorder != nil {
+		if recErr := r.recorder.RecordResult(ctx, res); recErr != nil {
+			r.getLogger().WarnContext(ctx, "failed to record run result", "error", recErr)
+		}
+	}
+	return res, err
+}
+
+// execute runs the pipeline, filling in res, and returns the stage that
+// failed along with the error. For incremental runs the watermark is only
+// advanced once the whole pipeline has succeeded, and provider
+// acknowledgements (see provider.Acks) are only committed then.
+func (r *ReportEngine) execute(ctx context.Context, res *RunResult) (string, error) {
+	ctx, err := r.beginWatermark(ctx, res)
+	if err != nil {
+		return StageFetch, err
+	}
+
+	acks := provider.NewAcks()
+	stage, err := r.runPipeline(provider.WithAcks(ctx, acks), res)
+	r.settleAcks(ctx, res, acks, err == nil)
+	if err == nil {
+		r.commitWatermark(ctx, res)
+	} else if res.Watermark != nil {
+		r.getLogger().WarnContext(ctx, "run failed, watermark not advanced",
+			"watermark_key", res.Watermark.Key,
+			"watermark", res.Watermark.Previous,
+		)
+	}
+	return stage, err
+}
+
+// runPipeline runs the batch or streaming pipeline.
+func (r *ReportEngine) runPipeline(ctx context.Context, res *RunResult) (string, error) {
 	logger := r.getLogger()
-	startTime := time.Now()
+	startTime := res.StartedAt
 
 	logger.InfoContext(ctx, "engine starting",
 		"stage", "validation",
@@ -151,7 +271,7 @@ func (r *ReportEngine) RunWithContext(ctx context.Context) error {
 			"error", err,
 			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
-		return err
+		return StageValidate, err
 	}
 
 	logger.DebugContext(ctx, "engine validation passed")
@@ -163,55 +283,90 @@ func (r *ReportEngine) RunWithContext(ctx context.Context) error {
 
 	if okProvider && okFormatter && okOutput {
 		logger.InfoContext(ctx, "executing streaming pipeline")
-		return r.runStreamingPipeline(ctx, streamingProvider, streamingFormatter, streamingOutput)
+		res.Mode = ModeStreaming
+		return r.runStreamingPipeline(ctx, res, streamingProvider, streamingFormatter, streamingOutput)
 	}
 
 	logger.InfoContext(ctx, "executing batch pipeline")
+	if r.checkpoints != nil && !r.readOnlyState {
+		logger.WarnContext(ctx, "checkpoints configured but the run is not streaming",
+			"checkpoint_key", r.checkpointKey,
+		)
+		res.warn("checkpoint %q not kept: checkpoints only apply to streaming runs", r.checkpointKey)
+	}
 
 	// Stage 1: Fetch data from provider
-	data, err := r.fetchDataWithContext(ctx)
-	if err != nil {
+	stageCtx, endStage := r.withDeadline(ctx, StageFetch, r.timeouts.Fetch)
+	data, err := r.fetchDataWithContext(stageCtx, res)
+	if err = endStage(StageFetch, err); err != nil {
 		logger.ErrorContext(ctx, "pipeline failed at fetch stage",
 			"error", err,
 			"stage", "fetch",
 			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
-		return err
+		return StageFetch, err
 	}
+	res.Watermark.observe(data)
 
 	// Stage 2: Process data through processor chain
-	processed, err := r.processDataWithContext(ctx, data)
-	if err != nil {
+	stageCtx, endStage = r.withDeadline(ctx, StageProcess, r.timeouts.Process)
+	processed, err := r.processDataWithContext(stageCtx, res, data)
+	if err = endStage(StageProcess, err); err != nil {
 		logger.ErrorContext(ctx, "pipeline failed at process stage",
 			"error", err,
 			"stage", "process",
 			"input_records", len(data),
 			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
-		return err
+		return StageProcess, err
+	}
+
+	// Record outputs consume structured data directly, so the format stage is skipped
+	if recordOutput, ok := r.Output.(output.RecordOutputStrategy); ok {
+		stageCtx, endStage = r.withDeadline(ctx, StageOutput, r.timeouts.Output)
+		err := r.outputRecordsWithContext(stageCtx, res, recordOutput, processed)
+		if err = endStage(StageOutput, err); err != nil {
+			logger.ErrorContext(ctx, "pipeline failed at output stage",
+				"error", err,
+				"stage", "output",
+				"record_count", len(processed),
+				"duration_ms", time.Since(startTime).Milliseconds(),
+			)
+			return StageOutput, err
+		}
+
+		logger.InfoContext(ctx, "engine completed successfully",
+			"total_duration_ms", time.Since(startTime).Milliseconds(),
+			"input_records", len(data),
+			"output_records", len(processed),
+		)
+		return "", nil
 	}
 
 	// Stage 3: Format data
-	formatted, err := r.formatDataWithContext(ctx, processed)
-	if err != nil {
+	stageCtx, endStage = r.withDeadline(ctx, StageFormat, r.timeouts.Format)
+	formatted, err := r.formatDataWithContext(stageCtx, res, processed)
+	if err = endStage(StageFormat, err); err != nil {
 		logger.ErrorContext(ctx, "pipeline failed at format stage",
 			"error", err,
 			"stage", "format",
 			"record_count", len(processed),
 			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
-		return err
+		return StageFormat, err
 	}
 
 	// Stage 4: Output data
-	if err := r.outputDataWithContext(ctx, formatted); err != nil {
+	stageCtx, endStage = r.withDeadline(ctx, StageOutput, r.timeouts.Output)
+	err = r.outputDataWithContext(stageCtx, res, formatted, len(processed))
+	if err = endStage(StageOutput, err); err != nil {
 		logger.ErrorContext(ctx, "pipeline failed at output stage",
 			"error", err,
 			"stage", "output",
 			"data_size_bytes", len(formatted),
 			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
-		return err
+		return StageOutput, err
 	}
 
 	// Success - log completion metrics
@@ -223,7 +378,22 @@ func (r *ReportEngine) RunWithContext(ctx context.Context) error {
 		"output_size_bytes", len(formatted),
 	)
 
-	return nil
+	return "", nil
+}
+
+// settleAcks commits the provider acknowledgements of a run that
+// delivered its report, and releases them otherwise. Runs with read-only
+// state never commit. Failures are recorded as warnings: the report has
+// already been delivered, and uncommitted data is delivered again.
+func (r *ReportEngine) settleAcks(ctx context.Context, res *RunResult, acks *provider.Acks, delivered bool) {
+	err := acks.Settle(context.WithoutCancel(ctx), delivered && !r.readOnlyState)
+	if err == nil {
+		return
+	}
+	r.getLogger().WarnContext(ctx, "failed to settle provider acknowledgements", "delivered", delivered, "error", err)
+	if delivered {
+		res.warn("provider acknowledgement failed, the next run may fetch the same data again: %v", err)
+	}
 }
 
 // validate ensures all required components are present.
@@ -261,7 +431,7 @@ func (r *ReportEngine) validate() error {
 }
 
 // fetchDataWithContext retrieves raw data from the provider with logging.
-func (r *ReportEngine) fetchDataWithContext(ctx context.Context) ([]map[string]interface{}, error) {
+func (r *ReportEngine) fetchDataWithContext(ctx context.Context, res *RunResult) ([]map[string]interface{}, error) {
 	logger := r.getLogger()
 	startTime := time.Now()
 
@@ -270,6 +440,8 @@ func (r *ReportEngine) fetchDataWithContext(ctx context.Context) ([]map[string]i
 	// UPDATED: Pass context to provider
 	data, err := r.Provider.Fetch(ctx)
 	duration := time.Since(startTime)
+	res.addStage(StageResult{Name: StageFetch, Duration: duration, RecordsOut: len(data)})
+	res.RecordsIn = len(data)
 
 	if err != nil {
 		logger.ErrorContext(ctx, "fetch stage failed",
@@ -297,13 +469,14 @@ func (r *ReportEngine) fetchDataWithContext(ctx context.Context) ([]map[string]i
 	// Log warning if no records fetched
 	if recordCount == 0 {
 		logger.WarnContext(ctx, "provider returned zero records")
+		res.warn("provider returned zero records")
 	}
 
 	return data, nil
 }
 
 // processDataWithContext runs data through the processor chain with logging.
-func (r *ReportEngine) processDataWithContext(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
+func (r *ReportEngine) processDataWithContext(ctx context.Context, res *RunResult, data []map[string]interface{}) ([]map[string]interface{}, error) {
 	logger := r.getLogger()
 	startTime := time.Now()
 	inputCount := len(data)
@@ -315,6 +488,7 @@ func (r *ReportEngine) processDataWithContext(ctx context.Context, data []map[st
 	// UPDATED: Pass context to processor
 	processed, err := r.Processor.Process(ctx, data)
 	duration := time.Since(startTime)
+	res.addStage(StageResult{Name: StageProcess, Duration: duration, RecordsIn: inputCount, RecordsOut: len(processed)})
 
 	if err != nil {
 		logger.ErrorContext(ctx, "process stage failed",
@@ -354,13 +528,14 @@ func (r *ReportEngine) processDataWithContext(ctx context.Context, data []map[st
 		logger.WarnContext(ctx, "all records filtered by processor",
 			"input_records", inputCount,
 		)
+		res.warn("all %d records filtered by processor", inputCount)
 	}
 
 	return processed, nil
 }
 
 // formatDataWithContext converts processed data into the desired output format with logging.
-func (r *ReportEngine) formatDataWithContext(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+func (r *ReportEngine) formatDataWithContext(ctx context.Context, res *RunResult, data []map[string]interface{}) ([]byte, error) {
 	logger := r.getLogger()
 	startTime := time.Now()
 	recordCount := len(data)
@@ -372,6 +547,7 @@ func (r *ReportEngine) formatDataWithContext(ctx context.Context, data []map[str
 	// UPDATED: Pass context to formatter
 	formatted, err := r.Formatter.Format(ctx, data)
 	duration := time.Since(startTime)
+	res.addStage(StageResult{Name: StageFormat, Duration: duration, RecordsIn: recordCount, RecordsOut: recordCount, Bytes: int64(len(formatted))})
 
 	if err != nil {
 		logger.ErrorContext(ctx, "format stage failed",
@@ -419,7 +595,7 @@ func (r *ReportEngine) formatDataWithContext(ctx context.Context, data []map[str
 }
 
 // outputDataWithContext sends formatted data to the output destination with logging.
-func (r *ReportEngine) outputDataWithContext(ctx context.Context, data []byte) error {
+func (r *ReportEngine) outputDataWithContext(ctx context.Context, res *RunResult, data []byte, recordCount int) error {
 	logger := r.getLogger()
 	startTime := time.Now()
 	dataSize := len(data)
@@ -432,6 +608,14 @@ func (r *ReportEngine) outputDataWithContext(ctx context.Context, data []byte) e
 	err := r.Output.Send(ctx, data)
 	duration := time.Since(startTime)
 
+	stageResult := StageResult{Name: StageOutput, Duration: duration, RecordsIn: recordCount, Bytes: int64(dataSize)}
+	if err == nil {
+		stageResult.RecordsOut = recordCount
+		res.RecordsOut = recordCount
+		res.BytesWritten = int64(dataSize)
+	}
+	res.addStage(stageResult)
+
 	if err != nil {
 		logger.ErrorContext(ctx, "output stage failed",
 			"error", err,
@@ -453,6 +637,45 @@ func (r *ReportEngine) outputDataWithContext(ctx context.Context, data []byte) e
 	return nil
 }
 
+// outputRecordsWithContext sends processed records to a record output with logging.
+func (r *ReportEngine) outputRecordsWithContext(ctx context.Context, res *RunResult, out output.RecordOutputStrategy, records []map[string]interface{}) error {
+	logger := r.getLogger()
+	startTime := time.Now()
+	recordCount := len(records)
+
+	logger.InfoContext(ctx, "output stage starting",
+		"record_count", recordCount,
+	)
+
+	err := out.SendRecords(ctx, records)
+	duration := time.Since(startTime)
+
+	stageResult := StageResult{Name: StageOutput, Duration: duration, RecordsIn: recordCount}
+	if err == nil {
+		stageResult.RecordsOut = recordCount
+		res.RecordsOut = recordCount
+	}
+	res.addStage(stageResult)
+
+	if err != nil {
+		logger.ErrorContext(ctx, "output stage failed",
+			"error", err,
+			"record_count", recordCount,
+			"duration_ms", duration.Milliseconds(),
+		)
+		return errors.NewErrorContext(errors.ComponentEngine, "output_stage").
+			WithContext("record_count", recordCount).
+			Wrap(err)
+	}
+
+	logger.InfoContext(ctx, "output stage completed",
+		"duration_ms", duration.Milliseconds(),
+		"record_count", recordCount,
+	)
+
+	return nil
+}
+
 // RunWithRecovery executes the pipeline with panic recovery and logging.
 // This is useful in production environments where you want to convert
 // panics into errors rather than crashing the application.
@@ -496,7 +719,9 @@ func (r *ReportEngine) RunWithRecoveryContext(ctx context.Context) (err error) {
 	return r.RunWithContext(ctx)
 }
 
-// Health checks the health of the engine and its components.
+// Health checks the health of the engine and its components. When the
+// engine was built with a circuit breaker, its state is reported under
+// "circuit_breaker", DEGRADED while the breaker is open or half-open.
 func (r *ReportEngine) Health(ctx context.Context) map[string]health.Result {
 	results := make(map[string]health.Result)
 
@@ -534,9 +759,30 @@ func (r *ReportEngine) Health(ctx context.Context) map[string]health.Result {
 		results["processor"] = res
 	}
 
+	// Check Circuit Breaker
+	if r.breaker != nil {
+		res, err := r.breaker.CheckHealth(ctx)
+		if err != nil && res.Status == "" {
+			res.Status = health.StatusDown
+			res.Error = err.Error()
+		}
+		results["circuit_breaker"] = res
+	}
+
 	return results
 }
 
+// CheckHealth implements health.Checker by aggregating the component
+// results from Health, so an engine can be registered with a
+// health.Composite. The error is non-nil when any component is down.
+func (r *ReportEngine) CheckHealth(ctx context.Context) (health.Result, error) {
+	res := health.Aggregate(r.Health(ctx))
+	if res.Status == health.StatusDown {
+		return res, fmt.Errorf("engine: %s", res.Error)
+	}
+	return res, nil
+}
+
 // max returns the maximum of two integers (helper for Go versions < 1.21)
 func max(a, b int) int {
 	if a > b {
@@ -545,101 +791,243 @@ func max(a, b int) int {
 	return b
 }
 
-// runStreamingPipeline executes the pipeline in streaming mode.
+// streamTotals accumulates per-stage figures over the chunks of a
+// streaming run.
+type streamTotals struct {
+	fetch, process, format, output StageResult
+
+	// Budgets bound the stage durations above by the stage timeouts
+	fetchBudget, processBudget, formatBudget, outputBudget *stageBudget
+}
+
+// newStreamTotals creates empty totals whose budgets follow the engine's
+// stage timeouts.
+func (r *ReportEngine) newStreamTotals() *streamTotals {
+	totals := &streamTotals{
+		fetch:   StageResult{Name: StageFetch},
+		process: StageResult{Name: StageProcess},
+		format:  StageResult{Name: StageFormat},
+		output:  StageResult{Name: StageOutput},
+	}
+	totals.fetchBudget = r.newStageBudget(StageFetch, r.timeouts.Fetch, &totals.fetch.Duration)
+	totals.processBudget = r.newStageBudget(StageProcess, r.timeouts.Process, &totals.process.Duration)
+	totals.formatBudget = r.newStageBudget(StageFormat, r.timeouts.Format, &totals.format.Duration)
+	totals.outputBudget = r.newStageBudget(StageOutput, r.timeouts.Output, &totals.output.Duration)
+	return totals
+}
+
+// timed adds the duration of fn to d.
+func timed(d *time.Duration, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	*d += time.Since(start)
+	return err
+}
+
+// runStreamingPipeline executes the pipeline in streaming mode and returns
+// the stage that failed along with the error. Stage timeouts bound the
+// total time of each stage over all chunks.
 func (r *ReportEngine) runStreamingPipeline(
 	ctx context.Context,
+	res *RunResult,
 	prov provider.StreamingProviderStrategy,
 	fmttr formatter.StreamingFormatterStrategy,
 	out output.StreamingOutputStrategy,
-) error {
+) (stage string, runErr error) {
 	logger := r.getLogger()
 	startTime := time.Now()
 
-	// Initialize output
-	if err := out.Initialize(ctx); err != nil {
+	totals := r.newStreamTotals()
+	defer func() {
+		if runErr == nil {
+			totals.output.RecordsOut = totals.output.RecordsIn
+			res.RecordsOut = totals.output.RecordsIn
+		}
+		res.RecordsIn = totals.fetch.RecordsOut
+		res.BytesWritten = totals.output.Bytes
+		res.addStage(totals.fetch)
+		res.addStage(totals.process)
+		res.addStage(totals.format)
+		res.addStage(totals.output)
+		r.recordThroughput(res, time.Since(startTime))
+	}()
+
+	// Checkpoints are kept only if both ends of the stream can resume
+	ck := r.newCheckpointer(ctx, res, prov, out)
+
+	// Initialize output, or reopen it after the last committed chunk
+	err := totals.outputBudget.run(ctx, func(ctx context.Context) error { return ck.openOutput(ctx, r, res, out) })
+	if err != nil {
 		logger.ErrorContext(ctx, "streaming: output initialization failed", "error", err)
-		return errors.NewErrorContext(errors.ComponentOutput, "initialize").Wrap(err)
+		return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "initialize").Wrap(err))
 	}
+	// closed is set once the output has been finalized on success
+	closed := false
 	defer func() {
-		if err := out.Close(ctx); err != nil {
+		if closed {
+			return
+		}
+		// Discard partial output on failure if the output supports it,
+		// unless it is kept for resuming from a checkpoint
+		if abortable, ok := out.(output.AbortableOutput); ok && ck == nil {
+			if err := abortable.Abort(ctx); err != nil {
+				logger.WarnContext(ctx, "streaming: output abort failed", "error", err)
+				res.warn("output abort failed: %v", err)
+			}
+			return
+		}
+		if err := timed(&totals.output.Duration, func() error { return out.Close(ctx) }); err != nil {
 			logger.WarnContext(ctx, "streaming: output close failed", "error", err)
+			res.warn("output close failed: %v", err)
 		}
 	}()
 
 	// Start stream
-	iterator, err := prov.Stream(ctx)
+	streamCtx := totals.fetchBudget.stream(ctx)
+	defer totals.fetchBudget.stop()
+	var iterator provider.Iterator
+	err = totals.fetchBudget.track(func() (err error) {
+		iterator, err = ck.openStream(streamCtx, res, prov)
+		return err
+	})
 	if err != nil {
 		logger.ErrorContext(ctx, "streaming: provider stream failed", "error", err)
-		return errors.NewErrorContext(errors.ComponentProvider, "stream").Wrap(err)
+		return StageFetch, totals.fetchBudget.check(errors.NewErrorContext(errors.ComponentProvider, "stream").Wrap(err))
 	}
 	defer func() {
 		if err := iterator.Close(); err != nil {
 			logger.WarnContext(ctx, "streaming: iterator close failed", "error", err)
+			res.warn("iterator close failed: %v", err)
 		}
 	}()
 
-	// Format Start
-	startBytes, err := fmttr.FormatStart(ctx)
-	if err != nil {
-		return errors.NewErrorContext(errors.ComponentFormatter, "format_start").Wrap(err)
-	}
-	if err := out.WriteChunk(ctx, startBytes); err != nil {
-		return errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err)
+	// Format Start; a resumed stream already has its start and earlier chunks
+	isFirstChunk := true
+	if ck.resumed() {
+		isFirstChunk = ck.base.RecordsOut == 0
+		logger.InfoContext(ctx, "streaming: resuming from checkpoint",
+			"checkpoint_run_id", ck.base.RunID,
+			"records", ck.base.Records,
+			"bytes", ck.base.Bytes,
+		)
+	} else {
+		var startBytes []byte
+		err := totals.formatBudget.run(ctx, func(ctx context.Context) (err error) {
+			startBytes, err = fmttr.FormatStart(ctx)
+			return err
+		})
+		if err != nil {
+			return StageFormat, totals.formatBudget.check(errors.NewErrorContext(errors.ComponentFormatter, "format_start").Wrap(err))
+		}
+		if err := r.writeChunk(ctx, out, totals, startBytes); err != nil {
+			return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err))
+		}
 	}
 
 	chunkSize := r.getChunkSize()
 	buffer := make([]map[string]interface{}, 0, chunkSize)
-	totalRecords := 0
-	isFirstChunk := true
 
-	// Iterate
-	for iterator.Next() {
-		buffer = append(buffer, iterator.Value())
+	// Iterate; wait is the time spent filling the current chunk
+	var wait time.Duration
+	for {
+		var hasNext bool
+		fetchStart := totals.fetch.Duration
+		err := totals.fetchBudget.track(func() error {
+			if hasNext = iterator.Next(); hasNext {
+				buffer = append(buffer, iterator.Value())
+			}
+			return nil
+		})
+		if err != nil {
+			return StageFetch, totals.fetchBudget.check(errors.NewErrorContext(errors.ComponentProvider, "iterate").Wrap(err))
+		}
+		wait += totals.fetch.Duration - fetchStart
+		if !hasNext {
+			break
+		}
 
 		if len(buffer) >= chunkSize {
-			if err := r.processAndWriteChunk(ctx, buffer, fmttr, out, &isFirstChunk); err != nil {
-				return err
+			if stage, err := r.processAndWriteChunk(ctx, res, totals, buffer, wait, fmttr, out, &isFirstChunk); err != nil {
+				return stage, err
 			}
-			totalRecords += len(buffer)
+			ck.save(ctx, r, res, totals)
 			buffer = buffer[:0]
+			wait = 0
 		}
 	}
 	if err := iterator.Err(); err != nil {
 		logger.ErrorContext(ctx, "streaming: iterator error", "error", err)
-		return errors.NewErrorContext(errors.ComponentProvider, "iterate").Wrap(err)
+		return StageFetch, totals.fetchBudget.check(errors.NewErrorContext(errors.ComponentProvider, "iterate").Wrap(err))
 	}
 
 	// Process remaining
 	if len(buffer) > 0 {
-		if err := r.processAndWriteChunk(ctx, buffer, fmttr, out, &isFirstChunk); err != nil {
-			return err
+		if stage, err := r.processAndWriteChunk(ctx, res, totals, buffer, wait, fmttr, out, &isFirstChunk); err != nil {
+			return stage, err
 		}
-		totalRecords += len(buffer)
+		ck.save(ctx, r, res, totals)
 	}
 
 	// Format End
-	endBytes, err := fmttr.FormatEnd(ctx)
+	var endBytes []byte
+	err = totals.formatBudget.run(ctx, func(ctx context.Context) (err error) {
+		endBytes, err = fmttr.FormatEnd(ctx)
+		return err
+	})
 	if err != nil {
-		return errors.NewErrorContext(errors.ComponentFormatter, "format_end").Wrap(err)
+		return StageFormat, totals.formatBudget.check(errors.NewErrorContext(errors.ComponentFormatter, "format_end").Wrap(err))
 	}
-	if err := out.WriteChunk(ctx, endBytes); err != nil {
-		return errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err)
+	if err := r.writeChunk(ctx, out, totals, endBytes); err != nil {
+		return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err))
+	}
+
+	// The report is only delivered once the output is finalized, e.g. an
+	// upload renamed into place, so a failed Close fails the run
+	closed = true
+	if err := totals.outputBudget.run(ctx, out.Close); err != nil {
+		logger.ErrorContext(ctx, "streaming: output close failed", "error", err)
+		return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "close").Wrap(err))
+	}
+	ck.clear(ctx, r, res)
+
+	if totals.fetch.RecordsOut == 0 {
+		logger.WarnContext(ctx, "provider returned zero records")
+		res.warn("provider returned zero records")
+	} else if totals.process.RecordsOut == 0 {
+		res.warn("all %d records filtered by processor", totals.fetch.RecordsOut)
 	}
 
 	logger.InfoContext(ctx, "streaming pipeline completed",
-		"total_records", totalRecords,
+		"total_records", totals.fetch.RecordsOut,
+		"output_records", totals.process.RecordsOut,
+		"chunks", res.Chunks,
+		"bytes_written", totals.output.Bytes,
 		"duration_ms", time.Since(startTime).Milliseconds(),
 	)
-	return nil
+	return "", nil
 }
 
+// writeChunk writes bytes to the output and adds them to the totals.
+func (r *ReportEngine) writeChunk(ctx context.Context, out output.StreamingOutputStrategy, totals *streamTotals, data []byte) error {
+	err := totals.outputBudget.run(ctx, func(ctx context.Context) error { return out.WriteChunk(ctx, data) })
+	if err == nil {
+		totals.output.Bytes += int64(len(data))
+	}
+	return err
+}
+
+// processAndWriteChunk processes, formats and writes one chunk. wait is
+// the time the iterator took to produce the chunk.
 func (r *ReportEngine) processAndWriteChunk(
 	ctx context.Context,
+	res *RunResult,
+	totals *streamTotals,
 	chunk []map[string]interface{},
+	wait time.Duration,
 	fmttr formatter.StreamingFormatterStrategy,
 	out output.StreamingOutputStrategy,
 	isFirstChunk *bool,
-) error {
+) (stage string, chunkErr error) {
 	// Release maps back to pool after processing
 	defer func() {
 		for _, m := range chunk {
@@ -647,32 +1035,53 @@ func (r *ReportEngine) processAndWriteChunk(
 		}
 	}()
 
+	ctx, trace := r.startChunk(ctx, res.Chunks, len(chunk), wait, totals)
+	defer func() { r.endChunk(trace, totals, chunkErr) }()
+
+	res.Chunks++
+	res.Watermark.observe(chunk)
+	totals.fetch.RecordsOut += len(chunk)
+	totals.process.RecordsIn += len(chunk)
+
 	// Process
-	processed, err := r.Processor.Process(ctx, chunk)
+	var processed []map[string]interface{}
+	err := totals.processBudget.run(ctx, func(ctx context.Context) (err error) {
+		processed, err = r.Processor.Process(ctx, chunk)
+		return err
+	})
 	if err != nil {
-		return errors.NewErrorContext(errors.ComponentProcessor, "process_chunk").Wrap(err)
+		return StageProcess, totals.processBudget.check(errors.NewErrorContext(errors.ComponentProcessor, "process_chunk").Wrap(err))
 	}
+	totals.process.RecordsOut += len(processed)
 	if len(processed) == 0 {
-		return nil
+		return "", nil
 	}
 
 	// Delimiter
 	if !*isFirstChunk {
-		if err := out.WriteChunk(ctx, []byte(",")); err != nil {
-			return errors.NewErrorContext(errors.ComponentOutput, "write_delimiter").Wrap(err)
+		if err := r.writeChunk(ctx, out, totals, []byte(",")); err != nil {
+			return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "write_delimiter").Wrap(err))
 		}
 	}
 	*isFirstChunk = false
 
 	// Format
-	bytes, err := fmttr.FormatChunk(ctx, processed)
+	var bytes []byte
+	err = totals.formatBudget.run(ctx, func(ctx context.Context) (err error) {
+		bytes, err = fmttr.FormatChunk(ctx, processed)
+		return err
+	})
 	if err != nil {
-		return errors.NewErrorContext(errors.ComponentFormatter, "format_chunk").Wrap(err)
+		return StageFormat, totals.formatBudget.check(errors.NewErrorContext(errors.ComponentFormatter, "format_chunk").Wrap(err))
 	}
+	totals.format.RecordsIn += len(processed)
+	totals.format.RecordsOut += len(processed)
+	totals.format.Bytes += int64(len(bytes))
+	totals.output.RecordsIn += len(processed)
 
 	// Write
-	if err := out.WriteChunk(ctx, bytes); err != nil {
-		return errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err)
+	if err := r.writeChunk(ctx, out, totals, bytes); err != nil {
+		return StageOutput, totals.outputBudget.check(errors.NewErrorContext(errors.ComponentOutput, "write_chunk").Wrap(err))
 	}
-	return nil
+	return "", nil
 }
@@ -63,6 +63,20 @@ func (m *mockOutput) Send(ctx context.Context, data []byte) error {
 	return nil
 }
 
+// mockRecordOutput is a test output that consumes structured records
+type mockRecordOutput struct {
+	mockOutput
+	records []map[string]interface{}
+}
+
+func (m *mockRecordOutput) SendRecords(ctx context.Context, data []map[string]interface{}) error {
+	if m.shouldErr {
+		return m.err
+	}
+	m.records = data
+	return nil
+}
+
 // emptyFormatter is a test formatter that returns empty byte slice
 type emptyFormatter struct{}
 
@@ -285,6 +299,61 @@ func TestReportEngineRunOutputError(t *testing.T) {
 	}
 }
 
+// TestReportEngineRunRecordOutput tests that record outputs receive
+// processed records and bypass the formatter
+func TestReportEngineRunRecordOutput(t *testing.T) {
+	testData := []map[string]interface{}{
+		{"id": 1, "name": "test"},
+		{"id": 2, "name": "other"},
+	}
+
+	out := &mockRecordOutput{}
+	engine := &ReportEngine{
+		Provider:  &mockProvider{data: testData},
+		Processor: &mockProcessor{},
+		Formatter: &mockFormatter{shouldErr: true, err: fmt.Errorf("formatter should not be called")},
+		Output:    out,
+	}
+
+	if err := engine.Run(); err != nil {
+		t.Fatalf("Run() should succeed, got error: %v", err)
+	}
+
+	if len(out.records) != 2 {
+		t.Errorf("Output received %d records, want 2", len(out.records))
+	}
+	if out.received != nil {
+		t.Error("Send should not be called for record outputs")
+	}
+}
+
+// TestReportEngineRunRecordOutputError tests record output failure
+func TestReportEngineRunRecordOutputError(t *testing.T) {
+	testData := []map[string]interface{}{
+		{"id": 1, "name": "test"},
+	}
+
+	engine := &ReportEngine{
+		Provider:  &mockProvider{data: testData},
+		Processor: &mockProcessor{},
+		Formatter: &mockFormatter{},
+		Output:    &mockRecordOutput{mockOutput: mockOutput{shouldErr: true, err: fmt.Errorf("insert failed")}},
+	}
+
+	err := engine.Run()
+	if err == nil {
+		t.Fatal("Run() should fail when record output fails")
+	}
+
+	engineErr, ok := err.(*errors.EngineError)
+	if !ok {
+		t.Fatal("Error should be EngineError")
+	}
+	if engineErr.Context["record_count"] != 1 {
+		t.Errorf("Error context record_count = %v, want 1", engineErr.Context["record_count"])
+	}
+}
+
 // TestReportEngineFetchDataNilData tests nil data from provider
 func TestReportEngineFetchDataNilData(t *testing.T) {
 	engine := &ReportEngine{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/AshishBagdane/go-report-engine/internal/engine"
 	"github.com/AshishBagdane/go-report-engine/internal/formatter"
@@ -11,6 +12,7 @@ import (
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
 )
 
 // MockHealthyProvider implements health.Checker
@@ -79,3 +81,56 @@ func TestEngineHealth(t *testing.T) {
 		t.Error("Unexpected processor health result (BaseProcessor does not implement Checker)")
 	}
 }
+
+func TestEngineCheckHealth(t *testing.T) {
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(&MockHealthyProvider{}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&MockUnhealthyOutput{}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build engine: %v", err)
+	}
+
+	res, err := eng.CheckHealth(context.Background())
+	if err == nil {
+		t.Error("Expected error when output is down")
+	}
+	if res.Status != health.StatusDown {
+		t.Errorf("Expected DOWN, got %v", res.Status)
+	}
+
+	eng.Output = &output.ConsoleOutput{}
+	res, err = eng.CheckHealth(context.Background())
+	if err != nil || res.Status != health.StatusUp {
+		t.Errorf("Expected UP without error, got %v (%v)", res.Status, err)
+	}
+}
+
+func TestEngineHealthCircuitBreaker(t *testing.T) {
+	breaker := resilience.NewCircuitBreaker("partner-api", 1, time.Minute)
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(&MockHealthyProvider{}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&output.ConsoleOutput{}).
+		WithCircuitBreaker(breaker).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build engine: %v", err)
+	}
+
+	if res := eng.Health(context.Background())["circuit_breaker"]; res.Status != health.StatusUp {
+		t.Errorf("Expected a closed breaker to be UP, got %v", res.Status)
+	}
+
+	breaker.RecordFailure()
+	res, err := eng.CheckHealth(context.Background())
+	if err != nil {
+		t.Errorf("Expected an open breaker not to take the engine down, got %v", err)
+	}
+	if res.Status != health.StatusDegraded {
+		t.Errorf("Expected DEGRADED while the breaker is open, got %v", res.Status)
+	}
+}
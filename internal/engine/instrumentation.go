@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+)
+
+// streamTags are the metric tags of streaming chunk and throughput metrics.
+var streamTags = map[string]string{
+	"component": "engine",
+	"operation": "stream",
+}
+
+// WithTracer sets a tracer that wraps every Execute in a "report.run"
+// span. The span is a child of any span in the run context, such as one
+// extracted from an incoming request, so component spans started by the
+// tracing decorators nest beneath it. When the run ends the span records
+// the run ID, mode, status, record counts and bytes written, and the
+// failed stage and error on failure.
+//
+// EngineBuilder.WithTracer sets it along with the component decorators.
+func (r *ReportEngine) WithTracer(tracer observability.Tracer) *ReportEngine {
+	r.tracer = tracer
+	return r
+}
+
+// WithMetrics sets a collector for the streaming pipeline: per-chunk
+// duration, size and iterator wait time, and end-to-end records per
+// second. Component metrics come from the decorators that
+// EngineBuilder.WithMetrics also applies.
+func (r *ReportEngine) WithMetrics(collector observability.MetricsCollector) *ReportEngine {
+	r.metrics = collector
+	return r
+}
+
+// startRunSpan starts the run span, or returns a nil span if no tracer is
+// set.
+func (r *ReportEngine) startRunSpan(ctx context.Context) (context.Context, observability.Span) {
+	if r.tracer == nil {
+		return ctx, nil
+	}
+	return r.tracer.StartSpan(ctx, "report.run")
+}
+
+// endRunSpan records the outcome of the run on span and ends it.
+func endRunSpan(span observability.Span, res *RunResult, err error) {
+	if span == nil {
+		return
+	}
+	defer span.End()
+
+	span.SetTag("run_id", res.RunID)
+	span.SetTag("mode", res.Mode)
+	span.SetTag("status", string(res.Status))
+	observability.SetIntTag(span, "records_in", int64(res.RecordsIn))
+	observability.SetIntTag(span, "records_out", int64(res.RecordsOut))
+	observability.SetIntTag(span, "bytes_written", res.BytesWritten)
+	if res.Chunks > 0 {
+		observability.SetIntTag(span, "chunks", int64(res.Chunks))
+	}
+	if res.OutputLocation != "" {
+		span.SetTag("output_location", res.OutputLocation)
+	}
+	if res.Mode == ModeStreaming && res.Duration > 0 {
+		rate := float64(res.RecordsIn) / res.Duration.Seconds()
+		span.SetTag("records_per_second", strconv.FormatFloat(rate, 'f', 1, 64))
+	}
+	if err != nil {
+		span.SetTag("failed_stage", res.FailedStage)
+		span.SetTag("error_type", res.ErrorType)
+		span.RecordError(err)
+	}
+}
+
+// chunkTrace instruments one chunk of a streaming run.
+type chunkTrace struct {
+	span  observability.Span
+	start time.Time
+	index int
+	size  int
+	wait  time.Duration
+
+	// totals before the chunk, to derive what it produced
+	recordsOut int
+	bytes      int64
+}
+
+// startChunk starts instrumenting the chunk with the given index and
+// number of records. The returned context carries the chunk span, so
+// processor and formatter spans nest beneath it.
+func (r *ReportEngine) startChunk(ctx context.Context, index, size int, wait time.Duration, totals *streamTotals) (context.Context, *chunkTrace) {
+	c := &chunkTrace{
+		start:      time.Now(),
+		index:      index,
+		size:       size,
+		wait:       wait,
+		recordsOut: totals.process.RecordsOut,
+		bytes:      totals.output.Bytes,
+	}
+	if r.tracer != nil {
+		ctx, c.span = r.tracer.StartSpan(ctx, "report.chunk")
+	}
+	return ctx, c
+}
+
+// endChunk records the outcome of a chunk on its span and in metrics.
+func (r *ReportEngine) endChunk(c *chunkTrace, totals *streamTotals, err error) {
+	duration := time.Since(c.start)
+
+	if c.span != nil {
+		observability.SetIntTag(c.span, "chunk_index", int64(c.index))
+		observability.SetIntTag(c.span, "chunk_size", int64(c.size))
+		observability.SetIntTag(c.span, "records_out", int64(totals.process.RecordsOut-c.recordsOut))
+		observability.SetIntTag(c.span, "bytes_count", totals.output.Bytes-c.bytes)
+		observability.SetIntTag(c.span, "iterator_wait_us", c.wait.Microseconds())
+		if err != nil {
+			c.span.RecordError(err)
+		}
+		c.span.End()
+	}
+
+	if r.metrics != nil {
+		r.metrics.Count("report_engine_stream_chunks_total", 1, streamTags)
+		r.metrics.Histogram("report_engine_stream_chunk_duration_seconds", duration.Seconds(), streamTags)
+		r.metrics.Histogram("report_engine_stream_chunk_records", float64(c.size), streamTags)
+		r.metrics.Histogram("report_engine_stream_iterator_wait_seconds", c.wait.Seconds(), streamTags)
+		if err != nil {
+			r.metrics.Count("report_engine_stream_chunk_errors_total", 1, streamTags)
+		}
+	}
+}
+
+// recordThroughput records the end-to-end records per second of a
+// streaming run that took duration.
+func (r *ReportEngine) recordThroughput(res *RunResult, duration time.Duration) {
+	if r.metrics == nil || duration <= 0 {
+		return
+	}
+	r.metrics.Gauge("report_engine_stream_records_in_per_second", float64(res.RecordsIn)/duration.Seconds(), streamTags)
+	r.metrics.Gauge("report_engine_stream_records_out_per_second", float64(res.RecordsOut)/duration.Seconds(), streamTags)
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+)
+
+// ErrReloadableClosed is returned when running a ReloadableEngine after Close.
+var ErrReloadableClosed = errors.New("engine: reloadable engine closed")
+
+// ReloadableEngine holds the current engine of a long-running service and
+// lets it be replaced while runs are in progress. Each run uses the engine
+// that was current when it started: Swap only affects later runs, and a
+// replaced engine is closed once its last run has finished.
+//
+// Thread-safe: Yes.
+//
+// Example:
+//
+//	live := engine.NewReloadableEngine(eng)
+//	defer live.Close()
+//
+//	// On a config change:
+//	next, err := factory.NewEngineFromConfig(*cfg)
+//	if err == nil {
+//	    live.Swap(next)
+//	}
+//
+//	res, err := live.Execute(ctx) // always runs the latest engine
+type ReloadableEngine struct {
+	mu      sync.Mutex
+	current *generation
+	retired sync.WaitGroup
+	closed  bool
+}
+
+// generation is one engine and the runs using it.
+type generation struct {
+	engine *ReportEngine
+	runs   sync.WaitGroup
+}
+
+// NewReloadableEngine creates a ReloadableEngine starting with eng.
+func NewReloadableEngine(eng *ReportEngine) *ReloadableEngine {
+	return &ReloadableEngine{current: &generation{engine: eng}}
+}
+
+// Current returns the engine new runs will use.
+func (r *ReloadableEngine) Current() *ReportEngine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current.engine
+}
+
+// Swap makes eng the engine for subsequent runs. The previous engine is
+// closed in the background after the runs still using it finish. After
+// Close, Swap closes eng instead.
+func (r *ReloadableEngine) Swap(eng *ReportEngine) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		_ = eng.Close()
+		return
+	}
+	previous := r.current
+	r.current = &generation{engine: eng}
+	r.retired.Add(1)
+	r.mu.Unlock()
+
+	go func() {
+		defer r.retired.Done()
+		previous.runs.Wait()
+		if err := previous.engine.Close(); err != nil {
+			previous.engine.getLogger().Warn("failed to close replaced engine", "error", err)
+		}
+	}()
+}
+
+// acquire returns the current generation with a run registered on it.
+func (r *ReloadableEngine) acquire() (*generation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrReloadableClosed
+	}
+	g := r.current
+	g.runs.Add(1)
+	return g, nil
+}
+
+// Execute runs the current engine's pipeline. See ReportEngine.Execute.
+func (r *ReloadableEngine) Execute(ctx context.Context) (*RunResult, error) {
+	g, err := r.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer g.runs.Done()
+	return g.engine.Execute(ctx)
+}
+
+// RunWithContext runs the current engine's pipeline. See
+// ReportEngine.RunWithContext.
+func (r *ReloadableEngine) RunWithContext(ctx context.Context) error {
+	g, err := r.acquire()
+	if err != nil {
+		return err
+	}
+	defer g.runs.Done()
+	return g.engine.RunWithContext(ctx)
+}
+
+// CheckHealth implements health.Checker for the current engine.
+func (r *ReloadableEngine) CheckHealth(ctx context.Context) (health.Result, error) {
+	return r.Current().CheckHealth(ctx)
+}
+
+// Close waits for runs in progress, then closes the current engine and
+// any replaced engines not closed yet. Later runs fail with
+// ErrReloadableClosed. Close is idempotent.
+func (r *ReloadableEngine) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	current := r.current
+	r.mu.Unlock()
+
+	current.runs.Wait()
+	err := current.engine.Close()
+	r.retired.Wait()
+	return err
+}
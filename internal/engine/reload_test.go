@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// gatedCloseableProvider blocks Fetch until released and records Close.
+type gatedCloseableProvider struct {
+	mockCloseableProvider
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func (p *gatedCloseableProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	p.started <- struct{}{}
+	<-p.gate
+	return p.data, nil
+}
+
+func newReloadTestEngine(p provider.ProviderStrategy, out *mockOutput) *ReportEngine {
+	eng := &ReportEngine{
+		Provider:  p,
+		Processor: &processor.BaseProcessor{},
+		Formatter: &mockFormatter{},
+		Output:    out,
+	}
+	return eng.WithLogger(logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText}))
+}
+
+// TestReloadableEngineSwap tests that a swap does not affect a run in progress
+func TestReloadableEngineSwap(t *testing.T) {
+	oldProvider := &gatedCloseableProvider{
+		mockCloseableProvider: mockCloseableProvider{data: []map[string]interface{}{{"id": 1}}},
+		started:               make(chan struct{}, 1),
+		gate:                  make(chan struct{}),
+	}
+	oldOutput := &mockOutput{}
+	live := NewReloadableEngine(newReloadTestEngine(oldProvider, oldOutput))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- live.RunWithContext(context.Background())
+	}()
+	<-oldProvider.started
+
+	newProvider := &mockCloseableProvider{data: []map[string]interface{}{{"id": 2}}}
+	newOutput := &mockOutput{}
+	next := newReloadTestEngine(newProvider, newOutput)
+	live.Swap(next)
+	if live.Current() != next {
+		t.Error("Current() should return the swapped-in engine")
+	}
+
+	// New runs use the new engine while the old run is still going
+	if _, err := live.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if newOutput.received == nil {
+		t.Error("run after Swap should use the new engine")
+	}
+	if oldProvider.isClosed() {
+		t.Error("old engine closed while a run was using it")
+	}
+
+	close(oldProvider.gate)
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight RunWithContext() error = %v", err)
+	}
+	if oldOutput.received == nil {
+		t.Error("in-flight run should finish on the old engine")
+	}
+
+	// The old engine is closed once its run finishes
+	deadline := time.Now().Add(2 * time.Second)
+	for !oldProvider.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !oldProvider.isClosed() {
+		t.Error("old engine not closed after its run finished")
+	}
+	if newProvider.isClosed() {
+		t.Error("current engine closed by Swap")
+	}
+
+	if err := live.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !newProvider.isClosed() {
+		t.Error("Close() should close the current engine")
+	}
+}
+
+// TestReloadableEngineClose tests runs and swaps after Close
+func TestReloadableEngineClose(t *testing.T) {
+	live := NewReloadableEngine(newReloadTestEngine(&mockCloseableProvider{}, &mockOutput{}))
+	if err := live.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := live.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+
+	if _, err := live.Execute(context.Background()); !errors.Is(err, ErrReloadableClosed) {
+		t.Errorf("Execute() error = %v, want ErrReloadableClosed", err)
+	}
+	if err := live.RunWithContext(context.Background()); !errors.Is(err, ErrReloadableClosed) {
+		t.Errorf("RunWithContext() error = %v, want ErrReloadableClosed", err)
+	}
+
+	// An engine swapped in after Close is closed rather than leaked
+	late := &mockCloseableProvider{}
+	live.Swap(newReloadTestEngine(late, &mockOutput{}))
+	if !late.isClosed() {
+		t.Error("Swap() after Close should close the new engine")
+	}
+}
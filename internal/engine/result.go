@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// RunStatus is the final status of a run.
+type RunStatus string
+
+// Run statuses reported in RunResult.
+const (
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+	RunStatusCanceled  RunStatus = "canceled"
+)
+
+// Execution modes reported in RunResult.
+const (
+	ModeBatch     = "batch"
+	ModeStreaming = "streaming"
+)
+
+// Pipeline stage names used in StageResult and RunResult.FailedStage.
+const (
+	StageValidate = "validate"
+	StageFetch    = "fetch"
+	StageProcess  = "process"
+	StageFormat   = "format"
+	StageOutput   = "output"
+)
+
+// StageResult describes one pipeline stage of a run. In streaming mode the
+// figures are totals over all chunks.
+type StageResult struct {
+	Name       string        `json:"name"`
+	Duration   time.Duration `json:"duration"`
+	RecordsIn  int           `json:"records_in"`
+	RecordsOut int           `json:"records_out"`
+	Bytes      int64         `json:"bytes,omitempty"`
+}
+
+// RunResult describes what a run did: its identity, per-stage timings,
+// per-processor record counts, bytes emitted, warnings and final status.
+// It is returned by Execute even when the run fails.
+type RunResult struct {
+	// RunID identifies the run. It is taken from the context request ID
+	// (see logging.WithRequestID) or generated.
+	RunID string `json:"run_id"`
+
+	// Mode is ModeBatch or ModeStreaming.
+	Mode string `json:"mode"`
+
+	Status     RunStatus     `json:"status"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Duration   time.Duration `json:"duration"`
+
+	// Stages lists the stages that ran, in pipeline order.
+	Stages []StageResult `json:"stages,omitempty"`
+
+	// Processors lists per-processor statistics. It is only populated for
+	// chains built with processor.Link, as BuildProcessorChain does.
+	Processors []processor.StepStats `json:"processors,omitempty"`
+
+	// RecordsIn is the number of records fetched from the provider.
+	RecordsIn int `json:"records_in"`
+
+	// RecordsOut is the number of records delivered to the output.
+	RecordsOut int `json:"records_out"`
+
+	// BytesWritten is the number of formatted bytes sent to the output.
+	// It is zero for record outputs, which receive records directly.
+	BytesWritten int64 `json:"bytes_written"`
+
+	// OutputLocation is where the output delivers reports, for outputs
+	// that implement output.LocatedOutput.
+	OutputLocation string `json:"output_location,omitempty"`
+
+	// Sources lists which provider and output alternatives served the run,
+	// for engines built with fallbacks.
+	Sources []resilience.FallbackUse `json:"sources,omitempty"`
+
+	// Chunks is the number of chunks processed in streaming mode.
+	Chunks int `json:"chunks,omitempty"`
+
+	// Watermark describes incremental progress, for engines configured
+	// with WithWatermark and an incremental provider.
+	Watermark *WatermarkResult `json:"watermark,omitempty"`
+
+	// ResumedFrom is the checkpoint a streaming run resumed from. Stage
+	// and record figures cover only the resumed part of the stream.
+	ResumedFrom *Checkpoint `json:"resumed_from,omitempty"`
+
+	// Warnings lists non-fatal issues, such as an empty provider result.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Error details, set when Status is not RunStatusSucceeded.
+	Error          string `json:"error,omitempty"`
+	ErrorType      string `json:"error_type,omitempty"`
+	ErrorComponent string `json:"error_component,omitempty"`
+	FailedStage    string `json:"failed_stage,omitempty"`
+}
+
+// ResultRecorder receives run results, for example to persist them.
+type ResultRecorder interface {
+	RecordResult(ctx context.Context, res *RunResult) error
+}
+
+// ResultRecorderFunc adapts a function to the ResultRecorder interface.
+type ResultRecorderFunc func(ctx context.Context, res *RunResult) error
+
+// RecordResult calls f(ctx, res).
+func (f ResultRecorderFunc) RecordResult(ctx context.Context, res *RunResult) error {
+	return f(ctx, res)
+}
+
+// Stage returns the result of the named stage.
+func (r *RunResult) Stage(name string) (StageResult, bool) {
+	for _, s := range r.Stages {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return StageResult{}, false
+}
+
+// Succeeded reports whether the run completed successfully.
+func (r *RunResult) Succeeded() bool {
+	return r.Status == RunStatusSucceeded
+}
+
+// addStage appends a stage result.
+func (r *RunResult) addStage(s StageResult) {
+	r.Stages = append(r.Stages, s)
+}
+
+// warn records a non-fatal issue.
+func (r *RunResult) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// addSources records the alternatives that served the run, with a warning
+// for every call served by a fallback.
+func (r *RunResult) addSources(uses []resilience.FallbackUse) {
+	r.Sources = uses
+	for _, use := range uses {
+		if use.Fallback {
+			r.warn("%s served by fallback %q after: %s", use.Component, use.Source, strings.Join(use.Failures, "; "))
+		}
+	}
+}
+
+// finish sets the final status and error details.
+func (r *RunResult) finish(ctx context.Context, stage string, err error, stats *processor.ChainStats) {
+	r.FinishedAt = time.Now()
+	r.Duration = r.FinishedAt.Sub(r.StartedAt)
+	r.Processors = stats.Steps()
+
+	if err == nil {
+		r.Status = RunStatusSucceeded
+		return
+	}
+
+	r.Status = RunStatusFailed
+	if ctx.Err() == context.Canceled {
+		r.Status = RunStatusCanceled
+	}
+	r.Error = err.Error()
+	r.ErrorType = errors.Classify(err).String()
+	r.FailedStage = stage
+	if origin := errors.GetOriginError(err); origin != nil {
+		r.ErrorComponent = string(origin.Component)
+	}
+}
+
+// newRunID returns a random run identifier.
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,325 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// minValue keeps rows whose "value" is at least min.
+type minValue struct {
+	min int
+}
+
+func (m minValue) Keep(row map[string]interface{}) bool {
+	v, _ := row["value"].(int)
+	return v >= m.min
+}
+
+// sinkOutput records what it was sent, or fails with err.
+type sinkOutput struct {
+	err  error
+	sent []byte
+}
+
+func (s *sinkOutput) Send(ctx context.Context, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = data
+	return nil
+}
+
+func quietLogger() *logging.Logger {
+	return logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText})
+}
+
+// linkedChain builds min>=20 -> min>=40 as processor.Links, like the factory does.
+func linkedChain() processor.ProcessorHandler {
+	first := processor.NewLink(processor.NewFilterWrapper(minValue{min: 20}), 0, "at_least_20")
+	second := processor.NewLink(processor.NewFilterWrapper(minValue{min: 40}), 1, "at_least_40")
+	first.SetNext(second)
+	return first
+}
+
+func rows(values ...int) []map[string]interface{} {
+	data := make([]map[string]interface{}, len(values))
+	for i, v := range values {
+		data[i] = map[string]interface{}{"value": v}
+	}
+	return data
+}
+
+func TestExecuteBatchResult(t *testing.T) {
+	out := &sinkOutput{}
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(10, 20, 30, 40, 50)),
+		Processor: linkedChain(),
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    out,
+	}).WithLogger(quietLogger())
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !res.Succeeded() || res.Mode != engine.ModeBatch || res.RunID == "" {
+		t.Errorf("result = %+v", res)
+	}
+	if res.RecordsIn != 5 || res.RecordsOut != 2 {
+		t.Errorf("records in/out = %d/%d, want 5/2", res.RecordsIn, res.RecordsOut)
+	}
+	if res.BytesWritten != int64(len(out.sent)) {
+		t.Errorf("BytesWritten = %d, want %d", res.BytesWritten, len(out.sent))
+	}
+
+	var names []string
+	for _, s := range res.Stages {
+		names = append(names, s.Name)
+	}
+	if len(names) != 4 || names[0] != engine.StageFetch || names[3] != engine.StageOutput {
+		t.Errorf("stages = %v", names)
+	}
+	if s, ok := res.Stage(engine.StageProcess); !ok || s.RecordsIn != 5 || s.RecordsOut != 2 {
+		t.Errorf("process stage = %+v", s)
+	}
+
+	if len(res.Processors) != 2 {
+		t.Fatalf("Processors = %+v, want 2 steps", res.Processors)
+	}
+	if p := res.Processors[0]; p.Type != "at_least_20" || p.RecordsIn != 5 || p.RecordsOut != 4 {
+		t.Errorf("first processor = %+v, want 5 in / 4 out", p)
+	}
+	if p := res.Processors[1]; p.Type != "at_least_40" || p.RecordsIn != 4 || p.RecordsOut != 2 {
+		t.Errorf("second processor = %+v, want 4 in / 2 out", p)
+	}
+}
+
+func TestExecuteRunIDFromContext(t *testing.T) {
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(1)),
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &sinkOutput{},
+	}).WithLogger(quietLogger())
+
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+	res, _ := eng.Execute(ctx)
+	if res.RunID != "req-42" {
+		t.Errorf("RunID = %q, want req-42", res.RunID)
+	}
+}
+
+func TestExecuteWarnings(t *testing.T) {
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(1, 2)),
+		Processor: linkedChain(),
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &sinkOutput{},
+	}).WithLogger(quietLogger())
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(res.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the all-filtered warning", res.Warnings)
+	}
+}
+
+func TestExecuteFailureResult(t *testing.T) {
+	cause := engerrors.NewOutputError("sftp", engerrors.ErrorTypeTransient, errors.New("connection reset"))
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(1, 2)),
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &sinkOutput{err: cause},
+	}).WithLogger(quietLogger())
+
+	res, err := eng.Execute(context.Background())
+	if err == nil {
+		t.Fatal("Execute() should fail")
+	}
+	if res.Status != engine.RunStatusFailed || res.FailedStage != engine.StageOutput {
+		t.Errorf("status/stage = %s/%s", res.Status, res.FailedStage)
+	}
+	if res.ErrorType != "transient" || res.ErrorComponent != "output" || res.Error == "" {
+		t.Errorf("error details = %q %q %q", res.ErrorType, res.ErrorComponent, res.Error)
+	}
+	if res.RecordsIn != 2 || res.RecordsOut != 0 || res.BytesWritten != 0 {
+		t.Errorf("records/bytes = %d/%d/%d", res.RecordsIn, res.RecordsOut, res.BytesWritten)
+	}
+}
+
+func TestExecuteCanceled(t *testing.T) {
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(1)),
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &sinkOutput{},
+	}).WithLogger(quietLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := eng.Execute(ctx)
+	if err == nil || res.Status != engine.RunStatusCanceled {
+		t.Errorf("Execute() = %v, status %s; want canceled", err, res.Status)
+	}
+}
+
+func TestExecuteResultRecorder(t *testing.T) {
+	var recorded []*engine.RunResult
+	eng := (&engine.ReportEngine{
+		Provider:  provider.NewMockProvider(rows(1)),
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &sinkOutput{},
+	}).WithLogger(quietLogger()).
+		WithResultRecorder(engine.ResultRecorderFunc(func(ctx context.Context, res *engine.RunResult) error {
+			recorded = append(recorded, res)
+			return errors.New("store unavailable")
+		}))
+
+	// Recorder errors must not fail the run
+	if err := eng.RunWithContext(context.Background()); err != nil {
+		t.Fatalf("RunWithContext() error = %v", err)
+	}
+	if len(recorded) != 1 || !recorded[0].Succeeded() {
+		t.Errorf("recorded = %+v", recorded)
+	}
+
+	data, err := json.Marshal(recorded[0])
+	if err != nil {
+		t.Fatalf("RunResult is not JSON-encodable: %v", err)
+	}
+	var decoded engine.RunResult
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.RunID != recorded[0].RunID {
+		t.Errorf("round trip = %+v, %v", decoded, err)
+	}
+}
+
+func TestExecuteSettlesQueueAcks(t *testing.T) {
+	tests := []struct {
+		name     string
+		outErr   error
+		readOnly bool
+		want     int64
+	}{
+		{"delivered", nil, false, 2},
+		{"output failed", errors.New("connection reset"), false, 0},
+		{"read-only state", nil, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := broker.NewMemoryBroker()
+			broker.Register("engine-acks", b)
+			defer broker.Unregister("engine-acks")
+			_ = b.Publish(context.Background(), "orders",
+				broker.Message{Value: []byte(`{"id": 1}`)},
+				broker.Message{Value: []byte(`{"id": 2}`)},
+			)
+
+			p := provider.NewQueueProvider()
+			if err := p.Configure(map[string]string{"broker": "engine-acks", "topic": "orders", "idle_timeout": "20ms"}); err != nil {
+				t.Fatalf("Configure() error = %v", err)
+			}
+			eng := (&engine.ReportEngine{
+				Provider:  p,
+				Processor: &processor.BaseProcessor{},
+				Formatter: formatter.NewJSONFormatter(""),
+				Output:    &sinkOutput{err: tt.outErr},
+			}).WithLogger(quietLogger())
+			if tt.readOnly {
+				eng.WithReadOnlyState()
+			}
+
+			_, _ = eng.Execute(context.Background())
+			if got := b.Committed("orders", p.Group); got != tt.want {
+				t.Errorf("Committed() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteEmptyQueueTopic(t *testing.T) {
+	b := broker.NewMemoryBroker()
+	broker.Register("engine-empty", b)
+	defer broker.Unregister("engine-empty")
+
+	p := provider.NewQueueProvider()
+	if err := p.Configure(map[string]string{"broker": "engine-empty", "topic": "orders", "idle_timeout": "20ms"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	out := &sinkOutput{}
+	eng := (&engine.ReportEngine{
+		Provider:  p,
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    out,
+	}).WithLogger(quietLogger())
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() on an idle topic error = %v", err)
+	}
+	if res.Status != engine.RunStatusSucceeded || res.RecordsIn != 0 {
+		t.Errorf("status/records = %s/%d, want succeeded/0", res.Status, res.RecordsIn)
+	}
+	if got := b.Committed("orders", p.Group); got != 0 {
+		t.Errorf("Committed() = %d, want 0", got)
+	}
+}
+
+func TestExecuteStreamingResult(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	createLargeCSV(t, csvPath, 250)
+
+	csvProv := provider.NewCSVProvider()
+	if err := csvProv.Configure(map[string]string{"file_path": csvPath}); err != nil {
+		t.Fatal(err)
+	}
+	fileOut := output.NewFileOutput()
+	if err := fileOut.Configure(map[string]string{"path": filepath.Join(tmpDir, "out.json")}); err != nil {
+		t.Fatal(err)
+	}
+
+	eng := (&engine.ReportEngine{
+		Provider:  csvProv,
+		Processor: processor.NewLink(&processor.BaseProcessor{}, 0, "base"),
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    fileOut,
+	}).WithLogger(quietLogger()).WithChunkSize(100)
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.OutputLocation != fileOut.Path {
+		t.Errorf("OutputLocation = %q, want %q", res.OutputLocation, fileOut.Path)
+	}
+	if res.Mode != engine.ModeStreaming || res.Chunks != 3 {
+		t.Errorf("mode/chunks = %s/%d, want streaming/3", res.Mode, res.Chunks)
+	}
+	if res.RecordsIn != 250 || res.RecordsOut != 250 || res.BytesWritten == 0 {
+		t.Errorf("records/bytes = %d/%d/%d", res.RecordsIn, res.RecordsOut, res.BytesWritten)
+	}
+	if s, _ := res.Stage(engine.StageOutput); s.Bytes != res.BytesWritten {
+		t.Errorf("output stage bytes = %d, want %d", s.Bytes, res.BytesWritten)
+	}
+	if len(res.Processors) != 1 || res.Processors[0].Calls != 3 || res.Processors[0].RecordsIn != 250 {
+		t.Errorf("Processors = %+v", res.Processors)
+	}
+}
@@ -119,3 +119,72 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// abortableOutput records whether the stream was finalized or aborted.
+type abortableOutput struct {
+	closeErr error
+	closed   bool
+	aborted  bool
+}
+
+func (a *abortableOutput) Send(ctx context.Context, data []byte) error       { return nil }
+func (a *abortableOutput) Initialize(ctx context.Context) error              { return nil }
+func (a *abortableOutput) WriteChunk(ctx context.Context, data []byte) error { return nil }
+func (a *abortableOutput) Close(ctx context.Context) error                   { a.closed = true; return a.closeErr }
+func (a *abortableOutput) Abort(ctx context.Context) error                   { a.aborted = true; return nil }
+
+// failingProcessor fails every Process call.
+type failingProcessor struct {
+	processor.BaseProcessor
+}
+
+func (f *failingProcessor) Process(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	return nil, fmt.Errorf("processing failed")
+}
+
+func TestStreamingPipeline_AbortOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	createLargeCSV(t, csvPath, 10)
+
+	csvProv := provider.NewCSVProvider()
+	if err := csvProv.Configure(map[string]string{"file_path": csvPath}); err != nil {
+		t.Fatalf("Failed to configure CSV provider: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		proc        processor.ProcessorHandler
+		closeErr    error
+		wantErr     bool
+		wantClosed  bool
+		wantAborted bool
+	}{
+		{name: "success finalizes output", proc: &processor.BaseProcessor{}, wantClosed: true},
+		{name: "failure aborts output", proc: &failingProcessor{}, wantErr: true, wantAborted: true},
+		{name: "close failure fails the run", proc: &processor.BaseProcessor{}, closeErr: fmt.Errorf("rename failed"), wantErr: true, wantClosed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &abortableOutput{closeErr: tt.closeErr}
+			eng := &engine.ReportEngine{
+				Provider:  csvProv,
+				Processor: tt.proc,
+				Formatter: formatter.NewJSONFormatter(""),
+				Output:    out,
+			}
+
+			err := eng.RunWithContext(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunWithContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.closed != tt.wantClosed {
+				t.Errorf("closed = %v, want %v", out.closed, tt.wantClosed)
+			}
+			if out.aborted != tt.wantAborted {
+				t.Errorf("aborted = %v, want %v", out.aborted, tt.wantAborted)
+			}
+		})
+	}
+}
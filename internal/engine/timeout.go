@@ -0,0 +1,282 @@
+package engine
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+)
+
+// DeadlineRun is the DeadlineWarning.Stage of a warning about the whole run.
+const DeadlineRun = "run"
+
+// Timeouts bounds how long a run and each of its stages may take, on top
+// of any deadline of the caller's context. A zero duration means no limit.
+//
+// A streaming run interleaves its stages chunk by chunk, so there a stage
+// timeout bounds the total time spent in the stage over all chunks.
+type Timeouts struct {
+	Run     time.Duration
+	Fetch   time.Duration
+	Process time.Duration
+	Format  time.Duration
+	Output  time.Duration
+
+	// WarnAt is the fraction (0-1) of a timeout after which a run or stage
+	// that is still going is logged and reported to the DeadlineHook.
+	// Zero disables the warnings.
+	WarnAt float64
+}
+
+// DeadlineWarning describes a run or stage that passed its soft deadline.
+type DeadlineWarning struct {
+	RunID   string
+	Stage   string // A Stage constant, or DeadlineRun
+	Elapsed time.Duration
+	Timeout time.Duration
+}
+
+// DeadlineHook is called when a run or stage passes its soft deadline
+// (see Timeouts.WarnAt), e.g. to page someone before a report is late.
+// It runs on its own goroutine while the stage goes on.
+type DeadlineHook func(ctx context.Context, warning DeadlineWarning)
+
+// WithTimeouts sets the run and stage timeouts. A stage that exceeds its
+// timeout fails with an ErrorTypeTransient error naming the stage, so a
+// retrying caller tries the run again; a deadline of the caller's own
+// context is reported as is.
+//
+// Example:
+//
+//	eng.WithTimeouts(engine.Timeouts{
+//	    Run:    15 * time.Minute,
+//	    Fetch:  10 * time.Minute,
+//	    Output: 2 * time.Minute,
+//	    WarnAt: 0.8,
+//	})
+func (r *ReportEngine) WithTimeouts(timeouts Timeouts) *ReportEngine {
+	r.timeouts = timeouts
+	return r
+}
+
+// WithDeadlineHook sets a hook called when a run or stage passes its soft
+// deadline. Warnings are logged whether or not a hook is set.
+func (r *ReportEngine) WithDeadlineHook(hook DeadlineHook) *ReportEngine {
+	r.deadlineHook = hook
+	return r
+}
+
+// deadlineError is the cancellation cause of a context derived by
+// withDeadline.
+type deadlineError struct {
+	scope   string
+	timeout time.Duration
+}
+
+func (e *deadlineError) Error() string {
+	if e.scope == DeadlineRun {
+		return fmt.Sprintf("run exceeded its %s timeout", e.timeout)
+	}
+	return fmt.Sprintf("%s stage exceeded its %s timeout", e.scope, e.timeout)
+}
+
+// withDeadline derives a context bounded by timeout for scope, a stage or
+// DeadlineRun, and arms its soft deadline. The returned function must be
+// called with the result of the scope: it releases the context and, if
+// err was caused by an engine timeout, returns it as a transient error
+// naming stage.
+func (r *ReportEngine) withDeadline(ctx context.Context, scope string, timeout time.Duration) (context.Context, func(stage string, err error) error) {
+	if timeout <= 0 {
+		return ctx, r.timeoutError(ctx)
+	}
+
+	logger := r.getLogger()
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, &deadlineError{scope: scope, timeout: timeout})
+	var soft *time.Timer
+	if warnAt := r.timeouts.WarnAt; warnAt > 0 && warnAt < 1 {
+		start := time.Now()
+		soft = time.AfterFunc(time.Duration(float64(timeout)*warnAt), func() {
+			r.softDeadline(ctx, logger, DeadlineWarning{
+				RunID:   logging.GetRequestID(ctx),
+				Stage:   scope,
+				Elapsed: time.Since(start),
+				Timeout: timeout,
+			})
+		})
+	}
+
+	convert := r.timeoutError(ctx)
+	return ctx, func(stage string, err error) error {
+		if soft != nil {
+			soft.Stop()
+		}
+		err = convert(stage, err)
+		cancel()
+		return err
+	}
+}
+
+// timeoutError returns a function that converts an error of a scope run
+// under ctx into a transient timeout error if an engine timeout, rather
+// than the caller's context, ended it.
+func (r *ReportEngine) timeoutError(ctx context.Context) func(stage string, err error) error {
+	return func(stage string, err error) error {
+		var cause *deadlineError
+		if err == nil || !stderrors.As(context.Cause(ctx), &cause) {
+			return err
+		}
+		var converted *deadlineError
+		if stderrors.As(err, &converted) {
+			return err
+		}
+
+		r.getLogger().ErrorContext(ctx, "timeout exceeded",
+			"stage", stage,
+			"timeout_scope", cause.scope,
+			"timeout_ms", cause.timeout.Milliseconds(),
+		)
+		return errors.NewErrorContext(errors.ComponentEngine, stage+"_stage").
+			WithType(errors.ErrorTypeTransient).
+			WithContext("stage", stage).
+			WithContext("timeout_scope", cause.scope).
+			WithContext("timeout", cause.timeout.String()).
+			Wrap(fmt.Errorf("%w: %w", cause, err))
+	}
+}
+
+// softDeadline logs warning and passes it to the deadline hook, if set.
+func (r *ReportEngine) softDeadline(ctx context.Context, logger *logging.Logger, warning DeadlineWarning) {
+	logger.WarnContext(ctx, "soft deadline passed",
+		"stage", warning.Stage,
+		"elapsed_ms", warning.Elapsed.Milliseconds(),
+		"timeout_ms", warning.Timeout.Milliseconds(),
+	)
+	if r.deadlineHook != nil {
+		r.deadlineHook(ctx, warning)
+	}
+}
+
+// stageBudget bounds the total time a streaming run spends in one stage
+// by the stage's timeout. The time is added to spent, the duration of
+// the stage in the run's totals.
+type stageBudget struct {
+	r       *ReportEngine
+	stage   string
+	timeout time.Duration
+	spent   *time.Duration
+	cause   *deadlineError
+	warned  bool
+
+	// ctx is the context of the last bounded call, or of the provider
+	// stream; see check
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	timer  *time.Timer
+}
+
+// newStageBudget creates the budget of stage. A zero timeout means no
+// limit.
+func (r *ReportEngine) newStageBudget(stage string, timeout time.Duration, spent *time.Duration) *stageBudget {
+	return &stageBudget{
+		r:       r,
+		stage:   stage,
+		timeout: timeout,
+		spent:   spent,
+		cause:   &deadlineError{scope: stage, timeout: timeout},
+	}
+}
+
+// run calls fn with ctx bounded by what is left of the budget and adds
+// the time it took to the stage. Once the budget is spent, run fails
+// without calling fn.
+func (b *stageBudget) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b.timeout <= 0 {
+		return timed(b.spent, func() error { return fn(ctx) })
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, b.timeout-*b.spent, b.cause)
+	defer cancel()
+	b.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := timed(b.spent, func() error { return fn(ctx) })
+	b.softDeadline(ctx)
+	return err
+}
+
+// stream derives the context a provider stream is opened with. The
+// stream outlives any single call, so its context has no deadline:
+// instead it is canceled once the calls made through track run past
+// the budget. stop releases it.
+func (b *stageBudget) stream(ctx context.Context) context.Context {
+	if b.timeout <= 0 {
+		return ctx
+	}
+	b.ctx, b.cancel = context.WithCancelCause(ctx)
+	return b.ctx
+}
+
+// track runs fn, a call on the stream opened with the context from
+// stream, and adds the time it took to the stage. Once the budget is
+// spent, track fails without calling fn.
+func (b *stageBudget) track(fn func() error) error {
+	if b.cancel == nil {
+		return timed(b.spent, fn)
+	}
+
+	remaining := b.timeout - *b.spent
+	if remaining <= 0 {
+		b.cancel(b.cause)
+		return b.ctx.Err()
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(remaining, func() { b.cancel(b.cause) })
+	} else {
+		b.timer.Reset(remaining)
+	}
+	err := timed(b.spent, fn)
+	b.timer.Stop()
+	b.softDeadline(b.ctx)
+	return err
+}
+
+// stop releases the stream context.
+func (b *stageBudget) stop() {
+	if b.cancel == nil {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.cancel(nil)
+}
+
+// check converts err, returned by the last call the budget bounded, into
+// a transient timeout error if the budget or the run ran out.
+func (b *stageBudget) check(err error) error {
+	if b.ctx == nil {
+		return err
+	}
+	return b.r.timeoutError(b.ctx)(b.stage, err)
+}
+
+// softDeadline reports the stage once it has spent the WarnAt fraction
+// of its budget.
+func (b *stageBudget) softDeadline(ctx context.Context) {
+	warnAt := b.r.timeouts.WarnAt
+	if b.warned || warnAt <= 0 || warnAt >= 1 || *b.spent < time.Duration(float64(b.timeout)*warnAt) {
+		return
+	}
+	b.warned = true
+	warning := DeadlineWarning{
+		RunID:   logging.GetRequestID(ctx),
+		Stage:   b.stage,
+		Elapsed: *b.spent,
+		Timeout: b.timeout,
+	}
+	go b.r.softDeadline(context.WithoutCancel(ctx), b.r.getLogger(), warning)
+}
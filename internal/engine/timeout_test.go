@@ -0,0 +1,290 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// blockingProvider returns its data after delay, or the context error if
+// the context ends first.
+type blockingProvider struct {
+	delay time.Duration
+}
+
+func (p *blockingProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	select {
+	case <-time.After(p.delay):
+		return []map[string]interface{}{{"id": 1}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// blockingOutput waits for the context to end.
+type blockingOutput struct{}
+
+func (o *blockingOutput) Send(ctx context.Context, data []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func timeoutEngine(prov *blockingProvider, out *blockingOutput) *ReportEngine {
+	eng := &ReportEngine{
+		Provider:  prov,
+		Processor: &processor.BaseProcessor{},
+		Formatter: &mockFormatter{},
+		Output:    &mockOutput{},
+	}
+	if out != nil {
+		eng.Output = out
+	}
+	return eng
+}
+
+func TestTimeouts_StageTimeout(t *testing.T) {
+	eng := timeoutEngine(&blockingProvider{delay: time.Hour}, nil).
+		WithTimeouts(Timeouts{Fetch: 20 * time.Millisecond})
+
+	res, err := eng.Execute(context.Background())
+	if err == nil {
+		t.Fatal("expected fetch timeout")
+	}
+	if !errors.IsRetryable(err) || errors.GetErrorType(err) != errors.ErrorTypeTransient {
+		t.Errorf("timeout should be transient and retryable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "fetch stage exceeded its 20ms timeout") {
+		t.Errorf("error should name the stage, got %v", err)
+	}
+	if res.FailedStage != StageFetch || res.ErrorType != errors.ErrorTypeTransient.String() {
+		t.Errorf("result = stage %q, type %q", res.FailedStage, res.ErrorType)
+	}
+}
+
+func TestTimeouts_RunTimeout(t *testing.T) {
+	eng := timeoutEngine(&blockingProvider{}, &blockingOutput{}).
+		WithTimeouts(Timeouts{Run: 30 * time.Millisecond, Output: time.Hour})
+
+	res, err := eng.Execute(context.Background())
+	if err == nil {
+		t.Fatal("expected run timeout")
+	}
+	if !errors.IsRetryable(err) {
+		t.Errorf("timeout should be retryable, got %v", err)
+	}
+	engineErr := err.(*errors.EngineError)
+	if engineErr.Context["stage"] != StageOutput || engineErr.Context["timeout_scope"] != DeadlineRun {
+		t.Errorf("context = %v", engineErr.Context)
+	}
+	if !strings.Contains(err.Error(), "run exceeded its 30ms timeout") {
+		t.Errorf("error should name the run timeout, got %v", err)
+	}
+	if strings.Count(err.Error(), "exceeded its") != 1 {
+		t.Errorf("timeout should be reported once, got %v", err)
+	}
+	if res.FailedStage != StageOutput {
+		t.Errorf("FailedStage = %q", res.FailedStage)
+	}
+}
+
+func TestTimeouts_CallerDeadline(t *testing.T) {
+	eng := timeoutEngine(&blockingProvider{delay: time.Hour}, nil).
+		WithTimeouts(Timeouts{Run: time.Hour, Fetch: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := eng.Execute(ctx)
+	if err == nil {
+		t.Fatal("expected deadline error")
+	}
+	if errors.IsRetryable(err) || strings.Contains(err.Error(), "exceeded its") {
+		t.Errorf("caller deadline should not be reported as an engine timeout, got %v", err)
+	}
+}
+
+func TestTimeouts_SoftDeadline(t *testing.T) {
+	var mu sync.Mutex
+	var warnings []DeadlineWarning
+	hook := func(ctx context.Context, w DeadlineWarning) {
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, w)
+	}
+
+	eng, err := NewEngineBuilder().
+		WithProvider(&blockingProvider{delay: 60 * time.Millisecond}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(&mockFormatter{}).
+		WithOutput(&mockOutput{}).
+		WithTimeouts(Timeouts{Fetch: time.Second, WarnAt: 0.02}).
+		WithDeadlineHook(hook).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("run should succeed past its soft deadline: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want one", warnings)
+	}
+	w := warnings[0]
+	if w.Stage != StageFetch || w.Timeout != time.Second || w.RunID != res.RunID || w.Elapsed < 20*time.Millisecond {
+		t.Errorf("warning = %+v", w)
+	}
+}
+
+// slowStreamProvider streams records records, taking delay for each. With
+// block set, its iterator instead waits for the stream context to end.
+type slowStreamProvider struct {
+	records int
+	delay   time.Duration
+	block   bool
+}
+
+func (p *slowStreamProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (p *slowStreamProvider) Stream(ctx context.Context) (provider.Iterator, error) {
+	return &slowIterator{p: p, ctx: ctx}, nil
+}
+
+type slowIterator struct {
+	p   *slowStreamProvider
+	ctx context.Context
+	n   int
+	err error
+}
+
+func (it *slowIterator) Next() bool {
+	if it.p.block {
+		<-it.ctx.Done()
+		it.err = it.ctx.Err()
+		return false
+	}
+	if it.n >= it.p.records {
+		return false
+	}
+	time.Sleep(it.p.delay)
+	it.n++
+	return true
+}
+
+func (it *slowIterator) Value() map[string]interface{} { return map[string]interface{}{"id": it.n} }
+func (it *slowIterator) Err() error                    { return it.err }
+func (it *slowIterator) Close() error                  { return nil }
+
+// slowStreamOutput takes delay for every chunk it writes.
+type slowStreamOutput struct {
+	delay time.Duration
+}
+
+func (o *slowStreamOutput) Send(ctx context.Context, data []byte) error { return nil }
+func (o *slowStreamOutput) Initialize(ctx context.Context) error        { return nil }
+func (o *slowStreamOutput) Close(ctx context.Context) error             { return nil }
+
+func (o *slowStreamOutput) WriteChunk(ctx context.Context, data []byte) error {
+	select {
+	case <-time.After(o.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestTimeouts_StreamingStageBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		prov     *slowStreamProvider
+		out      *slowStreamOutput
+		timeouts Timeouts
+		stage    string
+	}{
+		{
+			// No single Next takes long, but together they exceed the timeout
+			name:     "fetch over all records",
+			prov:     &slowStreamProvider{records: 20, delay: 5 * time.Millisecond},
+			out:      &slowStreamOutput{},
+			timeouts: Timeouts{Fetch: 30 * time.Millisecond},
+			stage:    StageFetch,
+		},
+		{
+			name:     "blocked fetch",
+			prov:     &slowStreamProvider{block: true},
+			out:      &slowStreamOutput{},
+			timeouts: Timeouts{Fetch: 20 * time.Millisecond},
+			stage:    StageFetch,
+		},
+		{
+			name:     "output over all chunks",
+			prov:     &slowStreamProvider{records: 20},
+			out:      &slowStreamOutput{delay: 10 * time.Millisecond},
+			timeouts: Timeouts{Output: 35 * time.Millisecond},
+			stage:    StageOutput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng := (&ReportEngine{
+				Provider:  tt.prov,
+				Processor: &processor.BaseProcessor{},
+				Formatter: formatter.NewJSONFormatter(""),
+				Output:    tt.out,
+			}).WithChunkSize(2).WithTimeouts(tt.timeouts)
+
+			res, err := eng.Execute(context.Background())
+			if err == nil {
+				t.Fatal("expected stage timeout")
+			}
+			if res.Mode != ModeStreaming {
+				t.Fatalf("Mode = %s, want streaming", res.Mode)
+			}
+			if !errors.IsRetryable(err) || !strings.Contains(err.Error(), tt.stage+" stage exceeded its") {
+				t.Errorf("error should be a retryable %s timeout, got %v", tt.stage, err)
+			}
+			if res.FailedStage != tt.stage {
+				t.Errorf("FailedStage = %q, want %q", res.FailedStage, tt.stage)
+			}
+		})
+	}
+}
+
+func TestTimeouts_StreamingWithinBudget(t *testing.T) {
+	eng := (&ReportEngine{
+		Provider:  &slowStreamProvider{records: 10, delay: time.Millisecond},
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    &slowStreamOutput{},
+	}).WithChunkSize(3).WithTimeouts(Timeouts{Fetch: time.Second, Process: time.Second, Format: time.Second, Output: time.Second})
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.Mode != ModeStreaming || res.RecordsOut != 10 {
+		t.Errorf("result = mode %s, %d records out", res.Mode, res.RecordsOut)
+	}
+}
+
+func TestTimeoutConfig_ToTimeouts(t *testing.T) {
+	got := TimeoutConfig{Run: "15m", Fetch: "10m", Output: "2m", WarnAt: 0.8}.ToTimeouts()
+	want := Timeouts{Run: 15 * time.Minute, Fetch: 10 * time.Minute, Output: 2 * time.Minute, WarnAt: 0.8}
+	if got != want {
+		t.Errorf("ToTimeouts() = %+v, want %+v", got, want)
+	}
+}
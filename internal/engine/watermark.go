@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
+)
+
+// WatermarkResult describes the incremental progress of a run.
+type WatermarkResult struct {
+	// Key is the state store key the watermark is kept under.
+	Key string `json:"key"`
+
+	// Field is the provider's watermark field.
+	Field string `json:"field"`
+
+	// Previous is the watermark the run started from ("" for a full fetch).
+	Previous string `json:"previous,omitempty"`
+
+	// Next is the highest watermark value fetched by the run.
+	Next string `json:"next,omitempty"`
+
+	// Committed reports whether Next was saved for the following run. It
+	// is false when the run failed, so the next run fetches the same
+	// records again.
+	Committed bool `json:"committed"`
+}
+
+// WithWatermark enables incremental runs. The provider must implement
+// provider.IncrementalProvider and declare a watermark field; otherwise
+// runs fail with a configuration error rather than silently fetching
+// everything. Each run fetches only records newer than the watermark
+// stored under key, and the highest watermark fetched is saved after the
+// output stage succeeds. A failed run leaves the stored watermark
+// untouched, so the next run fetches the same records again.
+//
+// Example:
+//
+//	eng.WithWatermark(state.NewFileStore("state.json"), "daily-orders")
+func (r *ReportEngine) WithWatermark(store state.Store, key string) *ReportEngine {
+	r.watermarks = store
+	r.watermarkKey = key
+	return r
+}
+
+// WithReadOnlyState keeps runs from changing the watermark and checkpoint
+// stores. The stored watermark still selects what is fetched, but it is
+// not advanced, and streaming runs neither resume nor save checkpoints.
+// Previews, such as the CLI's dry-run, use it so the next real run is
+// unaffected.
+func (r *ReportEngine) WithReadOnlyState() *ReportEngine {
+	r.readOnlyState = true
+	return r
+}
+
+// beginWatermark loads the stored watermark and adds it to ctx. It does
+// nothing unless a store is configured, and fails if the provider is not
+// incremental.
+func (r *ReportEngine) beginWatermark(ctx context.Context, res *RunResult) (context.Context, error) {
+	if r.watermarks == nil {
+		return ctx, nil
+	}
+	incremental, ok := r.Provider.(provider.IncrementalProvider)
+	if !ok || incremental.WatermarkField() == "" {
+		return ctx, errors.NewErrorContext(errors.ComponentEngine, "load_watermark").
+			WithType(errors.ErrorTypeConfiguration).
+			WithContext("key", r.watermarkKey).
+			New("watermark configured but the provider declares no watermark field")
+	}
+
+	previous, err := r.watermarks.Get(r.watermarkKey)
+	if err != nil && !stderrors.Is(err, state.ErrNotFound) {
+		return ctx, errors.NewErrorContext(errors.ComponentEngine, "load_watermark").
+			WithType(errors.ErrorTypeResource).
+			WithContext("key", r.watermarkKey).
+			Wrap(err)
+	}
+
+	res.Watermark = &WatermarkResult{
+		Key:      r.watermarkKey,
+		Field:    incremental.WatermarkField(),
+		Previous: previous,
+	}
+	r.getLogger().InfoContext(ctx, "incremental run",
+		"watermark_key", r.watermarkKey,
+		"watermark_field", res.Watermark.Field,
+		"watermark", previous,
+	)
+	return provider.WithWatermark(ctx, previous), nil
+}
+
+// observe raises Next to the highest watermark value in records. It must
+// be called on fetched records before processors can change them.
+func (w *WatermarkResult) observe(records []map[string]interface{}) {
+	if w == nil {
+		return
+	}
+	for _, record := range records {
+		if value, ok := record[w.Field]; ok && value != nil {
+			w.raise(value)
+		}
+	}
+}
+
+// raise sets Next to value if it is higher.
+func (w *WatermarkResult) raise(value interface{}) {
+	if w == nil || value == "" {
+		return
+	}
+	if w.Next == "" || provider.CompareWatermark(value, w.Next) > 0 {
+		w.Next = provider.FormatWatermark(value)
+	}
+}
+
+// commitWatermark saves the new watermark after a successful run. Failures
+// are recorded as warnings: the report has already been delivered.
+func (r *ReportEngine) commitWatermark(ctx context.Context, res *RunResult) {
+	w := res.Watermark
+	if w == nil || w.Next == "" || r.readOnlyState {
+		return
+	}
+	if w.Previous != "" && provider.CompareWatermark(w.Next, w.Previous) <= 0 {
+		return
+	}
+
+	if err := r.watermarks.Set(w.Key, w.Next); err != nil {
+		r.getLogger().WarnContext(ctx, "failed to save watermark",
+			"watermark_key", w.Key,
+			"watermark", w.Next,
+			"error", err,
+		)
+		res.warn("watermark not saved, the next run will fetch these records again: %v", err)
+		return
+	}
+	w.Committed = true
+}
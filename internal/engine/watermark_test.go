@@ -0,0 +1,219 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
+)
+
+// incrementalProvider returns the rows whose "id" is above the watermark.
+type incrementalProvider struct {
+	ids     []int
+	fetched []string // watermark seen by each Fetch
+}
+
+func (p *incrementalProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	watermark := provider.WatermarkFrom(ctx)
+	p.fetched = append(p.fetched, watermark)
+
+	data := []map[string]interface{}{}
+	for _, id := range p.ids {
+		row := map[string]interface{}{"id": id}
+		if watermark == "" || provider.CompareWatermark(id, watermark) > 0 {
+			data = append(data, row)
+		}
+	}
+	return data, nil
+}
+
+func (p *incrementalProvider) WatermarkField() string {
+	return "id"
+}
+
+func incrementalEngine(prov provider.ProviderStrategy, out output.OutputStrategy, store state.Store) *engine.ReportEngine {
+	return (&engine.ReportEngine{
+		Provider:  prov,
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    out,
+	}).WithLogger(quietLogger()).WithWatermark(store, "orders")
+}
+
+func TestWatermark_IncrementalRuns(t *testing.T) {
+	store := state.NewMemoryStore()
+	prov := &incrementalProvider{ids: []int{1, 2, 3}}
+
+	res, err := incrementalEngine(prov, &sinkOutput{}, store).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("first run error = %v", err)
+	}
+	if w := res.Watermark; w == nil || w.Previous != "" || w.Next != "3" || !w.Committed {
+		t.Fatalf("first run watermark = %+v", res.Watermark)
+	}
+
+	// Only newer rows are fetched on the next run
+	prov.ids = append(prov.ids, 4, 5)
+	res, err = incrementalEngine(prov, &sinkOutput{}, store).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("second run error = %v", err)
+	}
+	if res.RecordsIn != 2 || res.Watermark.Previous != "3" || res.Watermark.Next != "5" {
+		t.Errorf("second run: %d records, watermark %+v", res.RecordsIn, res.Watermark)
+	}
+
+	// No new rows: the watermark stays where it is
+	res, _ = incrementalEngine(prov, &sinkOutput{}, store).Execute(context.Background())
+	if res.RecordsIn != 0 || res.Watermark.Committed {
+		t.Errorf("empty run: %d records, watermark %+v", res.RecordsIn, res.Watermark)
+	}
+	if v, _ := store.Get("orders"); v != "5" {
+		t.Errorf("stored watermark = %q, want 5", v)
+	}
+}
+
+func TestWatermark_RollbackOnOutputFailure(t *testing.T) {
+	store := state.NewMemoryStore()
+	_ = store.Set("orders", "1")
+	prov := &incrementalProvider{ids: []int{1, 2, 3}}
+
+	failing := &sinkOutput{err: errors.New("disk full")}
+	res, err := incrementalEngine(prov, failing, store).Execute(context.Background())
+	if err == nil {
+		t.Fatal("run should fail")
+	}
+	if res.Watermark.Next != "3" || res.Watermark.Committed {
+		t.Errorf("watermark = %+v, want next 3 not committed", res.Watermark)
+	}
+	if v, _ := store.Get("orders"); v != "1" {
+		t.Errorf("stored watermark = %q, want 1 (unchanged)", v)
+	}
+
+	// The retry fetches the same records again
+	res, err = incrementalEngine(prov, &sinkOutput{}, store).Execute(context.Background())
+	if err != nil || res.RecordsIn != 2 || !res.Watermark.Committed {
+		t.Errorf("retry: %v, %d records, watermark %+v", err, res.RecordsIn, res.Watermark)
+	}
+}
+
+func TestWatermark_StoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	prov := &incrementalProvider{ids: []int{1}}
+	res, err := incrementalEngine(prov, &sinkOutput{}, state.NewFileStore(corrupt)).Execute(context.Background())
+	if err == nil || res.FailedStage != engine.StageFetch || len(prov.fetched) != 0 {
+		t.Errorf("unreadable state: err %v, stage %q, fetches %d", err, res.FailedStage, len(prov.fetched))
+	}
+}
+
+func TestWatermark_NonIncrementalProvider(t *testing.T) {
+	eng := incrementalEngine(provider.NewMockProvider(rows(1, 2)), &sinkOutput{}, state.NewMemoryStore())
+	res, err := eng.Execute(context.Background())
+	if err == nil || engerrors.GetErrorType(err) != engerrors.ErrorTypeConfiguration {
+		t.Errorf("Execute() = %v; want a configuration error", err)
+	}
+	if res.FailedStage != engine.StageFetch {
+		t.Errorf("FailedStage = %q, want %q", res.FailedStage, engine.StageFetch)
+	}
+}
+
+func TestWatermark_ThroughDecorators(t *testing.T) {
+	store := state.NewMemoryStore()
+	prov := &incrementalProvider{ids: []int{1, 2}}
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(prov).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&sinkOutput{}).
+		WithMetrics(observability.NewNoopCollector()).
+		WithProviderRateLimiter(resilience.NewRateLimiter("test", 0, 1)).
+		WithRetry(resilience.DefaultRetryPolicy).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	eng.WithLogger(quietLogger()).WithWatermark(store, "orders")
+
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.Watermark == nil || !res.Watermark.Committed || res.Watermark.Next != "2" {
+		t.Errorf("Watermark = %+v, want 2 committed", res.Watermark)
+	}
+}
+
+func TestWatermark_ReadOnlyState(t *testing.T) {
+	store := state.NewMemoryStore()
+	if err := store.Set("orders", "1"); err != nil {
+		t.Fatal(err)
+	}
+	prov := &incrementalProvider{ids: []int{1, 2, 3}}
+
+	res, err := incrementalEngine(prov, &sinkOutput{}, store).WithReadOnlyState().Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if prov.fetched[0] != "1" {
+		t.Errorf("fetched from %q, want the stored watermark", prov.fetched[0])
+	}
+	if res.Watermark == nil || res.Watermark.Next != "3" || res.Watermark.Committed {
+		t.Errorf("Watermark = %+v, want 3 not committed", res.Watermark)
+	}
+	if v, _ := store.Get("orders"); v != "1" {
+		t.Errorf("stored watermark = %q, want it unchanged", v)
+	}
+}
+
+func TestWatermark_Streaming(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "orders.csv")
+	if err := os.WriteFile(csvPath, []byte("id,total\n1,10\n2,20\n3,30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := state.NewFileStore(filepath.Join(dir, "state.json"))
+
+	run := func() *engine.RunResult {
+		t.Helper()
+		csvProv := provider.NewCSVProvider()
+		if err := csvProv.Configure(map[string]string{"file_path": csvPath, "watermark_field": "id"}); err != nil {
+			t.Fatal(err)
+		}
+		fileOut := output.NewFileOutput()
+		if err := fileOut.Configure(map[string]string{"path": filepath.Join(dir, "out.json")}); err != nil {
+			t.Fatal(err)
+		}
+		res, err := incrementalEngine(csvProv, fileOut, store).WithChunkSize(2).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return res
+	}
+
+	res := run()
+	if res.Mode != engine.ModeStreaming || res.RecordsIn != 3 || res.Watermark.Next != "3" || !res.Watermark.Committed {
+		t.Fatalf("first run: mode %s, %d records, watermark %+v", res.Mode, res.RecordsIn, res.Watermark)
+	}
+
+	if err := os.WriteFile(csvPath, []byte("id,total\n1,10\n2,20\n3,30\n4,40\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if res := run(); res.RecordsIn != 1 || res.Watermark.Next != "4" {
+		t.Errorf("second run: %d records, watermark %+v", res.RecordsIn, res.Watermark)
+	}
+}
@@ -275,6 +275,50 @@ func GetRootCause(err error) error {
 	}
 }
 
+// Classify returns the first known ErrorType in the error chain.
+// Stage wrappers are often unclassified, so the most specific
+// classification is usually found below the outermost error. Component
+// errors such as ProviderError and OutputError are recognized too.
+func Classify(err error) ErrorType {
+	for _, e := range GetErrorChain(err) {
+		if engineErr, ok := asEngineError(e); ok && engineErr.Type != ErrorTypeUnknown {
+			return engineErr.Type
+		}
+	}
+	return ErrorTypeUnknown
+}
+
+// GetOriginError returns the innermost EngineError in the chain, which
+// identifies the component where the failure originated. Returns nil if
+// the chain contains no EngineError.
+func GetOriginError(err error) *EngineError {
+	var origin *EngineError
+	for _, e := range GetErrorChain(err) {
+		if engineErr, ok := asEngineError(e); ok {
+			origin = engineErr
+		}
+	}
+	return origin
+}
+
+// engineError lets asEngineError find the EngineError embedded in
+// component error types.
+func (e *EngineError) engineError() *EngineError {
+	return e
+}
+
+// asEngineError returns the EngineError of e, including the one embedded
+// in component errors like ProviderError and OutputError.
+func asEngineError(e error) (*EngineError, bool) {
+	switch v := e.(type) {
+	case *EngineError:
+		return v, true
+	case interface{ engineError() *EngineError }:
+		return v.engineError(), true
+	}
+	return nil, false
+}
+
 // ErrorContext is a helper for building errors with context.
 type ErrorContext struct {
 	component Component
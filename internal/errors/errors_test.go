@@ -387,6 +387,41 @@ func TestGetRootCause(t *testing.T) {
 	}
 }
 
+// TestClassify tests the Classify function
+func TestClassify(t *testing.T) {
+	baseErr := fmt.Errorf("base error")
+	err1 := NewEngineError(ComponentProvider, "fetch", ErrorTypeTransient, baseErr)
+	err2 := NewEngineError(ComponentEngine, "fetch_stage", ErrorTypeUnknown, err1)
+
+	if got := Classify(err2); got != ErrorTypeTransient {
+		t.Errorf("Classify() = %v, want transient", got)
+	}
+	if got := Classify(fmt.Errorf("wrapped: %w", err2)); got != ErrorTypeTransient {
+		t.Errorf("Classify() through fmt wrapping = %v, want transient", got)
+	}
+	if got := Classify(baseErr); got != ErrorTypeUnknown {
+		t.Errorf("Classify() of plain error = %v, want unknown", got)
+	}
+	outputErr := NewOutputError("send", ErrorTypeResource, baseErr)
+	if got := Classify(Wrap(ComponentEngine, "output_stage", outputErr)); got != ErrorTypeResource {
+		t.Errorf("Classify() of component error = %v, want resource", got)
+	}
+}
+
+// TestGetOriginError tests the GetOriginError function
+func TestGetOriginError(t *testing.T) {
+	baseErr := fmt.Errorf("base error")
+	err1 := NewEngineError(ComponentOutput, "send", ErrorTypeTransient, baseErr)
+	err2 := NewEngineError(ComponentEngine, "output_stage", ErrorTypeUnknown, err1)
+
+	if origin := GetOriginError(err2); origin != err1 {
+		t.Errorf("GetOriginError() = %v, want the output error", origin)
+	}
+	if origin := GetOriginError(baseErr); origin != nil {
+		t.Errorf("GetOriginError() of plain error = %v, want nil", origin)
+	}
+}
+
 // TestErrorContext tests the ErrorContext builder
 func TestErrorContext(t *testing.T) {
 	ec := NewErrorContext(ComponentProvider, "fetch").
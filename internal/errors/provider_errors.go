@@ -106,3 +106,11 @@ func ErrProviderResourceExhausted(providerType string, resource string, err erro
 		WithProviderType(providerType).
 		WithContext("exhausted_resource", resource)
 }
+
+// ErrProviderStatus creates an error for an unsuccessful HTTP response,
+// classified by its status code (see StatusError.Type).
+func ErrProviderStatus(providerType string, statusErr *StatusError) *ProviderError {
+	return NewProviderError("fetch", statusErr.Type(), statusErr).
+		WithProviderType(providerType).
+		WithContext("status_code", statusErr.StatusCode)
+}
@@ -0,0 +1,169 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// StatusError is an HTTP response with an unsuccessful status code.
+// RetryAfter is the delay the server asked for with a Retry-After header,
+// or zero.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+// NewStatusError creates a StatusError from a response, reading its
+// Retry-After header.
+func NewStatusError(resp *http.Response) *StatusError {
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("http status %s", e.Status)
+	}
+	return fmt.Sprintf("http status %d", e.StatusCode)
+}
+
+// Type classifies the status: 408, 429, 502, 503, 504 and other 5xx
+// codes are transient, 401 and 403 are configuration errors (bad
+// credentials) and other codes are permanent.
+func (e *StatusError) Type() ErrorType {
+	switch {
+	case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusTooManyRequests:
+		return ErrorTypeTransient
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrorTypeConfiguration
+	case e.StatusCode >= 500 && e.StatusCode != http.StatusNotImplemented && e.StatusCode != http.StatusHTTPVersionNotSupported:
+		return ErrorTypeTransient
+	default:
+		return ErrorTypePermanent
+	}
+}
+
+// ParseRetryAfter parses a Retry-After header value, either a number of
+// seconds or an HTTP date, into a delay from now. It returns zero for an
+// empty, invalid or past value.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// IsTransientNetwork reports whether err is a network failure that may
+// succeed on retry: a timeout, a refused, reset or aborted connection, a
+// broken pipe, a connection closed mid-response, or a temporary DNS
+// failure. Context cancellation is never transient, and neither is the
+// caller's expired context deadline, although it reports itself as a
+// timeout; a client's own timeout, such as http.Client.Timeout, is.
+func IsTransientNetwork(err error) bool {
+	if err == nil || stderrors.Is(err, context.Canceled) || isContextDeadline(err) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	for _, errno := range []syscall.Errno{syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE, syscall.ETIMEDOUT} {
+		if stderrors.Is(err, errno) {
+			return true
+		}
+	}
+	return stderrors.Is(err, io.ErrUnexpectedEOF) || stderrors.Is(err, net.ErrClosed)
+}
+
+// isContextDeadline reports whether the chain of err holds the error of
+// an expired context. Unlike errors.Is, it does not match errors that
+// only compare equal to context.DeadlineExceeded, as the timeouts of
+// net/http do.
+func isContextDeadline(err error) bool {
+	for err != nil {
+		if err == context.DeadlineExceeded {
+			return true
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			return slices.ContainsFunc(e.Unwrap(), isContextDeadline)
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// transientSQLStates are SQLSTATE codes and classes of failures that may
+// succeed on retry.
+var transientSQLStates = []string{
+	"08",    // connection exception
+	"40001", // serialization failure
+	"40P01", // deadlock detected
+	"53",    // insufficient resources
+	"55P03", // lock not available
+	"57P01", // admin shutdown
+	"57P02", // crash shutdown
+	"57P03", // cannot connect now
+	"HYT00", // timeout expired
+}
+
+// IsTransientSQL reports whether err is a database failure that may
+// succeed on retry: a bad or closed connection, or a driver error whose
+// SQLSTATE (from a SQLState() string method, as pq and pgx errors have)
+// is a connection exception, serialization failure, deadlock, resource
+// shortage or server shutdown.
+func IsTransientSQL(err error) bool {
+	if err == nil {
+		return false
+	}
+	if stderrors.Is(err, driver.ErrBadConn) || stderrors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var stated interface{ SQLState() string }
+	if stderrors.As(err, &stated) {
+		state := stated.SQLState()
+		for _, prefix := range transientSQLStates {
+			if strings.HasPrefix(state, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
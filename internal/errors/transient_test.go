@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestParseRetryAfter tests both Retry-After forms
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"0", 0},
+		{"-3", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestStatusErrorType tests status code classification
+func TestStatusErrorType(t *testing.T) {
+	tests := []struct {
+		code int
+		want ErrorType
+	}{
+		{http.StatusRequestTimeout, ErrorTypeTransient},
+		{http.StatusTooManyRequests, ErrorTypeTransient},
+		{http.StatusInternalServerError, ErrorTypeTransient},
+		{http.StatusBadGateway, ErrorTypeTransient},
+		{http.StatusServiceUnavailable, ErrorTypeTransient},
+		{http.StatusGatewayTimeout, ErrorTypeTransient},
+		{http.StatusNotImplemented, ErrorTypePermanent},
+		{http.StatusUnauthorized, ErrorTypeConfiguration},
+		{http.StatusForbidden, ErrorTypeConfiguration},
+		{http.StatusNotFound, ErrorTypePermanent},
+		{http.StatusBadRequest, ErrorTypePermanent},
+	}
+
+	for _, tt := range tests {
+		err := &StatusError{StatusCode: tt.code}
+		if got := err.Type(); got != tt.want {
+			t.Errorf("StatusError{%d}.Type() = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
+
+// TestNewStatusError tests reading a response
+func TestNewStatusError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Header:     http.Header{"Retry-After": []string{"3"}},
+	}
+
+	err := NewStatusError(resp)
+	if err.RetryAfter != 3*time.Second {
+		t.Errorf("RetryAfter = %s, want 3s", err.RetryAfter)
+	}
+	if err.Error() != "http status 429 Too Many Requests" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	providerErr := ErrProviderStatus("rest", err)
+	if providerErr.Type != ErrorTypeTransient || !providerErr.Retryable {
+		t.Errorf("expected a retryable transient provider error, got %v", providerErr)
+	}
+}
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// TestIsTransientNetwork tests network failure detection
+func TestIsTransientNetwork(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", fmt.Errorf("fetch: %w", timeoutError{}), true},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"broken pipe", fmt.Errorf("write: %w", syscall.EPIPE), true},
+		{"unexpected eof", fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), true},
+		{"temporary dns", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"unknown host", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"canceled", fmt.Errorf("fetch: %w", context.Canceled), false},
+		{"caller deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), false},
+		{"caller deadline during a request", &url.Error{Op: "Get", URL: "http://api", Err: context.DeadlineExceeded}, false},
+		{"plain", errors.New("bad request"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientNetwork(tt.err); got != tt.want {
+				t.Errorf("IsTransientNetwork(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// sqlStateError is a driver error carrying a SQLSTATE code
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// TestIsTransientSQL tests database failure detection
+func TestIsTransientSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad connection", fmt.Errorf("query: %w", driver.ErrBadConn), true},
+		{"connection done", sql.ErrConnDone, true},
+		{"connection exception", sqlStateError("08006"), true},
+		{"serialization failure", sqlStateError("40001"), true},
+		{"deadlock", sqlStateError("40P01"), true},
+		{"too many connections", sqlStateError("53300"), true},
+		{"admin shutdown", sqlStateError("57P01"), true},
+		{"unique violation", sqlStateError("23505"), false},
+		{"syntax error", sqlStateError("42601"), false},
+		{"plain", errors.New("no rows"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientSQL(tt.err); got != tt.want {
+				t.Errorf("IsTransientSQL(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,52 @@
 package factory
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
 	"github.com/AshishBagdane/go-report-engine/internal/registry"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
+// deprecationLogger reports deprecated parameters found in configs.
+var deprecationLogger = logging.NewLogger(logging.Config{
+	Level:     logging.LevelInfo,
+	Format:    logging.FormatJSON,
+	Component: "factory",
+})
+
 // NewEngineFromConfig acts as the central Factory defined in your diagram.
 // It reads the Config struct and uses the EngineBuilder to construct the engine.
 func NewEngineFromConfig(cfg engine.Config) (*engine.ReportEngine, error) {
+	return newEngine(cfg, &options{})
+}
+
+// ValidateConfig checks that an engine can be built from cfg, by building
+// and closing one with opts. Shared rate limiters, bulkheads and circuit
+// breakers are only checked against the registered ones: the registries
+// are not changed.
+func ValidateConfig(cfg engine.Config, opts ...Option) error {
+	o := newOptions(opts)
+	o.limiters = o.limiterRegistry().Clone()
+	o.breakers = o.breakerRegistry().Clone()
+
+	eng, err := newEngine(cfg, o)
+	if err != nil {
+		return err
+	}
+	return eng.Close()
+}
+
+// newEngine builds an engine from cfg with the given options.
+func newEngine(cfg engine.Config, o *options) (*engine.ReportEngine, error) {
 	// 1. Validate Config for required fields
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -22,30 +59,229 @@ func NewEngineFromConfig(cfg engine.Config) (*engine.ReportEngine, error) {
 	if err != nil {
 		return nil, fmt.Errorf("provider error: %w", err)
 	}
+	if err := configure(prov, cfg.Provider.Params, "provider", cfg.Positions); err != nil {
+		return nil, fmt.Errorf("provider ('%s') configuration failed: %w", cfg.Provider.Type, err)
+	}
 
 	// Formatter
 	fmtStrategy, err := registry.GetFormatter(cfg.Formatter.Type) //
 	if err != nil {
 		return nil, fmt.Errorf("formatter error: %w", err)
 	}
+	if err := configure(fmtStrategy, cfg.Formatter.Params, "formatter", cfg.Positions); err != nil {
+		return nil, fmt.Errorf("formatter ('%s') configuration failed: %w", cfg.Formatter.Type, err)
+	}
 
 	// Output
 	outStrategy, err := registry.GetOutput(cfg.Output.Type) //
 	if err != nil {
 		return nil, fmt.Errorf("output error: %w", err)
 	}
+	if err := configure(outStrategy, cfg.Output.Params, "output", cfg.Positions); err != nil {
+		return nil, fmt.Errorf("output ('%s') configuration failed: %w", cfg.Output.Type, err)
+	}
 
 	// Processor Chain (Dynamic Creation using the processor_chain_factory)
-	procChain, err := BuildProcessorChain(cfg.Processors)
+	procChain, err := buildProcessorChain(cfg.Processors, cfg.Positions, o)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. Assemble using the Builder
-	return engine.NewEngineBuilder().
+	builder := engine.NewEngineBuilder().
 		WithProvider(prov).
 		WithFormatter(fmtStrategy).
 		WithOutput(outStrategy).
-		WithProcessor(procChain).
-		Build()
+		WithProcessor(procChain)
+	if o.metrics != nil {
+		builder.WithMetrics(o.metrics)
+	}
+	if o.tracer != nil {
+		builder.WithTracer(o.tracer)
+	}
+	if err := applyLimits(builder, cfg, o.limiterRegistry()); err != nil {
+		return nil, err
+	}
+	if cfg.CircuitBreaker != nil {
+		cb, err := newCircuitBreaker(cfg, o)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithCircuitBreaker(cb)
+	}
+	if cfg.Timeouts != nil {
+		builder.WithTimeouts(cfg.Timeouts.ToTimeouts())
+	}
+	if cfg.Fallback != nil {
+		if err := applyFallbacks(builder, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Spool != nil {
+		builder.WithSpool(cfg.Output.Type, spool.New(cfg.Spool.Dir), cfg.Spool.RetryPolicy())
+	}
+	eng, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Optional incremental runs
+	if cfg.Watermark != nil {
+		if incremental, ok := eng.Provider.(provider.IncrementalProvider); !ok || incremental.WatermarkField() == "" {
+			_ = eng.Close()
+			return nil, fmt.Errorf("watermark: provider ('%s') declares no watermark field", cfg.Provider.Type)
+		}
+		eng.WithWatermark(state.NewFileStore(cfg.Watermark.Store), cfg.Watermark.Key)
+	}
+	if cfg.Checkpoint != nil {
+		eng.WithCheckpoints(state.NewFileStore(cfg.Checkpoint.Store), cfg.Checkpoint.Key)
+	}
+	return eng, nil
+}
+
+// applyLimits adds the configured rate limits and bulkheads to builder,
+// shared by name through limiters.
+func applyLimits(builder *engine.EngineBuilder, cfg engine.Config, limiters *resilience.LimiterRegistry) error {
+	if cfg.RateLimit != nil {
+		if rule := cfg.RateLimit.Provider; rule != nil {
+			l, err := limiters.RateLimiter(rule.Name, rule.Rate, rule.Burst)
+			if err != nil {
+				return fmt.Errorf("rate_limit.provider: %w", err)
+			}
+			builder.WithProviderRateLimiter(l)
+		}
+		if rule := cfg.RateLimit.Output; rule != nil {
+			l, err := limiters.RateLimiter(rule.Name, rule.Rate, rule.Burst)
+			if err != nil {
+				return fmt.Errorf("rate_limit.output: %w", err)
+			}
+			builder.WithOutputRateLimiter(l)
+		}
+	}
+	if cfg.Bulkhead != nil {
+		if rule := cfg.Bulkhead.Provider; rule != nil {
+			b, err := limiters.Bulkhead(rule.Name, rule.MaxConcurrent, maxWait(rule))
+			if err != nil {
+				return fmt.Errorf("bulkhead.provider: %w", err)
+			}
+			builder.WithProviderBulkhead(b)
+		}
+		if rule := cfg.Bulkhead.Output; rule != nil {
+			b, err := limiters.Bulkhead(rule.Name, rule.MaxConcurrent, maxWait(rule))
+			if err != nil {
+				return fmt.Errorf("bulkhead.output: %w", err)
+			}
+			builder.WithOutputBulkhead(b)
+		}
+	}
+	return nil
+}
+
+// applyFallbacks creates the configured fallback providers and outputs
+// and adds them to builder. Alternatives are named after their type.
+func applyFallbacks(builder *engine.EngineBuilder, cfg engine.Config) error {
+	var providers []resilience.ProviderAlternative
+	for i, pc := range cfg.Fallback.Providers {
+		prov, err := registry.GetProvider(pc.Type)
+		if err != nil {
+			return fmt.Errorf("fallback provider error: %w", err)
+		}
+		if err := configure(prov, pc.Params, fmt.Sprintf("fallback.providers[%d]", i), cfg.Positions); err != nil {
+			return fmt.Errorf("fallback provider %d ('%s') configuration failed: %w", i, pc.Type, err)
+		}
+		providers = append(providers, resilience.ProviderAlternative{Name: pc.Type, Provider: prov})
+	}
+	if len(providers) > 0 {
+		builder.WithProviderFallbacks(cfg.Provider.Type, providers...)
+	}
+
+	var outputs []resilience.OutputAlternative
+	for i, oc := range cfg.Fallback.Outputs {
+		out, err := registry.GetOutput(oc.Type)
+		if err != nil {
+			return fmt.Errorf("fallback output error: %w", err)
+		}
+		if err := configure(out, oc.Params, fmt.Sprintf("fallback.outputs[%d]", i), cfg.Positions); err != nil {
+			return fmt.Errorf("fallback output %d ('%s') configuration failed: %w", i, oc.Type, err)
+		}
+		outputs = append(outputs, resilience.OutputAlternative{Name: oc.Type, Output: out})
+	}
+	if len(outputs) > 0 {
+		builder.WithOutputFallbacks(cfg.Output.Type, outputs...)
+	}
+	return nil
+}
+
+// newCircuitBreaker returns the configured circuit breaker, shared by name
+// through the factory's breaker registry. A new breaker reports its state
+// changes to the factory's metrics and the log.
+func newCircuitBreaker(cfg engine.Config, o *options) (*resilience.CircuitBreaker, error) {
+	cb := cfg.CircuitBreaker
+	name := cb.Name
+	if name == "" {
+		name = cfg.Provider.Type
+	}
+
+	// Durations are validated by cfg.Validate; empty means the default
+	resetTimeout, _ := time.ParseDuration(cb.ResetTimeout)
+	logger := logging.NewLogger(logging.Config{
+		Level:     logging.LevelInfo,
+		Format:    logging.FormatJSON,
+		Component: "circuit_breaker",
+	})
+	opts := []resilience.CircuitBreakerOption{
+		resilience.WithHalfOpenCalls(cb.HalfOpenCalls),
+		resilience.WithStateListener(observability.NewBreakerListener(o.metrics, logger)),
+	}
+	switch cb.Window {
+	case "count":
+		opts = append(opts, resilience.WithCountWindow(cb.WindowSize, cb.FailureRate, cb.MinCalls))
+	case "time":
+		window, _ := time.ParseDuration(cb.WindowDuration)
+		opts = append(opts, resilience.WithTimeWindow(window, cb.FailureRate, cb.MinCalls))
+	}
+	breaker, err := o.breakerRegistry().CircuitBreaker(name, cb.FailureThreshold, resetTimeout, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("circuit_breaker: %w", err)
+	}
+	return breaker, nil
+}
+
+// maxWait returns the queue wait of a validated bulkhead rule; empty
+// means zero, waiting for the run's context.
+func maxWait(rule *engine.BulkheadRule) time.Duration {
+	d, _ := time.ParseDuration(rule.MaxWait)
+	return d
+}
+
+// configure passes params to a component if it implements api.Configurable.
+// Components that declare their parameters via api.ParamDescriber have
+// params validated first, so typos and malformed values fail the build
+// instead of being silently ignored. Components without configuration
+// support are left untouched.
+//
+// path is the component's config path, such as "processors[1]"; parameter
+// problems are reported with their position in the config file when
+// positions knows it.
+func configure(component interface{}, params map[string]string, path string, positions engine.Positions) error {
+	if describer, ok := component.(api.ParamDescriber); ok {
+		for _, warning := range api.DeprecatedParams(describer.ParamSpecs(), params) {
+			deprecationLogger.Warn("deprecated config parameter", "component", path, "warning", warning)
+		}
+		if err := api.ValidateParams(describer.ParamSpecs(), params); err != nil {
+			var problems api.ParamErrors
+			if errors.As(err, &problems) {
+				for _, problem := range problems {
+					if pos, ok := positions.Locate(path + ".params." + problem.Name); ok {
+						problem.Line, problem.Column = pos.Line, pos.Column
+					}
+				}
+			}
+			return err
+		}
+	}
+	if configurable, ok := component.(api.Configurable); ok {
+		return configurable.Configure(params)
+	}
+	return nil
 }
@@ -2,14 +2,21 @@ package factory
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
 	"github.com/AshishBagdane/go-report-engine/internal/engine"
 	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/health"
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
 	"github.com/AshishBagdane/go-report-engine/internal/registry"
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
+	"github.com/AshishBagdane/go-report-engine/internal/state"
 )
 
 // setupRegistries initializes all required registries for testing
@@ -429,6 +436,262 @@ func TestNewEngineFromConfigRunnable(t *testing.T) {
 	}
 }
 
+// TestNewEngineFromConfigConfiguresComponents tests that params reach
+// configurable providers, formatters and outputs.
+func TestNewEngineFromConfigConfiguresComponents(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	config := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output: engine.OutputConfig{
+			Type:   "file",
+			Params: map[string]string{"path": path},
+		},
+	}
+
+	eng, err := NewEngineFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() failed: %v", err)
+	}
+
+	fileOut, ok := eng.Output.(*output.FileOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *output.FileOutput", eng.Output)
+	}
+	if fileOut.Path != path {
+		t.Errorf("FileOutput.Path = %q, want %q", fileOut.Path, path)
+	}
+}
+
+// TestNewEngineFromConfigQueuePipeline tests an event pipeline that reads
+// from one topic and publishes processed rows to another.
+func TestNewEngineFromConfigQueuePipeline(t *testing.T) {
+	setupRegistries()
+	registry.RegisterProvider("queue", func() provider.ProviderStrategy {
+		return provider.NewQueueProvider()
+	})
+	registry.RegisterOutput("queue", func() output.OutputStrategy {
+		return output.NewQueueOutput()
+	})
+
+	b := broker.NewMemoryBroker()
+	broker.Register("factory-test", b)
+	defer broker.Unregister("factory-test")
+
+	ctx := context.Background()
+	_ = b.Publish(ctx, "orders",
+		broker.Message{Value: []byte(`{"id": 1}`)},
+		broker.Message{Value: []byte(`{"id": 2}`)},
+	)
+
+	config := engine.Config{
+		Provider: engine.ProviderConfig{
+			Type:   "queue",
+			Params: map[string]string{"broker": "factory-test", "topic": "orders", "idle_timeout": "20ms"},
+		},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output: engine.OutputConfig{
+			Type:   "queue",
+			Params: map[string]string{"broker": "factory-test", "topic": "order-rows", "key_field": "id"},
+		},
+	}
+
+	eng, err := NewEngineFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() failed: %v", err)
+	}
+	if err := eng.RunWithContext(ctx); err != nil {
+		t.Fatalf("RunWithContext() failed: %v", err)
+	}
+
+	if got := b.Len("order-rows"); got != 2 {
+		t.Errorf("published %d messages, want 2", got)
+	}
+}
+
+// TestNewEngineFromConfigComponentConfigureError tests that configuration
+// errors from components are reported.
+func TestNewEngineFromConfigComponentConfigureError(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+
+	config := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "file"},
+	}
+
+	_, err := NewEngineFromConfig(config)
+	if err == nil {
+		t.Fatal("NewEngineFromConfig() should fail when output is missing required params")
+	}
+	if !strings.Contains(err.Error(), "output ('file') configuration failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestNewEngineFromConfigParamValidation tests that params are checked
+// against the component's declared parameters.
+func TestNewEngineFromConfigParamValidation(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+	registry.RegisterProvider("csv", func() provider.ProviderStrategy {
+		return provider.NewCSVProvider()
+	})
+
+	tests := []struct {
+		name   string
+		config engine.Config
+		want   string
+	}{
+		{
+			"misspelled output param",
+			engine.Config{
+				Provider:  engine.ProviderConfig{Type: "mock"},
+				Formatter: engine.FormatterConfig{Type: "json"},
+				Output:    engine.OutputConfig{Type: "file", Params: map[string]string{"path": "out.json", "mod": "0600"}},
+			},
+			`output ('file') configuration failed: invalid parameters: unknown parameter "mod" (did you mean "mode"?)`,
+		},
+		{
+			"malformed provider param",
+			engine.Config{
+				Provider:  engine.ProviderConfig{Type: "csv", Params: map[string]string{"file_path": "in.csv", "has_header": "maybe"}},
+				Formatter: engine.FormatterConfig{Type: "json"},
+				Output:    engine.OutputConfig{Type: "console"},
+			},
+			`parameter "has_header" must be a bool`,
+		},
+		{
+			"param located in the config file",
+			engine.Config{
+				Provider:  engine.ProviderConfig{Type: "mock"},
+				Formatter: engine.FormatterConfig{Type: "json"},
+				Output:    engine.OutputConfig{Type: "file", Params: map[string]string{"mod": "0600"}},
+				Positions: engine.Positions{
+					"output":            {Line: 5, Column: 1},
+					"output.params":     {Line: 7, Column: 3},
+					"output.params.mod": {Line: 8, Column: 5},
+				},
+			},
+			`missing required parameter "path" (line 7, column 3); unknown parameter "mod" (did you mean "mode"?) (line 8, column 5)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEngineFromConfig(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewEngineFromConfig() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewEngineFromConfigWatermark tests that incremental runs are wired
+// from the watermark config section.
+func TestNewEngineFromConfigWatermark(t *testing.T) {
+	setupRegistries()
+	registry.RegisterProvider("csv", func() provider.ProviderStrategy {
+		return provider.NewCSVProvider()
+	})
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "orders.csv")
+	if err := os.WriteFile(csvPath, []byte("id,total\n1,10\n2,20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, "state.json")
+
+	config := engine.Config{
+		Provider: engine.ProviderConfig{
+			Type:   "csv",
+			Params: map[string]string{"file_path": csvPath, "watermark_field": "id"},
+		},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+		Watermark: &engine.WatermarkConfig{Store: statePath, Key: "orders"},
+	}
+
+	eng, err := NewEngineFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() failed: %v", err)
+	}
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if res.Watermark == nil || !res.Watermark.Committed {
+		t.Fatalf("Watermark = %+v, want committed", res.Watermark)
+	}
+	if v, err := state.NewFileStore(statePath).Get("orders"); err != nil || v != "2" {
+		t.Errorf("stored watermark = %q, %v; want 2", v, err)
+	}
+}
+
+// TestNewEngineFromConfigWatermarkUnsupported tests that a watermark
+// section fails the build when the provider declares no watermark field.
+func TestNewEngineFromConfigWatermarkUnsupported(t *testing.T) {
+	setupRegistries()
+
+	config := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+		Watermark: &engine.WatermarkConfig{Store: filepath.Join(t.TempDir(), "state.json"), Key: "orders"},
+	}
+
+	if _, err := NewEngineFromConfig(config); err == nil || !strings.Contains(err.Error(), "watermark") {
+		t.Errorf("NewEngineFromConfig() error = %v, want a watermark error", err)
+	}
+}
+
+// TestNewEngineFromConfigCheckpoint tests that a config with a checkpoint
+// section builds an engine that completes and leaves no checkpoint behind.
+func TestNewEngineFromConfigCheckpoint(t *testing.T) {
+	setupRegistries()
+	registry.RegisterProvider("csv", func() provider.ProviderStrategy {
+		return provider.NewCSVProvider()
+	})
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "orders.csv")
+	if err := os.WriteFile(csvPath, []byte("id,total\n1,10\n2,20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, "state.json")
+
+	config := engine.Config{
+		Provider:   engine.ProviderConfig{Type: "csv", Params: map[string]string{"file_path": csvPath}},
+		Formatter:  engine.FormatterConfig{Type: "json"},
+		Output:     engine.OutputConfig{Type: "file", Params: map[string]string{"path": filepath.Join(dir, "out.json")}},
+		Checkpoint: &engine.CheckpointConfig{Store: statePath, Key: "export"},
+	}
+
+	eng, err := NewEngineFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() failed: %v", err)
+	}
+	res, err := eng.Execute(context.Background())
+	if err != nil || res.Mode != engine.ModeStreaming {
+		t.Fatalf("Execute() = %v, mode %s", err, res.Mode)
+	}
+	if _, err := state.NewFileStore(statePath).Get("export"); err != state.ErrNotFound {
+		t.Errorf("checkpoint left after a completed run: %v", err)
+	}
+}
+
 // BenchmarkNewEngineFromConfig benchmarks engine creation
 func BenchmarkNewEngineFromConfig(b *testing.B) {
 	setupRegistries()
@@ -473,3 +736,146 @@ func BenchmarkNewEngineFromConfigWithProcessors(b *testing.B) {
 		_, _ = NewEngineFromConfig(config)
 	}
 }
+
+func TestNewEngineFromConfigCircuitBreaker(t *testing.T) {
+	setupRegistries()
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+		CircuitBreaker: &engine.CircuitBreakerConfig{
+			ResetTimeout: "1m", Window: "count", WindowSize: 10, FailureRate: 0.5, MinCalls: 5,
+		},
+	}
+
+	eng, err := NewEngineFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() error = %v", err)
+	}
+
+	res, ok := eng.Health(context.Background())["circuit_breaker"]
+	if !ok {
+		t.Fatal("expected the configured circuit breaker in the engine health")
+	}
+	if res.Status != health.StatusUp || res.Details["name"] != "mock" {
+		t.Errorf("circuit breaker health = %+v, want UP named after the provider", res)
+	}
+}
+
+// blockingProvider waits for the context to end.
+type blockingProvider struct{}
+
+func (p *blockingProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestNewEngineFromConfigTimeouts tests that configured stage timeouts are enforced
+func TestNewEngineFromConfigTimeouts(t *testing.T) {
+	setupRegistries()
+	registry.RegisterProvider("blocking", func() provider.ProviderStrategy {
+		return &blockingProvider{}
+	})
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "blocking"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+		Timeouts:  &engine.TimeoutConfig{Run: "1m", Fetch: "20ms"},
+	}
+
+	eng, err := NewEngineFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() error = %v", err)
+	}
+
+	res, err := eng.Execute(context.Background())
+	if err == nil {
+		t.Fatal("expected the fetch stage to time out")
+	}
+	if res.FailedStage != engine.StageFetch || res.ErrorType != "transient" {
+		t.Errorf("result = stage %q, type %q, want a transient fetch failure", res.FailedStage, res.ErrorType)
+	}
+}
+
+// failingOutput always fails to send.
+type failingOutput struct{}
+
+func (o *failingOutput) Send(ctx context.Context, data []byte) error {
+	return fmt.Errorf("drop unavailable")
+}
+
+// TestNewEngineFromConfigFallback tests that configured fallback outputs serve the run
+func TestNewEngineFromConfigFallback(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("failing", func() output.OutputStrategy {
+		return &failingOutput{}
+	})
+	registry.RegisterOutput("file", func() output.OutputStrategy {
+		return output.NewFileOutput()
+	})
+	path := filepath.Join(t.TempDir(), "spool.json")
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "failing"},
+		Fallback: &engine.FallbackConfig{
+			Outputs: []engine.OutputConfig{{Type: "file", Params: map[string]string{"path": path}}},
+		},
+	}
+
+	eng, err := NewEngineFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() error = %v", err)
+	}
+	res, err := eng.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(res.Sources) != 1 || res.Sources[0].Source != "file" || !res.Sources[0].Fallback {
+		t.Errorf("Sources = %+v, want the file fallback", res.Sources)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the report in the spool file: %v", err)
+	}
+}
+
+// TestNewEngineFromConfigSpool tests that a spooled output keeps reports it cannot deliver
+func TestNewEngineFromConfigSpool(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("failing", func() output.OutputStrategy {
+		return &failingOutput{}
+	})
+	dir := t.TempDir()
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "failing"},
+		Spool:     &engine.SpoolConfig{Dir: dir, MaxRetries: 1, BaseDelay: "1ms", MaxDelay: "1ms"},
+	}
+
+	eng, err := NewEngineFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig() error = %v", err)
+	}
+	if _, ok := eng.Output.(*spool.Output); !ok {
+		t.Fatalf("Output = %T, want *spool.Output", eng.Output)
+	}
+	if _, err := eng.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() should succeed once the report is spooled, got %v", err)
+	}
+	if err := eng.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	entries, err := spool.New(dir).List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List() = %+v, %v; want the undelivered report", entries, err)
+	}
+	if entries[0].Output != "failing" || entries[0].Attempts != 1 {
+		t.Errorf("entry = %+v", entries[0])
+	}
+}
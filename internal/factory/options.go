@@ -0,0 +1,103 @@
+package factory
+
+import (
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// Option configures how engines and processor chains are built.
+type Option func(*options)
+
+type options struct {
+	metrics  observability.MetricsCollector
+	tracer   observability.Tracer
+	limiters *resilience.LimiterRegistry
+	breakers *resilience.BreakerRegistry
+}
+
+// WithMetrics records metrics for every processor of a chain, tagged with
+// its type and position (see observability.ChainObserver). Engines are
+// also built with EngineBuilder.WithMetrics.
+func WithMetrics(collector observability.MetricsCollector) Option {
+	return func(o *options) {
+		o.metrics = collector
+	}
+}
+
+// WithTracer traces every processor of a chain with a "processor.step"
+// span (see observability.ChainObserver). Engines are also built with
+// EngineBuilder.WithTracer.
+func WithTracer(tracer observability.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithLimiters looks up the rate limiters and bulkheads named in configs
+// in limiters instead of resilience.DefaultLimiters, so only engines built
+// with the same registry share them.
+func WithLimiters(limiters *resilience.LimiterRegistry) Option {
+	return func(o *options) {
+		o.limiters = limiters
+	}
+}
+
+// WithBreakers looks up the circuit breakers named in configs in breakers
+// instead of resilience.DefaultBreakers, so only engines built with the
+// same registry share them.
+func WithBreakers(breakers *resilience.BreakerRegistry) Option {
+	return func(o *options) {
+		o.breakers = breakers
+	}
+}
+
+// NewEngineFactory returns a function that builds engines from configs
+// like NewEngineFromConfig, applying opts. It fits
+// scheduler.WithEngineFactory:
+//
+//	sched := scheduler.New(scheduler.WithEngineFactory(
+//	    factory.NewEngineFactory(factory.WithMetrics(collector)),
+//	))
+func NewEngineFactory(opts ...Option) func(cfg engine.Config) (*engine.ReportEngine, error) {
+	o := newOptions(opts)
+	return func(cfg engine.Config) (*engine.ReportEngine, error) {
+		return newEngine(cfg, o)
+	}
+}
+
+// newOptions applies opts.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// linkObserver returns the observer for chain links, or nil if chains
+// are not instrumented.
+func (o *options) linkObserver() *observability.ChainObserver {
+	if o.metrics == nil && o.tracer == nil {
+		return nil
+	}
+	return observability.NewChainObserver(o.tracer, o.metrics)
+}
+
+// limiterRegistry returns the registry shared rate limiters and bulkheads
+// are looked up in.
+func (o *options) limiterRegistry() *resilience.LimiterRegistry {
+	if o.limiters == nil {
+		return resilience.DefaultLimiters
+	}
+	return o.limiters
+}
+
+// breakerRegistry returns the registry shared circuit breakers are looked
+// up in.
+func (o *options) breakerRegistry() *resilience.BreakerRegistry {
+	if o.breakers == nil {
+		return resilience.DefaultBreakers
+	}
+	return o.breakers
+}
@@ -0,0 +1,263 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/registry"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// stepCounts sums counters by name and processor type.
+type stepCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newStepCounts() *stepCounts {
+	return &stepCounts{counts: make(map[string]int)}
+}
+
+func (c *stepCounts) Count(name string, value int, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[name+"/"+tags["processor_type"]+"@"+tags["position"]] += value
+}
+
+func (c *stepCounts) Gauge(name string, value float64, tags map[string]string)     {}
+func (c *stepCounts) Histogram(name string, value float64, tags map[string]string) {}
+
+func TestBuildProcessorChainWithMetrics(t *testing.T) {
+	setupProcessorRegistries()
+	collector := newStepCounts()
+
+	chain, err := BuildProcessorChain([]engine.ProcessorConfig{
+		{Type: "test_transformer"},
+		{Type: "test_filter"},
+	}, WithMetrics(collector))
+	if err != nil {
+		t.Fatalf("BuildProcessorChain() error = %v", err)
+	}
+
+	data := []map[string]interface{}{{"value": 10}, {"value": 60}, {"value": 70}}
+	result, err := chain.Process(context.Background(), data)
+	if err != nil || len(result) != 2 {
+		t.Fatalf("Process() = %d records, %v; want 2", len(result), err)
+	}
+
+	for key, want := range map[string]int{
+		"report_engine_processor_step_records_in_total/test_transformer@0":  3,
+		"report_engine_processor_step_records_out_total/test_transformer@0": 3,
+		"report_engine_processor_step_records_in_total/test_filter@1":       3,
+		"report_engine_processor_step_records_out_total/test_filter@1":      2,
+	} {
+		if got := collector.counts[key]; got != want {
+			t.Errorf("%s = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestBuildProcessorChainUninstrumented(t *testing.T) {
+	setupProcessorRegistries()
+	collector := newStepCounts()
+
+	chain, err := BuildProcessorChain([]engine.ProcessorConfig{{Type: "test_filter"}})
+	if err != nil {
+		t.Fatalf("BuildProcessorChain() error = %v", err)
+	}
+	if _, err := chain.Process(context.Background(), []map[string]interface{}{{"value": 60}}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(collector.counts) != 0 {
+		t.Errorf("uninstrumented chain recorded %v", collector.counts)
+	}
+}
+
+func TestNewEngineFactory(t *testing.T) {
+	setupRegistries()
+	setupProcessorRegistries()
+	registry.RegisterOutput("discard", func() output.OutputStrategy { return &discardOutput{} })
+	collector := newStepCounts()
+
+	newEngine := NewEngineFactory(WithMetrics(collector))
+	eng, err := newEngine(engine.Config{
+		Provider:   engine.ProviderConfig{Type: "mock"},
+		Processors: []engine.ProcessorConfig{{Type: "test_transformer"}},
+		Formatter:  engine.FormatterConfig{Type: "json"},
+		Output:     engine.OutputConfig{Type: "discard"},
+	})
+	if err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+	if err := eng.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := collector.counts["report_engine_processor_step_records_in_total/test_transformer@0"]; got != 2 {
+		t.Errorf("step records in = %d, want 2", got)
+	}
+	// Engine-level metrics come from the builder decorators
+	if got := collector.counts["report_engine_provider_records_count/@"]; got != 2 {
+		t.Errorf("provider records = %d, want 2", got)
+	}
+}
+
+// discardOutput drops reports.
+type discardOutput struct{}
+
+func (discardOutput) Send(ctx context.Context, data []byte) error { return nil }
+
+func TestWithLimitersSharesRateLimits(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("discard", func() output.OutputStrategy { return &discardOutput{} })
+	limiters := resilience.NewLimiterRegistry()
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "discard"},
+		RateLimit: &engine.RateLimitConfig{
+			Provider: &engine.RateLimitRule{Name: "partner-api", Rate: 0.01, Burst: 1},
+		},
+		Bulkhead: &engine.BulkheadConfig{
+			Output: &engine.BulkheadRule{Name: "archive", MaxConcurrent: 2},
+		},
+	}
+
+	newEngine := NewEngineFactory(WithLimiters(limiters))
+	first, err := newEngine(cfg)
+	if err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+	second, err := newEngine(cfg)
+	if err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+
+	if err := first.Run(); err != nil {
+		t.Fatalf("first Run() error = %v", err)
+	}
+
+	// The first run used the only token of the shared limiter
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := second.RunWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the second run to wait for the shared limit, got %v", err)
+	}
+
+	archive, err := limiters.Bulkhead("archive", 2, 0)
+	if err != nil {
+		t.Fatalf("Bulkhead() error = %v", err)
+	}
+	if got := archive.InFlight(); got != 0 {
+		t.Errorf("expected the output bulkhead slots to be released, got %d in flight", got)
+	}
+}
+
+func TestWithLimitersRejectsMismatchedLimits(t *testing.T) {
+	setupRegistries()
+	registry.RegisterOutput("discard", func() output.OutputStrategy { return &discardOutput{} })
+	limiters := resilience.NewLimiterRegistry()
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "discard"},
+		RateLimit: &engine.RateLimitConfig{
+			Provider: &engine.RateLimitRule{Name: "partner-api", Rate: 5, Burst: 1},
+		},
+	}
+	newEngine := NewEngineFactory(WithLimiters(limiters))
+	if _, err := newEngine(cfg); err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+
+	cfg.RateLimit.Provider.Rate = 50
+	if err := ValidateConfig(cfg, WithLimiters(limiters)); !errors.Is(err, resilience.ErrLimitMismatch) {
+		t.Errorf("expected ValidateConfig to reject the changed limit, got %v", err)
+	}
+	if _, err := newEngine(cfg); !errors.Is(err, resilience.ErrLimitMismatch) {
+		t.Errorf("expected the factory to reject the changed limit, got %v", err)
+	}
+	l, _ := limiters.RateLimiter("partner-api", 5, 1)
+	if rate, _ := l.Limit(); rate != 5 {
+		t.Errorf("expected the shared limit to be kept, got rate %g", rate)
+	}
+
+	// Validating a new name does not register it
+	cfg.RateLimit.Provider.Name = "reporting-db"
+	if err := ValidateConfig(cfg, WithLimiters(limiters)); err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if _, err := limiters.RateLimiter("reporting-db", 1, 1); err != nil {
+		t.Errorf("expected validation not to register the limiter, got %v", err)
+	}
+}
+
+// brokenProvider always fails and counts its calls.
+type brokenProvider struct {
+	calls *int
+}
+
+func (p brokenProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	*p.calls++
+	return nil, errors.New("partner api down")
+}
+
+func TestWithBreakersSharesState(t *testing.T) {
+	setupRegistries()
+	calls := 0
+	registry.RegisterProvider("broken", func() provider.ProviderStrategy { return brokenProvider{calls: &calls} })
+	registry.RegisterOutput("discard", func() output.OutputStrategy { return &discardOutput{} })
+	breakers := resilience.NewBreakerRegistry()
+
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "broken"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "discard"},
+		CircuitBreaker: &engine.CircuitBreakerConfig{
+			Name: "partner-api", FailureThreshold: 1, ResetTimeout: "1m",
+		},
+	}
+
+	// Two factories, like two schedulers or a scheduler building an
+	// engine per run
+	first, err := NewEngineFactory(WithBreakers(breakers))(cfg)
+	if err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+	second, err := NewEngineFactory(WithBreakers(breakers))(cfg)
+	if err != nil {
+		t.Fatalf("factory error = %v", err)
+	}
+
+	if err := first.Run(); err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+	if err := second.Run(); !errors.Is(err, resilience.ErrCircuitOpen) {
+		t.Errorf("expected the breaker opened by the first engine to reject the second, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the open breaker to spare the provider, got %d calls", calls)
+	}
+
+	cfg.CircuitBreaker.FailureThreshold = 5
+	if _, err := NewEngineFactory(WithBreakers(breakers))(cfg); !errors.Is(err, resilience.ErrBreakerMismatch) {
+		t.Errorf("expected other settings for the shared breaker to be rejected, got %v", err)
+	}
+
+	// Validating a new name does not register it
+	cfg.CircuitBreaker.Name = "warehouse-db"
+	if err := ValidateConfig(cfg, WithBreakers(breakers)); err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if _, err := breakers.CircuitBreaker("warehouse-db", 1, time.Minute); err != nil {
+		t.Errorf("expected validation not to register the breaker, got %v", err)
+	}
+}
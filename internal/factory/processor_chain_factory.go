@@ -6,12 +6,21 @@ import (
 	"github.com/AshishBagdane/go-report-engine/internal/engine"    // For ProcessorConfig
 	"github.com/AshishBagdane/go-report-engine/internal/processor" // For ProcessorHandler
 	"github.com/AshishBagdane/go-report-engine/internal/registry"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // BuildProcessorChain reads a list of configurations and links them together
-// using the Chain of Responsibility pattern.
-func BuildProcessorChain(configs []engine.ProcessorConfig) (processor.ProcessorHandler, error) {
+// using the Chain of Responsibility pattern. Each processor is wrapped in a
+// processor.Link so runs can report per-processor statistics. With
+// WithMetrics or WithTracer, each link is also instrumented on its own.
+func BuildProcessorChain(configs []engine.ProcessorConfig, opts ...Option) (processor.ProcessorHandler, error) {
+	return buildProcessorChain(configs, nil, newOptions(opts))
+}
+
+// buildProcessorChain builds a chain with the given options. positions
+// locates the processors' params in the config file, if known.
+func buildProcessorChain(configs []engine.ProcessorConfig, positions engine.Positions, o *options) (processor.ProcessorHandler, error) {
+	observer := o.linkObserver()
+
 	if len(configs) == 0 {
 		// Return a default base processor if no chain is defined
 		return &processor.BaseProcessor{}, nil //
@@ -26,21 +35,23 @@ func BuildProcessorChain(configs []engine.ProcessorConfig) (processor.ProcessorH
 			return nil, fmt.Errorf("step %d ('%s') factory failed: %w", i, cfg.Type, err)
 		}
 
-		// 2. Configure the instance if it's configurable
+		// 2. Validate params and configure the instance if it's configurable
 		// The wrappers implement Configure to pass params to the user's strategy
-		if configurable, ok := procInstance.(api.Configurable); ok {
-			if err := configurable.Configure(cfg.Params); err != nil {
-				return nil, fmt.Errorf("step %d ('%s') configuration failed: %w", i, cfg.Type, err)
-			}
+		if err := configure(procInstance, cfg.Params, fmt.Sprintf("processors[%d]", i), positions); err != nil {
+			return nil, fmt.Errorf("step %d ('%s') configuration failed: %w", i, cfg.Type, err)
 		}
 
 		// 3. Link the chain
+		link := processor.NewLink(procInstance, i, cfg.Type)
+		if observer != nil {
+			link.WithObserver(observer)
+		}
 		if head == nil {
-			head = procInstance
-			current = procInstance
+			head = link
+			current = link
 		} else {
-			current.SetNext(procInstance) //
-			current = procInstance
+			current.SetNext(link) //
+			current = link
 		}
 	}
 
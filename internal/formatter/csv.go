@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // CSVFormatter implements FormatterStrategy for CSV output.
@@ -84,6 +86,14 @@ func (f *CSVFormatter) Format(ctx context.Context, data []map[string]interface{}
 	return buf.Bytes(), nil
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (f *CSVFormatter) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "delimiter", Type: api.ParamString, Default: ",", Description: "Field separator character"},
+		{Name: "include_header", Type: api.ParamBool, Default: "true", Description: "Write a header row"},
+	}
+}
+
 // Configure sets up the formatter from a map of parameters.
 // Params:
 // - delimiter: Character separator (default: ",")
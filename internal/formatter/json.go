@@ -3,6 +3,11 @@ package formatter
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // JSONFormatter formats data as JSON with optional indentation.
@@ -51,6 +56,41 @@ func NewJSONFormatter(indent string) *JSONFormatter {
 	}
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (j *JSONFormatter) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "indent", Type: api.ParamString, Description: "Number of spaces, tab, or a literal indent string (0 for compact)"},
+		{Name: "pretty", Type: api.ParamBool, Description: "Ignored; indent controls pretty-printing", Deprecated: "it has no effect, set indent instead"},
+	}
+}
+
+// Configure sets up the formatter from a map of parameters.
+// Params:
+// - indent: Number of spaces, "tab", or a literal indent string ("0" or "" for compact JSON)
+// - pretty: Deprecated and ignored; indent controls pretty-printing
+func (j *JSONFormatter) Configure(params map[string]string) error {
+	indent, ok := params["indent"]
+	if !ok {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(indent); err == nil {
+		if n < 0 {
+			return fmt.Errorf("json formatter: indent must not be negative")
+		}
+		j.Indent = strings.Repeat(" ", n)
+		return nil
+	}
+
+	if strings.EqualFold(indent, "tab") {
+		j.Indent = "\t"
+		return nil
+	}
+
+	j.Indent = indent
+	return nil
+}
+
 // Format converts data to JSON format.
 // The output is either compact or indented based on the Indent setting.
 //
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // TestNewJSONFormatter tests the factory function
@@ -19,6 +21,51 @@ func TestNewJSONFormatter(t *testing.T) {
 	var _ FormatStrategy = formatter
 }
 
+// TestJSONFormatterConfigure tests indent parameter parsing
+func TestJSONFormatterConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "no params keeps default", params: map[string]string{}, want: "  "},
+		{name: "spaces", params: map[string]string{"indent": "4"}, want: "    "},
+		{name: "compact", params: map[string]string{"indent": "0"}, want: ""},
+		{name: "empty", params: map[string]string{"indent": ""}, want: ""},
+		{name: "tab", params: map[string]string{"indent": "tab"}, want: "\t"},
+		{name: "literal", params: map[string]string{"indent": "--"}, want: "--"},
+		{name: "negative", params: map[string]string{"indent": "-1"}, wantErr: true},
+		{name: "deprecated pretty is ignored", params: map[string]string{"indent": "4", "pretty": "true"}, want: "    "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewJSONFormatter("  ")
+			err := f.Configure(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && f.Indent != tt.want {
+				t.Errorf("Indent = %q, want %q", f.Indent, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONFormatterDeprecatedPretty tests that configs setting pretty
+// still validate, with a deprecation warning
+func TestJSONFormatterDeprecatedPretty(t *testing.T) {
+	specs := NewJSONFormatter("").ParamSpecs()
+	params := map[string]string{"indent": "2", "pretty": "true"}
+	if err := api.ValidateParams(specs, params); err != nil {
+		t.Errorf("ValidateParams() error = %v, want pretty accepted", err)
+	}
+	if warnings := api.DeprecatedParams(specs, params); len(warnings) != 1 || !strings.Contains(warnings[0], `"pretty" is deprecated`) {
+		t.Errorf("DeprecatedParams() = %v", warnings)
+	}
+}
+
 // TestJSONFormatterFormat tests basic formatting
 func TestJSONFormatterFormat(t *testing.T) {
 	formatter := NewJSONFormatter("  ")
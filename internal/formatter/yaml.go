@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 	"gopkg.in/yaml.v3"
 )
 
@@ -46,6 +47,13 @@ func (f *YAMLFormatter) Format(ctx context.Context, data []map[string]interface{
 	return buf.Bytes(), nil
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (f *YAMLFormatter) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "indent", Type: api.ParamInt, Default: "2", Description: "Number of spaces for indentation"},
+	}
+}
+
 // Configure sets up the formatter from a map of parameters.
 // Params:
 // - indent: Number of spaces for indentation (default: "2")
@@ -0,0 +1,185 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CheckerFunc adapts a function to the Checker interface.
+type CheckerFunc func(ctx context.Context) (Result, error)
+
+// CheckHealth calls f(ctx).
+func (f CheckerFunc) CheckHealth(ctx context.Context) (Result, error) {
+	return f(ctx)
+}
+
+// Aggregate combines component results into an overall result. The overall
+// status is DOWN if any component is down, DEGRADED if any component is
+// degraded (or reports an unrecognized status) and UP otherwise. The
+// component results are returned under Details["components"].
+func Aggregate(results map[string]Result) Result {
+	return aggregate(results, nil)
+}
+
+// aggregate implements Aggregate; components in optional only degrade the
+// overall status when they are down.
+func aggregate(results map[string]Result, optional map[string]bool) Result {
+	status := StatusUp
+	var down []string
+
+	for name, res := range results {
+		switch res.Status {
+		case StatusUp:
+		case StatusDown:
+			if optional[name] {
+				if status == StatusUp {
+					status = StatusDegraded
+				}
+				continue
+			}
+			status = StatusDown
+			down = append(down, name)
+		default:
+			if status == StatusUp {
+				status = StatusDegraded
+			}
+		}
+	}
+
+	overall := Result{
+		Status:  status,
+		Details: map[string]interface{}{"components": results},
+	}
+	if len(down) > 0 {
+		sort.Strings(down)
+		overall.Error = fmt.Sprintf("components down: %s", strings.Join(down, ", "))
+	}
+	return overall
+}
+
+// Composite is a Checker that checks a set of named components concurrently
+// and aggregates their results.
+//
+// Components are critical by default: a critical component that is down
+// takes the composite down. Optional components only degrade it, so a
+// service can keep serving while, say, a secondary output is unavailable.
+//
+// Thread-safe: Yes.
+type Composite struct {
+	mu       sync.RWMutex
+	checkers map[string]Checker
+	optional map[string]bool
+}
+
+// NewComposite creates an empty composite checker.
+func NewComposite() *Composite {
+	return &Composite{
+		checkers: make(map[string]Checker),
+		optional: make(map[string]bool),
+	}
+}
+
+// Register adds a critical component, replacing any with the same name.
+func (c *Composite) Register(name string, checker Checker) {
+	c.register(name, checker, false)
+}
+
+// RegisterOptional adds a component whose failure degrades rather than
+// fails the composite.
+func (c *Composite) RegisterOptional(name string, checker Checker) {
+	c.register(name, checker, true)
+}
+
+func (c *Composite) register(name string, checker Checker, optional bool) {
+	if name == "" || checker == nil {
+		panic("health: component name and checker are required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkers[name] = checker
+	c.optional[name] = optional
+}
+
+// Unregister removes a component.
+func (c *Composite) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checkers, name)
+	delete(c.optional, name)
+}
+
+// Names returns the registered component names in sorted order.
+func (c *Composite) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.checkers))
+	for name := range c.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckHealth runs every component check concurrently and aggregates the
+// results. A check that returns an error without a status is reported as
+// DOWN. The returned error is non-nil only when the composite is DOWN.
+func (c *Composite) CheckHealth(ctx context.Context) (Result, error) {
+	c.mu.RLock()
+	checkers := make(map[string]Checker, len(c.checkers))
+	optional := make(map[string]bool, len(c.optional))
+	for name, checker := range c.checkers {
+		checkers[name] = checker
+		optional[name] = c.optional[name]
+	}
+	c.mu.RUnlock()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]Result, len(checkers))
+	)
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := check(ctx, checker)
+			mu.Lock()
+			results[name] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	overall := aggregate(results, optional)
+	if overall.Status == StatusDown {
+		return overall, fmt.Errorf("health: %s", overall.Error)
+	}
+	return overall, nil
+}
+
+// check runs a single checker, normalizing errors and panics to DOWN.
+func check(ctx context.Context, checker Checker) (res Result) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			res = Result{Status: StatusDown, Error: fmt.Sprintf("health check panicked: %v", rec)}
+		}
+	}()
+
+	res, err := checker.CheckHealth(ctx)
+	if err != nil {
+		if res.Status == "" {
+			res.Status = StatusDown
+		}
+		if res.Error == "" {
+			res.Error = err.Error()
+		}
+	}
+	if res.Status == "" {
+		res.Status = StatusUp
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func status(s Status) Checker {
+	return CheckerFunc(func(ctx context.Context) (Result, error) {
+		return Result{Status: s}, nil
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[string]Result
+		want    Status
+	}{
+		{name: "empty", results: map[string]Result{}, want: StatusUp},
+		{name: "all up", results: map[string]Result{"a": {Status: StatusUp}, "b": {Status: StatusUp}}, want: StatusUp},
+		{name: "degraded", results: map[string]Result{"a": {Status: StatusUp}, "b": {Status: StatusDegraded}}, want: StatusDegraded},
+		{name: "unknown status", results: map[string]Result{"a": {Status: "WEIRD"}}, want: StatusDegraded},
+		{name: "down wins", results: map[string]Result{"a": {Status: StatusDegraded}, "b": {Status: StatusDown}}, want: StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Aggregate(tt.results)
+			if res.Status != tt.want {
+				t.Errorf("Status = %v, want %v", res.Status, tt.want)
+			}
+			if _, ok := res.Details["components"]; !ok {
+				t.Error("Details should include components")
+			}
+		})
+	}
+
+	res := Aggregate(map[string]Result{"b": {Status: StatusDown}, "a": {Status: StatusDown}})
+	if res.Error != "components down: a, b" {
+		t.Errorf("Error = %q", res.Error)
+	}
+}
+
+func TestCompositeCheckHealth(t *testing.T) {
+	c := NewComposite()
+	c.Register("db", status(StatusUp))
+	c.RegisterOptional("cache", status(StatusUp))
+
+	res, err := c.CheckHealth(context.Background())
+	if err != nil || res.Status != StatusUp {
+		t.Fatalf("CheckHealth() = %v, %v; want UP", res.Status, err)
+	}
+
+	// Optional component down only degrades the composite
+	c.RegisterOptional("cache", status(StatusDown))
+	res, err = c.CheckHealth(context.Background())
+	if err != nil || res.Status != StatusDegraded {
+		t.Fatalf("CheckHealth() = %v, %v; want DEGRADED", res.Status, err)
+	}
+
+	// Critical component failing with an error takes it down
+	c.Register("db", CheckerFunc(func(ctx context.Context) (Result, error) {
+		return Result{}, errors.New("connection refused")
+	}))
+	res, err = c.CheckHealth(context.Background())
+	if err == nil || res.Status != StatusDown {
+		t.Fatalf("CheckHealth() = %v, %v; want DOWN with error", res.Status, err)
+	}
+	components := res.Details["components"].(map[string]Result)
+	if components["db"].Error != "connection refused" {
+		t.Errorf("db result = %+v", components["db"])
+	}
+
+	c.Unregister("db")
+	if names := c.Names(); len(names) != 1 || names[0] != "cache" {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestCompositeRecoversPanics(t *testing.T) {
+	c := NewComposite()
+	c.Register("boom", CheckerFunc(func(ctx context.Context) (Result, error) {
+		panic("bad checker")
+	}))
+
+	res, err := c.CheckHealth(context.Background())
+	if err == nil || res.Status != StatusDown {
+		t.Errorf("CheckHealth() = %v, %v; want DOWN with error", res.Status, err)
+	}
+}
+
+func TestCompositeRegisterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register with nil checker should panic")
+		}
+	}()
+	NewComposite().Register("x", nil)
+}
@@ -0,0 +1,403 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+// DefaultMaxRuns is the number of runs a FileHistory keeps by default.
+const DefaultMaxRuns = 1000
+
+// Lock timing of a FileHistory. A lock older than staleLock was left
+// behind by a process that crashed while writing, and is taken over.
+const (
+	lockRetry   = 10 * time.Millisecond
+	lockTimeout = 10 * time.Second
+	staleLock   = time.Minute
+)
+
+// Compaction thresholds: the file is rewritten once it holds more than
+// compactRatio lines per retained run, plus compactSlack lines.
+const (
+	compactRatio = 4
+	compactSlack = 100
+)
+
+// FileHistoryOption configures a FileHistory.
+type FileHistoryOption func(*FileHistory)
+
+// WithMaxRuns keeps only the newest n runs (default: DefaultMaxRuns). A
+// limit <= 0 keeps every run.
+func WithMaxRuns(n int) FileHistoryOption {
+	return func(f *FileHistory) {
+		f.maxRuns = n
+	}
+}
+
+// WithMaxAge drops runs scheduled more than d ago (default: no limit).
+func WithMaxAge(d time.Duration) FileHistoryOption {
+	return func(f *FileHistory) {
+		f.maxAge = d
+	}
+}
+
+// FileHistory is a scheduler.History that keeps runs in a JSON Lines file,
+// one run per line.
+//
+// Save appends a line, so the updates a scheduler saves as a run changes
+// status add newer versions; readers use the last version of each ID.
+// Runs beyond the retention limits are dropped, and once most of the file
+// is superseded or dropped lines, Save compacts it to one line per kept
+// run. Lines that cannot be decoded, such as a line truncated by a crash,
+// are skipped.
+//
+// Runs are cached in memory. Get and List only read the file again when
+// another process has changed it, and writers hold a lock file next to
+// the history, so processes can share one file.
+type FileHistory struct {
+	path    string
+	maxRuns int
+	maxAge  time.Duration
+
+	// writing serializes writers in this process; the lock file
+	// serializes them across processes
+	writing sync.Mutex
+
+	mu    sync.Mutex
+	runs  []scheduler.Run // retained runs, in first-saved order
+	index map[string]int  // run ID -> position in runs
+	lines int             // lines in the file, including superseded ones
+	file  os.FileInfo     // the file as of the last read or write
+}
+
+// NewFileHistory creates a history backed by the file at path. The file
+// and its directory are created on the first Save.
+func NewFileHistory(path string, opts ...FileHistoryOption) *FileHistory {
+	f := &FileHistory{
+		path:    path,
+		maxRuns: DefaultMaxRuns,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Path returns the backing file path.
+func (f *FileHistory) Path() string {
+	return f.path
+}
+
+// Save appends the run to the file, compacting it when it has grown too
+// far beyond the runs it keeps.
+func (f *FileHistory) Save(run scheduler.Run) error {
+	if run.ID == "" {
+		return fmt.Errorf("history: run ID cannot be empty")
+	}
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("history: failed to encode run %q: %w", run.ID, err)
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("history: failed to create directory: %w", err)
+	}
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Pick up runs other processes saved since the last read
+	if err := f.sync(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("history: failed to open %s: %w", f.path, err)
+	}
+
+	// A single write keeps each run on one intact line
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("history: failed to write %s: %w", f.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("history: failed to write %s: %w", f.path, err)
+	}
+
+	f.lines++
+	f.put(run)
+	f.prune()
+	if f.lines > compactRatio*len(f.runs)+compactSlack {
+		return f.compact()
+	}
+	return f.stat()
+}
+
+// Get returns a run by ID.
+func (f *FileHistory) Get(id string) (scheduler.Run, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.sync(); err != nil {
+		return scheduler.Run{}, err
+	}
+	i, ok := f.index[id]
+	if !ok {
+		return scheduler.Run{}, scheduler.ErrRunNotFound
+	}
+	return f.runs[i], nil
+}
+
+// List returns runs for job (all jobs if empty), newest first.
+func (f *FileHistory) List(job string, limit int) ([]scheduler.Run, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.sync(); err != nil {
+		return nil, err
+	}
+
+	var listed []scheduler.Run
+	for i := len(f.runs) - 1; i >= 0; i-- {
+		if job != "" && f.runs[i].Job != job {
+			continue
+		}
+		listed = append(listed, f.runs[i])
+		if limit > 0 && len(listed) == limit {
+			break
+		}
+	}
+	return listed, nil
+}
+
+// Compact rewrites the file with the latest version of each run that the
+// retention limits keep. Saves wait for the rewrite, and the file is
+// replaced atomically. Save compacts on its own as the file grows, so
+// calling Compact is only needed to apply new limits right away.
+func (f *FileHistory) Compact() error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.sync(); err != nil {
+		return err
+	}
+	return f.compact()
+}
+
+// compact rewrites the file from the cache. Caller holds the write lock
+// and mu, with the cache in sync.
+func (f *FileHistory) compact() error {
+	var buf bytes.Buffer
+	for _, run := range f.runs {
+		line, err := json.Marshal(run)
+		if err != nil {
+			return fmt.Errorf("history: failed to encode run %q: %w", run.ID, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("history: failed to create temp file: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("history: failed to write %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("history: failed to write %s: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("history: failed to replace %s: %w", f.path, err)
+	}
+
+	f.lines = len(f.runs)
+	return f.stat()
+}
+
+// lock takes the write lock: the in-process mutex, then the lock file
+// that excludes writers in other processes. It waits up to lockTimeout.
+func (f *FileHistory) lock() (unlock func(), err error) {
+	f.writing.Lock()
+	lock := f.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			return func() {
+				_ = os.Remove(lock)
+				f.writing.Unlock()
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			f.writing.Unlock()
+			return nil, fmt.Errorf("history: failed to lock %s: %w", f.path, err)
+		}
+
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > staleLock {
+			// Left behind by a writer that never finished
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			f.writing.Unlock()
+			return nil, fmt.Errorf("history: timed out waiting for lock on %s", f.path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// sync reloads the cache if the file changed since it was last read or
+// written, by this or another process. Caller holds mu.
+func (f *FileHistory) sync() error {
+	info, err := os.Stat(f.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		info = nil
+	case err != nil:
+		return fmt.Errorf("history: failed to stat %s: %w", f.path, err)
+	}
+	if f.index != nil && sameFile(f.file, info) {
+		return nil
+	}
+	return f.load()
+}
+
+// stat records the file as the cache reflects it. Caller holds mu.
+func (f *FileHistory) stat() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("history: failed to stat %s: %w", f.path, err)
+	}
+	f.file = info
+	return nil
+}
+
+// load reads every run into the cache, keeping the latest version of
+// each. A missing file holds no runs. Caller holds mu.
+func (f *FileHistory) load() error {
+	f.runs, f.index, f.lines, f.file = nil, make(map[string]int), 0, nil
+
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("history: failed to open %s: %w", f.path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("history: failed to stat %s: %w", f.path, err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			f.lines++
+			var run scheduler.Run
+			if err := json.Unmarshal(line, &run); err == nil && run.ID != "" {
+				f.put(run)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			f.index = nil
+			return fmt.Errorf("history: failed to read %s: %w", f.path, readErr)
+		}
+	}
+
+	f.prune()
+	f.file = info
+	return nil
+}
+
+// put inserts the run into the cache, or replaces its older version.
+// Caller holds mu.
+func (f *FileHistory) put(run scheduler.Run) {
+	if i, ok := f.index[run.ID]; ok {
+		f.runs[i] = run
+		return
+	}
+	f.index[run.ID] = len(f.runs)
+	f.runs = append(f.runs, run)
+}
+
+// prune drops runs beyond the retention limits from the cache. Caller
+// holds mu.
+func (f *FileHistory) prune() {
+	keep := f.runs
+	if f.maxAge > 0 {
+		cutoff := time.Now().Add(-f.maxAge)
+		kept := keep[:0:0]
+		for _, run := range keep {
+			if !runTime(run).Before(cutoff) {
+				kept = append(kept, run)
+			}
+		}
+		keep = kept
+	}
+	if f.maxRuns > 0 && len(keep) > f.maxRuns {
+		keep = keep[len(keep)-f.maxRuns:]
+	}
+	if len(keep) == len(f.runs) {
+		return
+	}
+
+	f.runs = append([]scheduler.Run(nil), keep...)
+	f.index = make(map[string]int, len(f.runs))
+	for i, run := range f.runs {
+		f.index[run.ID] = i
+	}
+}
+
+// runTime is when a run was scheduled, or started for runs saved without
+// a schedule time.
+func runTime(run scheduler.Run) time.Time {
+	if run.ScheduledAt.IsZero() {
+		return run.StartedAt
+	}
+	return run.ScheduledAt
+}
+
+// sameFile reports whether two stats describe the same, unchanged file.
+func sameFile(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
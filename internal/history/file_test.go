@@ -0,0 +1,219 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+func TestFileHistory(t *testing.T) {
+	testHistory(t, NewFileHistory(filepath.Join(t.TempDir(), "nested", "runs.jsonl")))
+}
+
+func TestFileHistory_MissingFile(t *testing.T) {
+	h := NewFileHistory(filepath.Join(t.TempDir(), "runs.jsonl"))
+	runs, err := h.List("", 0)
+	if err != nil || len(runs) != 0 {
+		t.Errorf("List() = %v, %v; want no runs", runs, err)
+	}
+}
+
+func TestFileHistory_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	if err := NewFileHistory(path).Save(run("r1", "finance", scheduler.RunStatusSucceeded, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := NewFileHistory(path).Get("r1"); err != nil || r.Job != "finance" {
+		t.Errorf("Get() = %+v, %v", r, err)
+	}
+}
+
+func TestFileHistory_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	h := NewFileHistory(path)
+	_ = h.Save(run("r1", "finance", scheduler.RunStatusSucceeded, 0))
+
+	// Simulate a write cut short by a crash
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"id":"r2","job":"fin`)
+	_ = f.Close()
+
+	runs, err := h.List("", 0)
+	if err != nil || len(runs) != 1 {
+		t.Errorf("List() = %d runs, %v; want 1", len(runs), err)
+	}
+}
+
+func TestFileHistory_EmptyID(t *testing.T) {
+	h := NewFileHistory(filepath.Join(t.TempDir(), "runs.jsonl"))
+	if err := h.Save(scheduler.Run{Job: "finance"}); err == nil {
+		t.Error("Save() should reject runs without an ID")
+	}
+}
+
+func TestFileHistory_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	h := NewFileHistory(path)
+	for i, id := range []string{"r1", "r2", "r3"} {
+		_ = h.Save(run(id, "finance", scheduler.RunStatusRunning, i))
+		_ = h.Save(run(id, "finance", scheduler.RunStatusSucceeded, i))
+	}
+
+	// New limits apply on the next compaction
+	h = NewFileHistory(path, WithMaxRuns(2))
+	if err := h.Compact(); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("compacted file has %d lines, want 2", lines)
+	}
+	runs, _ := NewFileHistory(path).List("", 0)
+	if len(runs) != 2 || runs[0].ID != "r3" || runs[1].ID != "r2" || runs[0].Status != scheduler.RunStatusSucceeded {
+		t.Errorf("List() after Compact = %+v", runs)
+	}
+}
+
+// TestFileHistory_Retention tests that old runs are dropped and the file
+// is compacted as it grows
+func TestFileHistory_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	h := NewFileHistory(path, WithMaxRuns(5))
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("r%03d", i)
+		_ = h.Save(run(id, "finance", scheduler.RunStatusRunning, i))
+		if err := h.Save(run(id, "finance", scheduler.RunStatusSucceeded, i)); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	runs, _ := h.List("", 0)
+	if len(runs) != 5 || runs[0].ID != "r199" || runs[4].ID != "r195" {
+		t.Errorf("List() = %d runs, newest %s; want the 5 newest", len(runs), runs[0].ID)
+	}
+	if _, err := h.Get("r000"); err == nil {
+		t.Error("runs beyond the limit should be dropped")
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines > compactRatio*5+compactSlack+1 {
+		t.Errorf("file has %d lines, want it compacted", lines)
+	}
+
+	// A fresh reader applies the same limits
+	if runs, _ := NewFileHistory(path, WithMaxRuns(5)).List("", 0); len(runs) != 5 {
+		t.Errorf("reloaded List() = %d runs, want 5", len(runs))
+	}
+}
+
+func TestFileHistory_MaxAge(t *testing.T) {
+	h := NewFileHistory(filepath.Join(t.TempDir(), "runs.jsonl"), WithMaxAge(24*time.Hour))
+	old := run("old", "finance", scheduler.RunStatusSucceeded, 0)
+	old.ScheduledAt = time.Now().Add(-48 * time.Hour)
+	recent := run("recent", "finance", scheduler.RunStatusSucceeded, 0)
+	recent.ScheduledAt = time.Now()
+	_ = h.Save(old)
+	_ = h.Save(recent)
+
+	runs, _ := h.List("", 0)
+	if len(runs) != 1 || runs[0].ID != "recent" {
+		t.Errorf("List() = %+v, want only the recent run", runs)
+	}
+}
+
+// TestFileHistory_ReadsFromMemory tests that reads use the cache until
+// another process changes the file
+func TestFileHistory_ReadsFromMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	h := NewFileHistory(path)
+	_ = h.Save(run("r1", "finance", scheduler.RunStatusSucceeded, 0))
+	if _, err := h.Get("r1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// Rewrite the file with the same size and time: the cache is kept
+	info, _ := os.Stat(path)
+	data, _ := os.ReadFile(path)
+	blanked := []byte(strings.Repeat(" ", len(data)-1) + "\n")
+	_ = os.WriteFile(path, blanked, 0644)
+	_ = os.Chtimes(path, info.ModTime(), info.ModTime())
+	if _, err := h.Get("r1"); err != nil {
+		t.Errorf("Get() after an unchanged stat error = %v, want the cached run", err)
+	}
+
+	// A save by another instance is picked up
+	_ = NewFileHistory(path).Save(run("r2", "finance", scheduler.RunStatusSucceeded, 1))
+	if _, err := h.Get("r2"); err != nil {
+		t.Errorf("Get(r2) error = %v, want the other instance's run", err)
+	}
+}
+
+// TestFileHistory_CompactWhileSaving tests that runs saved during a
+// compaction, by this or another instance, are not lost
+func TestFileHistory_CompactWhileSaving(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	writer, compactor := NewFileHistory(path), NewFileHistory(path)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = writer.Save(run(fmt.Sprintf("r%02d", i), "finance", scheduler.RunStatusSucceeded, i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := compactor.Compact(); err != nil {
+				t.Errorf("Compact() error = %v", err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	if runs, _ := writer.List("", 0); len(runs) != 50 {
+		t.Errorf("List() returned %d runs, want 50", len(runs))
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 0 {
+		t.Errorf("leftover files: %v", matches)
+	}
+}
+
+func TestFileHistory_StaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	lock := path + ".lock"
+	if err := os.WriteFile(lock, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	_ = os.Chtimes(lock, old, old)
+
+	if err := NewFileHistory(path).Save(run("r1", "finance", scheduler.RunStatusSucceeded, 0)); err != nil {
+		t.Errorf("Save() with a stale lock error = %v", err)
+	}
+}
+
+func TestFileHistory_ConcurrentSave(t *testing.T) {
+	h := NewFileHistory(filepath.Join(t.TempDir(), "runs.jsonl"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = h.Save(run(string(rune('a'+i)), "finance", scheduler.RunStatusSucceeded, i))
+		}(i)
+	}
+	wg.Wait()
+
+	if runs, _ := h.List("", 0); len(runs) != 20 {
+		t.Errorf("List() returned %d runs, want 20", len(runs))
+	}
+}
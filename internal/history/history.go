@@ -0,0 +1,138 @@
+// Package history persists the outcome of report runs so questions like
+// "when did the finance report last succeed and how many rows did it
+// produce?" can be answered after the process that ran it has exited.
+//
+// Runs are scheduler.Run records kept in a scheduler.History. A run holds
+// its metadata, a hash of the config it used and the engine's RunResult,
+// with the classified error (type and originating component) and the
+// output the run delivered to. FileHistory keeps runs in a JSON Lines
+// file with size and age retention; it is the only built-in backend, and
+// other stores implement scheduler.History. A scheduler records every job
+// run with:
+//
+//	s := scheduler.New(scheduler.WithHistory(history.NewFileHistory("runs.jsonl")))
+//
+// Engines run outside a scheduler save their runs through a Recorder:
+//
+//	h := history.NewFileHistory("runs.jsonl")
+//	eng.WithResultRecorder(history.NewRecorder(h, "finance", cfg))
+//
+//	last, err := history.Latest(h, "finance", scheduler.RunStatusSucceeded)
+//	if err == nil {
+//	    fmt.Printf("%s: %d rows\n", last.FinishedAt, last.Result.RecordsOut)
+//	}
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+// Query selects runs. Zero-valued fields match everything.
+type Query struct {
+	// Job restricts results to one job or report.
+	Job string
+
+	// Status restricts results to one status.
+	Status scheduler.RunStatus
+
+	// Since and Until bound the run start time (inclusive).
+	Since time.Time
+	Until time.Time
+
+	// Limit caps the number of results. A limit <= 0 returns every match.
+	Limit int
+}
+
+// Matches reports whether run satisfies the query filters. Job and Limit
+// are not considered.
+func (q Query) Matches(run scheduler.Run) bool {
+	if q.Status != "" && run.Status != q.Status {
+		return false
+	}
+	if !q.Since.IsZero() && run.StartedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && run.StartedAt.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Find returns the runs in h that match q, newest first.
+func Find(h scheduler.History, q Query) ([]scheduler.Run, error) {
+	runs, err := h.List(q.Job, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := runs[:0]
+	for _, run := range runs {
+		if q.Matches(run) {
+			matched = append(matched, run)
+		}
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched, nil
+}
+
+// Latest returns the most recent run of job with the given status (any
+// status if empty), or scheduler.ErrRunNotFound.
+func Latest(h scheduler.History, job string, status scheduler.RunStatus) (scheduler.Run, error) {
+	runs, err := Find(h, Query{Job: job, Status: status, Limit: 1})
+	if err != nil {
+		return scheduler.Run{}, err
+	}
+	if len(runs) == 0 {
+		return scheduler.Run{}, scheduler.ErrRunNotFound
+	}
+	return runs[0], nil
+}
+
+// NewRun builds the run record of an engine result. Runs are keyed by the
+// result's run ID, so a scheduler and a recorder saving the same run
+// write one record.
+func NewRun(job, trigger, configHash string, res *engine.RunResult) scheduler.Run {
+	return scheduler.Run{
+		ID:          res.RunID,
+		Job:         job,
+		Trigger:     trigger,
+		Status:      scheduler.RunStatus(res.Status),
+		ScheduledAt: res.StartedAt,
+		StartedAt:   res.StartedAt,
+		FinishedAt:  res.FinishedAt,
+		Duration:    res.Duration,
+		Error:       res.Error,
+		ConfigHash:  configHash,
+		Result:      res,
+	}
+}
+
+// Recorder is an engine.ResultRecorder that saves every run result to a
+// History, for engines that are not run by a scheduler.
+type Recorder struct {
+	History    scheduler.History
+	Job        string
+	Trigger    string
+	ConfigHash string
+}
+
+// NewRecorder creates a recorder for runs of job built from cfg.
+func NewRecorder(h scheduler.History, job string, cfg engine.Config) *Recorder {
+	return &Recorder{
+		History:    h,
+		Job:        job,
+		Trigger:    scheduler.TriggerManual,
+		ConfigHash: scheduler.ConfigHash(cfg),
+	}
+}
+
+// RecordResult saves res as a run.
+func (r *Recorder) RecordResult(ctx context.Context, res *engine.RunResult) error {
+	return r.History.Save(NewRun(r.Job, r.Trigger, r.ConfigHash, res))
+}
@@ -0,0 +1,136 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+)
+
+var base = time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+
+// run returns a run of job started hours after base.
+func run(id, job string, status scheduler.RunStatus, hours int) scheduler.Run {
+	return scheduler.Run{ID: id, Job: job, Status: status, StartedAt: base.Add(time.Duration(hours) * time.Hour)}
+}
+
+// testHistory runs the History contract and queries against h.
+func testHistory(t *testing.T, h scheduler.History) {
+	t.Helper()
+
+	for _, r := range []scheduler.Run{
+		run("r1", "finance", scheduler.RunStatusSucceeded, 0),
+		run("r2", "finance", scheduler.RunStatusRunning, 1),
+		run("r3", "sales", scheduler.RunStatusSucceeded, 2),
+		run("r4", "finance", scheduler.RunStatusSucceeded, 3),
+	} {
+		if err := h.Save(r); err != nil {
+			t.Fatalf("Save(%s) error = %v", r.ID, err)
+		}
+	}
+
+	// Saving again replaces the run
+	updated := run("r2", "finance", scheduler.RunStatusFailed, 1)
+	updated.Result = &engine.RunResult{ErrorType: "transient"}
+	if err := h.Save(updated); err != nil {
+		t.Fatalf("Save(update) error = %v", err)
+	}
+	got, err := h.Get("r2")
+	if err != nil || got.Status != scheduler.RunStatusFailed || got.Result.ErrorType != "transient" {
+		t.Errorf("Get(r2) = %+v, %v", got, err)
+	}
+	if _, err := h.Get("missing"); !errors.Is(err, scheduler.ErrRunNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrRunNotFound", err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all newest first", Query{}, []string{"r4", "r3", "r2", "r1"}},
+		{"by job", Query{Job: "finance"}, []string{"r4", "r2", "r1"}},
+		{"by status", Query{Job: "finance", Status: scheduler.RunStatusSucceeded}, []string{"r4", "r1"}},
+		{"time range", Query{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)}, []string{"r3", "r2"}},
+		{"limit", Query{Limit: 2}, []string{"r4", "r3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := Find(h, tt.query)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			var ids []string
+			for _, r := range runs {
+				ids = append(ids, r.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("Find() = %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("Find() = %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+
+	last, err := Latest(h, "finance", scheduler.RunStatusSucceeded)
+	if err != nil || last.ID != "r4" {
+		t.Errorf("Latest() = %s, %v; want r4", last.ID, err)
+	}
+	if _, err := Latest(h, "payroll", ""); !errors.Is(err, scheduler.ErrRunNotFound) {
+		t.Errorf("Latest(unknown) error = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestMemoryHistoryQueries(t *testing.T) {
+	testHistory(t, scheduler.NewMemoryHistory(0))
+}
+
+func TestRecorder(t *testing.T) {
+	h := scheduler.NewMemoryHistory(0)
+	cfg := engine.Config{Output: engine.OutputConfig{Type: "file"}}
+	recorder := NewRecorder(h, "finance", cfg)
+	recorder.Trigger = "cli"
+
+	var _ engine.ResultRecorder = recorder
+
+	ok := &engine.RunResult{
+		RunID:          "run-1",
+		Status:         engine.RunStatusSucceeded,
+		StartedAt:      base,
+		RecordsIn:      10,
+		RecordsOut:     8,
+		BytesWritten:   512,
+		OutputLocation: "/reports/finance.json",
+	}
+	failed := &engine.RunResult{
+		RunID:          "run-2",
+		Status:         engine.RunStatusFailed,
+		StartedAt:      base.Add(time.Hour),
+		Error:          "connection reset",
+		ErrorType:      "transient",
+		ErrorComponent: "provider",
+		FailedStage:    engine.StageFetch,
+	}
+	for _, res := range []*engine.RunResult{ok, failed} {
+		if err := recorder.RecordResult(context.Background(), res); err != nil {
+			t.Fatalf("RecordResult() error = %v", err)
+		}
+	}
+
+	r, _ := h.Get("run-1")
+	if r.Job != "finance" || r.Trigger != "cli" || r.Status != scheduler.RunStatusSucceeded ||
+		r.ConfigHash != scheduler.ConfigHash(cfg) || r.Result.OutputLocation != "/reports/finance.json" {
+		t.Errorf("run = %+v", r)
+	}
+
+	r, _ = h.Get("run-2")
+	if r.Status != scheduler.RunStatusFailed || r.Error != "connection reset" || r.Result.ErrorComponent != "provider" {
+		t.Errorf("failed run = %+v", r)
+	}
+}
@@ -0,0 +1,157 @@
+package observability_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+)
+
+// stepCollector records metrics keyed by name and processor type.
+type stepCollector struct {
+	mu     sync.Mutex
+	counts map[string]int
+	gauges map[string]float64
+	tags   map[string]map[string]string
+}
+
+func newStepCollector() *stepCollector {
+	return &stepCollector{
+		counts: make(map[string]int),
+		gauges: make(map[string]float64),
+		tags:   make(map[string]map[string]string),
+	}
+}
+
+func (c *stepCollector) key(name string, tags map[string]string) string {
+	key := name + "/" + tags["processor_type"]
+	c.tags[key] = tags
+	return key
+}
+
+func (c *stepCollector) Count(name string, value int, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[c.key(name, tags)] += value
+}
+
+func (c *stepCollector) Gauge(name string, value float64, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gauges[c.key(name, tags)] = value
+}
+
+func (c *stepCollector) Histogram(name string, value float64, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.key(name, tags)
+}
+
+// keepFirst keeps the first n records.
+type keepFirst struct {
+	processor.BaseProcessor
+	n   int
+	err error
+}
+
+func (k *keepFirst) Process(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	return k.BaseProcessor.Process(ctx, data[:min(k.n, len(data))])
+}
+
+func records(n int) []map[string]interface{} {
+	data := make([]map[string]interface{}, n)
+	for i := range data {
+		data[i] = map[string]interface{}{"id": i}
+	}
+	return data
+}
+
+func TestChainObserver(t *testing.T) {
+	tracer := &MockTracer{}
+	collector := newStepCollector()
+	observer := observability.NewChainObserver(tracer, collector)
+
+	validate := processor.NewLink(&processor.BaseProcessor{}, 0, "validate").WithObserver(observer)
+	filter := processor.NewLink(&keepFirst{n: 3}, 1, "filter").WithObserver(observer)
+	validate.SetNext(filter)
+
+	result, err := validate.Process(context.Background(), records(4))
+	if err != nil || len(result) != 3 {
+		t.Fatalf("Process() = %d records, %v; want 3", len(result), err)
+	}
+
+	if len(tracer.Spans) != 2 {
+		t.Fatalf("%d spans, want 2", len(tracer.Spans))
+	}
+	for i, want := range []map[string]string{
+		{"processor_type": "validate", "position": "0", "records_in": "4", "records_out": "4", "records_dropped": "0", "drop_ratio": "0.000"},
+		{"processor_type": "filter", "position": "1", "records_in": "4", "records_out": "3", "records_dropped": "1", "drop_ratio": "0.250"},
+	} {
+		span := tracer.Spans[i]
+		if span.Name != "processor.step" || !span.Ended {
+			t.Errorf("span %d = %s, ended %v", i, span.Name, span.Ended)
+		}
+		for key, value := range want {
+			if span.Tags[key] != value {
+				t.Errorf("span %d tag %s = %q, want %q", i, key, span.Tags[key], value)
+			}
+		}
+		if span.Tags["self_duration_us"] == "" {
+			t.Errorf("span %d has no self_duration_us tag", i)
+		}
+	}
+
+	for key, want := range map[string]int{
+		"report_engine_processor_step_records_in_total/validate":  4,
+		"report_engine_processor_step_records_out_total/validate": 4,
+		"report_engine_processor_step_records_in_total/filter":    4,
+		"report_engine_processor_step_records_out_total/filter":   3,
+	} {
+		if got := collector.counts[key]; got != want {
+			t.Errorf("%s = %d, want %d", key, got, want)
+		}
+	}
+	if got := collector.gauges["report_engine_processor_step_drop_ratio/filter"]; got != 0.25 {
+		t.Errorf("filter drop ratio = %v, want 0.25", got)
+	}
+	if tags := collector.tags["report_engine_processor_step_duration_seconds/filter"]; tags["position"] != "1" || tags["component"] != "processor" {
+		t.Errorf("filter duration tags = %v", tags)
+	}
+}
+
+func TestChainObserverError(t *testing.T) {
+	tracer := &MockTracer{}
+	collector := newStepCollector()
+	boom := errors.New("boom")
+	link := processor.NewLink(&keepFirst{err: boom}, 0, "broken").
+		WithObserver(observability.NewChainObserver(tracer, collector))
+
+	if _, err := link.Process(context.Background(), records(2)); !errors.Is(err, boom) {
+		t.Fatalf("Process() error = %v, want boom", err)
+	}
+	if len(tracer.Spans) != 1 || tracer.Spans[0].Err != boom {
+		t.Errorf("span error not recorded: %+v", tracer.Spans)
+	}
+	if got := collector.counts["report_engine_processor_step_errors_total/broken"]; got != 1 {
+		t.Errorf("errors_total = %d, want 1", got)
+	}
+}
+
+func TestChainObserverMetricsOnly(t *testing.T) {
+	collector := newStepCollector()
+	link := processor.NewLink(&processor.BaseProcessor{}, 0, "base").
+		WithObserver(observability.NewChainObserver(nil, collector))
+
+	if _, err := link.Process(context.Background(), records(2)); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got := collector.counts["report_engine_processor_step_records_in_total/base"]; got != 2 {
+		t.Errorf("records_in_total = %d, want 2", got)
+	}
+}
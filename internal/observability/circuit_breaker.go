@@ -0,0 +1,50 @@
+package observability
+
+import (
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// NewBreakerListener returns a resilience.StateListener that records
+// circuit breaker transitions with collector and logs them with logger.
+// Either may be nil to skip it. Openings are logged as warnings, other
+// transitions as info.
+//
+// Metrics, tagged with the breaker name:
+//   - report_engine_circuit_breaker_state (gauge: 0 closed, 1 open, 2 half-open)
+//   - report_engine_circuit_breaker_transitions_total (counter, also tagged from and to)
+//
+// Example:
+//
+//	breaker := resilience.NewCircuitBreaker("partner-api", 5, time.Minute,
+//	    resilience.WithStateListener(observability.NewBreakerListener(collector, logger)),
+//	)
+func NewBreakerListener(collector MetricsCollector, logger *logging.Logger) resilience.StateListener {
+	return func(change resilience.StateChange) {
+		if collector != nil {
+			collector.Gauge("report_engine_circuit_breaker_state", float64(change.To), map[string]string{
+				"breaker": change.Name,
+			})
+			collector.Count("report_engine_circuit_breaker_transitions_total", 1, map[string]string{
+				"breaker": change.Name,
+				"from":    change.From.String(),
+				"to":      change.To.String(),
+			})
+		}
+
+		if logger != nil {
+			args := []interface{}{
+				"breaker", change.Name,
+				"from", change.From.String(),
+				"to", change.To.String(),
+				"calls", change.Calls,
+				"failures", change.Failures,
+			}
+			if change.To == resilience.StateOpen {
+				logger.Warn("circuit breaker opened", args...)
+			} else {
+				logger.Info("circuit breaker state changed", args...)
+			}
+		}
+	}
+}
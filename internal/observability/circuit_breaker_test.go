@@ -0,0 +1,65 @@
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// failingProvider always fails to fetch.
+type failingProvider struct{}
+
+func (failingProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, errors.New("partner api unavailable")
+}
+
+func TestBreakerListener(t *testing.T) {
+	collector := NewMockCollector()
+	var logs bytes.Buffer
+	logger := logging.NewLogger(logging.Config{Level: logging.LevelInfo, Format: logging.FormatJSON, Output: &logs})
+
+	breaker := resilience.NewCircuitBreaker("partner-api", 2, time.Minute,
+		resilience.WithStateListener(observability.NewBreakerListener(collector, logger)))
+
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(failingProvider{}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&output.ConsoleOutput{}).
+		WithCircuitBreaker(breaker).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = eng.Run()
+	}
+
+	if got := collector.Counts["report_engine_circuit_breaker_transitions_total"]; got != 1 {
+		t.Errorf("transitions = %d, want 1", got)
+	}
+	if got := collector.Gauges["report_engine_circuit_breaker_state"]; got != float64(resilience.StateOpen) {
+		t.Errorf("state gauge = %v, want %v", got, float64(resilience.StateOpen))
+	}
+	if !strings.Contains(logs.String(), "circuit breaker opened") || !strings.Contains(logs.String(), `"breaker":"partner-api"`) {
+		t.Errorf("expected the opening to be logged, got %s", logs.String())
+	}
+
+	res, _ := eng.CheckHealth(context.Background())
+	if res.Status != health.StatusDegraded {
+		t.Errorf("engine health = %v, want DEGRADED", res.Status)
+	}
+}
@@ -0,0 +1,103 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// FormatterWithMetrics wraps a FormatStrategy with metrics collection.
+type FormatterWithMetrics struct {
+	delegate  formatter.FormatStrategy
+	collector MetricsCollector
+}
+
+// StreamingFormatterWithMetrics wraps a StreamingFormatterStrategy with
+// metrics collection, recording FormatStart, FormatChunk and FormatEnd
+// as well as Format.
+type StreamingFormatterWithMetrics struct {
+	FormatterWithMetrics
+	streamer formatter.StreamingFormatterStrategy
+}
+
+// NewFormatterWithMetrics creates a formatter metrics decorator. If
+// delegate supports streaming, the decorator is a
+// *StreamingFormatterWithMetrics, so wrapping a formatter does not take
+// the streaming pipeline away from the engine.
+func NewFormatterWithMetrics(delegate formatter.FormatStrategy, collector MetricsCollector) formatter.FormatStrategy {
+	base := FormatterWithMetrics{delegate: delegate, collector: collector}
+	if streamer, ok := delegate.(formatter.StreamingFormatterStrategy); ok {
+		return &StreamingFormatterWithMetrics{FormatterWithMetrics: base, streamer: streamer}
+	}
+	return &base
+}
+
+// Format executes the delegate's Format method and records metrics.
+func (f *FormatterWithMetrics) Format(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+	return f.record("format", len(data), func() ([]byte, error) {
+		return f.delegate.Format(ctx, data)
+	})
+}
+
+// Close delegates to the underlying formatter if it implements Closeable.
+func (f *FormatterWithMetrics) Close() error {
+	return closeFormatter(f.delegate)
+}
+
+// FormatStart executes the delegate's FormatStart method and records metrics.
+func (f *StreamingFormatterWithMetrics) FormatStart(ctx context.Context) ([]byte, error) {
+	return f.record("format_start", 0, func() ([]byte, error) {
+		return f.streamer.FormatStart(ctx)
+	})
+}
+
+// FormatChunk executes the delegate's FormatChunk method and records metrics.
+func (f *StreamingFormatterWithMetrics) FormatChunk(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+	return f.record("format_chunk", len(data), func() ([]byte, error) {
+		return f.streamer.FormatChunk(ctx, data)
+	})
+}
+
+// FormatEnd executes the delegate's FormatEnd method and records metrics.
+func (f *StreamingFormatterWithMetrics) FormatEnd(ctx context.Context) ([]byte, error) {
+	return f.record("format_end", 0, func() ([]byte, error) {
+		return f.streamer.FormatEnd(ctx)
+	})
+}
+
+// record runs a formatter operation on records records and records its
+// duration, input records, output bytes and errors.
+func (f *FormatterWithMetrics) record(operation string, records int, format func() ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+	result, err := format()
+	duration := time.Since(start).Seconds()
+
+	tags := map[string]string{
+		"component": "formatter",
+		"operation": operation,
+	}
+
+	f.collector.Histogram("report_engine_formatter_duration_seconds", duration, tags)
+	f.collector.Count("report_engine_formatter_records_count", records, tags)
+
+	if err != nil {
+		f.collector.Count("report_engine_formatter_errors_total", 1, tags)
+		return nil, err
+	}
+
+	f.collector.Count("report_engine_formatter_bytes_count", len(result), tags)
+	return result, nil
+}
+
+// closeFormatter closes a wrapped formatter that holds resources.
+func closeFormatter(delegate formatter.FormatStrategy) error {
+	if closer, ok := delegate.(api.CloseableWithContext); ok {
+		return closer.CloseWithContext(context.Background())
+	}
+	if closer, ok := delegate.(api.Closeable); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package observability_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// sliceProvider streams a fixed set of records.
+type sliceProvider struct {
+	records []map[string]interface{}
+}
+
+func (p *sliceProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	return p.records, nil
+}
+
+func (p *sliceProvider) Stream(ctx context.Context) (provider.Iterator, error) {
+	return &sliceIterator{records: p.records, pos: -1}, nil
+}
+
+type sliceIterator struct {
+	records []map[string]interface{}
+	pos     int
+}
+
+func (it *sliceIterator) Next() bool {
+	it.pos++
+	return it.pos < len(it.records)
+}
+
+func (it *sliceIterator) Value() map[string]interface{} {
+	return map[string]interface{}{"id": it.records[it.pos]["id"]}
+}
+
+func (it *sliceIterator) Err() error   { return nil }
+func (it *sliceIterator) Close() error { return nil }
+
+// chunkOutput collects a streamed report.
+type chunkOutput struct {
+	data []byte
+}
+
+func (o *chunkOutput) Send(ctx context.Context, data []byte) error { o.data = data; return nil }
+func (o *chunkOutput) Initialize(ctx context.Context) error        { return nil }
+func (o *chunkOutput) WriteChunk(ctx context.Context, data []byte) error {
+	o.data = append(o.data, data...)
+	return nil
+}
+func (o *chunkOutput) Close(ctx context.Context) error { return nil }
+
+// closingFormatter is a non-streaming formatter that records Close.
+type closingFormatter struct {
+	closed bool
+}
+
+func (f *closingFormatter) Format(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+	return []byte("x"), nil
+}
+
+func (f *closingFormatter) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestFormatterDecoratorsKeepStreaming(t *testing.T) {
+	json := formatter.NewJSONFormatter("")
+	plain := &closingFormatter{}
+	tracer := &MockTracer{}
+	collector := NewMockCollector()
+
+	for name, f := range map[string]formatter.FormatStrategy{
+		"tracing": observability.NewFormatterWithTracing(json, tracer),
+		"metrics": observability.NewFormatterWithMetrics(json, collector),
+	} {
+		if _, ok := f.(formatter.StreamingFormatterStrategy); !ok {
+			t.Errorf("%s decorator of a streaming formatter does not stream", name)
+		}
+	}
+
+	for name, f := range map[string]formatter.FormatStrategy{
+		"tracing": observability.NewFormatterWithTracing(plain, tracer),
+		"metrics": observability.NewFormatterWithMetrics(plain, collector),
+	} {
+		if _, ok := f.(formatter.StreamingFormatterStrategy); ok {
+			t.Errorf("%s decorator of a batch formatter claims to stream", name)
+		}
+		plain.closed = false
+		if err := f.(interface{ Close() error }).Close(); err != nil || !plain.closed {
+			t.Errorf("%s decorator Close() = %v, delegate closed = %v", name, err, plain.closed)
+		}
+	}
+}
+
+func TestStreamingPipelineInstrumentation(t *testing.T) {
+	tracer := &MockTracer{}
+	collector := NewMockCollector()
+
+	records := make([]map[string]interface{}, 5)
+	for i := range records {
+		records[i] = map[string]interface{}{"id": i}
+	}
+	out := &chunkOutput{}
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(&sliceProvider{records: records}).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(out).
+		WithTracer(tracer).
+		WithMetrics(collector).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	res, err := eng.WithChunkSize(2).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if res.Mode != engine.ModeStreaming || res.Chunks != 3 {
+		t.Fatalf("mode = %s, chunks = %d, want streaming with 3 chunks", res.Mode, res.Chunks)
+	}
+
+	var chunks []*MockSpan
+	spans := make(map[string]int)
+	for _, span := range tracer.Spans {
+		spans[span.Name]++
+		if !span.Ended {
+			t.Errorf("span %s not ended", span.Name)
+		}
+		if span.Name == "report.chunk" {
+			chunks = append(chunks, span)
+		}
+	}
+	for name, want := range map[string]int{
+		"report.run":             1,
+		"report.chunk":           3,
+		"formatter.format_start": 1,
+		"formatter.format_chunk": 3,
+		"formatter.format_end":   1,
+		"provider.stream_init":   1,
+		"output.initialize":      1,
+		"output.write_chunk":     7, // start, 3 chunks, 2 delimiters and end
+		"output.close":           1,
+	} {
+		if spans[name] != want {
+			t.Errorf("%d %s spans, want %d", spans[name], name, want)
+		}
+	}
+	for i, want := range []string{"2", "2", "1"} {
+		if i >= len(chunks) {
+			break
+		}
+		tags := chunks[i].Tags
+		if tags["chunk_index"] != strconv.Itoa(i) || tags["chunk_size"] != want || tags["records_out"] != want {
+			t.Errorf("chunk %d tags = %v", i, tags)
+		}
+		if tags["bytes_count"] == "" || tags["iterator_wait_us"] == "" {
+			t.Errorf("chunk %d tags = %v, want bytes_count and iterator_wait_us", i, tags)
+		}
+	}
+
+	if got := collector.Counts["report_engine_stream_chunks_total"]; got != 3 {
+		t.Errorf("report_engine_stream_chunks_total = %d, want 3", got)
+	}
+	if got := collector.Counts["report_engine_formatter_records_count"]; got != 5 {
+		t.Errorf("report_engine_formatter_records_count = %d, want 5", got)
+	}
+	// Everything written except the two delimiters between chunks
+	if got := collector.Counts["report_engine_formatter_bytes_count"]; got != len(out.data)-2 {
+		t.Errorf("report_engine_formatter_bytes_count = %d, want %d", got, len(out.data)-2)
+	}
+	if got := collector.Counts["report_engine_output_bytes_count"]; got != len(out.data) {
+		t.Errorf("report_engine_output_bytes_count = %d, want %d", got, len(out.data))
+	}
+	for _, name := range []string{
+		"report_engine_stream_chunk_duration_seconds",
+		"report_engine_stream_chunk_records",
+		"report_engine_stream_iterator_wait_seconds",
+	} {
+		if _, ok := collector.Histograms[name]; !ok {
+			t.Errorf("histogram %s missing", name)
+		}
+	}
+	if collector.Gauges["report_engine_stream_records_in_per_second"] <= 0 {
+		t.Errorf("records in per second = %v, want > 0", collector.Gauges["report_engine_stream_records_in_per_second"])
+	}
+}
@@ -13,12 +13,14 @@ import (
 // MockCollector captures metrics for verification
 type MockCollector struct {
 	Counts     map[string]int
+	Gauges     map[string]float64
 	Histograms map[string]float64
 }
 
 func NewMockCollector() *MockCollector {
 	return &MockCollector{
 		Counts:     make(map[string]int),
+		Gauges:     make(map[string]float64),
 		Histograms: make(map[string]float64),
 	}
 }
@@ -27,7 +29,9 @@ func (m *MockCollector) Count(name string, value int, tags map[string]string) {
 	m.Counts[name] += value
 }
 
-func (m *MockCollector) Gauge(name string, value float64, tags map[string]string) {}
+func (m *MockCollector) Gauge(name string, value float64, tags map[string]string) {
+	m.Gauges[name] = value
+}
 
 func (m *MockCollector) Histogram(name string, value float64, tags map[string]string) {
 	m.Histograms[name] = value // Just store last value for simplicity
@@ -69,6 +73,7 @@ func TestMetricsIntegration(t *testing.T) {
 		"report_engine_provider_records_count":         1,
 		"report_engine_processor_input_records_count":  1,
 		"report_engine_processor_output_records_count": 1,
+		"report_engine_formatter_records_count":        1,
 		"report_engine_output_bytes_count":             15, // Approximate JSON length [{"id":1...}]
 	}
 
@@ -86,6 +91,7 @@ func TestMetricsIntegration(t *testing.T) {
 	expectedHistograms := []string{
 		"report_engine_provider_fetch_duration_seconds",
 		"report_engine_processor_duration_seconds",
+		"report_engine_formatter_duration_seconds",
 		"report_engine_output_duration_seconds",
 	}
 
@@ -0,0 +1,179 @@
+package observability
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the OpenTelemetry instrumentation scope of the
+// report engine's tracers and meters.
+const InstrumentationName = "github.com/AshishBagdane/go-report-engine"
+
+// OTelTracer is a Tracer backed by an OpenTelemetry TracerProvider. Spans
+// started from a context that carries a span, such as one extracted from
+// an incoming request, become its children.
+//
+// Thread-safe: Yes.
+//
+// Example:
+//
+//	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+//	eng, _ := engine.NewEngineBuilder().
+//	    WithProvider(prov).WithFormatter(fmt).WithOutput(out).
+//	    WithTracer(observability.NewOTelTracer(tp)).
+//	    Build()
+type OTelTracer struct {
+	tracer trace.Tracer
+}
+
+// NewOTelTracer creates a tracer from tp, or from the global
+// TracerProvider if tp is nil.
+func NewOTelTracer(tp trace.TracerProvider) *OTelTracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &OTelTracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+// StartSpan starts an OpenTelemetry span as a child of any span in ctx.
+func (t *OTelTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	ctx, span := t.tracer.Start(ctx, name)
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan adapts an OpenTelemetry span to Span and IntTagger.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+func (s *otelSpan) SetTag(key, value string) {
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+func (s *otelSpan) SetIntTag(key string, value int64) {
+	s.span.SetAttributes(attribute.Int64(key, value))
+}
+
+// RecordError records err as an exception event and marks the span
+// failed.
+func (s *otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// OTelMetrics is a MetricsCollector backed by an OpenTelemetry
+// MeterProvider. Instruments are created on first use of a name: Count
+// records to an Int64Counter, Gauge to a Float64Gauge and Histogram to a
+// Float64Histogram. Tags become attributes. Instruments that cannot be
+// created are reported to otel.Handle and skipped.
+//
+// Histogram buckets are configured on the MeterProvider with views.
+//
+// Thread-safe: Yes.
+//
+// Example:
+//
+//	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+//	eng, _ := engine.NewEngineBuilder().
+//	    WithProvider(prov).WithFormatter(fmt).WithOutput(out).
+//	    WithMetrics(observability.NewOTelMetrics(mp)).
+//	    Build()
+type OTelMetrics struct {
+	meter metric.Meter
+
+	mu         sync.Mutex
+	counters   map[string]metric.Int64Counter
+	gauges     map[string]metric.Float64Gauge
+	histograms map[string]metric.Float64Histogram
+}
+
+// NewOTelMetrics creates a collector from mp, or from the global
+// MeterProvider if mp is nil.
+func NewOTelMetrics(mp metric.MeterProvider) *OTelMetrics {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	return &OTelMetrics{
+		meter:      mp.Meter(InstrumentationName),
+		counters:   make(map[string]metric.Int64Counter),
+		gauges:     make(map[string]metric.Float64Gauge),
+		histograms: make(map[string]metric.Float64Histogram),
+	}
+}
+
+// Count adds value to a counter. Negative values are ignored, since
+// counters only increase.
+func (m *OTelMetrics) Count(name string, value int, tags map[string]string) {
+	if value < 0 {
+		return
+	}
+	m.mu.Lock()
+	counter, ok := m.counters[name]
+	if !ok {
+		var err error
+		if counter, err = m.meter.Int64Counter(name); err != nil {
+			otel.Handle(err)
+		}
+		m.counters[name] = counter
+	}
+	m.mu.Unlock()
+
+	if counter != nil {
+		counter.Add(context.Background(), int64(value), metric.WithAttributeSet(attributes(tags)))
+	}
+}
+
+// Gauge records the current value of a gauge.
+func (m *OTelMetrics) Gauge(name string, value float64, tags map[string]string) {
+	m.mu.Lock()
+	gauge, ok := m.gauges[name]
+	if !ok {
+		var err error
+		if gauge, err = m.meter.Float64Gauge(name); err != nil {
+			otel.Handle(err)
+		}
+		m.gauges[name] = gauge
+	}
+	m.mu.Unlock()
+
+	if gauge != nil {
+		gauge.Record(context.Background(), value, metric.WithAttributeSet(attributes(tags)))
+	}
+}
+
+// Histogram records value in a histogram.
+func (m *OTelMetrics) Histogram(name string, value float64, tags map[string]string) {
+	m.mu.Lock()
+	histogram, ok := m.histograms[name]
+	if !ok {
+		var err error
+		if histogram, err = m.meter.Float64Histogram(name); err != nil {
+			otel.Handle(err)
+		}
+		m.histograms[name] = histogram
+	}
+	m.mu.Unlock()
+
+	if histogram != nil {
+		histogram.Record(context.Background(), value, metric.WithAttributeSet(attributes(tags)))
+	}
+}
+
+// attributes converts tags to an attribute set.
+func attributes(tags map[string]string) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(tags))
+	for key, value := range tags {
+		kvs = append(kvs, attribute.String(key, value))
+	}
+	return attribute.NewSet(kvs...)
+}
@@ -0,0 +1,187 @@
+package observability_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracer() (*observability.OTelTracer, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return observability.NewOTelTracer(tp), exporter, tp
+}
+
+func newTracedEngine(t *testing.T, tracer observability.Tracer, out output.OutputStrategy) *engine.ReportEngine {
+	t.Helper()
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(provider.NewMockProvider([]map[string]interface{}{{"id": 1}, {"id": 2}})).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(out).
+		WithTracer(tracer).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return eng.WithLogger(logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText}))
+}
+
+// spansByName indexes exported spans by name.
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	return byName
+}
+
+// attr returns the value of a span attribute.
+func attr(s tracetest.SpanStub, key string) (attribute.Value, bool) {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestOTelTracerEngineSpans(t *testing.T) {
+	tracer, exporter, tp := newTestTracer()
+	eng := newTracedEngine(t, tracer, &discardOutput{})
+
+	// The caller's span, e.g. one extracted from an incoming request
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	res, err := eng.Execute(ctx)
+	parent.End()
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	spans := spansByName(exporter.GetSpans())
+	run, ok := spans["report.run"]
+	if !ok {
+		t.Fatalf("no report.run span in %v", exporter.GetSpans())
+	}
+	if run.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("report.run parent = %s, want request span", run.Parent.SpanID())
+	}
+	if run.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+		t.Error("report.run is not in the caller's trace")
+	}
+	if v, _ := attr(run, "run_id"); v.AsString() != res.RunID {
+		t.Errorf("run_id = %q, want %q", v.AsString(), res.RunID)
+	}
+	if v, _ := attr(run, "records_in"); v.Type() != attribute.INT64 || v.AsInt64() != 2 {
+		t.Errorf("records_in = %v, want int 2", v.Emit())
+	}
+	if v, _ := attr(run, "status"); v.AsString() != "succeeded" {
+		t.Errorf("status = %q, want succeeded", v.AsString())
+	}
+
+	for _, name := range []string{"provider.fetch", "processor.process", "output.send"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("no %s span", name)
+			continue
+		}
+		if s.Parent.SpanID() != run.SpanContext.SpanID() {
+			t.Errorf("%s is not a child of report.run", name)
+		}
+		if _, ok := attr(s, "component_type"); !ok {
+			t.Errorf("%s has no component_type attribute", name)
+		}
+	}
+	if v, _ := attr(spans["provider.fetch"], "record_count"); v.AsInt64() != 2 {
+		t.Errorf("provider.fetch record_count = %v, want 2", v.Emit())
+	}
+	if v, _ := attr(spans["provider.fetch"], "component_type"); v.AsString() != "*provider.MockProvider" {
+		t.Errorf("provider.fetch component_type = %q", v.AsString())
+	}
+}
+
+func TestOTelTracerRecordsErrors(t *testing.T) {
+	tracer, exporter, _ := newTestTracer()
+	eng := newTracedEngine(t, tracer, failingOutput{})
+
+	if _, err := eng.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() succeeded, want output error")
+	}
+
+	spans := spansByName(exporter.GetSpans())
+	for _, name := range []string{"report.run", "output.send"} {
+		s := spans[name]
+		if s.Status.Code != codes.Error {
+			t.Errorf("%s status = %v, want Error", name, s.Status.Code)
+		}
+		if len(s.Events) == 0 || s.Events[0].Name != "exception" {
+			t.Errorf("%s has no exception event", name)
+		}
+	}
+	if v, _ := attr(spans["report.run"], "failed_stage"); v.AsString() != engine.StageOutput {
+		t.Errorf("failed_stage = %q, want %q", v.AsString(), engine.StageOutput)
+	}
+}
+
+func TestOTelMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	m := observability.NewOTelMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	tags := map[string]string{"component": "output"}
+	m.Count("jobs_total", 2, tags)
+	m.Count("jobs_total", 3, tags)
+	m.Count("jobs_total", -1, tags)
+	m.Gauge("queue_size", 7, nil)
+	m.Histogram("job_seconds", 5, tags)
+	m.Histogram("job_seconds", 1, tags)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(rm.ScopeMetrics) != 1 || rm.ScopeMetrics[0].Scope.Name != observability.InstrumentationName {
+		t.Fatalf("scope metrics = %+v", rm.ScopeMetrics)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	sum, ok := metrics["jobs_total"].(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 5 {
+		t.Errorf("jobs_total = %+v, want 5", metrics["jobs_total"])
+	} else if v, _ := sum.DataPoints[0].Attributes.Value("component"); v.AsString() != "output" {
+		t.Errorf("jobs_total component = %q", v.AsString())
+	}
+
+	gauge, ok := metrics["queue_size"].(metricdata.Gauge[float64])
+	if !ok || len(gauge.DataPoints) != 1 || gauge.DataPoints[0].Value != 7 {
+		t.Errorf("queue_size = %+v, want 7", metrics["queue_size"])
+	}
+
+	hist, ok := metrics["job_seconds"].(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 2 || hist.DataPoints[0].Sum != 6 {
+		t.Errorf("job_seconds = %+v, want count 2 sum 6", metrics["job_seconds"])
+	}
+}
+
+// failingOutput rejects every report.
+type failingOutput struct{}
+
+func (failingOutput) Send(ctx context.Context, data []byte) error {
+	return errors.New("destination unavailable")
+}
@@ -5,36 +5,39 @@ import (
 	"time"
 
 	"github.com/AshishBagdane/go-report-engine/internal/output"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
-// OutputWithMetrics wraps an OutputStrategy with metrics collection.
+// OutputWithMetrics records metrics for the calls to an OutputStrategy.
 type OutputWithMetrics struct {
-	delegate  output.OutputStrategy
 	collector MetricsCollector
 }
 
-// NewOutputWithMetrics creates a new OutputWithMetrics decorator.
-func NewOutputWithMetrics(delegate output.OutputStrategy, collector MetricsCollector) *OutputWithMetrics {
-	return &OutputWithMetrics{
-		delegate:  delegate,
-		collector: collector,
-	}
+// NewOutputWithMetrics creates a new metrics decorator. It keeps the
+// optional interfaces of delegate (see output.Decorate), so a streaming
+// output still streams and every chunk write is measured.
+func NewOutputWithMetrics(delegate output.OutputStrategy, collector MetricsCollector) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithMetrics{collector: collector})
 }
 
-// Send executes the delegate's Send method and records metrics.
-func (o *OutputWithMetrics) Send(ctx context.Context, data []byte) error {
+// Intercept runs call and records its duration, the bytes or records it
+// delivered and errors, tagged with the operation.
+func (o *OutputWithMetrics) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
 	start := time.Now()
-	err := o.delegate.Send(ctx, data)
+	err := call(ctx)
 	duration := time.Since(start).Seconds()
 
 	tags := map[string]string{
 		"component": "output",
-		"operation": "send",
+		"operation": op,
 	}
 
 	o.collector.Histogram("report_engine_output_duration_seconds", duration, tags)
-	o.collector.Count("report_engine_output_bytes_count", len(data), tags)
+	switch op {
+	case output.OpSend, output.OpWriteChunk:
+		o.collector.Count("report_engine_output_bytes_count", size, tags)
+	case output.OpSendRecords:
+		o.collector.Count("report_engine_output_records_count", size, tags)
+	}
 
 	if err != nil {
 		o.collector.Count("report_engine_output_errors_total", 1, tags)
@@ -43,11 +46,3 @@ func (o *OutputWithMetrics) Send(ctx context.Context, data []byte) error {
 
 	return nil
 }
-
-// Close delegates to the underlying output if it implements Closeable.
-func (o *OutputWithMetrics) Close() error {
-	if closer, ok := o.delegate.(api.Closeable); ok {
-		return closer.Close()
-	}
-	return nil
-}
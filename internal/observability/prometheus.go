@@ -0,0 +1,297 @@
+package observability
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusOption configures a PrometheusCollector.
+type PrometheusOption func(*PrometheusCollector)
+
+// WithRegistry registers metrics with reg and serves them from Handler,
+// instead of the global default registry.
+func WithRegistry(reg *prometheus.Registry) PrometheusOption {
+	return func(c *PrometheusCollector) {
+		c.registerer = reg
+		c.gatherer = reg
+	}
+}
+
+// WithDefaultBuckets sets the buckets of histograms without buckets of
+// their own (default: prometheus.DefBuckets, suited to durations in
+// seconds).
+func WithDefaultBuckets(buckets []float64) PrometheusOption {
+	return func(c *PrometheusCollector) {
+		c.defaultBuckets = buckets
+	}
+}
+
+// WithBuckets sets the buckets of the named histogram, e.g. wider ones for
+// "report_engine_provider_fetch_duration_seconds" when queries take minutes.
+func WithBuckets(name string, buckets []float64) PrometheusOption {
+	return func(c *PrometheusCollector) {
+		c.buckets[name] = buckets
+	}
+}
+
+// WithLabelMapping renames tags to labels, e.g. {"component": "stage"}.
+// Mapping a tag to "" drops it. Tags not in the mapping keep their name,
+// with characters not allowed in label names replaced by "_".
+func WithLabelMapping(mapping map[string]string) PrometheusOption {
+	return func(c *PrometheusCollector) {
+		for tag, label := range mapping {
+			c.labels[tag] = label
+		}
+	}
+}
+
+// WithPrometheusLogger sets the logger for metrics that cannot be
+// recorded.
+func WithPrometheusLogger(l *logging.Logger) PrometheusOption {
+	return func(c *PrometheusCollector) {
+		c.logger = l
+	}
+}
+
+// PrometheusCollector is a MetricsCollector backed by Prometheus.
+//
+// Metrics are registered lazily: the first Count, Gauge or Histogram call
+// for a name registers a counter, gauge or histogram whose labels are the
+// tags of that call. Later calls with other tags fill missing labels with
+// "" and drop extra tags, since a Prometheus metric has a fixed label set.
+// A name already registered as another metric type is logged once and
+// ignored.
+//
+// Thread-safe: Yes.
+//
+// Example:
+//
+//	metrics := observability.NewPrometheusCollector(
+//	    observability.WithBuckets("report_engine_provider_fetch_duration_seconds", prometheus.ExponentialBuckets(0.1, 2, 12)),
+//	)
+//	eng, _ := engine.NewEngineBuilder().
+//	    WithProvider(prov).WithFormatter(fmt).WithOutput(out).
+//	    WithMetrics(metrics).
+//	    Build()
+//	srv := server.New(server.WithMetricsHandler(metrics.Handler()))
+type PrometheusCollector struct {
+	registerer     prometheus.Registerer
+	gatherer       prometheus.Gatherer
+	defaultBuckets []float64
+	buckets        map[string][]float64
+	labels         map[string]string
+	logger         *logging.Logger
+
+	mu      sync.Mutex
+	metrics map[string]*promMetric
+	failed  map[string]bool
+}
+
+// promMetric is a registered metric and its label names.
+type promMetric struct {
+	kind   string
+	labels []string
+	// tags maps each label back to the tag that fills it
+	tags      map[string]string
+	counter   *prometheus.CounterVec
+	gauge     *prometheus.GaugeVec
+	histogram *prometheus.HistogramVec
+}
+
+// NewPrometheusCollector creates a collector that registers with the
+// default Prometheus registry, so an existing promhttp.Handler() also
+// serves report engine metrics.
+func NewPrometheusCollector(opts ...PrometheusOption) *PrometheusCollector {
+	c := &PrometheusCollector{
+		registerer:     prometheus.DefaultRegisterer,
+		gatherer:       prometheus.DefaultGatherer,
+		defaultBuckets: prometheus.DefBuckets,
+		buckets:        make(map[string][]float64),
+		labels:         make(map[string]string),
+		metrics:        make(map[string]*promMetric),
+		failed:         make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = logging.NewLogger(logging.Config{
+			Level:     logging.LevelInfo,
+			Format:    logging.FormatJSON,
+			Component: "metrics",
+		})
+	}
+	return c
+}
+
+// Count adds value to a counter. Negative values are ignored, since
+// Prometheus counters only increase.
+func (c *PrometheusCollector) Count(name string, value int, tags map[string]string) {
+	if value < 0 {
+		return
+	}
+	if m := c.metric(name, "counter", tags); m != nil {
+		m.counter.With(m.values(tags)).Add(float64(value))
+	}
+}
+
+// Gauge sets a gauge.
+func (c *PrometheusCollector) Gauge(name string, value float64, tags map[string]string) {
+	if m := c.metric(name, "gauge", tags); m != nil {
+		m.gauge.With(m.values(tags)).Set(value)
+	}
+}
+
+// Histogram observes value in a histogram.
+func (c *PrometheusCollector) Histogram(name string, value float64, tags map[string]string) {
+	if m := c.metric(name, "histogram", tags); m != nil {
+		m.histogram.With(m.values(tags)).Observe(value)
+	}
+}
+
+// Handler serves the collector's registry in the Prometheus exposition
+// format, for mounting at /metrics.
+func (c *PrometheusCollector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.gatherer, promhttp.HandlerOpts{})
+}
+
+// metric returns the metric for name, registering it on first use, or
+// nil if it cannot be recorded.
+func (c *PrometheusCollector) metric(name, kind string, tags map[string]string) *promMetric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok := c.metrics[name]; ok {
+		if m.kind != kind {
+			c.fail(name, "metric already registered as another type", "type", m.kind, "requested", kind)
+			return nil
+		}
+		return m
+	}
+	if c.failed[name] {
+		return nil
+	}
+
+	m := c.newMetric(name, kind, tags)
+	collector := m.collector()
+	if err := c.registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) || !m.adopt(already.ExistingCollector) {
+			c.fail(name, "failed to register metric", "error", err)
+			return nil
+		}
+	}
+	c.metrics[name] = m
+	return m
+}
+
+// newMetric creates an unregistered metric labeled by the mapped tags.
+func (c *PrometheusCollector) newMetric(name, kind string, tags map[string]string) *promMetric {
+	m := &promMetric{kind: kind, tags: make(map[string]string, len(tags))}
+	for tag := range tags {
+		label := c.labelName(tag)
+		if label == "" {
+			continue
+		}
+		if _, dup := m.tags[label]; dup {
+			continue
+		}
+		m.tags[label] = tag
+		m.labels = append(m.labels, label)
+	}
+	sort.Strings(m.labels)
+
+	help := "Report engine metric " + name + "."
+	switch kind {
+	case "counter":
+		m.counter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, m.labels)
+	case "gauge":
+		m.gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, m.labels)
+	default:
+		buckets, ok := c.buckets[name]
+		if !ok {
+			buckets = c.defaultBuckets
+		}
+		m.histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, m.labels)
+	}
+	return m
+}
+
+// labelName maps a tag to its label name, or "" to drop it.
+func (c *PrometheusCollector) labelName(tag string) string {
+	if label, ok := c.labels[tag]; ok {
+		return label
+	}
+	return sanitizeLabel(tag)
+}
+
+// fail logs a metric that cannot be recorded, once per name. Caller holds
+// mu.
+func (c *PrometheusCollector) fail(name, msg string, args ...any) {
+	if c.failed[name] {
+		return
+	}
+	c.failed[name] = true
+	c.logger.Warn(msg, append([]any{"metric", name}, args...)...)
+}
+
+// collector returns the metric's vector.
+func (m *promMetric) collector() prometheus.Collector {
+	switch m.kind {
+	case "counter":
+		return m.counter
+	case "gauge":
+		return m.gauge
+	default:
+		return m.histogram
+	}
+}
+
+// adopt switches to an identical vector registered earlier, e.g. by
+// another collector sharing the registry. It reports whether existing is
+// of the metric's type.
+func (m *promMetric) adopt(existing prometheus.Collector) bool {
+	var ok bool
+	switch m.kind {
+	case "counter":
+		m.counter, ok = existing.(*prometheus.CounterVec)
+	case "gauge":
+		m.gauge, ok = existing.(*prometheus.GaugeVec)
+	default:
+		m.histogram, ok = existing.(*prometheus.HistogramVec)
+	}
+	return ok
+}
+
+// values returns the label values for tags. Missing tags are "".
+func (m *promMetric) values(tags map[string]string) prometheus.Labels {
+	values := make(prometheus.Labels, len(m.labels))
+	for _, label := range m.labels {
+		values[label] = tags[m.tags[label]]
+	}
+	return values
+}
+
+// sanitizeLabel replaces characters not allowed in a Prometheus label
+// name with "_".
+func sanitizeLabel(tag string) string {
+	var b strings.Builder
+	for i, r := range tag {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package observability_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestPrometheus(opts ...observability.PrometheusOption) (*observability.PrometheusCollector, *prometheus.Registry) {
+	reg := prometheus.NewRegistry()
+	logger := logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText})
+	opts = append([]observability.PrometheusOption{
+		observability.WithRegistry(reg),
+		observability.WithPrometheusLogger(logger),
+	}, opts...)
+	return observability.NewPrometheusCollector(opts...), reg
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	c, reg := newTestPrometheus(
+		observability.WithBuckets("job_seconds", []float64{1, 10}),
+		observability.WithLabelMapping(map[string]string{"component": "stage", "request_id": ""}),
+	)
+
+	tags := map[string]string{"component": "output", "request_id": "abc", "output.type": "sftp"}
+	c.Count("jobs_total", 2, tags)
+	c.Count("jobs_total", 3, tags)
+	c.Count("jobs_total", -1, tags)
+	c.Gauge("queue_size", 7, nil)
+	c.Histogram("job_seconds", 5, tags)
+
+	want := `
+# HELP jobs_total Report engine metric jobs_total.
+# TYPE jobs_total counter
+jobs_total{output_type="sftp",stage="output"} 5
+# HELP queue_size Report engine metric queue_size.
+# TYPE queue_size gauge
+queue_size 7
+# HELP job_seconds Report engine metric job_seconds.
+# TYPE job_seconds histogram
+job_seconds_bucket{output_type="sftp",stage="output",le="1"} 0
+job_seconds_bucket{output_type="sftp",stage="output",le="10"} 1
+job_seconds_bucket{output_type="sftp",stage="output",le="+Inf"} 1
+job_seconds_sum{output_type="sftp",stage="output"} 5
+job_seconds_count{output_type="sftp",stage="output"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "jobs_total", "queue_size", "job_seconds"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrometheusCollectorLabelSets(t *testing.T) {
+	c, reg := newTestPrometheus()
+
+	// The first call fixes the labels; later calls fill or drop tags
+	c.Count("runs_total", 1, map[string]string{"status": "ok", "report": "daily"})
+	c.Count("runs_total", 1, map[string]string{"status": "failed"})
+	c.Count("runs_total", 1, map[string]string{"status": "ok", "report": "daily", "extra": "x"})
+
+	want := `
+# HELP runs_total Report engine metric runs_total.
+# TYPE runs_total counter
+runs_total{report="",status="failed"} 1
+runs_total{report="daily",status="ok"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "runs_total"); err != nil {
+		t.Error(err)
+	}
+
+	// A name used as another type is ignored rather than panicking
+	c.Gauge("runs_total", 1, nil)
+	if got := testutil.CollectAndCount(reg, "runs_total"); got != 2 {
+		t.Errorf("runs_total series = %d, want 2", got)
+	}
+}
+
+func TestPrometheusCollectorSharedRegistry(t *testing.T) {
+	first, reg := newTestPrometheus()
+	second := observability.NewPrometheusCollector(observability.WithRegistry(reg))
+
+	// Identical metrics from two collectors share one series
+	tags := map[string]string{"component": "provider"}
+	first.Count("fetches_total", 1, tags)
+	second.Count("fetches_total", 1, tags)
+
+	want := `
+# HELP fetches_total Report engine metric fetches_total.
+# TYPE fetches_total counter
+fetches_total{component="provider"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "fetches_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrometheusCollectorEngineMetrics(t *testing.T) {
+	c, _ := newTestPrometheus()
+
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(provider.NewMockProvider([]map[string]interface{}{{"id": 1}, {"id": 2}})).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&discardOutput{}).
+		WithMetrics(c).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if err := eng.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`report_engine_provider_records_count{component="provider",operation="fetch"} 2`,
+		`report_engine_output_duration_seconds_count{component="output",operation="send"} 1`,
+		`report_engine_processor_output_records_count`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+// discardOutput accepts and drops reports.
+type discardOutput struct{}
+
+func (d *discardOutput) Send(ctx context.Context, data []byte) error {
+	return nil
+}
@@ -7,24 +7,21 @@ import (
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
 )
 
-// ProviderWithMetrics wraps a ProviderStrategy with metrics collection.
+// ProviderWithMetrics records metrics for the calls to a ProviderStrategy.
 type ProviderWithMetrics struct {
-	delegate  provider.ProviderStrategy
 	collector MetricsCollector
 }
 
-// NewProviderWithMetrics creates a new ProviderWithMetrics decorator.
-func NewProviderWithMetrics(delegate provider.ProviderStrategy, collector MetricsCollector) *ProviderWithMetrics {
-	return &ProviderWithMetrics{
-		delegate:  delegate,
-		collector: collector,
-	}
+// NewProviderWithMetrics creates a new metrics decorator. It keeps the
+// optional interfaces of delegate (see provider.Decorate).
+func NewProviderWithMetrics(delegate provider.ProviderStrategy, collector MetricsCollector) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithMetrics{collector: collector})
 }
 
-// Fetch executes the delegate's Fetch method and records metrics.
-func (p *ProviderWithMetrics) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+// InterceptFetch runs fetch and records its duration, records and errors.
+func (p *ProviderWithMetrics) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
 	start := time.Now()
-	results, err := p.delegate.Fetch(ctx)
+	results, err := fetch(ctx)
 	duration := time.Since(start).Seconds()
 
 	tags := map[string]string{
@@ -43,24 +40,21 @@ func (p *ProviderWithMetrics) Fetch(ctx context.Context) ([]map[string]interface
 	return results, nil
 }
 
-// Stream wraps the streaming iterator with metrics if supported.
-func (p *ProviderWithMetrics) Stream(ctx context.Context) (provider.Iterator, error) {
-	if streamer, ok := p.delegate.(provider.StreamingProviderStrategy); ok {
-		start := time.Now()
-		iter, err := streamer.Stream(ctx)
-		duration := time.Since(start).Seconds()
-
-		tags := map[string]string{
-			"component": "provider",
-			"operation": "stream_init",
-		}
-		p.collector.Histogram("report_engine_provider_stream_init_duration_seconds", duration, tags)
+// InterceptStream runs open and records how long the stream took to start.
+func (p *ProviderWithMetrics) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	start := time.Now()
+	iter, err := open(ctx)
+	duration := time.Since(start).Seconds()
 
-		if err != nil {
-			p.collector.Count("report_engine_provider_errors_total", 1, tags)
-		}
+	tags := map[string]string{
+		"component": "provider",
+		"operation": "stream_init",
+	}
+	p.collector.Histogram("report_engine_provider_stream_init_duration_seconds", duration, tags)
 
-		return iter, err
+	if err != nil {
+		p.collector.Count("report_engine_provider_errors_total", 1, tags)
 	}
-	return nil, nil
+
+	return iter, err
 }
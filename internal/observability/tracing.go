@@ -1,6 +1,9 @@
 package observability
 
-import "context"
+import (
+	"context"
+	"strconv"
+)
 
 // Tracer defines the interface for creating spans.
 // It abstracts away the underlying tracing system (OpenTelemetry, Zipkin, etc.).
@@ -22,6 +25,23 @@ type Span interface {
 	RecordError(err error)
 }
 
+// IntTagger is implemented by spans that keep typed attributes, such as
+// OpenTelemetry spans, so counts stay numeric.
+type IntTagger interface {
+	// SetIntTag adds an integer key-value pair to the span.
+	SetIntTag(key string, value int64)
+}
+
+// SetIntTag adds an integer tag to span, as a number if the span is an
+// IntTagger and as a decimal string otherwise.
+func SetIntTag(span Span, key string, value int64) {
+	if tagger, ok := span.(IntTagger); ok {
+		tagger.SetIntTag(key, value)
+		return
+	}
+	span.SetTag(key, strconv.FormatInt(value, 10))
+}
+
 // NoopTracer is a default implementation that does nothing.
 type NoopTracer struct{}
 
@@ -4,58 +4,54 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/processor"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
-// ProviderWithTracing wraps a ProviderStrategy with tracing.
+// ProviderWithTracing traces the calls to a ProviderStrategy.
 type ProviderWithTracing struct {
 	delegate provider.ProviderStrategy
 	tracer   Tracer
 }
 
-func NewProviderWithTracing(delegate provider.ProviderStrategy, tracer Tracer) *ProviderWithTracing {
-	return &ProviderWithTracing{
-		delegate: delegate,
-		tracer:   tracer,
-	}
+// NewProviderWithTracing creates a new tracing decorator. It keeps the
+// optional interfaces of delegate (see provider.Decorate).
+func NewProviderWithTracing(delegate provider.ProviderStrategy, tracer Tracer) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithTracing{delegate: delegate, tracer: tracer})
 }
 
-func (p *ProviderWithTracing) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+// InterceptFetch runs fetch in a "provider.fetch" span.
+func (p *ProviderWithTracing) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
 	ctx, span := p.tracer.StartSpan(ctx, "provider.fetch")
 	defer span.End()
+	tagComponent(span, "provider", p.delegate)
 
-	results, err := p.delegate.Fetch(ctx)
+	results, err := fetch(ctx)
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
-	span.SetTag("record_count", fmt.Sprintf("%d", len(results)))
+	SetIntTag(span, "record_count", int64(len(results)))
 	return results, err
 }
 
-func (p *ProviderWithTracing) Stream(ctx context.Context) (provider.Iterator, error) {
-	if streamer, ok := p.delegate.(provider.StreamingProviderStrategy); ok {
-		ctx, span := p.tracer.StartSpan(ctx, "provider.stream_init")
-		defer span.End()
-
-		iter, err := streamer.Stream(ctx)
-		if err != nil {
-			span.RecordError(err)
-		}
-		return iter, err
+// InterceptStream runs open in a "provider.stream_init" span.
+func (p *ProviderWithTracing) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	ctx, span := p.tracer.StartSpan(ctx, "provider.stream_init")
+	defer span.End()
+	tagComponent(span, "provider", p.delegate)
+	if op == provider.OpStreamFrom {
+		span.SetTag("resumed", "true")
 	}
-	return nil, nil
-}
 
-func (p *ProviderWithTracing) Close() error {
-	if closer, ok := p.delegate.(api.Closeable); ok {
-		return closer.Close()
+	iter, err := open(ctx)
+	if err != nil {
+		span.RecordError(err)
 	}
-	return nil
+	return iter, err
 }
 
 // ProcessorWithTracing wraps a ProcessorHandler with tracing.
@@ -78,8 +74,9 @@ func (p *ProcessorWithTracing) SetNext(next processor.ProcessorHandler) {
 func (p *ProcessorWithTracing) Process(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
 	ctx, span := p.tracer.StartSpan(ctx, "processor.process")
 	defer span.End()
+	tagComponent(span, "processor", p.delegate)
 
-	span.SetTag("input_count", fmt.Sprintf("%d", len(data)))
+	SetIntTag(span, "input_count", int64(len(data)))
 
 	results, err := p.delegate.Process(ctx, data)
 	if err != nil {
@@ -87,39 +84,117 @@ func (p *ProcessorWithTracing) Process(ctx context.Context, data []map[string]in
 		return nil, err
 	}
 
-	span.SetTag("output_count", fmt.Sprintf("%d", len(results)))
+	SetIntTag(span, "output_count", int64(len(results)))
 	return results, nil
 }
 
-// OutputWithTracing wraps an OutputStrategy with tracing.
+// FormatterWithTracing wraps a FormatStrategy with tracing.
+type FormatterWithTracing struct {
+	delegate formatter.FormatStrategy
+	tracer   Tracer
+}
+
+// StreamingFormatterWithTracing wraps a StreamingFormatterStrategy with
+// tracing, adding spans for FormatStart, FormatChunk and FormatEnd.
+type StreamingFormatterWithTracing struct {
+	FormatterWithTracing
+	streamer formatter.StreamingFormatterStrategy
+}
+
+// NewFormatterWithTracing creates a formatter tracing decorator. If
+// delegate supports streaming, the decorator is a
+// *StreamingFormatterWithTracing, so wrapping a formatter does not take
+// the streaming pipeline away from the engine.
+func NewFormatterWithTracing(delegate formatter.FormatStrategy, tracer Tracer) formatter.FormatStrategy {
+	base := FormatterWithTracing{delegate: delegate, tracer: tracer}
+	if streamer, ok := delegate.(formatter.StreamingFormatterStrategy); ok {
+		return &StreamingFormatterWithTracing{FormatterWithTracing: base, streamer: streamer}
+	}
+	return &base
+}
+
+func (f *FormatterWithTracing) Format(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+	return f.trace(ctx, "formatter.format", data, func(ctx context.Context) ([]byte, error) {
+		return f.delegate.Format(ctx, data)
+	})
+}
+
+func (f *FormatterWithTracing) Close() error {
+	return closeFormatter(f.delegate)
+}
+
+func (f *StreamingFormatterWithTracing) FormatStart(ctx context.Context) ([]byte, error) {
+	return f.trace(ctx, "formatter.format_start", nil, f.streamer.FormatStart)
+}
+
+func (f *StreamingFormatterWithTracing) FormatChunk(ctx context.Context, data []map[string]interface{}) ([]byte, error) {
+	return f.trace(ctx, "formatter.format_chunk", data, func(ctx context.Context) ([]byte, error) {
+		return f.streamer.FormatChunk(ctx, data)
+	})
+}
+
+func (f *StreamingFormatterWithTracing) FormatEnd(ctx context.Context) ([]byte, error) {
+	return f.trace(ctx, "formatter.format_end", nil, f.streamer.FormatEnd)
+}
+
+// trace runs a formatter operation in a span tagged with its input
+// records and output bytes.
+func (f *FormatterWithTracing) trace(ctx context.Context, name string, data []map[string]interface{}, format func(context.Context) ([]byte, error)) ([]byte, error) {
+	ctx, span := f.tracer.StartSpan(ctx, name)
+	defer span.End()
+	tagComponent(span, "formatter", f.delegate)
+
+	if data != nil {
+		SetIntTag(span, "input_count", int64(len(data)))
+	}
+
+	result, err := format(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	SetIntTag(span, "bytes_count", int64(len(result)))
+	return result, nil
+}
+
+// OutputWithTracing traces the calls to an OutputStrategy.
 type OutputWithTracing struct {
 	delegate output.OutputStrategy
 	tracer   Tracer
 }
 
-func NewOutputWithTracing(delegate output.OutputStrategy, tracer Tracer) *OutputWithTracing {
-	return &OutputWithTracing{
-		delegate: delegate,
-		tracer:   tracer,
-	}
+// NewOutputWithTracing creates a new tracing decorator. It keeps the
+// optional interfaces of delegate (see output.Decorate), so a streaming
+// output still streams, with an "output.write_chunk" span per chunk.
+func NewOutputWithTracing(delegate output.OutputStrategy, tracer Tracer) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithTracing{delegate: delegate, tracer: tracer})
 }
 
-func (o *OutputWithTracing) Send(ctx context.Context, data []byte) error {
-	ctx, span := o.tracer.StartSpan(ctx, "output.send")
+// Intercept runs call in an "output.<op>" span tagged with the bytes or
+// records it delivered.
+func (o *OutputWithTracing) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	ctx, span := o.tracer.StartSpan(ctx, "output."+op)
 	defer span.End()
+	tagComponent(span, "output", o.delegate)
 
-	span.SetTag("bytes_count", fmt.Sprintf("%d", len(data)))
+	switch op {
+	case output.OpSend, output.OpWriteChunk:
+		SetIntTag(span, "bytes_count", int64(size))
+	case output.OpSendRecords:
+		SetIntTag(span, "record_count", int64(size))
+	}
 
-	err := o.delegate.Send(ctx, data)
+	err := call(ctx)
 	if err != nil {
 		span.RecordError(err)
 	}
 	return err
 }
 
-func (o *OutputWithTracing) Close() error {
-	if closer, ok := o.delegate.(api.Closeable); ok {
-		return closer.Close()
-	}
-	return nil
+// tagComponent names the pipeline component a span covers and the type
+// implementing it, such as "*provider.SQLProvider".
+func tagComponent(span Span, component string, delegate interface{}) {
+	span.SetTag("component", component)
+	span.SetTag("component_type", fmt.Sprintf("%T", delegate))
 }
@@ -0,0 +1,181 @@
+package output
+
+import (
+	"context"
+	"io"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// Operations an Interceptor is asked to run.
+const (
+	OpSend        = "send"
+	OpSendRecords = "send_records"
+	OpInitialize  = "initialize"
+	OpResume      = "resume"
+	OpWriteChunk  = "write_chunk"
+	OpClose       = "close"
+	OpAbort       = "abort"
+)
+
+// Interceptor adds behavior, such as retries, limits or instrumentation,
+// around the calls a decorator forwards to the output it wraps.
+type Interceptor interface {
+	// Intercept runs call, the forwarded operation op. size is the number
+	// of bytes (Send, WriteChunk) or records (SendRecords) delivered by
+	// the call, or 0.
+	Intercept(ctx context.Context, op string, size int, call func(ctx context.Context) error) error
+}
+
+// Decorate wraps delegate so every call to it goes through interceptor.
+//
+// The decorator implements the optional interfaces delegate implements:
+// RecordOutputStrategy, StreamingOutputStrategy and ResumableOutput, so
+// wrapping an output does not change how the engine drives it. Streaming
+// decorators are always AbortableOutput; for a delegate that cannot abort,
+// Abort closes the stream, as the engine would. Location returns the
+// delegate's location, or "" if it does not describe one.
+func Decorate(delegate OutputStrategy, interceptor Interceptor) OutputStrategy {
+	base := decorated{delegate: delegate, interceptor: interceptor}
+	records, isRecord := delegate.(RecordOutputStrategy)
+	streamer, isStreaming := delegate.(StreamingOutputStrategy)
+	resumer, isResumable := delegate.(ResumableOutput)
+
+	if !isStreaming {
+		if isRecord {
+			return &recordDecorated{decorated: base, records: records}
+		}
+		return &base
+	}
+
+	stream := streamingDecorated{decorated: base, streamer: streamer}
+	switch {
+	case isResumable && isRecord:
+		return &resumableRecordDecorated{resumableDecorated: resumableDecorated{streamingDecorated: stream, resumer: resumer}, records: records}
+	case isResumable:
+		return &resumableDecorated{streamingDecorated: stream, resumer: resumer}
+	case isRecord:
+		return &streamingRecordDecorated{streamingDecorated: stream, records: records}
+	}
+	return &stream
+}
+
+// decorated forwards Send and cleanup.
+type decorated struct {
+	delegate    OutputStrategy
+	interceptor Interceptor
+}
+
+func (d *decorated) Send(ctx context.Context, data []byte) error {
+	return d.interceptor.Intercept(ctx, OpSend, len(data), func(ctx context.Context) error {
+		return d.delegate.Send(ctx, data)
+	})
+}
+
+// Location returns the delegate's location, if it has one.
+func (d *decorated) Location() string {
+	if located, ok := d.delegate.(LocatedOutput); ok {
+		return located.Location()
+	}
+	return ""
+}
+
+// Unwrap returns the wrapped output.
+func (d *decorated) Unwrap() OutputStrategy {
+	return d.delegate
+}
+
+// Close releases the delegate's resources, if it holds any.
+func (d *decorated) Close() error {
+	return d.CloseWithContext(context.Background())
+}
+
+// CloseWithContext releases the delegate's resources, if it holds any.
+func (d *decorated) CloseWithContext(ctx context.Context) error {
+	if closer, ok := d.delegate.(api.CloseableWithContext); ok {
+		return closer.CloseWithContext(ctx)
+	}
+	if closer, ok := d.delegate.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// recordDecorated also forwards SendRecords.
+type recordDecorated struct {
+	decorated
+	records RecordOutputStrategy
+}
+
+func (d *recordDecorated) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	return sendRecords(ctx, d.interceptor, d.records, records)
+}
+
+// streamingDecorated also forwards the streaming calls. Its Close
+// finalizes the stream; CloseWithContext still releases resources.
+type streamingDecorated struct {
+	decorated
+	streamer StreamingOutputStrategy
+}
+
+func (d *streamingDecorated) Initialize(ctx context.Context) error {
+	return d.interceptor.Intercept(ctx, OpInitialize, 0, d.streamer.Initialize)
+}
+
+func (d *streamingDecorated) WriteChunk(ctx context.Context, data []byte) error {
+	return d.interceptor.Intercept(ctx, OpWriteChunk, len(data), func(ctx context.Context) error {
+		return d.streamer.WriteChunk(ctx, data)
+	})
+}
+
+func (d *streamingDecorated) Close(ctx context.Context) error {
+	return d.interceptor.Intercept(ctx, OpClose, 0, d.streamer.Close)
+}
+
+func (d *streamingDecorated) Abort(ctx context.Context) error {
+	return d.interceptor.Intercept(ctx, OpAbort, 0, func(ctx context.Context) error {
+		if abortable, ok := d.streamer.(AbortableOutput); ok {
+			return abortable.Abort(ctx)
+		}
+		return d.streamer.Close(ctx)
+	})
+}
+
+// streamingRecordDecorated forwards streaming calls and SendRecords.
+type streamingRecordDecorated struct {
+	streamingDecorated
+	records RecordOutputStrategy
+}
+
+func (d *streamingRecordDecorated) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	return sendRecords(ctx, d.interceptor, d.records, records)
+}
+
+// resumableDecorated also forwards Resume.
+type resumableDecorated struct {
+	streamingDecorated
+	resumer ResumableOutput
+}
+
+func (d *resumableDecorated) Resume(ctx context.Context, offset int64) error {
+	return d.interceptor.Intercept(ctx, OpResume, 0, func(ctx context.Context) error {
+		return d.resumer.Resume(ctx, offset)
+	})
+}
+
+// resumableRecordDecorated forwards Resume and SendRecords.
+type resumableRecordDecorated struct {
+	resumableDecorated
+	records RecordOutputStrategy
+}
+
+func (d *resumableRecordDecorated) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	return sendRecords(ctx, d.interceptor, d.records, records)
+}
+
+// sendRecords forwards a SendRecords call through interceptor.
+func sendRecords(ctx context.Context, interceptor Interceptor, out RecordOutputStrategy, records []map[string]interface{}) error {
+	return interceptor.Intercept(ctx, OpSendRecords, len(records), func(ctx context.Context) error {
+		return out.SendRecords(ctx, records)
+	})
+}
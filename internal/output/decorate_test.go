@@ -0,0 +1,168 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordingInterceptor records the operations it runs.
+type recordingInterceptor struct {
+	ops   []string
+	sizes []int
+}
+
+func (i *recordingInterceptor) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	i.ops = append(i.ops, op)
+	i.sizes = append(i.sizes, size)
+	return call(ctx)
+}
+
+// streamOutput is a streaming output that records its calls.
+type streamOutput struct {
+	calls []string
+}
+
+func (o *streamOutput) Send(ctx context.Context, data []byte) error {
+	o.calls = append(o.calls, "send")
+	return nil
+}
+func (o *streamOutput) Initialize(ctx context.Context) error {
+	o.calls = append(o.calls, "initialize")
+	return nil
+}
+func (o *streamOutput) WriteChunk(ctx context.Context, data []byte) error {
+	o.calls = append(o.calls, "write_chunk")
+	return nil
+}
+func (o *streamOutput) Close(ctx context.Context) error {
+	o.calls = append(o.calls, "close")
+	return nil
+}
+func (o *streamOutput) Location() string { return "/reports/out.json" }
+
+// abortableOutput is a streaming output that can abort.
+type abortableOutput struct{ streamOutput }
+
+func (o *abortableOutput) Abort(ctx context.Context) error {
+	o.calls = append(o.calls, "abort")
+	return nil
+}
+
+// resumableOutput is a streaming output that can resume.
+type resumableOutput struct{ streamOutput }
+
+func (o *resumableOutput) Resume(ctx context.Context, offset int64) error {
+	o.calls = append(o.calls, "resume")
+	return nil
+}
+
+// recordSink is a record output with resources to release.
+type recordSink struct {
+	records int
+	closed  bool
+}
+
+func (o *recordSink) Send(ctx context.Context, data []byte) error {
+	return errors.New("bytes not supported")
+}
+func (o *recordSink) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	o.records += len(records)
+	return nil
+}
+func (o *recordSink) Close() error {
+	o.closed = true
+	return nil
+}
+
+func TestDecorate_KeepsOptionalInterfaces(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		delegate                              OutputStrategy
+		record, streaming, resumable, located bool
+	}{
+		{"records", &recordSink{}, true, false, false, false},
+		{"console", NewConsoleOutput(), false, false, false, false},
+		{"streaming", &streamOutput{}, false, true, false, true},
+		{"resumable", &resumableOutput{}, false, true, true, true},
+		{"file", NewFileOutput(), false, true, true, false}, // no path configured
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decorate(tt.delegate, &recordingInterceptor{})
+			if _, ok := d.(RecordOutputStrategy); ok != tt.record {
+				t.Errorf("RecordOutputStrategy = %v, want %v", ok, tt.record)
+			}
+			if _, ok := d.(StreamingOutputStrategy); ok != tt.streaming {
+				t.Errorf("StreamingOutputStrategy = %v, want %v", ok, tt.streaming)
+			}
+			if _, ok := d.(AbortableOutput); ok != tt.streaming {
+				t.Errorf("AbortableOutput = %v, want %v", ok, tt.streaming)
+			}
+			if _, ok := d.(ResumableOutput); ok != tt.resumable {
+				t.Errorf("ResumableOutput = %v, want %v", ok, tt.resumable)
+			}
+			if located := d.(LocatedOutput).Location() != ""; located != tt.located {
+				t.Errorf("Location() set = %v, want %v", located, tt.located)
+			}
+		})
+	}
+}
+
+func TestDecorate_InterceptsStream(t *testing.T) {
+	delegate := &resumableOutput{}
+	interceptor := &recordingInterceptor{}
+	d := Decorate(delegate, interceptor).(ResumableOutput)
+	ctx := context.Background()
+
+	_ = d.Resume(ctx, 10)
+	_ = d.WriteChunk(ctx, []byte("abc"))
+	_ = d.Close(ctx)
+
+	want := []string{OpResume, OpWriteChunk, OpClose}
+	if !reflect.DeepEqual(interceptor.ops, want) {
+		t.Errorf("intercepted %v, want %v", interceptor.ops, want)
+	}
+	if interceptor.sizes[1] != 3 {
+		t.Errorf("write_chunk size = %d, want 3", interceptor.sizes[1])
+	}
+	if !reflect.DeepEqual(delegate.calls, []string{"resume", "write_chunk", "close"}) {
+		t.Errorf("delegate calls = %v", delegate.calls)
+	}
+}
+
+func TestDecorate_Abort(t *testing.T) {
+	ctx := context.Background()
+
+	abortable := &abortableOutput{}
+	_ = Decorate(abortable, &recordingInterceptor{}).(AbortableOutput).Abort(ctx)
+	if !reflect.DeepEqual(abortable.calls, []string{"abort"}) {
+		t.Errorf("abortable delegate calls = %v, want abort", abortable.calls)
+	}
+
+	// An output that cannot abort is closed, as the engine would do
+	plain := &streamOutput{}
+	_ = Decorate(plain, &recordingInterceptor{}).(AbortableOutput).Abort(ctx)
+	if !reflect.DeepEqual(plain.calls, []string{"close"}) {
+		t.Errorf("delegate calls = %v, want close", plain.calls)
+	}
+}
+
+func TestDecorate_RecordsAndCleanup(t *testing.T) {
+	sink := &recordSink{}
+	interceptor := &recordingInterceptor{}
+	d := Decorate(sink, interceptor)
+
+	records := []map[string]interface{}{{"id": 1}, {"id": 2}}
+	if err := d.(RecordOutputStrategy).SendRecords(context.Background(), records); err != nil {
+		t.Fatalf("SendRecords failed: %v", err)
+	}
+	if sink.records != 2 || interceptor.ops[0] != OpSendRecords || interceptor.sizes[0] != 2 {
+		t.Errorf("records = %d, intercepted %v %v", sink.records, interceptor.ops, interceptor.sizes)
+	}
+
+	if err := d.(interface{ Close() error }).Close(); err != nil || !sink.closed {
+		t.Errorf("Close() = %v, delegate closed = %v", err, sink.closed)
+	}
+}
@@ -3,9 +3,12 @@ package output
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // FileOutput implements OutputStrategy for writing to the filesystem.
@@ -64,6 +67,14 @@ func (f *FileOutput) Send(ctx context.Context, data []byte) error {
 	return nil
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (f *FileOutput) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "path", Type: api.ParamString, Required: true, Description: "Output file path"},
+		{Name: "mode", Type: api.ParamString, Default: "0644", Description: "Octal file permission"},
+	}
+}
+
 // Configure sets up the output from a map of parameters.
 // Params:
 // - path: Output file path (required)
@@ -87,6 +98,11 @@ func (f *FileOutput) Configure(params map[string]string) error {
 	return nil
 }
 
+// Location returns the output file path.
+func (f *FileOutput) Location() string {
+	return f.Path
+}
+
 // Initialize prepares the output for streaming (opens the file).
 func (f *FileOutput) Initialize(ctx context.Context) error {
 	if f.Path == "" {
@@ -108,6 +124,37 @@ func (f *FileOutput) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// Resume reopens the file to continue a stream, truncating it to the
+// offset bytes committed before the previous run failed.
+func (f *FileOutput) Resume(ctx context.Context, offset int64) error {
+	if f.Path == "" {
+		return fmt.Errorf("file output: path not configured")
+	}
+
+	file, err := os.OpenFile(f.Path, os.O_WRONLY, f.Mode)
+	if err != nil {
+		return fmt.Errorf("file output: failed to reopen file %s: %w", f.Path, err)
+	}
+
+	info, err := file.Stat()
+	if err == nil && info.Size() < offset {
+		err = fmt.Errorf("file is %d bytes, expected at least %d", info.Size(), offset)
+	}
+	if err == nil {
+		err = file.Truncate(offset)
+	}
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("file output: cannot resume %s at byte %d: %w", f.Path, offset, err)
+	}
+
+	f.file = file
+	return nil
+}
+
 // WriteChunk writes a chunk of data to the open file.
 func (f *FileOutput) WriteChunk(ctx context.Context, data []byte) error {
 	if f.file == nil {
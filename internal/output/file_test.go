@@ -108,3 +108,57 @@ func TestFileOutput_Configure(t *testing.T) {
 		})
 	}
 }
+
+func TestFileOutput_Location(t *testing.T) {
+	out := NewFileOutput()
+	if err := out.Configure(map[string]string{"path": "/tmp/report.json"}); err != nil {
+		t.Fatal(err)
+	}
+	var located LocatedOutput = out
+	if got := located.Location(); got != "/tmp/report.json" {
+		t.Errorf("Location() = %q", got)
+	}
+}
+
+func TestFileOutput_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte(`[{"id":1},{"id":2`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := NewFileOutput()
+	if err := out.Configure(map[string]string{"path": path}); err != nil {
+		t.Fatal(err)
+	}
+	var resumable ResumableOutput = out
+
+	// Bytes after the committed offset are discarded
+	if err := resumable.Resume(context.Background(), 9); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if err := out.WriteChunk(context.Background(), []byte(`,{"id":2}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(path)
+	if string(got) != `[{"id":1},{"id":2}]` {
+		t.Errorf("file content = %s", got)
+	}
+
+	if err := out.Resume(context.Background(), 100); err == nil {
+		t.Error("Resume() past the end of the file should fail")
+	}
+	if err := out.Resume(context.Background(), 0); err != nil {
+		t.Errorf("Resume(0) error = %v", err)
+	}
+	_ = out.Close(context.Background())
+
+	missing := NewFileOutput()
+	_ = missing.Configure(map[string]string{"path": filepath.Join(t.TempDir(), "missing.json")})
+	if err := missing.Resume(context.Background(), 0); err == nil {
+		t.Error("Resume() of a missing file should fail")
+	}
+}
@@ -106,3 +106,50 @@ type StreamingOutputStrategy interface {
 	// Close finalizes the output stream (e.g., closing a file).
 	Close(ctx context.Context) error
 }
+
+// AbortableOutput is implemented by streaming outputs that can discard a
+// partially written stream instead of finalizing it.
+//
+// When a streaming run fails, the engine calls Abort instead of Close so
+// outputs with atomic delivery semantics (e.g. temp-file-then-rename) never
+// publish incomplete reports.
+type AbortableOutput interface {
+	// Abort discards any partially written data and releases resources.
+	Abort(ctx context.Context) error
+}
+
+// RecordOutputStrategy is implemented by outputs that consume structured
+// records instead of formatted bytes, such as database sinks.
+//
+// When the engine's output implements this interface, the batch pipeline
+// hands the processed records to SendRecords and skips the format stage.
+// Send remains available as a fallback for callers that only have
+// formatted data.
+type RecordOutputStrategy interface {
+	OutputStrategy
+
+	// SendRecords delivers processed records to the destination.
+	SendRecords(ctx context.Context, records []map[string]interface{}) error
+}
+
+// LocatedOutput is implemented by outputs that can describe where they
+// deliver reports, such as a file path, remote URL, table or topic.
+//
+// The engine records the location in RunResult.OutputLocation so run
+// history can show which outputs a run produced.
+type LocatedOutput interface {
+	// Location returns the destination of delivered reports.
+	Location() string
+}
+
+// ResumableOutput is implemented by streaming outputs that can continue a
+// partially written stream, so a failed streaming run can resume from its
+// last checkpoint.
+type ResumableOutput interface {
+	StreamingOutputStrategy
+
+	// Resume prepares the output to continue a stream whose first offset
+	// bytes were committed, discarding anything written after them. It is
+	// called instead of Initialize.
+	Resume(ctx context.Context, offset int64) error
+}
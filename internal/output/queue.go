@@ -0,0 +1,198 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// Queue publish modes supported by QueueOutput.
+const (
+	QueueModeRecord = "record"
+	QueueModeChunk  = "chunk"
+)
+
+// QueueOutput implements RecordOutputStrategy for publishing report rows to
+// a broker topic.
+//
+// In "record" mode every record becomes one JSON object message, keyed by
+// KeyField when set. In "chunk" mode records are grouped into JSON array
+// messages of up to ChunkSize records each. Either shape can be read back by
+// QueueProvider.
+type QueueOutput struct {
+	Broker    string
+	Topic     string
+	Mode      string
+	KeyField  string
+	ChunkSize int
+	Headers   map[string]string
+
+	// broker, if set, overrides the named broker lookup (useful for tests)
+	broker broker.Broker
+}
+
+// NewQueueOutput creates a new instance of QueueOutput with defaults.
+func NewQueueOutput() *QueueOutput {
+	return &QueueOutput{
+		Broker:    broker.DefaultBroker,
+		Mode:      QueueModeRecord,
+		ChunkSize: 100,
+		Headers:   make(map[string]string),
+	}
+}
+
+// Location returns the destination as "<broker>/<topic>".
+func (q *QueueOutput) Location() string {
+	return q.Broker + "/" + q.Topic
+}
+
+// ParamSpecs declares the parameters accepted by Configure.
+func (q *QueueOutput) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "topic", Type: api.ParamString, Required: true, Description: "Topic to publish to"},
+		{Name: "broker", Type: api.ParamString, Default: "memory", Description: "Registered broker name"},
+		{Name: "mode", Type: api.ParamString, Default: "record", Description: "Publish one message per record or per chunk", Enum: []string{"record", "chunk"}},
+		{Name: "key_field", Type: api.ParamString, Description: "Record field used as the message key (record mode only)"},
+		{Name: "chunk_size", Type: api.ParamInt, Default: "100", Description: "Records per message in chunk mode"},
+		{Name: "header_*", Type: api.ParamString, Description: "Static message header, e.g. header_source"},
+	}
+}
+
+// Configure sets up the output from a map of parameters.
+// Params:
+// - topic: Topic to publish to (required)
+// - broker: Registered broker name (default: "memory")
+// - mode: "record" or "chunk" (default: "record")
+// - key_field: Record field used as the message key (record mode only)
+// - chunk_size: Records per message in chunk mode (default: "100")
+// - header_<KEY>: Static message headers, e.g., "header_source"
+func (q *QueueOutput) Configure(params map[string]string) error {
+	if topic, ok := params["topic"]; ok && topic != "" {
+		q.Topic = topic
+	} else {
+		return fmt.Errorf("queue output: missing required parameter 'topic'")
+	}
+
+	if name, ok := params["broker"]; ok && name != "" {
+		q.Broker = name
+	}
+
+	if mode, ok := params["mode"]; ok {
+		q.Mode = strings.ToLower(mode)
+		if q.Mode != QueueModeRecord && q.Mode != QueueModeChunk {
+			return fmt.Errorf("queue output: unsupported mode %q (use 'record' or 'chunk')", mode)
+		}
+	}
+
+	if keyField, ok := params["key_field"]; ok {
+		q.KeyField = keyField
+	}
+
+	if sizeStr, ok := params["chunk_size"]; ok {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("queue output: invalid chunk_size %s", sizeStr)
+		}
+		q.ChunkSize = size
+	}
+
+	for k, v := range params {
+		if strings.HasPrefix(k, "header_") {
+			if key := strings.TrimPrefix(k, "header_"); key != "" {
+				q.Headers[key] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+// Send decodes JSON-formatted records and publishes them with SendRecords.
+// The formatter must produce a JSON array of objects.
+func (q *QueueOutput) Send(ctx context.Context, data []byte) error {
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("queue output: data must be a JSON array of objects: %w", err)
+	}
+	return q.SendRecords(ctx, records)
+}
+
+// SendRecords publishes the records according to the configured mode.
+func (q *QueueOutput) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if q.Topic == "" {
+		return fmt.Errorf("queue output: topic not configured")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	b := q.broker
+	if b == nil {
+		var err error
+		b, err = broker.Get(q.Broker)
+		if err != nil {
+			return fmt.Errorf("queue output: %w", err)
+		}
+	}
+
+	msgs, err := q.messages(records)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Publish(ctx, q.Topic, msgs...); err != nil {
+		return fmt.Errorf("queue output: publish to %s failed: %w", q.Topic, err)
+	}
+	return nil
+}
+
+// messages encodes records into broker messages for the configured mode.
+func (q *QueueOutput) messages(records []map[string]interface{}) ([]broker.Message, error) {
+	if q.Mode == QueueModeChunk {
+		size := q.ChunkSize
+		if size <= 0 {
+			size = 100
+		}
+
+		msgs := make([]broker.Message, 0, (len(records)+size-1)/size)
+		for start := 0; start < len(records); start += size {
+			end := start + size
+			if end > len(records) {
+				end = len(records)
+			}
+			value, err := json.Marshal(records[start:end])
+			if err != nil {
+				return nil, fmt.Errorf("queue output: failed to encode chunk starting at row %d: %w", start, err)
+			}
+			msgs = append(msgs, broker.Message{Value: value, Headers: q.Headers})
+		}
+		return msgs, nil
+	}
+
+	msgs := make([]broker.Message, 0, len(records))
+	for i, record := range records {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("queue output: failed to encode row %d: %w", i, err)
+		}
+		msg := broker.Message{Value: value, Headers: q.Headers}
+		if q.KeyField != "" {
+			if key, ok := record[q.KeyField]; ok && key != nil {
+				msg.Key = fmt.Sprintf("%v", key)
+			}
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,214 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
+)
+
+// readTopic returns every message published to topic.
+func readTopic(t *testing.T, b *broker.MemoryBroker, topic string) []broker.Message {
+	t.Helper()
+	ctx := context.Background()
+
+	sub, err := b.Subscribe(ctx, topic, "test-reader")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	msgs := make([]broker.Message, 0, b.Len(topic))
+	for i := 0; i < b.Len(topic); i++ {
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestQueueOutput_RecordMode(t *testing.T) {
+	b := broker.NewMemoryBroker()
+
+	q := NewQueueOutput()
+	q.Topic = "rows"
+	q.KeyField = "id"
+	q.Headers["source"] = "report-engine"
+	q.broker = b
+
+	records := []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+	}
+	if err := q.SendRecords(context.Background(), records); err != nil {
+		t.Fatalf("SendRecords() error = %v", err)
+	}
+
+	msgs := readTopic(t, b, "rows")
+	if len(msgs) != 2 {
+		t.Fatalf("published %d messages, want 2", len(msgs))
+	}
+	if msgs[1].Key != "2" {
+		t.Errorf("message key = %q, want %q", msgs[1].Key, "2")
+	}
+	if msgs[0].Headers["source"] != "report-engine" {
+		t.Errorf("message headers = %v", msgs[0].Headers)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(msgs[0].Value, &decoded); err != nil {
+		t.Fatalf("message value is not a JSON object: %v", err)
+	}
+	if decoded["name"] != "a" {
+		t.Errorf("decoded record = %v", decoded)
+	}
+}
+
+func TestQueueOutput_ChunkMode(t *testing.T) {
+	b := broker.NewMemoryBroker()
+
+	q := NewQueueOutput()
+	q.Topic = "chunks"
+	q.Mode = QueueModeChunk
+	q.ChunkSize = 2
+	q.broker = b
+
+	records := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}}
+	if err := q.SendRecords(context.Background(), records); err != nil {
+		t.Fatalf("SendRecords() error = %v", err)
+	}
+
+	msgs := readTopic(t, b, "chunks")
+	if len(msgs) != 2 {
+		t.Fatalf("published %d messages, want 2", len(msgs))
+	}
+
+	var chunk []map[string]interface{}
+	if err := json.Unmarshal(msgs[1].Value, &chunk); err != nil {
+		t.Fatalf("message value is not a JSON array: %v", err)
+	}
+	if len(chunk) != 1 || chunk[0]["id"] != float64(3) {
+		t.Errorf("last chunk = %v", chunk)
+	}
+}
+
+func TestQueueOutput_Send(t *testing.T) {
+	b := broker.NewMemoryBroker()
+
+	q := NewQueueOutput()
+	q.Topic = "rows"
+	q.broker = b
+
+	if err := q.Send(context.Background(), []byte(`[{"id": 1}, {"id": 2}]`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if b.Len("rows") != 2 {
+		t.Errorf("published %d messages, want 2", b.Len("rows"))
+	}
+
+	if err := q.Send(context.Background(), []byte("id,name")); err == nil {
+		t.Error("expected error for non-JSON data")
+	}
+}
+
+func TestQueueOutput_Errors(t *testing.T) {
+	t.Run("topic not configured", func(t *testing.T) {
+		q := NewQueueOutput()
+		if err := q.SendRecords(context.Background(), []map[string]interface{}{{"id": 1}}); err == nil {
+			t.Error("expected error when topic is not configured")
+		}
+	})
+
+	t.Run("unknown broker", func(t *testing.T) {
+		q := NewQueueOutput()
+		q.Topic = "t"
+		q.Broker = "missing"
+		if err := q.SendRecords(context.Background(), []map[string]interface{}{{"id": 1}}); err == nil {
+			t.Error("expected error for unregistered broker")
+		}
+	})
+
+	t.Run("closed broker", func(t *testing.T) {
+		b := broker.NewMemoryBroker()
+		_ = b.Close()
+		q := NewQueueOutput()
+		q.Topic = "t"
+		q.broker = b
+		if err := q.SendRecords(context.Background(), []map[string]interface{}{{"id": 1}}); err == nil {
+			t.Error("expected error for closed broker")
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		q := NewQueueOutput()
+		q.Topic = "t"
+		if err := q.SendRecords(ctx, []map[string]interface{}{{"id": 1}}); err == nil {
+			t.Error("expected error for canceled context")
+		}
+	})
+}
+
+func TestQueueOutput_Configure(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantErr bool
+	}{
+		{name: "valid config", params: map[string]string{"topic": "rows"}},
+		{
+			name: "all options",
+			params: map[string]string{
+				"topic":         "rows",
+				"broker":        "memory",
+				"mode":          "chunk",
+				"chunk_size":    "50",
+				"key_field":     "id",
+				"header_source": "reports",
+			},
+		},
+		{name: "missing topic", params: map[string]string{}, wantErr: true},
+		{name: "invalid mode", params: map[string]string{"topic": "t", "mode": "batch"}, wantErr: true},
+		{name: "invalid chunk_size", params: map[string]string{"topic": "t", "chunk_size": "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueueOutput()
+			if err := q.Configure(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("QueueOutput.Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueueOutput_RoundTripThroughNamedBroker(t *testing.T) {
+	b := broker.NewMemoryBroker()
+	broker.Register("queue-output-test", b)
+	defer broker.Unregister("queue-output-test")
+
+	q := NewQueueOutput()
+	if err := q.Configure(map[string]string{"topic": "t", "broker": "queue-output-test", "header_env": "test"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if err := q.SendRecords(context.Background(), []map[string]interface{}{{"id": 1}}); err != nil {
+		t.Fatalf("SendRecords() error = %v", err)
+	}
+
+	msgs := readTopic(t, b, "t")
+	if len(msgs) != 1 || msgs[0].Headers["env"] != "test" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestQueueOutput_Location(t *testing.T) {
+	out := NewQueueOutput()
+	out.Topic = "reports"
+	if got := out.Location(); got != "memory/reports" {
+		t.Errorf("Location() = %q", got)
+	}
+}
@@ -0,0 +1,554 @@
+package output
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sftpSession is the subset of SFTP client operations used by SFTPOutput.
+// It exists so tests can run against an in-memory SFTP server.
+type sftpSession interface {
+	MkdirAll(dir string) error
+	Create(name string) (io.WriteCloser, error)
+	Rename(oldname, newname string) error
+	Remove(name string) error
+	Stat(name string) (os.FileInfo, error)
+	Close() error
+}
+
+// sftpDialer opens a new SFTP session.
+type sftpDialer func(ctx context.Context) (sftpSession, error)
+
+// Upload tuning. Writes are split into chunks of writeChunkSize so a
+// canceled upload stops between them, and removing the temporary file of
+// an interrupted upload waits at most cleanupTimeout when no connect
+// timeout is configured.
+const (
+	writeChunkSize = 256 * 1024
+	cleanupTimeout = 30 * time.Second
+)
+
+// SFTPOutput implements OutputStrategy and StreamingOutputStrategy for
+// delivering reports to a remote SFTP drop folder.
+//
+// Delivery is atomic: data is written to a temporary file next to the
+// destination (RemotePath, a random token and TempSuffix, e.g.
+// "orders.json.5f1c9a2b7d3e.tmp") and renamed into place only after the
+// upload has completed. Every upload has its own temporary file, so
+// concurrent uploads to the same path never write into each other. Failed uploads remove the temporary file, so
+// partners polling the folder never see a partially written report.
+//
+// Authentication is key-based. The server host key is verified against a
+// known_hosts file unless InsecureIgnoreHostKey is explicitly enabled.
+//
+// Thread-safe: Send may be called concurrently. A streaming session
+// (Initialize/WriteChunk/Close) must not be shared between goroutines.
+type SFTPOutput struct {
+	Host                  string
+	Port                  int
+	User                  string
+	PrivateKeyPath        string
+	Passphrase            string
+	KnownHostsPath        string
+	InsecureIgnoreHostKey bool
+	RemotePath            string
+	TempSuffix            string
+	Timeout               time.Duration
+
+	// dial allows injecting a custom session factory (useful for tests).
+	dial sftpDialer
+
+	mu      sync.Mutex
+	session sftpSession
+	file    io.WriteCloser
+	tmpPath string
+}
+
+// NewSFTPOutput creates a new instance of SFTPOutput with defaults.
+func NewSFTPOutput() *SFTPOutput {
+	return &SFTPOutput{
+		Port:       22,
+		TempSuffix: ".tmp",
+		Timeout:    30 * time.Second,
+	}
+}
+
+// ParamSpecs declares the parameters accepted by Configure.
+func (s *SFTPOutput) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "host", Type: api.ParamString, Required: true, Description: "SFTP server host"},
+		{Name: "port", Type: api.ParamInt, Default: "22", Description: "SFTP server port"},
+		{Name: "user", Type: api.ParamString, Required: true, Description: "Login user"},
+		{Name: "private_key_path", Type: api.ParamString, Required: true, Description: "Path to the PEM encoded private key"},
+		{Name: "passphrase", Type: api.ParamString, Description: "Passphrase for an encrypted private key"},
+		{Name: "known_hosts_path", Type: api.ParamString, Description: "Path to a known_hosts file used to verify the server"},
+		{Name: "insecure_ignore_host_key", Type: api.ParamBool, Default: "false", Description: "Skip host key verification"},
+		{Name: "remote_path", Type: api.ParamString, Required: true, Description: "Destination file path on the server"},
+		{Name: "temp_suffix", Type: api.ParamString, Default: ".tmp", Description: "Suffix for the temporary upload file"},
+		{Name: "timeout", Type: api.ParamDuration, Default: "30s", Description: "Connection timeout"},
+	}
+}
+
+// Configure sets up the output from a map of parameters.
+// Params:
+// - host: SFTP server host (required)
+// - port: SFTP server port (default: "22")
+// - user: Login user (required)
+// - private_key_path: Path to the PEM encoded private key (required)
+// - passphrase: Passphrase for an encrypted private key
+// - known_hosts_path: Path to a known_hosts file used to verify the server
+// - insecure_ignore_host_key: "true" to skip host key verification
+// - remote_path: Destination file path on the server (required)
+// - temp_suffix: Suffix for the temporary upload file (default: ".tmp")
+// - timeout: Connection timeout duration string (default: "30s")
+func (s *SFTPOutput) Configure(params map[string]string) error {
+	if host, ok := params["host"]; ok && host != "" {
+		s.Host = host
+	} else {
+		return fmt.Errorf("sftp output: missing required parameter 'host'")
+	}
+
+	if user, ok := params["user"]; ok && user != "" {
+		s.User = user
+	} else {
+		return fmt.Errorf("sftp output: missing required parameter 'user'")
+	}
+
+	if keyPath, ok := params["private_key_path"]; ok && keyPath != "" {
+		s.PrivateKeyPath = keyPath
+	} else {
+		return fmt.Errorf("sftp output: missing required parameter 'private_key_path'")
+	}
+
+	if remotePath, ok := params["remote_path"]; ok && remotePath != "" {
+		s.RemotePath = remotePath
+	} else {
+		return fmt.Errorf("sftp output: missing required parameter 'remote_path'")
+	}
+
+	if portStr, ok := params["port"]; ok {
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port <= 0 || port > 65535 {
+			return fmt.Errorf("sftp output: invalid port %s", portStr)
+		}
+		s.Port = port
+	}
+
+	s.Passphrase = params["passphrase"]
+	s.KnownHostsPath = params["known_hosts_path"]
+	s.InsecureIgnoreHostKey = strings.EqualFold(params["insecure_ignore_host_key"], "true")
+
+	if s.KnownHostsPath == "" && !s.InsecureIgnoreHostKey {
+		return fmt.Errorf("sftp output: 'known_hosts_path' is required unless 'insecure_ignore_host_key' is true")
+	}
+
+	if suffix, ok := params["temp_suffix"]; ok {
+		if suffix == "" {
+			return fmt.Errorf("sftp output: temp_suffix cannot be empty")
+		}
+		s.TempSuffix = suffix
+	}
+
+	if timeoutStr, ok := params["timeout"]; ok {
+		d, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return fmt.Errorf("sftp output: invalid timeout %s: %w", timeoutStr, err)
+		}
+		s.Timeout = d
+	}
+
+	return nil
+}
+
+// Send uploads the data to the remote path atomically. If ctx is done
+// before the upload completes, the session is closed to interrupt any
+// pending write, the temporary file is removed and ctx's error returned.
+func (s *SFTPOutput) Send(ctx context.Context, data []byte) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	session, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = session.Close() }()
+
+	file, tmpPath, err := s.createTemp(session)
+	if err != nil {
+		return err
+	}
+
+	err = interruptible(ctx, session, func() error {
+		if err := writeChunks(ctx, file, data); err != nil {
+			_ = file.Close()
+			_ = session.Remove(tmpPath)
+			return fmt.Errorf("sftp output: failed to write %s: %w", tmpPath, err)
+		}
+		return s.commit(session, file, tmpPath)
+	})
+	if ctx.Err() != nil && err != nil {
+		return s.interrupted(ctx, tmpPath)
+	}
+	return err
+}
+
+// Initialize opens an SFTP session and the temporary upload file for streaming.
+func (s *SFTPOutput) Initialize(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session != nil {
+		return fmt.Errorf("sftp output: stream already initialized")
+	}
+
+	session, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, tmpPath, err := s.createTemp(session)
+	if err != nil {
+		_ = session.Close()
+		return err
+	}
+
+	s.session = session
+	s.file = file
+	s.tmpPath = tmpPath
+	return nil
+}
+
+// WriteChunk appends a chunk of data to the temporary upload file.
+func (s *SFTPOutput) WriteChunk(ctx context.Context, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("sftp output: stream not initialized")
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	err := interruptible(ctx, s.session, func() error {
+		return writeChunks(ctx, s.file, data)
+	})
+	if ctx.Err() != nil && err != nil {
+		tmpPath := s.tmpPath
+		_ = s.file.Close()
+		s.resetStream()
+		return s.interrupted(ctx, tmpPath)
+	}
+	if err != nil {
+		return fmt.Errorf("sftp output: failed to write chunk: %w", err)
+	}
+	return nil
+}
+
+// Close finalizes the stream by renaming the temporary file into place.
+func (s *SFTPOutput) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil {
+		return nil
+	}
+	defer s.resetStream()
+
+	err := interruptible(ctx, s.session, func() error {
+		return s.commit(s.session, s.file, s.tmpPath)
+	})
+	if ctx.Err() != nil && err != nil {
+		return s.interrupted(ctx, s.tmpPath)
+	}
+	return err
+}
+
+// Abort discards a stream that failed midway. The temporary file is removed
+// and the destination path is left untouched.
+func (s *SFTPOutput) Abort(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil {
+		return nil
+	}
+	defer s.resetStream()
+
+	_ = s.file.Close()
+	if err := s.session.Remove(s.tmpPath); err != nil {
+		return fmt.Errorf("sftp output: failed to remove %s: %w", s.tmpPath, err)
+	}
+	return nil
+}
+
+// CheckHealth implements health.Checker by connecting to the server and
+// verifying that the destination directory is reachable.
+func (s *SFTPOutput) CheckHealth(ctx context.Context) (health.Result, error) {
+	start := time.Now()
+	details := map[string]interface{}{
+		"host": s.address(),
+	}
+
+	session, err := s.connect(ctx)
+	if err != nil {
+		return health.Result{Status: health.StatusDown, Details: details, Error: err.Error()}, err
+	}
+	defer func() { _ = session.Close() }()
+
+	dir := path.Dir(s.RemotePath)
+	details["remote_dir"] = dir
+	if _, err := session.Stat(dir); err != nil {
+		err = fmt.Errorf("sftp output: remote directory %s not accessible: %w", dir, err)
+		return health.Result{Status: health.StatusDown, Details: details, Error: err.Error()}, err
+	}
+
+	details["latency_ms"] = time.Since(start).Milliseconds()
+	return health.Result{Status: health.StatusUp, Details: details}, nil
+}
+
+// createTemp ensures the remote directory exists and creates a temporary
+// file unique to this upload.
+func (s *SFTPOutput) createTemp(session sftpSession) (io.WriteCloser, string, error) {
+	if s.RemotePath == "" {
+		return nil, "", fmt.Errorf("sftp output: remote path not configured")
+	}
+
+	dir := path.Dir(s.RemotePath)
+	if err := session.MkdirAll(dir); err != nil {
+		return nil, "", fmt.Errorf("sftp output: failed to create directory %s: %w", dir, err)
+	}
+
+	token := make([]byte, 6)
+	if _, err := rand.Read(token); err != nil {
+		return nil, "", fmt.Errorf("sftp output: failed to name temporary file: %w", err)
+	}
+	tmpPath := s.RemotePath + "." + hex.EncodeToString(token) + s.TempSuffix
+	file, err := session.Create(tmpPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("sftp output: failed to create %s: %w", tmpPath, err)
+	}
+	return file, tmpPath, nil
+}
+
+// commit closes the temporary file and renames it to the destination path.
+func (s *SFTPOutput) commit(session sftpSession, file io.WriteCloser, tmpPath string) error {
+	if err := file.Close(); err != nil {
+		_ = session.Remove(tmpPath)
+		return fmt.Errorf("sftp output: failed to close %s: %w", tmpPath, err)
+	}
+
+	if err := session.Rename(tmpPath, s.RemotePath); err != nil {
+		_ = session.Remove(tmpPath)
+		return fmt.Errorf("sftp output: failed to rename %s to %s: %w", tmpPath, s.RemotePath, err)
+	}
+	return nil
+}
+
+// interrupted cleans up an upload that ctx interrupted. The upload's
+// session was closed, so the temporary file is removed over a new session,
+// bounded by the connect timeout.
+func (s *SFTPOutput) interrupted(ctx context.Context, tmpPath string) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = cleanupTimeout
+	}
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	if session, err := s.connect(cleanupCtx); err == nil {
+		_ = interruptible(cleanupCtx, session, func() error {
+			return session.Remove(tmpPath)
+		})
+		_ = session.Close()
+	}
+	return fmt.Errorf("sftp output: upload of %s interrupted: %w", tmpPath, ctx.Err())
+}
+
+// interruptible runs op, closing the session if ctx is done first so that
+// an operation blocked on an unresponsive server returns.
+func interruptible(ctx context.Context, session sftpSession, op func() error) error {
+	stop := context.AfterFunc(ctx, func() { _ = session.Close() })
+	defer stop()
+	return op()
+}
+
+// writeChunks writes data in pieces of at most writeChunkSize bytes,
+// checking ctx between writes.
+func writeChunks(ctx context.Context, w io.Writer, data []byte) error {
+	for len(data) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := min(len(data), writeChunkSize)
+		if _, err := w.Write(data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// resetStream closes the streaming session and clears its state.
+func (s *SFTPOutput) resetStream() {
+	_ = s.session.Close()
+	s.session = nil
+	s.file = nil
+	s.tmpPath = ""
+}
+
+// connect opens a new SFTP session using the injected dialer or SSH.
+func (s *SFTPOutput) connect(ctx context.Context) (sftpSession, error) {
+	if s.dial != nil {
+		return s.dial(ctx)
+	}
+	return s.dialSSH(ctx)
+}
+
+// dialSSH establishes an SSH connection with key-based authentication and
+// starts an SFTP subsystem on top of it.
+func (s *SFTPOutput) dialSSH(ctx context.Context) (sftpSession, error) {
+	if s.Host == "" || s.User == "" {
+		return nil, fmt.Errorf("sftp output: host and user are required")
+	}
+
+	clientConfig, err := s.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	addr := s.address()
+	dialer := &net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("sftp output: failed to connect to %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sftp output: ssh handshake with %s failed: %w", addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		_ = sshClient.Close()
+		return nil, fmt.Errorf("sftp output: failed to start sftp subsystem: %w", err)
+	}
+
+	return &sftpClientSession{client: client, conn: sshClient}, nil
+}
+
+// clientConfig builds the SSH client configuration from the key settings.
+func (s *SFTPOutput) clientConfig() (*ssh.ClientConfig, error) {
+	key, err := os.ReadFile(s.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("sftp output: failed to read private key: %w", err)
+	}
+
+	var signer ssh.Signer
+	if s.Passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(s.Passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sftp output: failed to parse private key: %w", err)
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case s.KnownHostsPath != "":
+		hostKeyCallback, err = knownhosts.New(s.KnownHostsPath)
+		if err != nil {
+			return nil, fmt.Errorf("sftp output: failed to load known hosts: %w", err)
+		}
+	case s.InsecureIgnoreHostKey:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey() // #nosec G106 -- explicitly requested via config
+	default:
+		return nil, fmt.Errorf("sftp output: host key verification not configured")
+	}
+
+	return &ssh.ClientConfig{
+		User:            s.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         s.Timeout,
+	}, nil
+}
+
+// Location returns the destination as an sftp:// URL. Credentials are
+// never included.
+func (s *SFTPOutput) Location() string {
+	return "sftp://" + s.address() + "/" + strings.TrimPrefix(s.RemotePath, "/")
+}
+
+// address returns the host:port pair of the configured server.
+func (s *SFTPOutput) address() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// sftpClientSession adapts *sftp.Client to the sftpSession interface and
+// owns the underlying SSH connection.
+type sftpClientSession struct {
+	client *sftp.Client
+	conn   io.Closer
+}
+
+func (c *sftpClientSession) MkdirAll(dir string) error { return c.client.MkdirAll(dir) }
+
+func (c *sftpClientSession) Create(name string) (io.WriteCloser, error) {
+	return c.client.Create(name)
+}
+
+// Rename prefers the atomic posix-rename extension, which overwrites an
+// existing destination, and falls back to remove-then-rename for servers
+// that do not support it.
+func (c *sftpClientSession) Rename(oldname, newname string) error {
+	if err := c.client.PosixRename(oldname, newname); err == nil {
+		return nil
+	}
+	if _, err := c.client.Stat(newname); err == nil {
+		if err := c.client.Remove(newname); err != nil {
+			return err
+		}
+	}
+	return c.client.Rename(oldname, newname)
+}
+
+func (c *sftpClientSession) Remove(name string) error { return c.client.Remove(name) }
+
+func (c *sftpClientSession) Stat(name string) (os.FileInfo, error) { return c.client.Stat(name) }
+
+func (c *sftpClientSession) Close() error {
+	err := c.client.Close()
+	if c.conn != nil {
+		if cerr := c.conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,468 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/pkg/sftp"
+)
+
+// newInMemorySFTP starts an in-memory SFTP server and returns a dialer that
+// opens new client sessions against it. All sessions share the same filesystem.
+func newInMemorySFTP(t *testing.T) (sftpDialer, func(name string) ([]byte, error)) {
+	t.Helper()
+	handlers := sftp.InMemHandler()
+
+	connect := func() (*sftp.Client, error) {
+		clientReader, serverWriter := io.Pipe()
+		serverReader, clientWriter := io.Pipe()
+
+		server := sftp.NewRequestServer(struct {
+			io.Reader
+			io.WriteCloser
+		}{serverReader, serverWriter}, handlers)
+		// Closing the server once the client hangs up unblocks client.Close
+		go func() {
+			_ = server.Serve()
+			_ = server.Close()
+		}()
+
+		return sftp.NewClientPipe(clientReader, clientWriter)
+	}
+
+	dial := func(ctx context.Context) (sftpSession, error) {
+		client, err := connect()
+		if err != nil {
+			return nil, err
+		}
+		return &sftpClientSession{client: client}, nil
+	}
+
+	read := func(name string) ([]byte, error) {
+		client, err := connect()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = client.Close() }()
+		f, err := client.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		return io.ReadAll(f)
+	}
+
+	return dial, read
+}
+
+// listDir returns the names of the files in dir.
+func listDir(t *testing.T, dial sftpDialer, dir string) []string {
+	t.Helper()
+	session, err := dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = session.Close() }()
+	infos, err := session.(*sftpClientSession).client.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names
+}
+
+func TestSFTPOutput_Send(t *testing.T) {
+	dial, read := newInMemorySFTP(t)
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/reports/report.json"
+	s.dial = dial
+
+	if err := s.Send(context.Background(), []byte(`[{"id":1}]`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	content, err := read("/drop/reports/report.json")
+	if err != nil {
+		t.Fatalf("failed to read uploaded file: %v", err)
+	}
+	if string(content) != `[{"id":1}]` {
+		t.Errorf("uploaded content = %q, want %q", content, `[{"id":1}]`)
+	}
+
+	if names := listDir(t, dial, "/drop/reports"); len(names) != 1 {
+		t.Errorf("temporary file should not remain after successful upload, got %v", names)
+	}
+}
+
+func TestSFTPOutput_SendOverwrites(t *testing.T) {
+	dial, read := newInMemorySFTP(t)
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/report.csv"
+	s.dial = dial
+
+	for _, payload := range []string{"first", "second"} {
+		if err := s.Send(context.Background(), []byte(payload)); err != nil {
+			t.Fatalf("Send(%q) error = %v", payload, err)
+		}
+	}
+
+	content, err := read("/drop/report.csv")
+	if err != nil {
+		t.Fatalf("failed to read uploaded file: %v", err)
+	}
+	if string(content) != "second" {
+		t.Errorf("uploaded content = %q, want %q", content, "second")
+	}
+}
+
+func TestSFTPOutput_ConcurrentUploads(t *testing.T) {
+	dial, read := newInMemorySFTP(t)
+	ctx := context.Background()
+
+	stream := NewSFTPOutput()
+	stream.RemotePath = "/drop/report.json"
+	stream.dial = dial
+	send := NewSFTPOutput()
+	send.RemotePath = "/drop/report.json"
+	send.dial = dial
+
+	// A Send to the same path while a stream is open must not take over
+	// the stream's temporary file
+	if err := stream.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := stream.WriteChunk(ctx, []byte("streamed")); err != nil {
+		t.Fatalf("WriteChunk() error = %v", err)
+	}
+	if err := send.Send(ctx, []byte("sent")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	content, err := read("/drop/report.json")
+	if err != nil || string(content) != "streamed" {
+		t.Errorf("uploaded content = %q, %v; want the last upload intact", content, err)
+	}
+	if names := listDir(t, dial, "/drop"); len(names) != 1 {
+		t.Errorf("temporary files should not remain, got %v", names)
+	}
+}
+
+func TestSFTPOutput_Streaming(t *testing.T) {
+	dial, read := newInMemorySFTP(t)
+	ctx := context.Background()
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/stream.json"
+	s.dial = dial
+
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	for _, chunk := range []string{"[", `{"id":1}`, ",", `{"id":2}`, "]"} {
+		if err := s.WriteChunk(ctx, []byte(chunk)); err != nil {
+			t.Fatalf("WriteChunk() error = %v", err)
+		}
+	}
+
+	if _, err := read("/drop/stream.json"); err == nil {
+		t.Error("destination should not exist before Close")
+	}
+
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	content, err := read("/drop/stream.json")
+	if err != nil {
+		t.Fatalf("failed to read uploaded file: %v", err)
+	}
+	if string(content) != `[{"id":1},{"id":2}]` {
+		t.Errorf("uploaded content = %q", content)
+	}
+}
+
+func TestSFTPOutput_Abort(t *testing.T) {
+	dial, read := newInMemorySFTP(t)
+	ctx := context.Background()
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/partial.json"
+	s.dial = dial
+
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := s.WriteChunk(ctx, []byte("[")); err != nil {
+		t.Fatalf("WriteChunk() error = %v", err)
+	}
+	if err := s.Abort(ctx); err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+
+	if _, err := read("/drop/partial.json"); err == nil {
+		t.Error("destination should not exist after Abort")
+	}
+	if names := listDir(t, dial, "/drop"); len(names) != 0 {
+		t.Errorf("temporary file should be removed after Abort, got %v", names)
+	}
+
+	// Close after Abort is a no-op
+	if err := s.Close(ctx); err != nil {
+		t.Errorf("Close() after Abort error = %v", err)
+	}
+}
+
+func TestSFTPOutput_WriteChunkNotInitialized(t *testing.T) {
+	s := NewSFTPOutput()
+	if err := s.WriteChunk(context.Background(), []byte("x")); err == nil {
+		t.Error("expected error when writing before Initialize")
+	}
+}
+
+func TestSFTPOutput_SendContextCanceled(t *testing.T) {
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/report.json"
+	s.dial = func(ctx context.Context) (sftpSession, error) {
+		t.Fatal("dial should not be called with canceled context")
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.Send(ctx, []byte("data")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send() error = %v, want context.Canceled", err)
+	}
+}
+
+// hungSession wraps a session whose files accept the first write and then
+// block, like a server that stops responding, until the session is closed.
+type hungSession struct {
+	sftpSession
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (h *hungSession) Create(name string) (io.WriteCloser, error) {
+	file, err := h.sftpSession.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &hungFile{WriteCloser: file, session: h}, nil
+}
+
+func (h *hungSession) Close() error {
+	h.once.Do(func() { close(h.closed) })
+	return h.sftpSession.Close()
+}
+
+type hungFile struct {
+	io.WriteCloser
+	session *hungSession
+	writes  int
+}
+
+func (f *hungFile) Write(p []byte) (int, error) {
+	if f.writes++; f.writes == 1 {
+		return f.WriteCloser.Write(p)
+	}
+	<-f.session.closed
+	return 0, errors.New("connection lost")
+}
+
+// hangingDialer returns a dialer whose first session hangs on writes and
+// whose later sessions work normally.
+func hangingDialer(dial sftpDialer) sftpDialer {
+	var dials atomic.Int32
+	return func(ctx context.Context) (sftpSession, error) {
+		session, err := dial(ctx)
+		if err != nil || dials.Add(1) > 1 {
+			return session, err
+		}
+		return &hungSession{sftpSession: session, closed: make(chan struct{})}, nil
+	}
+}
+
+func TestSFTPOutput_SendInterruptedByTimeout(t *testing.T) {
+	dial, _ := newInMemorySFTP(t)
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/report.json"
+	s.dial = hangingDialer(dial)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	data := make([]byte, 2*writeChunkSize)
+	if err := s.Send(ctx, data); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send() error = %v, want context.DeadlineExceeded", err)
+	}
+	if names := listDir(t, dial, "/drop"); len(names) != 0 {
+		t.Errorf("interrupted upload left files behind: %v", names)
+	}
+}
+
+func TestSFTPOutput_WriteChunkInterruptedByTimeout(t *testing.T) {
+	dial, _ := newInMemorySFTP(t)
+
+	s := NewSFTPOutput()
+	s.RemotePath = "/drop/report.json"
+	s.dial = hangingDialer(dial)
+
+	if err := s.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := s.WriteChunk(ctx, []byte("first")); err != nil {
+		t.Fatalf("WriteChunk() error = %v", err)
+	}
+	if err := s.WriteChunk(ctx, []byte("second")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WriteChunk() error = %v, want context.DeadlineExceeded", err)
+	}
+	if err := s.Abort(context.Background()); err != nil {
+		t.Errorf("Abort() after interrupted write error = %v", err)
+	}
+	if names := listDir(t, dial, "/drop"); len(names) != 0 {
+		t.Errorf("interrupted stream left files behind: %v", names)
+	}
+}
+
+func TestSFTPOutput_CheckHealth(t *testing.T) {
+	dial, _ := newInMemorySFTP(t)
+
+	s := NewSFTPOutput()
+	s.Host = "sftp.example.com"
+	s.RemotePath = "/report.json"
+	s.dial = dial
+
+	res, err := s.CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if res.Status != health.StatusUp {
+		t.Errorf("Status = %v, want %v", res.Status, health.StatusUp)
+	}
+	if res.Details["host"] != "sftp.example.com:22" {
+		t.Errorf("host detail = %v", res.Details["host"])
+	}
+}
+
+func TestSFTPOutput_CheckHealthDown(t *testing.T) {
+	s := NewSFTPOutput()
+	s.RemotePath = "/report.json"
+	s.dial = func(ctx context.Context) (sftpSession, error) {
+		return nil, os.ErrDeadlineExceeded
+	}
+
+	res, err := s.CheckHealth(context.Background())
+	if err == nil {
+		t.Fatal("expected error from CheckHealth")
+	}
+	if res.Status != health.StatusDown {
+		t.Errorf("Status = %v, want %v", res.Status, health.StatusDown)
+	}
+	if res.Error == "" {
+		t.Error("expected error message in result")
+	}
+}
+
+func TestSFTPOutput_Configure(t *testing.T) {
+	valid := func() map[string]string {
+		return map[string]string{
+			"host":             "sftp.example.com",
+			"user":             "reports",
+			"private_key_path": "/keys/id_ed25519",
+			"known_hosts_path": "/keys/known_hosts",
+			"remote_path":      "/drop/report.csv",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p map[string]string)
+		wantErr bool
+	}{
+		{name: "valid config", modify: func(p map[string]string) {}},
+		{name: "missing host", modify: func(p map[string]string) { delete(p, "host") }, wantErr: true},
+		{name: "missing user", modify: func(p map[string]string) { delete(p, "user") }, wantErr: true},
+		{name: "missing key", modify: func(p map[string]string) { delete(p, "private_key_path") }, wantErr: true},
+		{name: "missing remote path", modify: func(p map[string]string) { delete(p, "remote_path") }, wantErr: true},
+		{name: "invalid port", modify: func(p map[string]string) { p["port"] = "abc" }, wantErr: true},
+		{name: "port out of range", modify: func(p map[string]string) { p["port"] = "70000" }, wantErr: true},
+		{name: "invalid timeout", modify: func(p map[string]string) { p["timeout"] = "soon" }, wantErr: true},
+		{name: "empty temp suffix", modify: func(p map[string]string) { p["temp_suffix"] = "" }, wantErr: true},
+		{
+			name:    "no host key verification",
+			modify:  func(p map[string]string) { delete(p, "known_hosts_path") },
+			wantErr: true,
+		},
+		{
+			name: "insecure host key",
+			modify: func(p map[string]string) {
+				delete(p, "known_hosts_path")
+				p["insecure_ignore_host_key"] = "true"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := valid()
+			tt.modify(params)
+			s := NewSFTPOutput()
+			if err := s.Configure(params); (err != nil) != tt.wantErr {
+				t.Errorf("SFTPOutput.Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSFTPOutput_ConfigureOptional(t *testing.T) {
+	s := NewSFTPOutput()
+	err := s.Configure(map[string]string{
+		"host":                     "sftp.example.com",
+		"port":                     "2222",
+		"user":                     "reports",
+		"private_key_path":         "/keys/id_rsa",
+		"insecure_ignore_host_key": "true",
+		"remote_path":              "/drop/report.csv",
+		"temp_suffix":              ".part",
+		"timeout":                  "5s",
+	})
+	if err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if s.Port != 2222 || s.TempSuffix != ".part" || s.Timeout.String() != "5s" {
+		t.Errorf("unexpected config: port=%d suffix=%s timeout=%v", s.Port, s.TempSuffix, s.Timeout)
+	}
+	if s.address() != "sftp.example.com:2222" {
+		t.Errorf("address() = %s", s.address())
+	}
+}
+
+func TestSFTPOutput_Location(t *testing.T) {
+	out := NewSFTPOutput()
+	out.Host = "files.example.com"
+	out.RemotePath = "/reports/daily.csv"
+	if got := out.Location(); got != "sftp://files.example.com:22/reports/daily.csv" {
+		t.Errorf("Location() = %q", got)
+	}
+}
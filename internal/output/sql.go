@@ -0,0 +1,417 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// SQL write modes supported by SQLOutput.
+const (
+	SQLModeInsert = "insert"
+	SQLModeUpsert = "upsert"
+)
+
+// SQL dialects supported by SQLOutput. The dialect controls placeholder
+// syntax and the UPSERT clause.
+const (
+	SQLDialectGeneric  = "generic"
+	SQLDialectPostgres = "postgres"
+	SQLDialectMySQL    = "mysql"
+	SQLDialectSQLite   = "sqlite"
+)
+
+// Bind parameters a single statement may use in each dialect. SQLite
+// builds before 3.32 allow 999; the generic dialect assumes the same
+// conservative limit.
+var maxPlaceholders = map[string]int{
+	SQLDialectGeneric:  999,
+	SQLDialectPostgres: 65535,
+	SQLDialectMySQL:    65535,
+	SQLDialectSQLite:   999,
+}
+
+// identifierPattern restricts table and column names to plain (optionally
+// schema-qualified) identifiers, since they cannot be bound as parameters.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLOutput implements RecordOutputStrategy for writing report rows into a
+// database table. It uses the standard database/sql interface, so it supports
+// any driver (postgres, mysql, sqlite, etc.).
+// Note: The driver MUST be imported in the main application (e.g. _ "github.com/lib/pq").
+//
+// Each run is written in a single transaction: records are inserted in
+// batches of BatchSize rows and committed together, or rolled back entirely
+// if any batch fails. Batches of wide tables are made smaller so a
+// statement never binds more parameters than the dialect allows.
+type SQLOutput struct {
+	Driver       string
+	DSN          string
+	Table        string
+	Columns      []string
+	Mode         string
+	ConflictKeys []string
+	BatchSize    int
+	Dialect      string
+
+	// db, if set allows reusing an existing connection pool,
+	// otherwise one is created per SendRecords (and closed).
+	db *sql.DB
+}
+
+// NewSQLOutput creates a new instance of SQLOutput with defaults.
+func NewSQLOutput() *SQLOutput {
+	return &SQLOutput{
+		Mode:      SQLModeInsert,
+		BatchSize: 500,
+	}
+}
+
+// Location returns the destination table.
+func (s *SQLOutput) Location() string {
+	return s.Table
+}
+
+// ParamSpecs declares the parameters accepted by Configure.
+func (s *SQLOutput) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "driver", Type: api.ParamString, Required: true, Description: "Database driver name, e.g. postgres"},
+		{Name: "dsn", Type: api.ParamString, Required: true, Description: "Data source name connection string"},
+		{Name: "table", Type: api.ParamString, Required: true, Description: "Destination table, optionally schema-qualified"},
+		{Name: "columns", Type: api.ParamString, Description: "Comma-separated column list (default: union of record keys)"},
+		{Name: "mode", Type: api.ParamString, Default: "insert", Description: "Write mode", Enum: []string{"insert", "upsert"}},
+		{Name: "conflict_keys", Type: api.ParamString, Description: "Comma-separated key columns, required for upsert"},
+		{Name: "batch_size", Type: api.ParamInt, Default: "500", Description: "Rows per INSERT statement, lowered for wide tables to fit the dialect's parameter limit"},
+		{Name: "dialect", Type: api.ParamString, Description: "SQL dialect (default: derived from driver)", Enum: []string{"postgres", "mysql", "sqlite", "generic"}},
+	}
+}
+
+// Configure sets up the output from a map of parameters.
+// Params:
+// - driver: Database driver name (e.g., "postgres", "mysql"). Required.
+// - dsn: Data Source Name connection string. Required.
+// - table: Destination table, optionally schema-qualified. Required.
+// - columns: Comma-separated column list (default: union of record keys)
+// - mode: "insert" or "upsert" (default: "insert")
+// - conflict_keys: Comma-separated key columns. Required for upsert.
+// - batch_size: Rows per INSERT statement (default: "500", lowered for wide tables)
+// - dialect: "postgres", "mysql", "sqlite" or "generic" (default: derived from driver)
+func (s *SQLOutput) Configure(params map[string]string) error {
+	if driver, ok := params["driver"]; ok {
+		s.Driver = driver
+	} else {
+		return fmt.Errorf("sql output: missing required parameter 'driver'")
+	}
+
+	if dsn, ok := params["dsn"]; ok {
+		s.DSN = dsn
+	} else {
+		return fmt.Errorf("sql output: missing required parameter 'dsn'")
+	}
+
+	if table, ok := params["table"]; ok {
+		s.Table = table
+	} else {
+		return fmt.Errorf("sql output: missing required parameter 'table'")
+	}
+
+	if columns, ok := params["columns"]; ok && columns != "" {
+		s.Columns = splitList(columns)
+	}
+
+	if mode, ok := params["mode"]; ok {
+		s.Mode = strings.ToLower(mode)
+	}
+
+	if keys, ok := params["conflict_keys"]; ok && keys != "" {
+		s.ConflictKeys = splitList(keys)
+	}
+
+	if batchStr, ok := params["batch_size"]; ok {
+		size, err := strconv.Atoi(batchStr)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("sql output: invalid batch_size %s", batchStr)
+		}
+		s.BatchSize = size
+	}
+
+	if dialect, ok := params["dialect"]; ok {
+		s.Dialect = strings.ToLower(dialect)
+	}
+
+	return s.validate()
+}
+
+// Send decodes JSON-formatted records and writes them with SendRecords.
+// This allows SQLOutput to be used behind decorators or with callers that
+// only deal in formatted bytes; the formatter must produce a JSON array
+// of objects.
+func (s *SQLOutput) Send(ctx context.Context, data []byte) error {
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("sql output: data must be a JSON array of objects: %w", err)
+	}
+	return s.SendRecords(ctx, records)
+}
+
+// SendRecords writes the records to the configured table in one transaction.
+func (s *SQLOutput) SendRecords(ctx context.Context, records []map[string]interface{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := s.Columns
+	if len(columns) == 0 {
+		columns = recordColumns(records)
+	}
+	for _, col := range columns {
+		if !identifierPattern.MatchString(col) {
+			return fmt.Errorf("sql output: invalid column name %q", col)
+		}
+	}
+
+	db := s.db
+	if db == nil {
+		if s.Driver == "" || s.DSN == "" {
+			return fmt.Errorf("sql output: driver and dsn are required if external db not provided")
+		}
+		var err error
+		db, err = sql.Open(s.Driver, s.DSN)
+		if err != nil {
+			return fmt.Errorf("sql output: failed to open connection: %w", err)
+		}
+		defer func() { _ = db.Close() }()
+	}
+
+	batchSize, err := s.rowsPerStatement(len(columns))
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sql output: failed to begin transaction: %w", err)
+	}
+
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		query, args := s.buildStatement(columns, records[start:end])
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("sql output: batch starting at row %d failed: %w", start, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sql output: commit failed: %w", err)
+	}
+	return nil
+}
+
+// rowsPerStatement returns the rows to insert per statement: BatchSize,
+// capped so the statement stays within the dialect's bind parameter limit
+// for the given number of columns.
+func (s *SQLOutput) rowsPerStatement(columns int) (int, error) {
+	limit := maxPlaceholders[s.dialect()]
+	if columns > limit {
+		return 0, fmt.Errorf("sql output: %d columns exceed the %s limit of %d parameters per statement", columns, s.dialect(), limit)
+	}
+
+	rows := s.BatchSize
+	if rows <= 0 {
+		rows = 500
+	}
+	return min(rows, limit/columns), nil
+}
+
+// validate checks that the table, mode and dialect settings are usable.
+func (s *SQLOutput) validate() error {
+	if s.Table == "" {
+		return fmt.Errorf("sql output: table not configured")
+	}
+	if !identifierPattern.MatchString(s.Table) {
+		return fmt.Errorf("sql output: invalid table name %q", s.Table)
+	}
+
+	switch s.dialect() {
+	case SQLDialectGeneric, SQLDialectPostgres, SQLDialectMySQL, SQLDialectSQLite:
+	default:
+		return fmt.Errorf("sql output: unsupported dialect %q", s.Dialect)
+	}
+
+	switch s.Mode {
+	case "", SQLModeInsert:
+	case SQLModeUpsert:
+		if s.dialect() == SQLDialectGeneric {
+			return fmt.Errorf("sql output: upsert requires a postgres, mysql or sqlite dialect")
+		}
+		if s.dialect() != SQLDialectMySQL && len(s.ConflictKeys) == 0 {
+			return fmt.Errorf("sql output: upsert requires 'conflict_keys'")
+		}
+		for _, key := range s.ConflictKeys {
+			if !identifierPattern.MatchString(key) {
+				return fmt.Errorf("sql output: invalid conflict key %q", key)
+			}
+		}
+	default:
+		return fmt.Errorf("sql output: unsupported mode %q (use 'insert' or 'upsert')", s.Mode)
+	}
+
+	return nil
+}
+
+// dialect returns the configured dialect or derives it from the driver name.
+func (s *SQLOutput) dialect() string {
+	if s.Dialect != "" {
+		return s.Dialect
+	}
+	switch strings.ToLower(s.Driver) {
+	case "postgres", "pgx", "postgresql":
+		return SQLDialectPostgres
+	case "mysql":
+		return SQLDialectMySQL
+	case "sqlite", "sqlite3":
+		return SQLDialectSQLite
+	default:
+		return SQLDialectGeneric
+	}
+}
+
+// buildStatement renders a multi-row INSERT (or UPSERT) for a batch and
+// returns it with the flattened argument list.
+func (s *SQLOutput) buildStatement(columns []string, batch []map[string]interface{}) (string, []interface{}) {
+	var sb strings.Builder
+	args := make([]interface{}, 0, len(columns)*len(batch))
+
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(s.Table)
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(columns, ", "))
+	sb.WriteString(") VALUES ")
+
+	for i, record := range batch {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, col := range columns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, sqlValue(record[col]))
+			sb.WriteString(s.placeholder(len(args)))
+		}
+		sb.WriteString(")")
+	}
+
+	if s.Mode == SQLModeUpsert {
+		sb.WriteString(s.upsertClause(columns))
+	}
+
+	return sb.String(), args
+}
+
+// placeholder returns the bind parameter for the n-th (1-based) argument.
+func (s *SQLOutput) placeholder(n int) string {
+	if s.dialect() == SQLDialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// upsertClause renders the dialect-specific conflict handling clause.
+func (s *SQLOutput) upsertClause(columns []string) string {
+	keys := make(map[string]bool, len(s.ConflictKeys))
+	for _, k := range s.ConflictKeys {
+		keys[k] = true
+	}
+
+	var updates []string
+	for _, col := range columns {
+		if keys[col] {
+			continue
+		}
+		switch s.dialect() {
+		case SQLDialectMySQL:
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", col, col))
+		default:
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", col, col))
+		}
+	}
+
+	if s.dialect() == SQLDialectMySQL {
+		if len(updates) == 0 {
+			return ""
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+
+	conflict := " ON CONFLICT (" + strings.Join(s.ConflictKeys, ", ") + ")"
+	if len(updates) == 0 {
+		return conflict + " DO NOTHING"
+	}
+	return conflict + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// recordColumns returns the sorted union of keys across all records.
+func recordColumns(records []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, record := range records {
+		for k := range record {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// sqlValue converts nested structures, which drivers cannot bind, to JSON text.
+func sqlValue(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, []map[string]interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	default:
+		return v
+	}
+}
+
+// splitList splits a comma-separated list and trims whitespace.
+func splitList(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
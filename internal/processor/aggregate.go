@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // AggregateProcessor groups data and calculates aggregates.
@@ -27,6 +29,14 @@ func NewAggregateProcessor(groupBy []string, aggregates map[string]string) *Aggr
 	}
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (p *AggregateProcessor) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "group_by", Type: api.ParamString, Description: "Comma-separated fields to group by"},
+		{Name: "agg_*", Type: api.ParamString, Description: "Aggregate spec for an output field, e.g. agg_total: sum:amount"},
+	}
+}
+
 // Configure sets up the processor from parameters.
 // Params:
 // - group_by: Comma-separated list of fields to group by.
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // DeduplicateProcessor filters out duplicate records.
@@ -24,6 +26,13 @@ func NewDeduplicateProcessor(fields []string) *DeduplicateProcessor {
 	}
 }
 
+// ParamSpecs declares the parameters accepted by Configure.
+func (p *DeduplicateProcessor) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "fields", Type: api.ParamString, Description: "Comma-separated fields checked for uniqueness (default: all fields)"},
+	}
+}
+
 // Configure sets up the processor from parameters.
 // Params:
 // - fields: Comma-separated list of fields to check for uniqueness.
@@ -251,6 +251,21 @@ func (p *ParallelProcessor) Configure(params map[string]string) error {
 	return nil
 }
 
+// ParamSpecs declares the parallel settings together with the wrapped
+// processor's parameters, since Configure passes params through to it.
+// It returns nil if the wrapped processor does not declare its parameters.
+func (p *ParallelProcessor) ParamSpecs() []api.ParamSpec {
+	describer, ok := p.processor.(api.ParamDescriber)
+	if !ok || describer.ParamSpecs() == nil {
+		return nil
+	}
+	return append([]api.ParamSpec{
+		{Name: "workers", Type: api.ParamInt, Description: "Number of concurrent workers"},
+		{Name: "chunk_size", Type: api.ParamInt, Description: "Records per chunk (0 for auto)"},
+		{Name: "min_chunk_size", Type: api.ParamInt, Description: "Minimum records per chunk"},
+	}, describer.ParamSpecs()...)
+}
+
 // Process executes the wrapped processor in parallel on data chunks.
 // Data is split into chunks, processed concurrently, and reassembled in order.
 //
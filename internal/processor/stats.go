@@ -0,0 +1,266 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// StepStats describes what one processor in a chain did during a run.
+type StepStats struct {
+	// Position is the processor's zero-based index in the chain.
+	Position int `json:"position"`
+
+	// Type is the processor's registered type name.
+	Type string `json:"type"`
+
+	// RecordsIn is the number of records the processor received.
+	RecordsIn int `json:"records_in"`
+
+	// RecordsOut is the number of records the processor passed on.
+	RecordsOut int `json:"records_out"`
+
+	// Duration is the time spent in this processor, excluding the
+	// processors after it in the chain.
+	Duration time.Duration `json:"duration"`
+
+	// Calls is the number of Process calls (one per chunk when streaming).
+	Calls int `json:"calls"`
+}
+
+// ChainStats collects per-processor statistics for a run. Attach it to the
+// run context with WithChainStats; every Link in the chain records into it.
+//
+// Thread-safe: Yes.
+type ChainStats struct {
+	mu    sync.Mutex
+	steps map[int]*linkStats
+}
+
+// linkStats is the raw measurement of one link. Durations and output
+// counts include the rest of the chain and are corrected in Steps.
+type linkStats struct {
+	typ       string
+	in        int
+	returned  int
+	inclusive time.Duration
+	calls     int
+}
+
+// NewChainStats creates an empty collector.
+func NewChainStats() *ChainStats {
+	return &ChainStats{steps: make(map[int]*linkStats)}
+}
+
+// record adds one Process call of the link at position.
+func (s *ChainStats) record(position int, typ string, in, returned int, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.steps[position]
+	if !ok {
+		st = &linkStats{typ: typ}
+		s.steps[position] = st
+	}
+	st.in += in
+	st.returned += returned
+	st.inclusive += d
+	st.calls++
+}
+
+// Steps returns the per-processor statistics ordered by position.
+//
+// A link's measured duration and output include every processor after it,
+// because each processor hands its result to the next. A processor's own
+// output is therefore the next processor's input, and its own duration is
+// its measured time minus the next processor's. The last processor's
+// output is what the chain returned.
+func (s *ChainStats) Steps() []StepStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions := make([]int, 0, len(s.steps))
+	for pos := range s.steps {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+
+	steps := make([]StepStats, len(positions))
+	for i, pos := range positions {
+		st := s.steps[pos]
+		steps[i] = StepStats{
+			Position:   pos,
+			Type:       st.typ,
+			RecordsIn:  st.in,
+			RecordsOut: st.returned,
+			Duration:   st.inclusive,
+			Calls:      st.calls,
+		}
+		if next, ok := s.steps[pos+1]; ok {
+			steps[i].RecordsOut = next.in
+			steps[i].Duration = max(st.inclusive-next.inclusive, 0)
+		}
+	}
+	return steps
+}
+
+type chainStatsKey struct{}
+
+// WithChainStats returns a context that carries stats for Links to record into.
+func WithChainStats(ctx context.Context, stats *ChainStats) context.Context {
+	return context.WithValue(ctx, chainStatsKey{}, stats)
+}
+
+// ChainStatsFrom returns the stats attached to ctx, or nil.
+func ChainStatsFrom(ctx context.Context) *ChainStats {
+	stats, _ := ctx.Value(chainStatsKey{}).(*ChainStats)
+	return stats
+}
+
+// LinkObserver instruments the processors of a chain, e.g. with spans
+// and metrics per processor. StartLink is called before a Link's
+// processor runs and may return a derived context for it; the returned
+// function is called once the processor returns, with figures for that
+// processor alone: its records out are the next processor's records in,
+// and its duration excludes the processors after it.
+type LinkObserver interface {
+	StartLink(ctx context.Context, position int, typ string) (context.Context, func(step StepStats, err error))
+}
+
+// Link wraps one processor of a chain and records its activity into the
+// ChainStats carried by the context and, if set, a LinkObserver. Without
+// either, Link only delegates.
+//
+// Thread-safe: Yes, if the wrapped processor is thread-safe.
+type Link struct {
+	delegate ProcessorHandler
+	position int
+	typ      string
+	observer LinkObserver
+}
+
+// NewLink wraps a processor at the given chain position.
+func NewLink(delegate ProcessorHandler, position int, typ string) *Link {
+	return &Link{delegate: delegate, position: position, typ: typ}
+}
+
+// WithObserver sets an observer for every Process call of the link.
+func (l *Link) WithObserver(observer LinkObserver) *Link {
+	l.observer = observer
+	return l
+}
+
+// Unwrap returns the wrapped processor.
+func (l *Link) Unwrap() ProcessorHandler {
+	return l.delegate
+}
+
+// Position returns the link's zero-based chain position.
+func (l *Link) Position() int {
+	return l.position
+}
+
+// Type returns the wrapped processor's type name.
+func (l *Link) Type() string {
+	return l.typ
+}
+
+// SetNext delegates to the wrapped processor.
+func (l *Link) SetNext(next ProcessorHandler) {
+	l.delegate.SetNext(next)
+}
+
+// Process delegates to the wrapped processor and records statistics.
+func (l *Link) Process(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	// An observed link before this one needs its figures, so the
+	// observer sees that processor's own records out and duration
+	parent, _ := ctx.Value(linkFrameKey{}).(*linkFrame)
+	stats := ChainStatsFrom(ctx)
+	if stats == nil && l.observer == nil && parent == nil {
+		return l.delegate.Process(ctx, data)
+	}
+
+	var finish func(StepStats, error)
+	if l.observer != nil {
+		ctx, finish = l.observer.StartLink(ctx, l.position, l.typ)
+	}
+	// The next link reports into frame
+	frame := &linkFrame{}
+	ctx = context.WithValue(ctx, linkFrameKey{}, frame)
+
+	start := time.Now()
+	result, err := l.delegate.Process(ctx, data)
+	d := time.Since(start)
+
+	if stats != nil {
+		stats.record(l.position, l.typ, len(data), len(result), d)
+	}
+	if parent != nil {
+		parent.add(len(data), d)
+	}
+	if finish != nil {
+		finish(frame.step(l.position, l.typ, len(data), len(result), d), err)
+	}
+	return result, err
+}
+
+type linkFrameKey struct{}
+
+// linkFrame collects what the next link did during one Process call of
+// an observed link.
+type linkFrame struct {
+	mu        sync.Mutex
+	calls     int
+	in        int
+	inclusive time.Duration
+}
+
+// add records one Process call of the next link.
+func (f *linkFrame) add(in int, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.in += in
+	f.inclusive += d
+}
+
+// step returns the statistics of one Process call of the observed link,
+// taking out what the next link did.
+func (f *linkFrame) step(position int, typ string, in, returned int, d time.Duration) StepStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	step := StepStats{
+		Position:   position,
+		Type:       typ,
+		RecordsIn:  in,
+		RecordsOut: returned,
+		Duration:   d,
+		Calls:      1,
+	}
+	if f.calls > 0 {
+		step.RecordsOut = f.in
+		step.Duration = max(d-f.inclusive, 0)
+	}
+	return step
+}
+
+// CloseWithContext forwards cleanup to the wrapped processor.
+func (l *Link) CloseWithContext(ctx context.Context) error {
+	if c, ok := l.delegate.(api.CloseableWithContext); ok {
+		return c.CloseWithContext(ctx)
+	}
+	if c, ok := l.delegate.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Close forwards cleanup to the wrapped processor.
+func (l *Link) Close() error {
+	return l.CloseWithContext(context.Background())
+}
@@ -0,0 +1,250 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type keepEven struct{}
+
+func (keepEven) Keep(row map[string]interface{}) bool {
+	return row["n"].(int)%2 == 0
+}
+
+// closingProcessor records Close calls.
+type closingProcessor struct {
+	BaseProcessor
+	closed bool
+}
+
+func (c *closingProcessor) Close() error {
+	c.closed = true
+	return nil
+}
+
+func numbered(n int) []map[string]interface{} {
+	data := make([]map[string]interface{}, n)
+	for i := range data {
+		data[i] = map[string]interface{}{"n": i}
+	}
+	return data
+}
+
+func TestLinkRecordsChainStats(t *testing.T) {
+	// pass-through -> keep even -> pass-through
+	first := NewLink(&BaseProcessor{}, 0, "base")
+	second := NewLink(NewFilterWrapper(keepEven{}), 1, "even")
+	third := NewLink(&BaseProcessor{}, 2, "tail")
+	first.SetNext(second)
+	second.SetNext(third)
+
+	stats := NewChainStats()
+	ctx := WithChainStats(context.Background(), stats)
+
+	// Two calls, as in streaming mode
+	for i := 0; i < 2; i++ {
+		if _, err := first.Process(ctx, numbered(10)); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	steps := stats.Steps()
+	if len(steps) != 3 {
+		t.Fatalf("len(Steps()) = %d, want 3", len(steps))
+	}
+
+	want := []struct {
+		typ     string
+		in, out int
+	}{
+		{"base", 20, 20},
+		{"even", 20, 10},
+		{"tail", 10, 10},
+	}
+	for i, w := range want {
+		s := steps[i]
+		if s.Position != i || s.Type != w.typ || s.RecordsIn != w.in || s.RecordsOut != w.out || s.Calls != 2 {
+			t.Errorf("step %d = %+v, want %s %d in / %d out over 2 calls", i, s, w.typ, w.in, w.out)
+		}
+		if s.Duration < 0 {
+			t.Errorf("step %d duration = %v, must not be negative", i, s.Duration)
+		}
+	}
+}
+
+func TestLinkWithoutStats(t *testing.T) {
+	link := NewLink(NewFilterWrapper(keepEven{}), 0, "even")
+	result, err := link.Process(context.Background(), numbered(4))
+	if err != nil || len(result) != 2 {
+		t.Errorf("Process() = %d records, %v; want 2", len(result), err)
+	}
+}
+
+func TestLinkRecordsOnError(t *testing.T) {
+	failing := &mockErrorProcessor{shouldError: true, errorMsg: "boom"}
+	link := NewLink(failing, 0, "failing")
+
+	stats := NewChainStats()
+	if _, err := link.Process(WithChainStats(context.Background(), stats), numbered(3)); err == nil {
+		t.Fatal("Process() should return the delegate error")
+	}
+	if steps := stats.Steps(); len(steps) != 1 || steps[0].RecordsIn != 3 || steps[0].RecordsOut != 0 {
+		t.Errorf("Steps() = %+v", steps)
+	}
+}
+
+func TestLinkConcurrentRecording(t *testing.T) {
+	link := NewLink(&BaseProcessor{}, 0, "base")
+	stats := NewChainStats()
+	ctx := WithChainStats(context.Background(), stats)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = link.Process(ctx, numbered(5))
+		}()
+	}
+	wg.Wait()
+
+	if steps := stats.Steps(); steps[0].RecordsIn != 100 || steps[0].Calls != 20 {
+		t.Errorf("Steps() = %+v", steps)
+	}
+}
+
+func TestLinkAccessorsAndClose(t *testing.T) {
+	inner := &closingProcessor{}
+	link := NewLink(inner, 3, "closing")
+
+	if link.Unwrap() != inner || link.Position() != 3 || link.Type() != "closing" {
+		t.Errorf("accessors = %v %d %q", link.Unwrap(), link.Position(), link.Type())
+	}
+	if err := link.Close(); err != nil || !inner.closed {
+		t.Errorf("Close() = %v, closed = %v", err, inner.closed)
+	}
+	if err := NewLink(&BaseProcessor{}, 0, "base").CloseWithContext(context.Background()); err != nil {
+		t.Errorf("CloseWithContext() on non-closer = %v", err)
+	}
+}
+
+func TestChainStatsFromEmptyContext(t *testing.T) {
+	if ChainStatsFrom(context.Background()) != nil {
+		t.Error("ChainStatsFrom() should be nil without stats")
+	}
+}
+
+// recordingObserver collects the steps reported by observed links.
+type recordingObserver struct {
+	mu    sync.Mutex
+	steps []StepStats
+	errs  []error
+}
+
+func (o *recordingObserver) StartLink(ctx context.Context, position int, typ string) (context.Context, func(StepStats, error)) {
+	return ctx, func(step StepStats, err error) {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.steps = append(o.steps, step)
+		o.errs = append(o.errs, err)
+	}
+}
+
+func TestLinkObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	first := NewLink(&BaseProcessor{}, 0, "base").WithObserver(observer)
+	second := NewLink(NewFilterWrapper(keepEven{}), 1, "even").WithObserver(observer)
+	third := NewLink(&BaseProcessor{}, 2, "tail").WithObserver(observer)
+	first.SetNext(second)
+	second.SetNext(third)
+
+	// No ChainStats in the context: the observer alone enables recording
+	if _, err := first.Process(context.Background(), numbered(10)); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	// Links finish innermost first
+	want := []struct {
+		position int
+		typ      string
+		in, out  int
+	}{
+		{2, "tail", 5, 5},
+		{1, "even", 10, 5},
+		{0, "base", 10, 10},
+	}
+	if len(observer.steps) != len(want) {
+		t.Fatalf("observed %d steps, want %d", len(observer.steps), len(want))
+	}
+	for i, w := range want {
+		s := observer.steps[i]
+		if s.Position != w.position || s.Type != w.typ || s.RecordsIn != w.in || s.RecordsOut != w.out || s.Calls != 1 {
+			t.Errorf("step %d = %+v, want %s at %d with %d in / %d out", i, s, w.typ, w.position, w.in, w.out)
+		}
+		if s.Duration < 0 {
+			t.Errorf("step %d duration = %v, must not be negative", i, s.Duration)
+		}
+	}
+}
+
+func TestLinkObserverUnobservedMiddle(t *testing.T) {
+	observer := &recordingObserver{}
+	first := NewLink(&BaseProcessor{}, 0, "base").WithObserver(observer)
+	second := NewLink(NewFilterWrapper(keepEven{}), 1, "even")
+	third := NewLink(NewFilterWrapper(keepEven{}), 2, "even-again").WithObserver(observer)
+	first.SetNext(second)
+	second.SetNext(third)
+
+	if _, err := first.Process(context.Background(), numbered(8)); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	// The head's output is what the unobserved second link received,
+	// not what reached the third
+	head := observer.steps[len(observer.steps)-1]
+	if head.Position != 0 || head.RecordsOut != 8 {
+		t.Errorf("head step = %+v, want 8 records out", head)
+	}
+}
+
+func TestLinkObserverError(t *testing.T) {
+	observer := &recordingObserver{}
+	link := NewLink(&mockErrorProcessor{shouldError: true, errorMsg: "boom"}, 0, "failing").WithObserver(observer)
+
+	if _, err := link.Process(context.Background(), numbered(3)); err == nil {
+		t.Fatal("Process() should return the delegate error")
+	}
+	if len(observer.errs) != 1 || observer.errs[0] == nil || observer.steps[0].RecordsIn != 3 {
+		t.Errorf("observed steps = %+v, errors = %v", observer.steps, observer.errs)
+	}
+}
+
+// sleepingProcessor takes d before passing records on.
+type sleepingProcessor struct {
+	BaseProcessor
+	d time.Duration
+}
+
+func (p *sleepingProcessor) Process(ctx context.Context, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	time.Sleep(p.d)
+	return p.BaseProcessor.Process(ctx, data)
+}
+
+func TestLinkObserverOwnDuration(t *testing.T) {
+	observer := &recordingObserver{}
+	head := NewLink(&BaseProcessor{}, 0, "base").WithObserver(observer)
+	slow := NewLink(&sleepingProcessor{d: 20 * time.Millisecond}, 1, "slow").WithObserver(observer)
+	head.SetNext(slow)
+
+	if _, err := head.Process(context.Background(), numbered(2)); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	slowStep, headStep := observer.steps[0], observer.steps[1]
+	if slowStep.Duration < 20*time.Millisecond {
+		t.Errorf("slow step duration = %v, want at least 20ms", slowStep.Duration)
+	}
+	if headStep.Duration >= 20*time.Millisecond {
+		t.Errorf("head step duration = %v includes the slow processor after it", headStep.Duration)
+	}
+}
@@ -37,6 +37,15 @@ func (f *FilterWrapper) Configure(params map[string]string) error {
 	return nil
 }
 
+// ParamSpecs returns the strategy's parameter declarations, or nil if the
+// strategy does not declare any.
+func (f *FilterWrapper) ParamSpecs() []api.ParamSpec {
+	if describer, ok := f.strategy.(api.ParamDescriber); ok {
+		return describer.ParamSpecs()
+	}
+	return nil
+}
+
 // Process filters data through the strategy and passes result to next processor.
 // Records are filtered based on the FilterStrategy.Keep() method.
 //
@@ -113,6 +122,15 @@ func (v *ValidatorWrapper) Configure(params map[string]string) error {
 	return nil
 }
 
+// ParamSpecs returns the strategy's parameter declarations, or nil if the
+// strategy does not declare any.
+func (v *ValidatorWrapper) ParamSpecs() []api.ParamSpec {
+	if describer, ok := v.strategy.(api.ParamDescriber); ok {
+		return describer.ParamSpecs()
+	}
+	return nil
+}
+
 // Process validates each record and fails fast on first error.
 // All records must pass validation for processing to continue.
 //
@@ -192,6 +210,15 @@ func (t *TransformWrapper) Configure(params map[string]string) error {
 	return nil
 }
 
+// ParamSpecs returns the strategy's parameter declarations, or nil if the
+// strategy does not declare any.
+func (t *TransformWrapper) ParamSpecs() []api.ParamSpec {
+	if describer, ok := t.strategy.(api.ParamDescriber); ok {
+		return describer.ParamSpecs()
+	}
+	return nil
+}
+
 // Process transforms each record using the strategy and passes result to next processor.
 //
 // Context handling:
@@ -477,6 +477,41 @@ func TestTransformWrapperConfigure(t *testing.T) {
 
 // --- Context Tests ---
 
+// describedFilter declares its parameters
+type describedFilter struct {
+	mockFilter
+}
+
+func (d *describedFilter) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{{Name: "threshold", Type: api.ParamInt, Required: true}}
+}
+
+// TestWrapperParamSpecs tests that wrappers expose the strategy's params
+func TestWrapperParamSpecs(t *testing.T) {
+	if specs := NewFilterWrapper(&describedFilter{}).ParamSpecs(); len(specs) != 1 || specs[0].Name != "threshold" {
+		t.Errorf("FilterWrapper.ParamSpecs() = %v", specs)
+	}
+	if specs := NewFilterWrapper(&mockFilter{}).ParamSpecs(); specs != nil {
+		t.Errorf("ParamSpecs() = %v, want nil for an undeclared strategy", specs)
+	}
+	if specs := NewValidatorWrapper(&mockValidator{}).ParamSpecs(); specs != nil {
+		t.Errorf("ValidatorWrapper.ParamSpecs() = %v, want nil", specs)
+	}
+	if specs := NewTransformWrapper(&mockTransformer{}).ParamSpecs(); specs != nil {
+		t.Errorf("TransformWrapper.ParamSpecs() = %v, want nil", specs)
+	}
+
+	parallel := NewParallelProcessor(NewFilterWrapper(&describedFilter{}))
+	defer func() { _ = parallel.Close() }()
+	specs := parallel.ParamSpecs()
+	if len(specs) != 4 || specs[0].Name != "workers" || specs[3].Name != "threshold" {
+		t.Errorf("ParallelProcessor.ParamSpecs() = %v, want parallel settings and the wrapped params", specs)
+	}
+	if err := api.ValidateParams(specs, map[string]string{"workers": "4", "threshold": "10"}); err != nil {
+		t.Errorf("ValidateParams() error = %v", err)
+	}
+}
+
 func TestFilterWrapperContextCancellation(t *testing.T) {
 	strategy := &mockFilter{threshold: 0} // Keep all records
 	wrapper := NewFilterWrapper(strategy)
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Ack finishes a deferred acknowledgement. delivered reports whether the
+// run delivered its report: if so the provider commits what it consumed,
+// such as queue offsets; if not it releases it for redelivery.
+type Ack func(ctx context.Context, delivered bool) error
+
+// Acks collects the acknowledgements providers defer until the engine
+// knows whether the run delivered its report. The engine adds one to the
+// context of every run (see WithAcks) and settles it once the output has
+// succeeded or the run has failed.
+//
+// Thread-safe: Defer may be called concurrently.
+type Acks struct {
+	mu      sync.Mutex
+	pending []Ack
+}
+
+// NewAcks creates an empty Acks.
+func NewAcks() *Acks {
+	return &Acks{}
+}
+
+// Defer adds ack to be settled with the run.
+func (a *Acks) Defer(ack Ack) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pending = append(a.pending, ack)
+}
+
+// Settle runs the deferred acknowledgements in order, once each, and
+// returns their errors joined.
+func (a *Acks) Settle(ctx context.Context, delivered bool) error {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+
+	var errs []error
+	for _, ack := range pending {
+		if err := ack(ctx, delivered); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// acksKey is the context key for the run's Acks.
+type acksKey struct{}
+
+// WithAcks returns a context whose providers defer their acknowledgements
+// to a.
+func WithAcks(ctx context.Context, a *Acks) context.Context {
+	return context.WithValue(ctx, acksKey{}, a)
+}
+
+// AcksFrom returns the Acks in ctx, or nil if acknowledgements are not
+// deferred and providers should acknowledge as soon as they are done.
+func AcksFrom(ctx context.Context) *Acks {
+	a, _ := ctx.Value(acksKey{}).(*Acks)
+	return a
+}
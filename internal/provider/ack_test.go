@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAcks_Settle(t *testing.T) {
+	acks := NewAcks()
+	var got []bool
+	acks.Defer(func(ctx context.Context, delivered bool) error {
+		got = append(got, delivered)
+		return errors.New("first")
+	})
+	acks.Defer(func(ctx context.Context, delivered bool) error {
+		got = append(got, delivered)
+		return errors.New("second")
+	})
+
+	err := acks.Settle(context.Background(), true)
+	if err == nil || err.Error() != "first\nsecond" {
+		t.Errorf("Settle() error = %v, want both errors joined", err)
+	}
+	if len(got) != 2 || !got[0] || !got[1] {
+		t.Errorf("acks ran with %v, want [true true]", got)
+	}
+
+	// Each ack runs once
+	if err := acks.Settle(context.Background(), false); err != nil {
+		t.Errorf("second Settle() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("acks ran %d times, want 2", len(got))
+	}
+}
+
+func TestAcksFrom(t *testing.T) {
+	if AcksFrom(context.Background()) != nil {
+		t.Error("AcksFrom() should be nil without WithAcks")
+	}
+	acks := NewAcks()
+	if AcksFrom(WithAcks(context.Background(), acks)) != acks {
+		t.Error("AcksFrom() should return the Acks set by WithAcks")
+	}
+}
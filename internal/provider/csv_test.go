@@ -136,3 +136,32 @@ Bob,25`
 		t.Errorf("Unexpected row content for no-header CSV: %v", firstRow)
 	}
 }
+
+func TestCSVProvider_StreamFrom(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(tmpFile, []byte("name\nAlice\nBob\nCarol\n"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	p := NewCSVProvider()
+	_ = p.Configure(map[string]string{"file_path": tmpFile})
+	var _ ResumableProvider = p
+
+	it, err := p.StreamFrom(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("StreamFrom() error = %v", err)
+	}
+	defer func() { _ = it.Close() }()
+
+	var names []interface{}
+	for it.Next() {
+		names = append(names, it.Value()["name"])
+	}
+	if it.Err() != nil || len(names) != 1 || names[0] != "Carol" {
+		t.Errorf("StreamFrom(2) names = %v, err %v; want [Carol]", names, it.Err())
+	}
+
+	if _, err := p.StreamFrom(context.Background(), 4); err == nil {
+		t.Error("StreamFrom() past the end of the file should fail")
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"io"
+
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// Operations an Interceptor is asked to run.
+const (
+	OpFetch      = "fetch"
+	OpStream     = "stream"
+	OpStreamFrom = "stream_from"
+)
+
+// Interceptor adds behavior, such as retries, limits or instrumentation,
+// around the calls a decorator forwards to the provider it wraps.
+type Interceptor interface {
+	// InterceptFetch runs fetch, a forwarded Fetch call.
+	InterceptFetch(ctx context.Context, fetch func(ctx context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error)
+
+	// InterceptStream runs open, a forwarded Stream (OpStream) or
+	// StreamFrom (OpStreamFrom) call, and may wrap the iterator it
+	// returns.
+	InterceptStream(ctx context.Context, op string, open func(ctx context.Context) (Iterator, error)) (Iterator, error)
+}
+
+// Decorate wraps delegate so every call to it goes through interceptor.
+//
+// The decorator implements StreamingProviderStrategy and ResumableProvider
+// only if delegate does, so wrapping a provider does not change how the
+// engine drives it. It is always an IncrementalProvider: WatermarkField
+// returns the delegate's field, or "" if the delegate is not incremental.
+func Decorate(delegate ProviderStrategy, interceptor Interceptor) ProviderStrategy {
+	base := decorated{delegate: delegate, interceptor: interceptor}
+	streamer, isStreaming := delegate.(StreamingProviderStrategy)
+	if !isStreaming {
+		return &base
+	}
+	stream := streamingDecorated{decorated: base, streamer: streamer}
+	if resumer, ok := delegate.(ResumableProvider); ok {
+		return &resumableDecorated{streamingDecorated: stream, resumer: resumer}
+	}
+	return &stream
+}
+
+// decorated forwards Fetch, WatermarkField and cleanup.
+type decorated struct {
+	delegate    ProviderStrategy
+	interceptor Interceptor
+}
+
+func (d *decorated) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	return d.interceptor.InterceptFetch(ctx, d.delegate.Fetch)
+}
+
+// WatermarkField returns the delegate's watermark field, if it is
+// incremental.
+func (d *decorated) WatermarkField() string {
+	if incremental, ok := d.delegate.(IncrementalProvider); ok {
+		return incremental.WatermarkField()
+	}
+	return ""
+}
+
+// Unwrap returns the wrapped provider.
+func (d *decorated) Unwrap() ProviderStrategy {
+	return d.delegate
+}
+
+// Close releases the delegate's resources, if it holds any.
+func (d *decorated) Close() error {
+	return d.CloseWithContext(context.Background())
+}
+
+// CloseWithContext releases the delegate's resources, if it holds any.
+func (d *decorated) CloseWithContext(ctx context.Context) error {
+	if closer, ok := d.delegate.(api.CloseableWithContext); ok {
+		return closer.CloseWithContext(ctx)
+	}
+	if closer, ok := d.delegate.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// streamingDecorated also forwards Stream.
+type streamingDecorated struct {
+	decorated
+	streamer StreamingProviderStrategy
+}
+
+func (d *streamingDecorated) Stream(ctx context.Context) (Iterator, error) {
+	return d.interceptor.InterceptStream(ctx, OpStream, d.streamer.Stream)
+}
+
+// resumableDecorated also forwards StreamFrom.
+type resumableDecorated struct {
+	streamingDecorated
+	resumer ResumableProvider
+}
+
+func (d *resumableDecorated) StreamFrom(ctx context.Context, offset int64) (Iterator, error) {
+	return d.interceptor.InterceptStream(ctx, OpStreamFrom, func(ctx context.Context) (Iterator, error) {
+		return d.resumer.StreamFrom(ctx, offset)
+	})
+}
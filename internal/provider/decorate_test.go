@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+// countingInterceptor counts the operations it runs.
+type countingInterceptor struct {
+	fetches int
+	streams []string
+}
+
+func (i *countingInterceptor) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	i.fetches++
+	return fetch(ctx)
+}
+
+func (i *countingInterceptor) InterceptStream(ctx context.Context, op string, open func(context.Context) (Iterator, error)) (Iterator, error) {
+	i.streams = append(i.streams, op)
+	return open(ctx)
+}
+
+func TestDecorate_KeepsOptionalInterfaces(t *testing.T) {
+	csv := NewCSVProvider()
+	if err := csv.Configure(map[string]string{"file_path": "orders.csv", "watermark_field": "updated_at"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                 string
+		delegate             ProviderStrategy
+		streaming, resumable bool
+		watermarkField       string
+	}{
+		{"mock", NewMockProvider(nil), false, false, ""},
+		{"rest", NewRESTProvider(), false, false, ""},
+		{"csv", csv, true, true, "updated_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decorate(tt.delegate, &countingInterceptor{})
+			if _, ok := d.(StreamingProviderStrategy); ok != tt.streaming {
+				t.Errorf("StreamingProviderStrategy = %v, want %v", ok, tt.streaming)
+			}
+			if _, ok := d.(ResumableProvider); ok != tt.resumable {
+				t.Errorf("ResumableProvider = %v, want %v", ok, tt.resumable)
+			}
+			if got := d.(IncrementalProvider).WatermarkField(); got != tt.watermarkField {
+				t.Errorf("WatermarkField() = %q, want %q", got, tt.watermarkField)
+			}
+		})
+	}
+}
+
+func TestDecorate_Intercepts(t *testing.T) {
+	interceptor := &countingInterceptor{}
+	d := Decorate(NewMockProvider([]map[string]interface{}{{"id": 1}}), interceptor)
+
+	data, err := d.Fetch(context.Background())
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Fetch = %v, %v", data, err)
+	}
+	if interceptor.fetches != 1 {
+		t.Errorf("fetches = %d, want 1", interceptor.fetches)
+	}
+}
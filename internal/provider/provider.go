@@ -97,3 +97,17 @@ type StreamingProviderStrategy interface {
 	// The context controls the lifetime of the stream initialization.
 	Stream(ctx context.Context) (Iterator, error)
 }
+
+// ResumableProvider is implemented by streaming providers that can start a
+// stream part-way through, so a failed streaming run can resume from its
+// last checkpoint instead of starting over.
+//
+// Resuming is only correct if the source returns the same records in the
+// same order as in the failed run.
+type ResumableProvider interface {
+	StreamingProviderStrategy
+
+	// StreamFrom returns an Iterator positioned after the first offset
+	// records that Stream would return.
+	StreamFrom(ctx context.Context, offset int64) (Iterator, error)
+}
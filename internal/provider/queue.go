@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// QueueProvider implements StreamingProviderStrategy for building reports
+// from messages on a broker topic.
+//
+// Each run consumes a bounded window: it stops after MaxMessages messages
+// or once no message arrives within IdleTimeout, whichever comes first.
+// Message values must be a JSON object (one record) or a JSON array of
+// objects (several records).
+//
+// Offsets are committed only if the whole window was read without error
+// and, when run by the engine, only once the run has delivered its report
+// (see Acks). A run that stops early or fails later (for example because a
+// processor or the output failed) re-reads the same messages next time,
+// giving at-least-once delivery. Used without the engine, offsets are
+// committed when the iterator is closed.
+type QueueProvider struct {
+	Broker          string
+	Topic           string
+	Group           string
+	MaxMessages     int
+	IdleTimeout     time.Duration
+	IncludeMetadata bool
+
+	// broker, if set, overrides the named broker lookup (useful for tests)
+	broker broker.Broker
+}
+
+// NewQueueProvider creates a new instance of QueueProvider with defaults.
+func NewQueueProvider() *QueueProvider {
+	return &QueueProvider{
+		Broker:      broker.DefaultBroker,
+		Group:       "report-engine",
+		MaxMessages: 1000,
+		IdleTimeout: time.Second,
+	}
+}
+
+// ParamSpecs declares the parameters accepted by Configure.
+func (p *QueueProvider) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "topic", Type: api.ParamString, Required: true, Description: "Topic to consume"},
+		{Name: "broker", Type: api.ParamString, Default: "memory", Description: "Registered broker name"},
+		{Name: "group", Type: api.ParamString, Default: "report-engine", Description: "Consumer group"},
+		{Name: "max_messages", Type: api.ParamInt, Default: "1000", Description: "Maximum messages per run"},
+		{Name: "idle_timeout", Type: api.ParamDuration, Default: "1s", Description: "Stop after waiting this long for a message"},
+		{Name: "include_metadata", Type: api.ParamBool, Default: "false", Description: "Add _topic, _offset and _key fields to records"},
+	}
+}
+
+// Configure sets up the provider from a map of parameters.
+// Params:
+// - topic: Topic to consume (required)
+// - broker: Registered broker name (default: "memory")
+// - group: Consumer group (default: "report-engine")
+// - max_messages: Maximum messages per run (default: "1000")
+// - idle_timeout: Stop after waiting this long for a message (default: "1s")
+// - include_metadata: Add _topic, _offset and _key fields to records (default: "false")
+func (p *QueueProvider) Configure(params map[string]string) error {
+	if topic, ok := params["topic"]; ok && topic != "" {
+		p.Topic = topic
+	} else {
+		return fmt.Errorf("queue provider: missing required parameter 'topic'")
+	}
+
+	if name, ok := params["broker"]; ok && name != "" {
+		p.Broker = name
+	}
+
+	if group, ok := params["group"]; ok && group != "" {
+		p.Group = group
+	}
+
+	if maxStr, ok := params["max_messages"]; ok {
+		n, err := strconv.Atoi(maxStr)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("queue provider: invalid max_messages %s", maxStr)
+		}
+		p.MaxMessages = n
+	}
+
+	if idleStr, ok := params["idle_timeout"]; ok {
+		d, err := time.ParseDuration(idleStr)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("queue provider: invalid idle_timeout %s", idleStr)
+		}
+		p.IdleTimeout = d
+	}
+
+	if metaStr, ok := params["include_metadata"]; ok {
+		b, err := strconv.ParseBool(metaStr)
+		if err != nil {
+			return fmt.Errorf("queue provider: invalid include_metadata %s: %w", metaStr, err)
+		}
+		p.IncludeMetadata = b
+	}
+
+	return nil
+}
+
+// Fetch consumes one window of messages and returns all decoded records.
+func (p *QueueProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	it, err := p.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An idle topic yields an empty window, which is not an error
+	results := []map[string]interface{}{}
+	for it.Next() {
+		results = append(results, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		_ = it.Close()
+		return nil, err
+	}
+	if err := it.Close(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Stream subscribes to the topic and returns an iterator over the window.
+func (p *QueueProvider) Stream(ctx context.Context) (Iterator, error) {
+	if p.Topic == "" {
+		return nil, fmt.Errorf("queue provider: topic not configured")
+	}
+
+	b := p.broker
+	if b == nil {
+		var err error
+		b, err = broker.Get(p.Broker)
+		if err != nil {
+			return nil, fmt.Errorf("queue provider: %w", err)
+		}
+	}
+
+	sub, err := b.Subscribe(ctx, p.Topic, p.Group)
+	if err != nil {
+		return nil, fmt.Errorf("queue provider: failed to subscribe to %s: %w", p.Topic, err)
+	}
+
+	return &QueueIterator{
+		ctx:      ctx,
+		sub:      sub,
+		provider: p,
+	}, nil
+}
+
+// QueueIterator iterates over records decoded from a window of messages.
+type QueueIterator struct {
+	ctx      context.Context
+	sub      broker.Subscription
+	provider *QueueProvider
+
+	pending  []map[string]interface{}
+	current  map[string]interface{}
+	received int
+	last     *broker.Message
+	done     bool
+	err      error
+}
+
+// Next advances to the next record, receiving messages as needed.
+func (it *QueueIterator) Next() bool {
+	for len(it.pending) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.received >= it.provider.MaxMessages {
+			it.done = true
+			return false
+		}
+
+		msg, err := it.receive()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && it.ctx.Err() == nil {
+				// Idle timeout: the window ends when the topic goes quiet
+				it.done = true
+				return false
+			}
+			it.err = fmt.Errorf("queue provider: receive failed: %w", err)
+			return false
+		}
+		it.received++
+		it.last = &msg
+
+		records, err := decodeMessage(msg)
+		if err != nil {
+			it.err = fmt.Errorf("queue provider: message at offset %d: %w", msg.Offset, err)
+			return false
+		}
+		if it.provider.IncludeMetadata {
+			for _, r := range records {
+				r["_topic"] = msg.Topic
+				r["_offset"] = msg.Offset
+				r["_key"] = msg.Key
+			}
+		}
+		it.pending = records
+	}
+
+	it.current = it.pending[0]
+	it.pending = it.pending[1:]
+	return true
+}
+
+// receive waits up to the idle timeout for the next message.
+func (it *QueueIterator) receive() (broker.Message, error) {
+	ctx, cancel := context.WithTimeout(it.ctx, it.provider.IdleTimeout)
+	defer cancel()
+	return it.sub.Receive(ctx)
+}
+
+// Value returns the current record.
+func (it *QueueIterator) Value() map[string]interface{} {
+	return it.current
+}
+
+// Err returns any error that occurred during iteration.
+func (it *QueueIterator) Err() error {
+	return it.err
+}
+
+// Close ends the iteration. The consumed window is committed if it was
+// read to the end without error: at once, or when the run is settled if
+// ctx carries Acks. The subscription stays open until then.
+func (it *QueueIterator) Close() error {
+	complete := it.done && it.err == nil && it.last != nil
+	if acks := AcksFrom(it.ctx); acks != nil {
+		acks.Defer(func(ctx context.Context, delivered bool) error {
+			return it.finish(ctx, complete && delivered)
+		})
+		return nil
+	}
+	return it.finish(context.WithoutCancel(it.ctx), complete)
+}
+
+// finish commits the consumed window if commit is set and ends the
+// subscription, so uncommitted messages are redelivered.
+func (it *QueueIterator) finish(ctx context.Context, commit bool) error {
+	var commitErr error
+	if commit {
+		if err := it.sub.Commit(ctx, *it.last); err != nil {
+			commitErr = fmt.Errorf("queue provider: commit failed: %w", err)
+		}
+	}
+	if err := it.sub.Close(); err != nil && commitErr == nil {
+		return fmt.Errorf("queue provider: close failed: %w", err)
+	}
+	return commitErr
+}
+
+// decodeMessage turns a JSON object or array of objects into records.
+func decodeMessage(msg broker.Message) ([]map[string]interface{}, error) {
+	var raw interface{}
+	if err := json.Unmarshal(msg.Value, &raw); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}, nil
+	case []interface{}:
+		records := make([]map[string]interface{}, 0, len(v))
+		for i, item := range v {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("item at index %d is not a json object", i)
+			}
+			records = append(records, m)
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("value must be a json object or array of objects")
+	}
+}
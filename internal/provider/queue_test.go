@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/broker"
+)
+
+func newTestQueueProvider(b broker.Broker, topic string) *QueueProvider {
+	p := NewQueueProvider()
+	p.Topic = topic
+	p.IdleTimeout = 20 * time.Millisecond
+	p.broker = b
+	return p
+}
+
+func TestQueueProvider_Fetch(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemoryBroker()
+	_ = b.Publish(ctx, "sales",
+		broker.Message{Value: []byte(`{"id": 1, "region": "east"}`)},
+		broker.Message{Value: []byte(`[{"id": 2}, {"id": 3}]`)},
+	)
+
+	p := newTestQueueProvider(b, "sales")
+	data, err := p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(data) != 3 {
+		t.Fatalf("Fetch() returned %d records, want 3", len(data))
+	}
+	if data[0]["region"] != "east" || data[2]["id"] != float64(3) {
+		t.Errorf("unexpected records: %v", data)
+	}
+
+	// Window was committed, so the next run sees nothing new
+	if got := b.Committed("sales", p.Group); got != 2 {
+		t.Errorf("Committed() = %d, want 2", got)
+	}
+	data, err = p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("second Fetch() error = %v", err)
+	}
+	if data == nil || len(data) != 0 {
+		t.Errorf("second Fetch() = %v, want an empty, non-nil result", data)
+	}
+}
+
+func TestQueueProvider_MaxMessages(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemoryBroker()
+	for i := 0; i < 5; i++ {
+		_ = b.Publish(ctx, "t", broker.Message{Value: []byte(`{"n": 1}`)})
+	}
+
+	p := newTestQueueProvider(b, "t")
+	p.MaxMessages = 2
+
+	data, err := p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Fetch() returned %d records, want 2", len(data))
+	}
+	if got := b.Committed("t", p.Group); got != 2 {
+		t.Errorf("Committed() = %d, want 2", got)
+	}
+}
+
+func TestQueueProvider_Metadata(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemoryBroker()
+	_ = b.Publish(ctx, "t", broker.Message{Key: "k1", Value: []byte(`{"id": 1}`)})
+
+	p := newTestQueueProvider(b, "t")
+	p.IncludeMetadata = true
+
+	data, err := p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("Fetch() returned %d records, want 1", len(data))
+	}
+	if data[0]["_topic"] != "t" || data[0]["_offset"] != int64(0) || data[0]["_key"] != "k1" {
+		t.Errorf("unexpected metadata: %v", data[0])
+	}
+}
+
+func TestQueueProvider_InvalidMessage(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemoryBroker()
+	_ = b.Publish(ctx, "t",
+		broker.Message{Value: []byte(`{"id": 1}`)},
+		broker.Message{Value: []byte(`not json`)},
+	)
+
+	p := newTestQueueProvider(b, "t")
+	if _, err := p.Fetch(ctx); err == nil {
+		t.Fatal("expected error for invalid message")
+	}
+
+	// Failed windows are not committed
+	if got := b.Committed("t", p.Group); got != 0 {
+		t.Errorf("Committed() = %d, want 0", got)
+	}
+}
+
+func TestQueueProvider_StreamEarlyCloseDoesNotCommit(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemoryBroker()
+	_ = b.Publish(ctx, "t", broker.Message{Value: []byte(`{"id": 1}`)}, broker.Message{Value: []byte(`{"id": 2}`)})
+
+	p := newTestQueueProvider(b, "t")
+	it, err := p.Stream(ctx)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if !it.Next() {
+		t.Fatal("Next() should return a record")
+	}
+	if err := it.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := b.Committed("t", p.Group); got != 0 {
+		t.Errorf("Committed() = %d, want 0 after early close", got)
+	}
+}
+
+func TestQueueProvider_DeferredAcks(t *testing.T) {
+	for _, delivered := range []bool{true, false} {
+		b := broker.NewMemoryBroker()
+		_ = b.Publish(context.Background(), "t", broker.Message{Value: []byte(`{"id": 1}`)}, broker.Message{Value: []byte(`{"id": 2}`)})
+
+		acks := NewAcks()
+		ctx := WithAcks(context.Background(), acks)
+		p := newTestQueueProvider(b, "t")
+		data, err := p.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if len(data) != 2 {
+			t.Fatalf("Fetch() returned %d records, want 2", len(data))
+		}
+
+		// Nothing is committed until the run settles
+		if got := b.Committed("t", p.Group); got != 0 {
+			t.Errorf("Committed() = %d before Settle, want 0", got)
+		}
+		if err := acks.Settle(context.Background(), delivered); err != nil {
+			t.Fatalf("Settle() error = %v", err)
+		}
+
+		want := int64(0)
+		if delivered {
+			want = 2
+		}
+		if got := b.Committed("t", p.Group); got != want {
+			t.Errorf("Committed() = %d after Settle(%v), want %d", got, delivered, want)
+		}
+	}
+}
+
+func TestQueueProvider_ContextCanceled(t *testing.T) {
+	b := broker.NewMemoryBroker()
+	p := newTestQueueProvider(b, "t")
+	p.IdleTimeout = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := p.Fetch(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestQueueProvider_UnknownBroker(t *testing.T) {
+	p := NewQueueProvider()
+	p.Topic = "t"
+	p.Broker = "missing"
+
+	if _, err := p.Fetch(context.Background()); err == nil {
+		t.Error("expected error for unregistered broker")
+	}
+}
+
+func TestQueueProvider_Configure(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantErr bool
+	}{
+		{name: "valid config", params: map[string]string{"topic": "sales"}},
+		{
+			name: "all options",
+			params: map[string]string{
+				"topic":            "sales",
+				"broker":           "memory",
+				"group":            "nightly",
+				"max_messages":     "50",
+				"idle_timeout":     "250ms",
+				"include_metadata": "true",
+			},
+		},
+		{name: "missing topic", params: map[string]string{}, wantErr: true},
+		{name: "invalid max_messages", params: map[string]string{"topic": "t", "max_messages": "0"}, wantErr: true},
+		{name: "invalid idle_timeout", params: map[string]string{"topic": "t", "idle_timeout": "soon"}, wantErr: true},
+		{name: "invalid include_metadata", params: map[string]string{"topic": "t", "include_metadata": "maybe"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQueueProvider()
+			if err := p.Configure(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("QueueProvider.Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,15 +7,31 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // RESTProvider implements ProviderStrategy for fetching data from REST APIs.
+//
+// Failures are classified for retries: network timeouts and connection
+// failures, 408, 429 and 5xx responses are transient (keeping any
+// Retry-After delay in an errors.StatusError), 401 and 403 responses are
+// configuration errors and other failures are permanent.
+//
+// For incremental runs, IncrementalField names the watermark property of
+// returned objects. When WatermarkParam is set, the watermark is also sent
+// as that query parameter (e.g. "?updated_since=...") so the API can skip
+// old records; objects are filtered against the watermark either way.
 type RESTProvider struct {
 	URL     string
 	Method  string
 	Headers map[string]string
 	Timeout time.Duration
 
+	IncrementalField string
+	WatermarkParam   string
+
 	// client allows injection of custom http client (useful for mocks or specific configs)
 	client *http.Client
 }
@@ -48,6 +64,13 @@ func (p *RESTProvider) Fetch(ctx context.Context) ([]map[string]interface{}, err
 		return nil, fmt.Errorf("rest provider: failed to create request: %w", err)
 	}
 
+	watermark := WatermarkFrom(ctx)
+	if watermark != "" && p.IncrementalField != "" && p.WatermarkParam != "" {
+		query := req.URL.Query()
+		query.Set(p.WatermarkParam, watermark)
+		req.URL.RawQuery = query.Encode()
+	}
+
 	// Add headers
 	req.Header.Set("Accept", "application/json")
 	for k, v := range p.Headers {
@@ -65,13 +88,13 @@ func (p *RESTProvider) Fetch(ctx context.Context) ([]map[string]interface{}, err
 	// Execute request
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("rest provider: request failed: %w", err)
+		return nil, restError(ctx, -1, fmt.Errorf("rest provider: request failed: %w", err))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Validate status code
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("rest provider: api returned error status: %d %s", resp.StatusCode, resp.Status)
+		return nil, errors.ErrProviderStatus("rest", errors.NewStatusError(resp))
 	}
 
 	// Decode response
@@ -80,11 +103,11 @@ func (p *RESTProvider) Fetch(ctx context.Context) ([]map[string]interface{}, err
 	var raw interface{}
 	decoder := json.NewDecoder(resp.Body)
 	if err := decoder.Decode(&raw); err != nil {
-		return nil, fmt.Errorf("rest provider: failed to decode json body: %w", err)
+		return nil, restError(ctx, 0, fmt.Errorf("rest provider: failed to decode json body: %w", err))
 	}
 
 	// Normalize data
-	var results []map[string]interface{}
+	results := []map[string]interface{}{}
 
 	switch v := raw.(type) {
 	case []interface{}:
@@ -95,17 +118,53 @@ func (p *RESTProvider) Fetch(ctx context.Context) ([]map[string]interface{}, err
 			} else {
 				// Warn or skip? skipping non-object items in array
 				// Ideally logging would happen here but we don't have logger injected yet in this simple struct.
-				return nil, fmt.Errorf("rest provider: item at index %d is not a json object", i)
+				return nil, errors.ErrProviderDataFormat("rest", i, fmt.Errorf("rest provider: item at index %d is not a json object", i))
 			}
 		}
 	case map[string]interface{}:
 		// Single object
 		results = append(results, v)
 	default:
-		return nil, fmt.Errorf("rest provider: response must be a json object or array of objects")
+		return nil, errors.ErrProviderDataFormat("rest", 0, fmt.Errorf("rest provider: response must be a json object or array of objects"))
 	}
 
-	return results, nil
+	return filterWatermark(results, p.IncrementalField, watermark), nil
+}
+
+// restError classifies a failed request or response read. Cancellation
+// is returned unclassified and transient network failures (including a
+// body cut off mid-read) as connection errors. Other request failures are
+// permanent, as are undecodable bodies, reported at recordNum when it is
+// not negative.
+func restError(ctx context.Context, recordNum int, err error) error {
+	switch {
+	case ctx.Err() != nil:
+		return err
+	case errors.IsTransientNetwork(err):
+		return errors.ErrProviderConnection("rest", err)
+	case recordNum >= 0:
+		return errors.ErrProviderDataFormat("rest", recordNum, err)
+	default:
+		return errors.NewProviderError("fetch", errors.ErrorTypePermanent, err).
+			WithProviderType("rest")
+	}
+}
+
+// WatermarkField returns the watermark property for incremental runs.
+func (p *RESTProvider) WatermarkField() string {
+	return p.IncrementalField
+}
+
+// ParamSpecs declares the parameters accepted by Configure.
+func (p *RESTProvider) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "url", Type: api.ParamString, Required: true, Description: "API URL"},
+		{Name: "method", Type: api.ParamString, Default: "GET", Description: "HTTP method"},
+		{Name: "header_*", Type: api.ParamString, Description: "Custom header, e.g. header_Authorization"},
+		{Name: "timeout", Type: api.ParamDuration, Description: "Request timeout, e.g. 30s"},
+		{Name: "watermark_field", Type: api.ParamString, Description: "Property used as the incremental-run watermark"},
+		{Name: "watermark_param", Type: api.ParamString, Description: "Query parameter receiving the watermark"},
+	}
 }
 
 // Configure sets up the provider from a map of parameters.
@@ -114,6 +173,8 @@ func (p *RESTProvider) Fetch(ctx context.Context) ([]map[string]interface{}, err
 // - method: HTTP Method (default: "GET")
 // - header_<KEY>: Custom headers, e.g., "header_Authorization"
 // - timeout: Timeout duration string (e.g., "30s", "1m")
+// - watermark_field: Property used as the incremental-run watermark (optional)
+// - watermark_param: Query parameter receiving the watermark (requires watermark_field)
 func (p *RESTProvider) Configure(params map[string]string) error {
 	if url, ok := params["url"]; ok {
 		p.URL = url
@@ -133,6 +194,12 @@ func (p *RESTProvider) Configure(params map[string]string) error {
 		p.Timeout = d
 	}
 
+	p.IncrementalField = params["watermark_field"]
+	p.WatermarkParam = params["watermark_param"]
+	if p.WatermarkParam != "" && p.IncrementalField == "" {
+		return fmt.Errorf("rest provider: watermark_param requires watermark_field")
+	}
+
 	// Parse headers (prefix "header_")
 	for k, v := range params {
 		if strings.HasPrefix(k, "header_") {
@@ -3,10 +3,13 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
 )
 
 func TestRESTProvider_Fetch(t *testing.T) {
@@ -156,4 +159,85 @@ func TestRESTProvider_Timeout(t *testing.T) {
 	if err == nil {
 		t.Error("Expected timeout error, got nil")
 	}
+	if got := engerrors.Classify(err); got != engerrors.ErrorTypeTransient {
+		t.Errorf("Expected timeout to be transient, got %s", got)
+	}
+}
+
+func TestRESTProvider_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		wantType   engerrors.ErrorType
+		wantAfter  time.Duration
+	}{
+		{"rate limited", http.StatusTooManyRequests, "7", engerrors.ErrorTypeTransient, 7 * time.Second},
+		{"unavailable", http.StatusServiceUnavailable, "", engerrors.ErrorTypeTransient, 0},
+		{"unauthorized", http.StatusUnauthorized, "", engerrors.ErrorTypeConfiguration, 0},
+		{"not found", http.StatusNotFound, "", engerrors.ErrorTypePermanent, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer ts.Close()
+
+			p := NewRESTProvider()
+			p.URL = ts.URL
+
+			_, err := p.Fetch(context.Background())
+			if got := engerrors.Classify(err); got != tt.wantType {
+				t.Errorf("Classify() = %s, want %s", got, tt.wantType)
+			}
+
+			var statusErr *engerrors.StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected a StatusError in %v", err)
+			}
+			if statusErr.StatusCode != tt.statusCode || statusErr.RetryAfter != tt.wantAfter {
+				t.Errorf("StatusError = %+v, want code %d and retry after %s", statusErr, tt.statusCode, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestRESTProvider_ConnectionRefused(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	p := NewRESTProvider()
+	p.URL = url
+
+	_, err := p.Fetch(context.Background())
+	if got := engerrors.Classify(err); got != engerrors.ErrorTypeTransient {
+		t.Errorf("Expected refused connection to be transient, got %s: %v", got, err)
+	}
+}
+
+func TestRESTProvider_CanceledIsUnclassified(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	p := NewRESTProvider()
+	p.URL = ts.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Fetch(ctx)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := engerrors.Classify(err); got != engerrors.ErrorTypeUnknown {
+		t.Errorf("Expected cancellation to be unclassified, got %s", got)
+	}
 }
@@ -4,16 +4,33 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
 // SQLProvider implements ProviderStrategy for fetching data from a SQL database.
 // It uses the standard database/sql interface, so it supports any driver (postgres, mysql, sqlite, etc.).
 // Note: The driver MUST be imported in the main application (e.g. _ "github.com/lib/pq").
+//
+// For incremental runs, IncrementalField names the watermark column and
+// WatermarkQuery is the query used once a watermark exists. It receives the
+// watermark as its only bind argument, e.g.
+// "SELECT * FROM orders WHERE updated_at > ?". Rows are also filtered
+// against the watermark after fetching, so WatermarkQuery is an
+// optimization: without it Query runs and older rows are dropped in memory.
+//
+// Failures are classified for retries: lost connections, network
+// failures and transient SQLSTATEs (serialization failures, deadlocks,
+// server shutdowns) are transient, other failures are permanent.
 type SQLProvider struct {
 	Driver string
 	DSN    string
 	Query  string
 
+	IncrementalField string
+	WatermarkQuery   string
+
 	// db, if set allows reusing an existing connection pool,
 	// otherwise one is created per Fetch (and closed).
 	// For production efficiency, managing the DB connection externally is better,
@@ -51,7 +68,9 @@ func (p *SQLProvider) Fetch(ctx context.Context) ([]map[string]interface{}, erro
 		}
 		db, err = sql.Open(p.Driver, p.DSN)
 		if err != nil {
-			return nil, fmt.Errorf("sql provider: failed to open connection: %w", err)
+			return nil, errors.NewProviderError("connect", errors.ErrorTypeConfiguration,
+				fmt.Errorf("sql provider: failed to open connection: %w", err)).
+				WithProviderType("sql")
 		}
 		shouldClose = true
 	}
@@ -62,24 +81,30 @@ func (p *SQLProvider) Fetch(ctx context.Context) ([]map[string]interface{}, erro
 
 	// Verify connection
 	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("sql provider: ping failed: %w", err)
+		return nil, sqlError(ctx, "connect", "", fmt.Errorf("sql provider: ping failed: %w", err))
 	}
 
-	// Execute query
-	rows, err := db.QueryContext(ctx, p.Query)
+	// Execute query, narrowed to new rows when a watermark is set
+	watermark := WatermarkFrom(ctx)
+	query, args := p.Query, []interface{}(nil)
+	if watermark != "" && p.IncrementalField != "" && p.WatermarkQuery != "" {
+		query, args = p.WatermarkQuery, []interface{}{watermark}
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("sql provider: query failed: %w", err)
+		return nil, sqlError(ctx, "query", query, fmt.Errorf("sql provider: query failed: %w", err))
 	}
 	defer func() { _ = rows.Close() }()
 
 	// Get columns
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("sql provider: failed to get columns: %w", err)
+		return nil, sqlError(ctx, "query", "", fmt.Errorf("sql provider: failed to get columns: %w", err))
 	}
 
-	// Prepare results
-	var results []map[string]interface{}
+	// Prepare results; an empty result is not nil, since an incremental
+	// run with no new rows is not an error
+	results := []map[string]interface{}{}
 
 	// Helper for scanning
 	count := len(columns)
@@ -93,7 +118,7 @@ func (p *SQLProvider) Fetch(ctx context.Context) ([]map[string]interface{}, erro
 		}
 
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, fmt.Errorf("sql provider: scan failed: %w", err)
+			return nil, errors.ErrProviderDataFormat("sql", len(results), fmt.Errorf("sql provider: scan failed: %w", err))
 		}
 
 		// Map to result
@@ -114,10 +139,46 @@ func (p *SQLProvider) Fetch(ctx context.Context) ([]map[string]interface{}, erro
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("sql provider: row iteration error: %w", err)
+		return nil, sqlError(ctx, "fetch", "", fmt.Errorf("sql provider: row iteration error: %w", err))
+	}
+
+	return filterWatermark(results, p.IncrementalField, watermark), nil
+}
+
+// sqlError classifies a database failure. Cancellation is returned
+// unclassified, lost connections, network failures and transient
+// SQLSTATEs are transient and anything else is permanent. A non-empty
+// query is attached to the error.
+func sqlError(ctx context.Context, operation string, query string, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+
+	errorType := errors.ErrorTypePermanent
+	if errors.IsTransientSQL(err) || errors.IsTransientNetwork(err) {
+		errorType = errors.ErrorTypeTransient
+	}
+	providerErr := errors.NewProviderError(operation, errorType, err).WithProviderType("sql")
+	if query != "" {
+		_ = providerErr.WithQuery(query)
 	}
+	return providerErr
+}
+
+// WatermarkField returns the watermark column for incremental runs.
+func (p *SQLProvider) WatermarkField() string {
+	return p.IncrementalField
+}
 
-	return results, nil
+// ParamSpecs declares the parameters accepted by Configure.
+func (p *SQLProvider) ParamSpecs() []api.ParamSpec {
+	return []api.ParamSpec{
+		{Name: "driver", Type: api.ParamString, Required: true, Description: "Database driver name, e.g. postgres"},
+		{Name: "dsn", Type: api.ParamString, Required: true, Description: "Data source name connection string"},
+		{Name: "query", Type: api.ParamString, Required: true, Description: "SQL query to execute"},
+		{Name: "watermark_field", Type: api.ParamString, Description: "Column used as the incremental-run watermark"},
+		{Name: "watermark_query", Type: api.ParamString, Description: "Query with one bind placeholder for the watermark"},
+	}
 }
 
 // Configure sets up the provider from a map of parameters.
@@ -125,6 +186,8 @@ func (p *SQLProvider) Fetch(ctx context.Context) ([]map[string]interface{}, erro
 // - driver: Database driver name (e.g., "postgres", "mysql"). Required.
 // - dsn: Data Source Name connection string. Required.
 // - query: SQL Query to execute. Required.
+// - watermark_field: Column used as the incremental-run watermark (optional)
+// - watermark_query: Query with one bind placeholder for the watermark (requires watermark_field)
 func (p *SQLProvider) Configure(params map[string]string) error {
 	if driver, ok := params["driver"]; ok {
 		p.Driver = driver
@@ -144,6 +207,12 @@ func (p *SQLProvider) Configure(params map[string]string) error {
 		return fmt.Errorf("sql provider: missing required parameter 'query'")
 	}
 
+	p.IncrementalField = params["watermark_field"]
+	p.WatermarkQuery = params["watermark_query"]
+	if p.WatermarkQuery != "" && p.IncrementalField == "" {
+		return fmt.Errorf("sql provider: watermark_query requires watermark_field")
+	}
+
 	return nil
 }
 
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
 )
 
 func TestSQLProvider_Fetch(t *testing.T) {
@@ -120,3 +122,45 @@ func TestSQLProvider_QueryError(t *testing.T) {
 		t.Error("Expected error from Fetch(), got nil")
 	}
 }
+
+// stateError is a driver error carrying a SQLSTATE code.
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestSQLProvider_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType engerrors.ErrorType
+	}{
+		{"serialization failure", stateError("40001"), engerrors.ErrorTypeTransient},
+		{"connection failure", stateError("08006"), engerrors.ErrorTypeTransient},
+		{"unique violation", stateError("23505"), engerrors.ErrorTypePermanent},
+		{"syntax error", errors.New("syntax error"), engerrors.ErrorTypePermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = db.Close() }()
+
+			mock.ExpectPing()
+			mock.ExpectQuery("SELECT").WillReturnError(tt.err)
+
+			p := &SQLProvider{Query: "SELECT * FROM users", db: db}
+
+			_, err = p.Fetch(context.Background())
+			if got := engerrors.Classify(err); got != tt.wantType {
+				t.Errorf("Classify() = %s, want %s (%v)", got, tt.wantType, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected the driver error to be wrapped, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/memory"
+)
+
+// IncrementalProvider is implemented by providers that support
+// watermark-based incremental runs.
+//
+// The provider declares a watermark field, a record field that only grows
+// as new data arrives (an update timestamp or an auto-increment ID). The
+// engine stores the highest value seen by each successful run and passes
+// it back on the next run via WithWatermark; the provider then returns
+// only records whose field is greater than the watermark.
+//
+// Records that lack the watermark field are always returned, so no data
+// is silently dropped.
+type IncrementalProvider interface {
+	ProviderStrategy
+
+	// WatermarkField returns the record field used as the watermark, or
+	// "" if incremental fetching is not configured.
+	WatermarkField() string
+}
+
+// watermarkKey is the context key for the current watermark.
+type watermarkKey struct{}
+
+// WithWatermark returns a context carrying the watermark for an
+// incremental fetch.
+func WithWatermark(ctx context.Context, watermark string) context.Context {
+	return context.WithValue(ctx, watermarkKey{}, watermark)
+}
+
+// WatermarkFrom returns the watermark in ctx, or "" for a full fetch.
+func WatermarkFrom(ctx context.Context) string {
+	watermark, _ := ctx.Value(watermarkKey{}).(string)
+	return watermark
+}
+
+// FormatWatermark converts a record field value to its stored watermark
+// form. Times are formatted as RFC 3339 in UTC.
+func FormatWatermark(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// CompareWatermark compares a record field value with a stored watermark
+// and returns -1, 0 or +1. Integers, decimals and RFC 3339 times compare
+// by value; anything else compares as strings.
+func CompareWatermark(value interface{}, watermark string) int {
+	s := FormatWatermark(value)
+
+	if a, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if b, err := strconv.ParseInt(watermark, 10, 64); err == nil {
+			return compareOrdered(a, b)
+		}
+	}
+	if a, err := strconv.ParseFloat(s, 64); err == nil {
+		if b, err := strconv.ParseFloat(watermark, 64); err == nil {
+			return compareOrdered(a, b)
+		}
+	}
+	if a, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		if b, err := time.Parse(time.RFC3339Nano, watermark); err == nil {
+			return a.Compare(b)
+		}
+	}
+	return strings.Compare(s, watermark)
+}
+
+// compareOrdered returns -1, 0 or +1.
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// newerThan reports whether record should be returned for watermark.
+func newerThan(record map[string]interface{}, field, watermark string) bool {
+	value, ok := record[field]
+	if !ok || value == nil {
+		return true
+	}
+	return CompareWatermark(value, watermark) > 0
+}
+
+// filterWatermark keeps the records newer than watermark. It returns
+// records unchanged when field or watermark is empty.
+func filterWatermark(records []map[string]interface{}, field, watermark string) []map[string]interface{} {
+	if field == "" || watermark == "" {
+		return records
+	}
+	kept := records[:0]
+	for _, record := range records {
+		if newerThan(record, field, watermark) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// watermarkIterator skips records that are not newer than the watermark.
+type watermarkIterator struct {
+	Iterator
+	field     string
+	watermark string
+}
+
+// Next advances to the next record newer than the watermark. Skipped
+// records are returned to the map pool.
+func (it *watermarkIterator) Next() bool {
+	for it.Iterator.Next() {
+		record := it.Iterator.Value()
+		if newerThan(record, it.field, it.watermark) {
+			return true
+		}
+		memory.PutMap(record)
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCompareWatermark(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		value     interface{}
+		watermark string
+		want      int
+	}{
+		{"int greater", int64(10), "9", 1},
+		{"int numeric not lexical", 10, "9", 1},
+		{"int equal", 42, "42", 0},
+		{"float less", 1.5, "2.25", -1},
+		{"numeric string", "100", "99", 1},
+		{"time greater", ts, "2026-03-01T11:59:59Z", 1},
+		{"time across zones", ts, "2026-03-01T13:00:00+01:00", 0},
+		{"rfc3339 strings", "2026-03-02T00:00:00Z", "2026-03-01T23:00:00-05:00", -1},
+		{"plain strings", "b", "a", 1},
+		{"bytes", []byte("a"), "b", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareWatermark(tt.value, tt.watermark); got != tt.want {
+				t.Errorf("CompareWatermark(%v, %q) = %d, want %d", tt.value, tt.watermark, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWatermark(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	if got := FormatWatermark(time.Date(2026, 3, 1, 13, 0, 0, 0, loc)); got != "2026-03-01T12:00:00Z" {
+		t.Errorf("FormatWatermark(time) = %q", got)
+	}
+	if got := FormatWatermark(12.5); got != "12.5" {
+		t.Errorf("FormatWatermark(float) = %q", got)
+	}
+	if got := FormatWatermark(int64(7)); got != "7" {
+		t.Errorf("FormatWatermark(int64) = %q", got)
+	}
+}
+
+func TestWatermarkContext(t *testing.T) {
+	if WatermarkFrom(context.Background()) != "" {
+		t.Error("WatermarkFrom() should be empty without a watermark")
+	}
+	if got := WatermarkFrom(WithWatermark(context.Background(), "42")); got != "42" {
+		t.Errorf("WatermarkFrom() = %q, want 42", got)
+	}
+}
+
+func TestFilterWatermark(t *testing.T) {
+	records := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}, {"name": "no id"}}
+
+	kept := filterWatermark(records, "id", "2")
+	if len(kept) != 2 || kept[0]["id"] != 3 || kept[1]["name"] != "no id" {
+		t.Errorf("filterWatermark() = %v, want id 3 and the record without id", kept)
+	}
+	if got := filterWatermark([]map[string]interface{}{{"id": 1}}, "id", ""); len(got) != 1 {
+		t.Errorf("filterWatermark() without watermark = %v", got)
+	}
+	if got := filterWatermark([]map[string]interface{}{{"id": 1}}, "id", "5"); got == nil || len(got) != 0 {
+		t.Errorf("filterWatermark() = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestSQLProvider_Watermark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	p := &SQLProvider{db: db}
+	if err := p.Configure(map[string]string{
+		"driver":          "postgres",
+		"dsn":             "unused",
+		"query":           "SELECT id FROM orders",
+		"watermark_field": "id",
+		"watermark_query": "SELECT id FROM orders WHERE id > ?",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var _ IncrementalProvider = p
+	if p.WatermarkField() != "id" {
+		t.Errorf("WatermarkField() = %q", p.WatermarkField())
+	}
+
+	// Full fetch without a watermark
+	mock.ExpectPing()
+	mock.ExpectQuery("SELECT id FROM orders").WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	if rows, err := p.Fetch(context.Background()); err != nil || len(rows) != 2 {
+		t.Errorf("full Fetch() = %v, %v", rows, err)
+	}
+
+	// Incremental fetch binds the watermark
+	mock.ExpectPing()
+	mock.ExpectQuery(`SELECT id FROM orders WHERE id > \?`).WithArgs("2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	rows, err := p.Fetch(WithWatermark(context.Background(), "2"))
+	if err != nil || rows == nil || len(rows) != 0 {
+		t.Errorf("incremental Fetch() = %#v, %v; want empty non-nil result", rows, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+
+	if err := NewSQLProvider().Configure(map[string]string{
+		"driver": "postgres", "dsn": "x", "query": "q", "watermark_query": "q2",
+	}); err == nil {
+		t.Error("Configure() should require watermark_field with watermark_query")
+	}
+}
+
+func TestRESTProvider_Watermark(t *testing.T) {
+	var since string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = r.URL.Query().Get("updated_since")
+		// The API ignores the parameter; the provider still filters
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": 1, "updated_at": "2026-03-01T00:00:00Z"},
+			{"id": 2, "updated_at": "2026-03-02T00:00:00Z"},
+		})
+	}))
+	defer ts.Close()
+
+	p := NewRESTProvider()
+	if err := p.Configure(map[string]string{
+		"url":             ts.URL + "?page=1",
+		"watermark_field": "updated_at",
+		"watermark_param": "updated_since",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := p.Fetch(WithWatermark(context.Background(), "2026-03-01T00:00:00Z"))
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if since != "2026-03-01T00:00:00Z" {
+		t.Errorf("watermark param = %q", since)
+	}
+	if len(rows) != 1 || rows[0]["id"] != float64(2) {
+		t.Errorf("Fetch() = %v, want only id 2", rows)
+	}
+
+	if _, err := p.Fetch(context.Background()); err != nil || since != "" {
+		t.Errorf("full Fetch() sent watermark %q, err %v", since, err)
+	}
+}
+
+func TestCSVProvider_Watermark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	content := "id,total\n1,10\n2,20\n3,30\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewCSVProvider()
+	if err := p.Configure(map[string]string{"file_path": path, "watermark_field": "id"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithWatermark(context.Background(), "1")
+
+	rows, err := p.Fetch(ctx)
+	if err != nil || len(rows) != 2 || rows[0]["id"] != "2" {
+		t.Errorf("Fetch() = %v, %v; want ids 2 and 3", rows, err)
+	}
+
+	it, err := p.Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = it.Close() }()
+	var ids []interface{}
+	for it.Next() {
+		ids = append(ids, it.Value()["id"])
+	}
+	if it.Err() != nil || len(ids) != 2 || ids[0] != "2" || ids[1] != "3" {
+		t.Errorf("Stream() ids = %v, err %v; want [2 3]", ids, it.Err())
+	}
+}
@@ -46,6 +46,16 @@ func init() {
 		return provider.NewRESTProvider()
 	})
 
+	// Register Queue provider
+	RegisterProvider("queue", func() provider.ProviderStrategy {
+		return provider.NewQueueProvider()
+	})
+
+	// Register JSON Formatter (indented by default)
+	RegisterFormatter("json", func() formatter.FormatStrategy {
+		return formatter.NewJSONFormatter("  ")
+	})
+
 	// Register CSV Formatter
 	RegisterFormatter("csv", func() formatter.FormatStrategy {
 		return formatter.NewCSVFormatter()
@@ -59,11 +69,35 @@ func init() {
 		return formatter.NewYAMLFormatter()
 	})
 
+	// Register Console Output
+	RegisterOutput("console", func() output.OutputStrategy {
+		return output.NewConsoleOutput()
+	})
+
 	// Register File Output
 	RegisterOutput("file", func() output.OutputStrategy {
 		return output.NewFileOutput()
 	})
 
+	// Register SFTP Output
+	RegisterOutput("sftp", func() output.OutputStrategy {
+		return output.NewSFTPOutput()
+	})
+
+	// Register SQL/Database Output
+	RegisterOutput("sql", func() output.OutputStrategy {
+		return output.NewSQLOutput()
+	})
+	// Alias 'database' for convenience
+	RegisterOutput("database", func() output.OutputStrategy {
+		return output.NewSQLOutput()
+	})
+
+	// Register Queue Output
+	RegisterOutput("queue", func() output.OutputStrategy {
+		return output.NewQueueOutput()
+	})
+
 	// Register Processors
 	RegisterProcessor("deduplicate", func() processor.ProcessorHandler {
 		return processor.NewDeduplicateProcessor(nil)
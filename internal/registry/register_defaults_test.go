@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// TestDefaultParamSpecs tests the built-in components' param declarations
+// are consistent: unique names and defaults that pass their own checks.
+func TestDefaultParamSpecs(t *testing.T) {
+	components := map[string]interface{}{
+		"provider csv":          provider.NewCSVProvider(),
+		"provider sql":          provider.NewSQLProvider(),
+		"provider rest":         provider.NewRESTProvider(),
+		"provider queue":        provider.NewQueueProvider(),
+		"formatter json":        formatter.NewJSONFormatter(""),
+		"formatter csv":         formatter.NewCSVFormatter(),
+		"formatter yaml":        formatter.NewYAMLFormatter(),
+		"output file":           output.NewFileOutput(),
+		"output sftp":           output.NewSFTPOutput(),
+		"output sql":            output.NewSQLOutput(),
+		"output queue":          output.NewQueueOutput(),
+		"processor aggregate":   processor.NewAggregateProcessor(nil, nil),
+		"processor deduplicate": processor.NewDeduplicateProcessor(nil),
+	}
+
+	for label, component := range components {
+		describer, ok := component.(api.ParamDescriber)
+		if !ok || len(describer.ParamSpecs()) == 0 {
+			t.Errorf("%s does not declare its params", label)
+			continue
+		}
+		seen := map[string]bool{}
+		for _, spec := range describer.ParamSpecs() {
+			if seen[spec.Name] {
+				t.Errorf("%s declares %q twice", label, spec.Name)
+			}
+			seen[spec.Name] = true
+			if spec.Description == "" {
+				t.Errorf("%s param %q has no description", label, spec.Name)
+			}
+			if spec.Default != "" {
+				if err := spec.Check(spec.Default); err != nil {
+					t.Errorf("%s param %q default: %v", label, spec.Name, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrBreakerMismatch is returned when a circuit breaker is requested
+	// with settings other than those it was registered with.
+	ErrBreakerMismatch = errors.New("circuit breaker settings differ from the registered ones")
+)
+
+// BreakerRegistry holds circuit breakers by name, so engines that name the
+// same breaker share its state: a breaker opened by one run also rejects
+// the calls of the next run, even when each run builds its own engine.
+type BreakerRegistry struct {
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// DefaultBreakers is the process-wide registry used by engines built from
+// configuration.
+var DefaultBreakers = NewBreakerRegistry()
+
+// NewBreakerRegistry creates an empty BreakerRegistry.
+func NewBreakerRegistry() *BreakerRegistry {
+	return &BreakerRegistry{breakers: make(map[string]*CircuitBreaker)}
+}
+
+// CircuitBreaker returns the breaker registered under name, creating it
+// with NewCircuitBreaker on first use. Every user of a name must ask for
+// the same settings; other settings fail with ErrBreakerMismatch and leave
+// the breaker unchanged. State listeners are those of the first user.
+func (r *BreakerRegistry) CircuitBreaker(name string, threshold uint, timeout time.Duration, opts ...CircuitBreakerOption) (*CircuitBreaker, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requested := NewCircuitBreaker(name, threshold, timeout, opts...)
+	if cb, ok := r.breakers[name]; ok {
+		if got, want := cb.settings(), requested.settings(); got != want {
+			return nil, fmt.Errorf("circuit breaker %q: %w: registered %s, requested %s", name, ErrBreakerMismatch, got, want)
+		}
+		return cb, nil
+	}
+	r.breakers[name] = requested
+	return requested, nil
+}
+
+// Clone returns a registry holding the breakers of r. Names first used
+// through the clone are not added to r, so it can check configs against
+// the registered breakers without registering new ones.
+func (r *BreakerRegistry) Clone() *BreakerRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &BreakerRegistry{breakers: maps.Clone(r.breakers)}
+}
@@ -0,0 +1,144 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrBulkheadFull is returned when a call waited longer than the
+	// bulkhead's MaxWait for a free slot.
+	ErrBulkheadFull = errors.New("bulkhead is full")
+)
+
+// Bulkhead limits how many calls run at once. Calls beyond the limit
+// queue for a free slot in arrival order.
+type Bulkhead struct {
+	name string
+
+	mu            sync.Mutex
+	maxConcurrent int
+	maxWait       time.Duration
+	inFlight      int
+	waiters       []chan struct{}
+}
+
+// NewBulkhead creates a new Bulkhead allowing maxConcurrent calls at once.
+// Queued calls fail with ErrBulkheadFull after maxWait; zero waits until
+// the context is done.
+func NewBulkhead(name string, maxConcurrent int, maxWait time.Duration) *Bulkhead {
+	b := &Bulkhead{name: name}
+	b.SetLimit(maxConcurrent, maxWait)
+	return b
+}
+
+// SetLimit changes the concurrency limit and queue wait. Raising the
+// limit admits queued calls; lowering it lets running calls finish. A
+// limit below 1 is treated as 1.
+func (b *Bulkhead) SetLimit(maxConcurrent int, maxWait time.Duration) {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maxConcurrent = maxConcurrent
+	b.maxWait = maxWait
+	for len(b.waiters) > 0 && b.inFlight < b.maxConcurrent {
+		b.admitNext()
+	}
+}
+
+// Limit returns the concurrency limit and queue wait.
+func (b *Bulkhead) Limit() (maxConcurrent int, maxWait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.maxConcurrent, b.maxWait
+}
+
+// Execute runs op once a slot is free.
+func (b *Bulkhead) Execute(ctx context.Context, op func() error) error {
+	if err := b.Acquire(ctx); err != nil {
+		return err
+	}
+	defer b.Release()
+	return op()
+}
+
+// Acquire waits for a free slot. Every successful Acquire must be
+// followed by a Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	b.mu.Lock()
+	if b.inFlight < b.maxConcurrent && len(b.waiters) == 0 {
+		b.inFlight++
+		b.mu.Unlock()
+		return nil
+	}
+	admitted := make(chan struct{})
+	b.waiters = append(b.waiters, admitted)
+	maxWait := b.maxWait
+	b.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-admitted:
+		return nil
+	case <-timeout:
+		err = fmt.Errorf("%w: %s (%d in flight)", ErrBulkheadFull, b.name, b.InFlight())
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, w := range b.waiters {
+		if w == admitted {
+			b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+			return err
+		}
+	}
+	// Admitted while giving up: hand the slot on.
+	b.release()
+	return err
+}
+
+// Release frees a slot taken by Acquire.
+func (b *Bulkhead) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.release()
+}
+
+// InFlight returns the number of calls holding a slot (thread-safe).
+func (b *Bulkhead) InFlight() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.inFlight
+}
+
+// release frees a slot and admits the next queued call. Callers hold mu.
+func (b *Bulkhead) release() {
+	b.inFlight--
+	if len(b.waiters) > 0 && b.inFlight < b.maxConcurrent {
+		b.admitNext()
+	}
+}
+
+// admitNext gives a slot to the longest-waiting call. Callers hold mu.
+func (b *Bulkhead) admitNext() {
+	next := b.waiters[0]
+	b.waiters = b.waiters[1:]
+	b.inFlight++
+	close(next)
+}
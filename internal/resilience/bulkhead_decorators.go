@@ -0,0 +1,111 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// ProviderWithBulkhead runs the calls to a ProviderStrategy in the slots
+// of a Bulkhead.
+type ProviderWithBulkhead struct {
+	bulkhead *Bulkhead
+}
+
+// NewProviderWithBulkhead creates a new decorator. It keeps the optional
+// interfaces of delegate (see provider.Decorate).
+func NewProviderWithBulkhead(delegate provider.ProviderStrategy, bulkhead *Bulkhead) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithBulkhead{bulkhead: bulkhead})
+}
+
+// InterceptFetch fetches once a slot is free.
+func (p *ProviderWithBulkhead) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+
+	op := func() error {
+		var err error
+		results, err = fetch(ctx)
+		return err
+	}
+
+	err := p.bulkhead.Execute(ctx, op)
+	return results, err
+}
+
+// InterceptStream holds a slot for the life of the stream, until the
+// iterator is closed.
+func (p *ProviderWithBulkhead) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	if err := p.bulkhead.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	iter, err := open(ctx)
+	if err != nil || iter == nil {
+		p.bulkhead.Release()
+		return iter, err
+	}
+	return &bulkheadIterator{Iterator: iter, release: p.bulkhead.Release}, nil
+}
+
+// bulkheadIterator releases its bulkhead slot when closed.
+type bulkheadIterator struct {
+	provider.Iterator
+	once    sync.Once
+	release func()
+}
+
+func (it *bulkheadIterator) Close() error {
+	err := it.Iterator.Close()
+	it.once.Do(it.release)
+	return err
+}
+
+// OutputWithBulkhead runs the calls to an OutputStrategy in the slots of
+// a Bulkhead.
+type OutputWithBulkhead struct {
+	bulkhead *Bulkhead
+
+	mu   sync.Mutex
+	held int // slots held by open streams
+}
+
+// NewOutputWithBulkhead creates a new decorator. It keeps the optional
+// interfaces of delegate (see output.Decorate).
+func NewOutputWithBulkhead(delegate output.OutputStrategy, bulkhead *Bulkhead) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithBulkhead{bulkhead: bulkhead})
+}
+
+// Intercept runs Send and SendRecords once a slot is free. A stream holds
+// a slot from Initialize or Resume until it is closed or aborted.
+func (o *OutputWithBulkhead) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	switch op {
+	case output.OpSend, output.OpSendRecords:
+		return o.bulkhead.Execute(ctx, func() error { return call(ctx) })
+	case output.OpInitialize, output.OpResume:
+		if err := o.bulkhead.Acquire(ctx); err != nil {
+			return err
+		}
+		if err := call(ctx); err != nil {
+			o.bulkhead.Release()
+			return err
+		}
+		o.mu.Lock()
+		o.held++
+		o.mu.Unlock()
+		return nil
+	case output.OpClose, output.OpAbort:
+		defer o.release()
+	}
+	return call(ctx)
+}
+
+// release frees the slot of a stream that has ended, if it holds one.
+func (o *OutputWithBulkhead) release() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.held > 0 {
+		o.held--
+		o.bulkhead.Release()
+	}
+}
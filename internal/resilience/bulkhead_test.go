@@ -0,0 +1,190 @@
+package resilience_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+func TestBulkhead_LimitsConcurrency(t *testing.T) {
+	b := resilience.NewBulkhead("test", 2, 0)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Execute(context.Background(), func() error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent calls, peak was %d", peak)
+	}
+	if b.InFlight() != 0 {
+		t.Errorf("expected all slots to be released, %d in flight", b.InFlight())
+	}
+}
+
+func TestBulkhead_MaxWait(t *testing.T) {
+	b := resilience.NewBulkhead("test", 1, 10*time.Millisecond)
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Release()
+
+	err := b.Execute(context.Background(), func() error { return nil })
+	if !errors.Is(err, resilience.ErrBulkheadFull) {
+		t.Errorf("expected ErrBulkheadFull, got %v", err)
+	}
+}
+
+func TestBulkhead_ContextCanceled(t *testing.T) {
+	b := resilience.NewBulkhead("test", 1, 0)
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+
+	b.Release()
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Errorf("expected the abandoned wait not to hold a slot, got %v", err)
+	}
+}
+
+func TestBulkhead_SetLimitAdmitsWaiters(t *testing.T) {
+	b := resilience.NewBulkhead("test", 1, 0)
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	admitted := make(chan error, 1)
+	go func() { admitted <- b.Acquire(context.Background()) }()
+
+	time.Sleep(5 * time.Millisecond)
+	b.SetLimit(2, 0)
+
+	select {
+	case err := <-admitted:
+		if err != nil {
+			t.Errorf("Acquire() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected raising the limit to admit the queued call")
+	}
+	if b.InFlight() != 2 {
+		t.Errorf("expected 2 calls in flight, got %d", b.InFlight())
+	}
+}
+
+// streamingProvider streams its records through a closable iterator.
+type streamingProvider struct {
+	MockProviderStrategy
+}
+
+func (p *streamingProvider) Stream(ctx context.Context) (provider.Iterator, error) {
+	return &sliceIterator{records: p.SuccessContent, index: -1}, nil
+}
+
+type sliceIterator struct {
+	records []map[string]interface{}
+	index   int
+}
+
+func (it *sliceIterator) Next() bool                    { it.index++; return it.index < len(it.records) }
+func (it *sliceIterator) Value() map[string]interface{} { return it.records[it.index] }
+func (it *sliceIterator) Err() error                    { return nil }
+func (it *sliceIterator) Close() error                  { return nil }
+
+func TestProviderWithBulkhead_StreamHoldsSlot(t *testing.T) {
+	b := resilience.NewBulkhead("test", 1, 0)
+	p, ok := resilience.NewProviderWithBulkhead(&streamingProvider{}, b).(provider.StreamingProviderStrategy)
+	if !ok {
+		t.Fatal("expected the decorator to keep streaming")
+	}
+
+	iter, err := p.Stream(context.Background())
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if b.InFlight() != 1 {
+		t.Errorf("expected the open stream to hold a slot, got %d in flight", b.InFlight())
+	}
+
+	_ = iter.Close()
+	_ = iter.Close()
+	if b.InFlight() != 0 {
+		t.Errorf("expected closing the stream to release its slot once, got %d in flight", b.InFlight())
+	}
+}
+
+// streamingOutput is a streaming output that records how it was driven.
+type streamingOutput struct {
+	initErr error
+	chunks  int
+	closed  bool
+}
+
+func (o *streamingOutput) Send(ctx context.Context, data []byte) error { return nil }
+func (o *streamingOutput) Initialize(ctx context.Context) error        { return o.initErr }
+func (o *streamingOutput) WriteChunk(ctx context.Context, data []byte) error {
+	o.chunks++
+	return nil
+}
+func (o *streamingOutput) Close(ctx context.Context) error { o.closed = true; return nil }
+
+func TestOutputWithBulkhead_StreamHoldsSlot(t *testing.T) {
+	b := resilience.NewBulkhead("test", 1, 0)
+	out, ok := resilience.NewOutputWithBulkhead(&streamingOutput{}, b).(output.StreamingOutputStrategy)
+	if !ok {
+		t.Fatal("expected the decorator to keep streaming")
+	}
+	ctx := context.Background()
+
+	if err := out.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	_ = out.WriteChunk(ctx, []byte("a"))
+	if b.InFlight() != 1 {
+		t.Errorf("expected the open stream to hold a slot, got %d in flight", b.InFlight())
+	}
+
+	_ = out.Close(ctx)
+	_ = out.(output.AbortableOutput).Abort(ctx)
+	if b.InFlight() != 0 {
+		t.Errorf("expected ending the stream to release its slot once, got %d in flight", b.InFlight())
+	}
+
+	failing := resilience.NewOutputWithBulkhead(&streamingOutput{initErr: errors.New("boom")}, b).(output.StreamingOutputStrategy)
+	if err := failing.Initialize(ctx); err == nil {
+		t.Fatal("expected the Initialize error")
+	}
+	if b.InFlight() != 0 {
+		t.Errorf("expected a failed Initialize to release its slot, got %d in flight", b.InFlight())
+	}
+}
@@ -1,9 +1,13 @@
 package resilience
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/health"
 )
 
 // CircuitState represents the state of the circuit breaker.
@@ -15,37 +19,157 @@ const (
 	StateHalfOpen
 )
 
+// String returns the state name, e.g. "half_open".
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
 var (
 	// ErrCircuitOpen is returned when the circuit breaker rejects a request.
 	ErrCircuitOpen = errors.New("circuit breaker is open")
 )
 
+// StateChange describes a circuit breaker transition.
+type StateChange struct {
+	Name string
+	From CircuitState
+	To   CircuitState
+	At   time.Time
+
+	// Calls and Failures are the counts that led to the transition: the
+	// sliding window in failure-rate mode, the consecutive failures
+	// otherwise.
+	Calls    int
+	Failures int
+}
+
+// StateListener is called after every circuit breaker transition. It runs
+// on the goroutine of the call that caused the transition, outside the
+// breaker's lock.
+type StateListener func(change StateChange)
+
+// CircuitBreakerOption configures a CircuitBreaker.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithCountWindow trips the breaker on the failure rate of the last size
+// calls instead of consecutive failures. The breaker opens once at least
+// minCalls calls are in the window and the fraction that failed is at
+// least failureRate (0-1).
+func WithCountWindow(size int, failureRate float64, minCalls int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if size < 1 {
+			size = 1
+		}
+		cb.window = &countWindow{outcomes: make([]bool, size)}
+		cb.failureRate = failureRate
+		cb.minCalls = minCalls
+	}
+}
+
+// WithTimeWindow trips the breaker on the failure rate of the calls made
+// in the last window, like WithCountWindow.
+func WithTimeWindow(window time.Duration, failureRate float64, minCalls int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if window <= 0 {
+			window = time.Minute
+		}
+		cb.window = newTimeWindow(window)
+		cb.failureRate = failureRate
+		cb.minCalls = minCalls
+	}
+}
+
+// WithHalfOpenCalls sets how many trial calls a half-open breaker lets
+// through (default: 1). The breaker closes once all of them succeed and
+// opens again on the first failure; further calls are rejected meanwhile.
+func WithHalfOpenCalls(n int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if n < 1 {
+			n = 1
+		}
+		cb.halfOpenCalls = n
+	}
+}
+
+// WithStateListener adds a listener for state changes, e.g.
+// observability.NewBreakerListener for metrics and logs.
+func WithStateListener(listener StateListener) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.listeners = append(cb.listeners, listener)
+	}
+}
+
 // CircuitBreaker implements the Circuit Breaker pattern.
+//
+// By default the breaker opens after threshold consecutive failures.
+// WithCountWindow and WithTimeWindow make it open on a failure rate over
+// a sliding window instead. After the reset timeout an open breaker turns
+// half-open and lets a limited number of trial calls through.
 type CircuitBreaker struct {
 	name             string
 	failureThreshold uint
 	resetTimeout     time.Duration
+	halfOpenCalls    int
+	listeners        []StateListener
+
+	// Failure-rate mode; window is nil in consecutive-failure mode
+	window      failureWindow
+	failureRate float64
+	minCalls    int
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures uint
+	openedAt time.Time
 
-	mu          sync.Mutex
-	state       CircuitState
-	failures    uint
-	lastFailure time.Time
+	// Half-open trial calls admitted and succeeded
+	trials    int
+	successes int
 }
 
 // NewCircuitBreaker creates a new CircuitBreaker.
-func NewCircuitBreaker(name string, threshold uint, timeout time.Duration) *CircuitBreaker {
+func NewCircuitBreaker(name string, threshold uint, timeout time.Duration, opts ...CircuitBreakerOption) *CircuitBreaker {
 	if threshold == 0 {
 		threshold = 5 // Default
 	}
 	if timeout == 0 {
 		timeout = 60 * time.Second // Default
 	}
-	return &CircuitBreaker{
+	cb := &CircuitBreaker{
 		name:             name,
 		failureThreshold: threshold,
 		resetTimeout:     timeout,
+		halfOpenCalls:    1,
 		state:            StateClosed,
 	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+// settings describes how the breaker trips and recovers, for comparing
+// breakers registered under the same name.
+func (cb *CircuitBreaker) settings() string {
+	trip := fmt.Sprintf("%d consecutive failures", cb.failureThreshold)
+	if cb.window != nil {
+		trip = fmt.Sprintf("failure rate %g of the %s (min %d calls)", cb.failureRate, cb.window, cb.minCalls)
+	}
+	return fmt.Sprintf("opens on %s, resets after %s with %d half-open calls", trip, cb.resetTimeout, cb.halfOpenCalls)
+}
+
+// Name returns the breaker name.
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
 }
 
 // Execute runs the given operation if the circuit is allowed.
@@ -67,57 +191,81 @@ func (cb *CircuitBreaker) Execute(op func() error) error {
 
 // Allow checks if a request should be allowed to proceed.
 // It handles the transition from Open to HalfOpen based on timeout.
+// A request allowed while half-open is a trial call and must be followed
+// by RecordSuccess or RecordFailure.
 func (cb *CircuitBreaker) Allow() bool {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	if cb.state == StateClosed {
-		return true
-	}
+	var change *StateChange
+	allowed := true
 
-	if cb.state == StateOpen {
-		if time.Since(cb.lastFailure) > cb.resetTimeout {
-			cb.state = StateHalfOpen
-			return true
+	switch cb.state {
+	case StateOpen:
+		if time.Since(cb.openedAt) > cb.resetTimeout {
+			change = cb.setState(StateHalfOpen)
+			cb.trials = 1
+		} else {
+			allowed = false
+		}
+	case StateHalfOpen:
+		if cb.trials < cb.halfOpenCalls {
+			cb.trials++
+		} else {
+			allowed = false
 		}
-		return false
 	}
 
-	// StateHalfOpen
-	// We allow the request to probe the service.
-	return true
+	cb.mu.Unlock()
+	cb.notify(change)
+	return allowed
 }
 
 // RecordFailure records a failure and updates the state.
 func (cb *CircuitBreaker) RecordFailure() {
+	now := time.Now()
+
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	var change *StateChange
 
 	cb.failures++
-	cb.lastFailure = time.Now()
 
-	if cb.state == StateHalfOpen {
-		cb.state = StateOpen
-		return
+	switch cb.state {
+	case StateHalfOpen:
+		change = cb.setState(StateOpen)
+	case StateClosed:
+		if cb.window != nil {
+			cb.window.record(now, true)
+		}
+		if cb.shouldTrip(now) {
+			change = cb.setState(StateOpen)
+		}
 	}
 
-	if cb.state == StateClosed && cb.failures >= cb.failureThreshold {
-		cb.state = StateOpen
-	}
+	cb.mu.Unlock()
+	cb.notify(change)
 }
 
 // RecordSuccess records a success and updates the state.
 func (cb *CircuitBreaker) RecordSuccess() {
+	now := time.Now()
+
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	var change *StateChange
 
 	switch cb.state {
 	case StateHalfOpen:
-		cb.state = StateClosed
-		cb.failures = 0
+		cb.successes++
+		if cb.successes >= cb.halfOpenCalls {
+			change = cb.setState(StateClosed)
+		}
 	case StateClosed:
 		cb.failures = 0
+		if cb.window != nil {
+			cb.window.record(now, false)
+		}
 	}
+
+	cb.mu.Unlock()
+	cb.notify(change)
 }
 
 // State returns the current state (thread-safe).
@@ -126,3 +274,91 @@ func (cb *CircuitBreaker) State() CircuitState {
 	defer cb.mu.Unlock()
 	return cb.state
 }
+
+// CheckHealth implements health.Checker. A closed breaker is UP; an open
+// or half-open breaker is DEGRADED, since calls are being rejected while
+// the protected service recovers.
+func (cb *CircuitBreaker) CheckHealth(ctx context.Context) (health.Result, error) {
+	now := time.Now()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	calls, failures := cb.counts(now)
+	details := map[string]interface{}{
+		"name":     cb.name,
+		"state":    cb.state.String(),
+		"calls":    calls,
+		"failures": failures,
+	}
+	if cb.state == StateClosed {
+		return health.Result{Status: health.StatusUp, Details: details}, nil
+	}
+
+	details["opened_at"] = cb.openedAt
+	if cb.state == StateOpen {
+		details["retry_in"] = max(cb.resetTimeout-now.Sub(cb.openedAt), 0).String()
+	}
+	return health.Result{
+		Status:  health.StatusDegraded,
+		Details: details,
+		Error:   "circuit breaker " + cb.name + " is " + cb.state.String(),
+	}, nil
+}
+
+// shouldTrip reports whether a closed breaker should open. Callers hold mu.
+func (cb *CircuitBreaker) shouldTrip(now time.Time) bool {
+	if cb.window == nil {
+		return cb.failures >= cb.failureThreshold
+	}
+	calls, failures := cb.window.counts(now)
+	return calls > 0 && calls >= cb.minCalls && float64(failures)/float64(calls) >= cb.failureRate
+}
+
+// counts returns the calls and failures that decide whether the breaker
+// trips. Callers hold mu.
+func (cb *CircuitBreaker) counts(now time.Time) (calls, failures int) {
+	if cb.window == nil {
+		return int(cb.failures), int(cb.failures)
+	}
+	return cb.window.counts(now)
+}
+
+// setState moves the breaker to state and returns the change to notify
+// once mu is released. Callers hold mu.
+func (cb *CircuitBreaker) setState(state CircuitState) *StateChange {
+	now := time.Now()
+	calls, failures := cb.counts(now)
+	change := &StateChange{
+		Name:     cb.name,
+		From:     cb.state,
+		To:       state,
+		At:       now,
+		Calls:    calls,
+		Failures: failures,
+	}
+
+	cb.state = state
+	cb.trials = 0
+	cb.successes = 0
+	switch state {
+	case StateOpen:
+		cb.openedAt = now
+	case StateClosed:
+		cb.failures = 0
+		if cb.window != nil {
+			cb.window.reset()
+		}
+	}
+	return change
+}
+
+// notify calls the listeners with change, if any.
+func (cb *CircuitBreaker) notify(change *StateChange) {
+	if change == nil {
+		return
+	}
+	for _, listener := range cb.listeners {
+		listener(*change)
+	}
+}
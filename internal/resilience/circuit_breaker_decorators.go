@@ -5,29 +5,27 @@ import (
 
 	"github.com/AshishBagdane/go-report-engine/internal/output"
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
-// ProviderWithCircuitBreaker wraps a ProviderStrategy with a CircuitBreaker.
+// ProviderWithCircuitBreaker runs the calls to a ProviderStrategy through
+// a CircuitBreaker.
 type ProviderWithCircuitBreaker struct {
-	delegate provider.ProviderStrategy
-	breaker  *CircuitBreaker
+	breaker *CircuitBreaker
 }
 
-// NewProviderWithCircuitBreaker creates a new decorator.
-func NewProviderWithCircuitBreaker(delegate provider.ProviderStrategy, breaker *CircuitBreaker) *ProviderWithCircuitBreaker {
-	return &ProviderWithCircuitBreaker{
-		delegate: delegate,
-		breaker:  breaker,
-	}
+// NewProviderWithCircuitBreaker creates a new decorator. It keeps the
+// optional interfaces of delegate (see provider.Decorate).
+func NewProviderWithCircuitBreaker(delegate provider.ProviderStrategy, breaker *CircuitBreaker) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithCircuitBreaker{breaker: breaker})
 }
 
-func (p *ProviderWithCircuitBreaker) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+// InterceptFetch fetches through the breaker.
+func (p *ProviderWithCircuitBreaker) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
 	op := func() error {
 		var err error
-		results, err = p.delegate.Fetch(ctx)
+		results, err = fetch(ctx)
 		return err
 	}
 
@@ -35,53 +33,40 @@ func (p *ProviderWithCircuitBreaker) Fetch(ctx context.Context) ([]map[string]in
 	return results, err
 }
 
-func (p *ProviderWithCircuitBreaker) Stream(ctx context.Context) (provider.Iterator, error) {
-	if streamer, ok := p.delegate.(provider.StreamingProviderStrategy); ok {
-		var iter provider.Iterator
-		// Only wrap the initialization logic with circuit breaker
-		op := func() error {
-			var err error
-			iter, err = streamer.Stream(ctx)
-			return err
-		}
+// InterceptStream opens the stream through the breaker. Only opening the
+// stream is gated, not reading it.
+func (p *ProviderWithCircuitBreaker) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	var iter provider.Iterator
 
-		err := p.breaker.Execute(op)
-		return iter, err
+	openOp := func() error {
+		var err error
+		iter, err = open(ctx)
+		return err
 	}
-	return nil, nil // Or unsupported error
-}
 
-func (p *ProviderWithCircuitBreaker) Close() error {
-	if closer, ok := p.delegate.(api.Closeable); ok {
-		return closer.Close()
-	}
-	return nil
+	err := p.breaker.Execute(openOp)
+	return iter, err
 }
 
-// OutputWithCircuitBreaker wraps an OutputStrategy with a CircuitBreaker.
+// OutputWithCircuitBreaker runs the calls to an OutputStrategy through a
+// CircuitBreaker.
 type OutputWithCircuitBreaker struct {
-	delegate output.OutputStrategy
-	breaker  *CircuitBreaker
+	breaker *CircuitBreaker
 }
 
-// NewOutputWithCircuitBreaker creates a new decorator.
-func NewOutputWithCircuitBreaker(delegate output.OutputStrategy, breaker *CircuitBreaker) *OutputWithCircuitBreaker {
-	return &OutputWithCircuitBreaker{
-		delegate: delegate,
-		breaker:  breaker,
-	}
-}
-
-func (o *OutputWithCircuitBreaker) Send(ctx context.Context, data []byte) error {
-	op := func() error {
-		return o.delegate.Send(ctx, data)
-	}
-	return o.breaker.Execute(op)
+// NewOutputWithCircuitBreaker creates a new decorator. It keeps the
+// optional interfaces of delegate (see output.Decorate).
+func NewOutputWithCircuitBreaker(delegate output.OutputStrategy, breaker *CircuitBreaker) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithCircuitBreaker{breaker: breaker})
 }
 
-func (o *OutputWithCircuitBreaker) Close() error {
-	if closer, ok := o.delegate.(api.Closeable); ok {
-		return closer.Close()
+// Intercept runs Send, SendRecords and the opening of a stream through
+// the breaker. Chunk writes and the end of a stream are not gated, so an
+// open stream can finish.
+func (o *OutputWithCircuitBreaker) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	switch op {
+	case output.OpSend, output.OpSendRecords, output.OpInitialize, output.OpResume:
+		return o.breaker.Execute(func() error { return call(ctx) })
 	}
-	return nil
+	return call(ctx)
 }
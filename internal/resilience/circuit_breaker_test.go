@@ -1,10 +1,12 @@
 package resilience_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/AshishBagdane/go-report-engine/internal/health"
 	"github.com/AshishBagdane/go-report-engine/internal/resilience"
 )
 
@@ -77,3 +79,168 @@ func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
 		t.Errorf("Expected Open state after failed probe, got %v", cb.State())
 	}
 }
+
+func TestCircuitBreaker_CountWindow(t *testing.T) {
+	cb := resilience.NewCircuitBreaker("test-cb", 1, time.Minute,
+		resilience.WithCountWindow(4, 0.5, 4))
+
+	opFail := func() error { return errors.New("boom") }
+	opSuccess := func() error { return nil }
+
+	// A single failure no longer trips the breaker
+	_ = cb.Execute(opFail)
+	_ = cb.Execute(opSuccess)
+	_ = cb.Execute(opSuccess)
+	if cb.State() != resilience.StateClosed {
+		t.Fatalf("Expected Closed below min calls, got %v", cb.State())
+	}
+
+	// 2 of the last 4 calls failed: 50%
+	_ = cb.Execute(opFail)
+	if cb.State() != resilience.StateOpen {
+		t.Errorf("Expected Open at a 50%% failure rate, got %v", cb.State())
+	}
+}
+
+func TestCircuitBreaker_CountWindowSlides(t *testing.T) {
+	cb := resilience.NewCircuitBreaker("test-cb", 1, time.Minute,
+		resilience.WithCountWindow(3, 0.6, 3))
+
+	opFail := func() error { return errors.New("boom") }
+	opSuccess := func() error { return nil }
+
+	// F S S S F: the first failure has left the window when the second comes
+	for _, op := range []func() error{opFail, opSuccess, opSuccess, opSuccess, opFail} {
+		_ = cb.Execute(op)
+	}
+	if cb.State() != resilience.StateClosed {
+		t.Errorf("Expected Closed with 1 of 3 calls failed, got %v", cb.State())
+	}
+
+	_ = cb.Execute(opFail)
+	if cb.State() != resilience.StateOpen {
+		t.Errorf("Expected Open with 2 of 3 calls failed, got %v", cb.State())
+	}
+}
+
+func TestCircuitBreaker_TimeWindow(t *testing.T) {
+	window := 50 * time.Millisecond
+	cb := resilience.NewCircuitBreaker("test-cb", 1, time.Minute,
+		resilience.WithTimeWindow(window, 0.5, 2))
+
+	opFail := func() error { return errors.New("boom") }
+
+	_ = cb.Execute(opFail)
+	time.Sleep(window + 10*time.Millisecond)
+
+	// The earlier failure has expired, so this one is below min calls
+	_ = cb.Execute(opFail)
+	if cb.State() != resilience.StateClosed {
+		t.Fatalf("Expected expired failures not to count, got %v", cb.State())
+	}
+
+	_ = cb.Execute(opFail)
+	if cb.State() != resilience.StateOpen {
+		t.Errorf("Expected Open with 2 failures in the window, got %v", cb.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenCalls(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	cb := resilience.NewCircuitBreaker("test-cb", 1, timeout, resilience.WithHalfOpenCalls(2))
+
+	cb.RecordFailure()
+	time.Sleep(timeout + 10*time.Millisecond)
+
+	// Two trial calls are let through, a third is rejected
+	if !cb.Allow() || !cb.Allow() {
+		t.Fatal("Expected 2 trial calls to be allowed")
+	}
+	if cb.Allow() {
+		t.Error("Expected a third call to be rejected while half-open")
+	}
+
+	cb.RecordSuccess()
+	if cb.State() != resilience.StateHalfOpen {
+		t.Errorf("Expected HalfOpen until every trial call succeeds, got %v", cb.State())
+	}
+	cb.RecordSuccess()
+	if cb.State() != resilience.StateClosed {
+		t.Errorf("Expected Closed after the trial calls succeeded, got %v", cb.State())
+	}
+}
+
+func TestCircuitBreaker_StateListener(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	var changes []resilience.StateChange
+	cb := resilience.NewCircuitBreaker("test-cb", 2, timeout,
+		resilience.WithStateListener(func(c resilience.StateChange) { changes = append(changes, c) }))
+
+	cb.RecordFailure()
+	cb.RecordFailure()
+	time.Sleep(timeout + 10*time.Millisecond)
+	_ = cb.Execute(func() error { return nil })
+
+	want := []struct{ from, to resilience.CircuitState }{
+		{resilience.StateClosed, resilience.StateOpen},
+		{resilience.StateOpen, resilience.StateHalfOpen},
+		{resilience.StateHalfOpen, resilience.StateClosed},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %d state changes, got %+v", len(want), changes)
+	}
+	for i, w := range want {
+		if changes[i].Name != "test-cb" || changes[i].From != w.from || changes[i].To != w.to {
+			t.Errorf("change %d = %+v, want %v -> %v", i, changes[i], w.from, w.to)
+		}
+	}
+	if changes[0].Failures != 2 {
+		t.Errorf("Expected the opening to report 2 failures, got %d", changes[0].Failures)
+	}
+}
+
+func TestCircuitBreaker_CheckHealth(t *testing.T) {
+	cb := resilience.NewCircuitBreaker("test-cb", 1, time.Minute)
+
+	res, err := cb.CheckHealth(context.Background())
+	if err != nil || res.Status != health.StatusUp {
+		t.Errorf("Expected a closed breaker to be UP, got %v, %v", res, err)
+	}
+
+	cb.RecordFailure()
+	res, err = cb.CheckHealth(context.Background())
+	if err != nil || res.Status != health.StatusDegraded {
+		t.Errorf("Expected an open breaker to be DEGRADED, got %v, %v", res, err)
+	}
+	if res.Details["state"] != "open" || res.Details["retry_in"] == nil {
+		t.Errorf("Expected state and retry_in details, got %v", res.Details)
+	}
+}
+
+func TestBreakerRegistry(t *testing.T) {
+	breakers := resilience.NewBreakerRegistry()
+	first, err := breakers.CircuitBreaker("partner-api", 3, time.Minute, resilience.WithCountWindow(20, 0.5, 10))
+	if err != nil {
+		t.Fatalf("CircuitBreaker() error = %v", err)
+	}
+	second, err := breakers.CircuitBreaker("partner-api", 3, time.Minute, resilience.WithCountWindow(20, 0.5, 10))
+	if err != nil || second != first {
+		t.Errorf("expected the registered breaker, got %p, %v", second, err)
+	}
+
+	if _, err := breakers.CircuitBreaker("partner-api", 3, time.Minute, resilience.WithCountWindow(50, 0.5, 10)); !errors.Is(err, resilience.ErrBreakerMismatch) {
+		t.Errorf("expected ErrBreakerMismatch for another window, got %v", err)
+	}
+	if _, err := breakers.CircuitBreaker("partner-api", 3, time.Second, resilience.WithCountWindow(20, 0.5, 10)); !errors.Is(err, resilience.ErrBreakerMismatch) {
+		t.Errorf("expected ErrBreakerMismatch for another reset timeout, got %v", err)
+	}
+
+	clone := breakers.Clone()
+	if cb, err := clone.CircuitBreaker("partner-api", 3, time.Minute, resilience.WithCountWindow(20, 0.5, 10)); err != nil || cb != first {
+		t.Errorf("expected the clone to hold the registered breaker, got %p, %v", cb, err)
+	}
+	_, _ = clone.CircuitBreaker("warehouse-db", 1, time.Minute)
+	if _, err := breakers.CircuitBreaker("warehouse-db", 2, time.Minute); err != nil {
+		t.Errorf("expected names used through the clone not to be registered, got %v", err)
+	}
+}
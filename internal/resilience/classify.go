@@ -0,0 +1,116 @@
+package resilience
+
+import (
+	stderrors "errors"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+)
+
+// Classification is a classifier's retry decision for an error.
+type Classification struct {
+	// Retry reports whether the operation should be retried.
+	Retry bool
+	// After is the minimum delay before the next attempt requested by the
+	// failing system (e.g. an HTTP Retry-After header), or zero.
+	After time.Duration
+}
+
+// ErrorClassifier decides whether an error is worth retrying. Classify
+// returns false when the classifier does not recognize the error, so the
+// next classifier is consulted.
+type ErrorClassifier interface {
+	Classify(err error) (Classification, bool)
+}
+
+// ClassifierFunc adapts a function to the ErrorClassifier interface.
+type ClassifierFunc func(err error) (Classification, bool)
+
+// Classify calls f(err).
+func (f ClassifierFunc) Classify(err error) (Classification, bool) {
+	return f(err)
+}
+
+// DefaultClassifiers returns the classifiers a Retrier uses when its
+// policy has none: the engine error classifier first, so an explicit
+// classification wins over the error it wraps, then the HTTP status, SQL
+// and network classifiers, most specific first.
+func DefaultClassifiers() []ErrorClassifier {
+	return append([]ErrorClassifier(nil), defaultClassifiers...)
+}
+
+var defaultClassifiers = []ErrorClassifier{
+	EngineErrorClassifier(),
+	HTTPStatusClassifier(),
+	SQLClassifier(),
+	NetworkClassifier(),
+}
+
+// HTTPStatusClassifier recognizes errors wrapping an errors.StatusError.
+// Request timeouts, 429 and 5xx responses are retried, honouring any
+// Retry-After delay; other statuses are not.
+func HTTPStatusClassifier() ErrorClassifier {
+	return ClassifierFunc(func(err error) (Classification, bool) {
+		var statusErr *errors.StatusError
+		if !stderrors.As(err, &statusErr) {
+			return Classification{}, false
+		}
+		if statusErr.Type() != errors.ErrorTypeTransient {
+			return Classification{}, true
+		}
+		return Classification{Retry: true, After: statusErr.RetryAfter}, true
+	})
+}
+
+// SQLClassifier recognizes transient database failures (see
+// errors.IsTransientSQL) and retries them.
+func SQLClassifier() ErrorClassifier {
+	return ClassifierFunc(func(err error) (Classification, bool) {
+		if errors.IsTransientSQL(err) {
+			return Classification{Retry: true}, true
+		}
+		return Classification{}, false
+	})
+}
+
+// NetworkClassifier recognizes transient network failures (see
+// errors.IsTransientNetwork) and retries them.
+func NetworkClassifier() ErrorClassifier {
+	return ClassifierFunc(func(err error) (Classification, bool) {
+		if errors.IsTransientNetwork(err) {
+			return Classification{Retry: true}, true
+		}
+		return Classification{}, false
+	})
+}
+
+// EngineErrorClassifier recognizes classified engine errors anywhere in
+// the chain. Transient errors, and errors explicitly marked retryable,
+// are retried; validation, configuration, permanent and resource errors
+// are not. Unclassified errors are left to other classifiers. Retries
+// keep the Retry-After delay of an errors.StatusError the error wraps.
+func EngineErrorClassifier() ErrorClassifier {
+	return ClassifierFunc(func(err error) (Classification, bool) {
+		if errors.IsRetryable(err) {
+			return Classification{Retry: true, After: retryAfter(err)}, true
+		}
+		switch errors.Classify(err) {
+		case errors.ErrorTypeUnknown:
+			return Classification{}, false
+		case errors.ErrorTypeTransient:
+			return Classification{Retry: true, After: retryAfter(err)}, true
+		default:
+			return Classification{}, true
+		}
+	})
+}
+
+// retryAfter returns the Retry-After delay of a StatusError in the chain
+// of err, or zero.
+func retryAfter(err error) time.Duration {
+	var statusErr *errors.StatusError
+	if stderrors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package resilience_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// fastPolicy retries quickly so tests stay fast.
+var fastPolicy = resilience.RetryPolicy{
+	MaxRetries: 2,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   5 * time.Millisecond,
+	Factor:     2.0,
+}
+
+// attempts runs op through the retrier and counts its calls.
+func attempts(r *resilience.Retrier, fail func(attempt int) error) (int, error) {
+	n := 0
+	err := r.Execute(context.Background(), func(ctx context.Context) error {
+		n++
+		return fail(n)
+	})
+	return n, err
+}
+
+func TestDefaultClassifiers(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantRetry bool
+	}{
+		{"raw connection reset", fmt.Errorf("rest provider: request failed: %w", reset), true},
+		{"service unavailable", &errors.StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"not found", &errors.StatusError{StatusCode: http.StatusNotFound}, false},
+		{"transient engine error", errors.ErrProviderTimeout("rest", fmt.Errorf("slow")), true},
+		{"wrapped transient engine error", fmt.Errorf("stage: %w", errors.ErrOutputConnection("s3", "bucket", fmt.Errorf("down"))), true},
+		{"permanent engine error", errors.ErrProviderQuery("sql", "SELECT", fmt.Errorf("syntax")), false},
+		{"permanent engine error wrapping a network failure", errors.NewErrorContext("rest", "fetch").WithType(errors.ErrorTypePermanent).Wrap(reset), false},
+		{"unknown", fmt.Errorf("something odd"), false},
+		{"canceled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := attempts(resilience.NewRetrier(fastPolicy), func(int) error { return tt.err })
+			if !stderrors.Is(err, tt.err) {
+				t.Errorf("expected the last error to be returned, got %v", err)
+			}
+			want := 1
+			if tt.wantRetry {
+				want = fastPolicy.MaxRetries + 1
+			}
+			if n != want {
+				t.Errorf("expected %d attempts, got %d", want, n)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsHonoured(t *testing.T) {
+	policy := fastPolicy
+	policy.MaxRetries = 1
+	policy.MaxRetryAfter = time.Second
+
+	start := time.Now()
+	n, err := attempts(resilience.NewRetrier(policy), func(attempt int) error {
+		if attempt == 1 {
+			return &errors.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond}
+		}
+		return nil
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("expected success on the second attempt, got %d attempts and %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to wait for Retry-After, waited %s", elapsed)
+	}
+}
+
+func TestEngineErrorKeepsRetryAfter(t *testing.T) {
+	status := &errors.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 30 * time.Second}
+	err := errors.NewErrorContext("rest", "fetch").WithType(errors.ErrorTypeTransient).Wrap(status)
+
+	c, ok := resilience.EngineErrorClassifier().Classify(err)
+	if !ok || !c.Retry || c.After != 30*time.Second {
+		t.Errorf("Classify() = %+v, %v; want a retry after 30s", c, ok)
+	}
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	policy := fastPolicy
+	policy.MaxRetries = 1
+	policy.MaxRetryAfter = 10 * time.Millisecond
+
+	start := time.Now()
+	_, _ = attempts(resilience.NewRetrier(policy), func(int) error {
+		return &errors.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Retry-After to be capped, waited %s", elapsed)
+	}
+}
+
+func TestCustomClassifiers(t *testing.T) {
+	errBusy := stderrors.New("busy")
+
+	policy := fastPolicy
+	policy.Classifiers = append([]resilience.ErrorClassifier{
+		resilience.ClassifierFunc(func(err error) (resilience.Classification, bool) {
+			if stderrors.Is(err, errBusy) {
+				return resilience.Classification{Retry: true}, true
+			}
+			return resilience.Classification{}, false
+		}),
+	}, resilience.DefaultClassifiers()...)
+	retrier := resilience.NewRetrier(policy)
+
+	n, _ := attempts(retrier, func(int) error { return errBusy })
+	if n != policy.MaxRetries+1 {
+		t.Errorf("expected custom classifier to retry, got %d attempts", n)
+	}
+
+	n, _ = attempts(retrier, func(int) error { return errors.ErrProviderTimeout("rest", errBusy) })
+	if n != policy.MaxRetries+1 {
+		t.Errorf("expected default classifiers to still apply, got %d attempts", n)
+	}
+
+	n, _ = attempts(retrier, func(int) error { return stderrors.New("other") })
+	if n != 1 {
+		t.Errorf("expected unrecognized errors not to be retried, got %d attempts", n)
+	}
+}
@@ -0,0 +1,302 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// ProviderAlternative is a named provider that a ProviderWithFallback can
+// fetch from.
+type ProviderAlternative struct {
+	Name     string
+	Provider provider.ProviderStrategy
+}
+
+// OutputAlternative is a named output that an OutputWithFallback can send
+// to.
+type OutputAlternative struct {
+	Name   string
+	Output output.OutputStrategy
+}
+
+// FallbackUse records which alternative served a call of a fallback
+// decorator.
+type FallbackUse struct {
+	// Component is "provider" or "output".
+	Component string `json:"component"`
+
+	// Source is the name of the alternative that served the call.
+	Source string `json:"source"`
+
+	// Fallback is true if Source is not the primary.
+	Fallback bool `json:"fallback"`
+
+	// Failures lists the errors of the alternatives tried before Source,
+	// as "name: error".
+	Failures []string `json:"failures,omitempty"`
+}
+
+// FallbackRecorder collects the FallbackUse of every call made with a
+// context from WithFallbackRecorder. It is safe for concurrent use.
+type FallbackRecorder struct {
+	mu   sync.Mutex
+	uses []FallbackUse
+}
+
+// NewFallbackRecorder creates an empty FallbackRecorder.
+func NewFallbackRecorder() *FallbackRecorder {
+	return &FallbackRecorder{}
+}
+
+// Uses returns the recorded uses, in call order.
+func (r *FallbackRecorder) Uses() []FallbackUse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FallbackUse(nil), r.uses...)
+}
+
+func (r *FallbackRecorder) record(use FallbackUse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uses = append(r.uses, use)
+}
+
+type fallbackRecorderKey struct{}
+
+// WithFallbackRecorder returns a context whose fallback decorator calls
+// are recorded in r. The engine uses it to fill in RunResult.Sources.
+func WithFallbackRecorder(ctx context.Context, r *FallbackRecorder) context.Context {
+	return context.WithValue(ctx, fallbackRecorderKey{}, r)
+}
+
+// recordFallback adds use to the recorder of ctx, if any.
+func recordFallback(ctx context.Context, use FallbackUse) {
+	if r, ok := ctx.Value(fallbackRecorderKey{}).(*FallbackRecorder); ok {
+		r.record(use)
+	}
+}
+
+// fallback calls op for each of names in order until one succeeds or ctx
+// is done, logging and recording which one served the call. Any error,
+// including ErrCircuitOpen, moves on to the next alternative.
+func fallback(ctx context.Context, logger *logging.Logger, component string, names []string, op func(i int) error) error {
+	var failures []string
+	var lastErr error
+	for i, name := range names {
+		err := op(i)
+		if err == nil {
+			use := FallbackUse{Component: component, Source: name, Fallback: i > 0, Failures: failures}
+			if use.Fallback {
+				logger.WarnContext(ctx, "served by fallback",
+					"component", component,
+					"source", name,
+					"failures", failures,
+				)
+			}
+			recordFallback(ctx, use)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		lastErr = err
+		if i < len(names)-1 {
+			logger.WarnContext(ctx, "call failed, trying fallback",
+				"component", component,
+				"source", name,
+				"next", names[i+1],
+				"error", err,
+			)
+		}
+	}
+	return fmt.Errorf("all %d %ss failed, last: %w", len(names), component, lastErr)
+}
+
+// defaultFallbackLogger returns the logger fallback decorators use unless
+// one is set with WithLogger.
+func defaultFallbackLogger() *logging.Logger {
+	return logging.NewLogger(logging.Config{
+		Level:     logging.LevelInfo,
+		Format:    logging.FormatJSON,
+		Component: "fallback",
+	})
+}
+
+// ProviderWithFallback fetches from the first of an ordered list of
+// providers that succeeds, e.g. a REST source and then yesterday's cached
+// snapshot.
+type ProviderWithFallback struct {
+	alternatives []ProviderAlternative
+	names        []string
+	logger       *logging.Logger
+}
+
+// NewProviderWithFallback creates a new decorator. The first alternative
+// is the primary. If the primary can stream, the decorator is a
+// *StreamingProviderWithFallback; otherwise it is a *ProviderWithFallback,
+// which does not stream, so the engine runs in batch mode and Fetch falls
+// back in order.
+func NewProviderWithFallback(alternatives ...ProviderAlternative) provider.ProviderStrategy {
+	names := make([]string, len(alternatives))
+	for i, alt := range alternatives {
+		names[i] = alt.Name
+	}
+	p := &ProviderWithFallback{
+		alternatives: alternatives,
+		names:        names,
+		logger:       defaultFallbackLogger(),
+	}
+	if len(alternatives) > 0 {
+		if _, ok := alternatives[0].Provider.(provider.StreamingProviderStrategy); ok {
+			return &StreamingProviderWithFallback{ProviderWithFallback: p}
+		}
+	}
+	return p
+}
+
+// WithLogger sets the logger fallbacks are logged to.
+func (p *ProviderWithFallback) WithLogger(logger *logging.Logger) *ProviderWithFallback {
+	p.logger = logger
+	return p
+}
+
+func (p *ProviderWithFallback) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+
+	op := func(i int) error {
+		var err error
+		results, err = p.alternatives[i].Provider.Fetch(ctx)
+		return err
+	}
+
+	err := fallback(ctx, p.logger, "provider", p.names, op)
+	return results, err
+}
+
+// WatermarkField returns the primary's watermark field, or "" if the
+// primary is not incremental. Alternatives are expected to honor it.
+func (p *ProviderWithFallback) WatermarkField() string {
+	if len(p.alternatives) == 0 {
+		return ""
+	}
+	if incremental, ok := p.alternatives[0].Provider.(provider.IncrementalProvider); ok {
+		return incremental.WatermarkField()
+	}
+	return ""
+}
+
+// StreamingProviderWithFallback is a ProviderWithFallback whose primary
+// can stream.
+type StreamingProviderWithFallback struct {
+	*ProviderWithFallback
+}
+
+// Stream falls back on the stream initialization only. Alternatives are
+// tried in order; one that cannot stream is fetched, and its records are
+// streamed from memory.
+func (p *StreamingProviderWithFallback) Stream(ctx context.Context) (provider.Iterator, error) {
+	var iter provider.Iterator
+	op := func(i int) error {
+		alt := p.alternatives[i].Provider
+		if streamer, ok := alt.(provider.StreamingProviderStrategy); ok {
+			var err error
+			iter, err = streamer.Stream(ctx)
+			return err
+		}
+		records, err := alt.Fetch(ctx)
+		if err != nil {
+			return err
+		}
+		iter = &recordsIterator{records: records, index: -1}
+		return nil
+	}
+
+	err := fallback(ctx, p.logger, "provider", p.names, op)
+	return iter, err
+}
+
+// recordsIterator streams records fetched by a fallback that cannot
+// stream.
+type recordsIterator struct {
+	records []map[string]interface{}
+	index   int
+}
+
+func (it *recordsIterator) Next() bool {
+	it.index++
+	return it.index < len(it.records)
+}
+
+func (it *recordsIterator) Value() map[string]interface{} { return it.records[it.index] }
+func (it *recordsIterator) Err() error                    { return nil }
+func (it *recordsIterator) Close() error                  { return nil }
+
+// Close closes every alternative.
+func (p *ProviderWithFallback) Close() error {
+	var errs []error
+	for _, alt := range p.alternatives {
+		if closer, ok := alt.Provider.(api.Closeable); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", alt.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OutputWithFallback sends to the first of an ordered list of outputs
+// that succeeds, e.g. an SFTP drop and then a local spool directory.
+type OutputWithFallback struct {
+	alternatives []OutputAlternative
+	names        []string
+	logger       *logging.Logger
+}
+
+// NewOutputWithFallback creates a new decorator. The first alternative is
+// the primary.
+func NewOutputWithFallback(alternatives ...OutputAlternative) *OutputWithFallback {
+	names := make([]string, len(alternatives))
+	for i, alt := range alternatives {
+		names[i] = alt.Name
+	}
+	return &OutputWithFallback{
+		alternatives: alternatives,
+		names:        names,
+		logger:       defaultFallbackLogger(),
+	}
+}
+
+// WithLogger sets the logger fallbacks are logged to.
+func (o *OutputWithFallback) WithLogger(logger *logging.Logger) *OutputWithFallback {
+	o.logger = logger
+	return o
+}
+
+func (o *OutputWithFallback) Send(ctx context.Context, data []byte) error {
+	op := func(i int) error {
+		return o.alternatives[i].Output.Send(ctx, data)
+	}
+	return fallback(ctx, o.logger, "output", o.names, op)
+}
+
+// Close closes every alternative.
+func (o *OutputWithFallback) Close() error {
+	var errs []error
+	for _, alt := range o.alternatives {
+		if closer, ok := alt.Output.(api.Closeable); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", alt.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
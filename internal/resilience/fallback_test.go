@@ -0,0 +1,242 @@
+package resilience_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+// failingProvider always fails with err and counts its calls.
+type failingProvider struct {
+	err    error
+	calls  int
+	closed bool
+}
+
+func (p *failingProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func (p *failingProvider) Close() error {
+	p.closed = true
+	return nil
+}
+
+// recordingOutput fails with err, if set, and keeps the data it was sent.
+type recordingOutput struct {
+	err      error
+	received []byte
+}
+
+func (o *recordingOutput) Send(ctx context.Context, data []byte) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.received = data
+	return nil
+}
+
+func TestProviderWithFallback_UsesFirstSuccess(t *testing.T) {
+	primary := &failingProvider{err: errors.New("connection refused")}
+	snapshot := &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 1}}}
+	unused := &failingProvider{}
+
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: primary},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: snapshot},
+		resilience.ProviderAlternative{Name: "unused", Provider: unused},
+	)
+
+	recorder := resilience.NewFallbackRecorder()
+	data, err := p.Fetch(resilience.WithFallbackRecorder(context.Background(), recorder))
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if len(data) != 1 || unused.calls != 0 {
+		t.Errorf("data = %v, unused calls = %d", data, unused.calls)
+	}
+
+	uses := recorder.Uses()
+	if len(uses) != 1 {
+		t.Fatalf("uses = %+v, want one", uses)
+	}
+	use := uses[0]
+	if use.Component != "provider" || use.Source != "snapshot" || !use.Fallback {
+		t.Errorf("use = %+v", use)
+	}
+	if len(use.Failures) != 1 || use.Failures[0] != "rest: connection refused" {
+		t.Errorf("failures = %v", use.Failures)
+	}
+}
+
+func TestProviderWithFallback_OpenCircuit(t *testing.T) {
+	cb := resilience.NewCircuitBreaker("rest", 1, time.Hour)
+	cb.RecordFailure()
+	primary := &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 1}}}
+
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: resilience.NewProviderWithCircuitBreaker(primary, cb)},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 2}}}},
+	)
+
+	data, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if primary.Attempts != 0 || data[0]["id"] != 2 {
+		t.Errorf("open circuit should fall back without calling the primary, got %v", data)
+	}
+}
+
+func TestProviderWithFallback_AllFail(t *testing.T) {
+	last := errors.New("snapshot missing")
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: &failingProvider{err: errors.New("timeout")}},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: &failingProvider{err: last}},
+	)
+
+	recorder := resilience.NewFallbackRecorder()
+	_, err := p.Fetch(resilience.WithFallbackRecorder(context.Background(), recorder))
+	if !errors.Is(err, last) {
+		t.Fatalf("error should wrap the last failure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "all 2 providers failed") {
+		t.Errorf("error = %v", err)
+	}
+	if len(recorder.Uses()) != 0 {
+		t.Errorf("failed calls should not be recorded, got %+v", recorder.Uses())
+	}
+}
+
+func TestProviderWithFallback_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fallback := &failingProvider{}
+
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: &failingProvider{err: context.Canceled}},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: fallback},
+	)
+
+	if _, err := p.Fetch(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if fallback.calls != 0 {
+		t.Error("a canceled call should not fall back")
+	}
+}
+
+// failingStreamer is a streaming provider whose stream fails to open.
+type failingStreamer struct {
+	failingProvider
+}
+
+func (p *failingStreamer) Stream(ctx context.Context) (provider.Iterator, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func TestProviderWithFallback_StreamFetchesNonStreaming(t *testing.T) {
+	p, ok := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: &failingStreamer{failingProvider{err: errors.New("down")}}},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 1}}}},
+		resilience.ProviderAlternative{Name: "csv", Provider: &streamingProvider{MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 2}}}}},
+	).(provider.StreamingProviderStrategy)
+	if !ok {
+		t.Fatal("expected a streaming primary to make the decorator stream")
+	}
+
+	recorder := resilience.NewFallbackRecorder()
+	iter, err := p.Stream(resilience.WithFallbackRecorder(context.Background(), recorder))
+	if err != nil || iter == nil {
+		t.Fatalf("Stream = %v, %v", iter, err)
+	}
+	if !iter.Next() || iter.Value()["id"] != 1 || iter.Next() {
+		t.Error("expected the records of the first fallback, in order, even though it cannot stream")
+	}
+	uses := recorder.Uses()
+	if len(uses) != 1 || uses[0].Source != "snapshot" || !uses[0].Fallback || len(uses[0].Failures) != 1 {
+		t.Errorf("uses = %+v", uses)
+	}
+}
+
+func TestProviderWithFallback_NonStreamingPrimary(t *testing.T) {
+	primary := &failingProvider{err: errors.New("connection refused")}
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "rest", Provider: primary},
+		resilience.ProviderAlternative{Name: "csv", Provider: &streamingProvider{MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 1}}}}},
+	)
+	if _, ok := p.(provider.StreamingProviderStrategy); ok {
+		t.Fatal("expected a primary that cannot stream to keep the decorator from streaming")
+	}
+
+	recorder := resilience.NewFallbackRecorder()
+	records, err := p.Fetch(resilience.WithFallbackRecorder(context.Background(), recorder))
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Fetch = %v, %v", records, err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("expected the primary to be tried first, got %d calls", primary.calls)
+	}
+	if uses := recorder.Uses(); len(uses) != 1 || uses[0].Source != "csv" || !uses[0].Fallback {
+		t.Errorf("uses = %+v", uses)
+	}
+}
+
+func TestProviderWithFallback_CloseClosesAll(t *testing.T) {
+	a, b := &failingProvider{}, &failingProvider{}
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "a", Provider: a},
+		resilience.ProviderAlternative{Name: "b", Provider: b},
+	).(*resilience.ProviderWithFallback)
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !a.closed || !b.closed {
+		t.Error("expected every alternative to be closed")
+	}
+}
+
+func TestOutputWithFallback(t *testing.T) {
+	sftp := &recordingOutput{err: errors.New("permission denied")}
+	spool := &recordingOutput{}
+	o := resilience.NewOutputWithFallback(
+		resilience.OutputAlternative{Name: "sftp", Output: sftp},
+		resilience.OutputAlternative{Name: "spool", Output: spool},
+	)
+
+	recorder := resilience.NewFallbackRecorder()
+	if err := o.Send(resilience.WithFallbackRecorder(context.Background(), recorder), []byte("report")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if string(spool.received) != "report" {
+		t.Errorf("spool received %q", spool.received)
+	}
+	if uses := recorder.Uses(); len(uses) != 1 || uses[0].Component != "output" || uses[0].Source != "spool" {
+		t.Errorf("uses = %+v", uses)
+	}
+}
+
+// incrementalMock is a provider with a watermark field.
+type incrementalMock struct {
+	MockProviderStrategy
+}
+
+func (p *incrementalMock) WatermarkField() string { return "updated_at" }
+
+func TestProviderWithFallback_WatermarkField(t *testing.T) {
+	p := resilience.NewProviderWithFallback(
+		resilience.ProviderAlternative{Name: "sql", Provider: &incrementalMock{}},
+		resilience.ProviderAlternative{Name: "snapshot", Provider: &MockProviderStrategy{}},
+	)
+	incremental, ok := p.(provider.IncrementalProvider)
+	if !ok || incremental.WatermarkField() != "updated_at" {
+		t.Errorf("expected the primary's watermark field, got %T", p)
+	}
+}
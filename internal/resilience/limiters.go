@@ -0,0 +1,88 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLimitMismatch is returned when a rate limiter or bulkhead is
+	// requested with limits other than those it was registered with.
+	ErrLimitMismatch = errors.New("limits differ from the registered ones")
+)
+
+// LimiterRegistry holds rate limiters and bulkheads by name, so engines
+// that name the same limiter share its quota.
+type LimiterRegistry struct {
+	mu        sync.Mutex
+	limiters  map[string]*RateLimiter
+	bulkheads map[string]*Bulkhead
+}
+
+// DefaultLimiters is the process-wide registry used by engines built from
+// configuration.
+var DefaultLimiters = NewLimiterRegistry()
+
+// NewLimiterRegistry creates an empty LimiterRegistry.
+func NewLimiterRegistry() *LimiterRegistry {
+	return &LimiterRegistry{
+		limiters:  make(map[string]*RateLimiter),
+		bulkheads: make(map[string]*Bulkhead),
+	}
+}
+
+// RateLimiter returns the rate limiter registered under name, creating it
+// on first use. Every user of a name must ask for the same limits; other
+// limits fail with ErrLimitMismatch and leave the limiter unchanged.
+func (r *LimiterRegistry) RateLimiter(name string, rate float64, burst int) (*RateLimiter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[name]; ok {
+		gotRate, gotBurst := l.Limit()
+		if gotRate != rate || gotBurst != max(burst, 1) {
+			return nil, fmt.Errorf("rate limit %q: %w: registered with rate %g and burst %d, requested rate %g and burst %d",
+				name, ErrLimitMismatch, gotRate, gotBurst, rate, burst)
+		}
+		return l, nil
+	}
+	l := NewRateLimiter(name, rate, burst)
+	r.limiters[name] = l
+	return l, nil
+}
+
+// Bulkhead returns the bulkhead registered under name, creating it on
+// first use. Every user of a name must ask for the same limits; other
+// limits fail with ErrLimitMismatch and leave the bulkhead unchanged.
+func (r *LimiterRegistry) Bulkhead(name string, maxConcurrent int, maxWait time.Duration) (*Bulkhead, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.bulkheads[name]; ok {
+		gotConcurrent, gotWait := b.Limit()
+		if gotConcurrent != max(maxConcurrent, 1) || gotWait != maxWait {
+			return nil, fmt.Errorf("bulkhead %q: %w: registered with max_concurrent %d and max_wait %s, requested max_concurrent %d and max_wait %s",
+				name, ErrLimitMismatch, gotConcurrent, gotWait, maxConcurrent, maxWait)
+		}
+		return b, nil
+	}
+	b := NewBulkhead(name, maxConcurrent, maxWait)
+	r.bulkheads[name] = b
+	return b, nil
+}
+
+// Clone returns a registry holding the limiters and bulkheads of r.
+// Names first used through the clone are not added to r, so it can check
+// configs against the registered limits without registering new ones.
+func (r *LimiterRegistry) Clone() *LimiterRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &LimiterRegistry{
+		limiters:  maps.Clone(r.limiters),
+		bulkheads: maps.Clone(r.bulkheads),
+	}
+}
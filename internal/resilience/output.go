@@ -4,36 +4,27 @@ import (
 	"context"
 
 	"github.com/AshishBagdane/go-report-engine/internal/output"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
-// OutputWithRetry wraps an OutputStrategy with retry logic.
+// OutputWithRetry retries the calls to an OutputStrategy that can safely
+// be repeated.
 type OutputWithRetry struct {
-	delegate output.OutputStrategy
-	retrier  *Retrier
+	retrier *Retrier
 }
 
-// NewOutputWithRetry creates a new OutputWithRetry decorator.
-func NewOutputWithRetry(delegate output.OutputStrategy, retrier *Retrier) *OutputWithRetry {
-	return &OutputWithRetry{
-		delegate: delegate,
-		retrier:  retrier,
-	}
-}
-
-// Send executes the delegate's Send method with retries.
-func (o *OutputWithRetry) Send(ctx context.Context, data []byte) error {
-	op := func(ctx context.Context) error {
-		return o.delegate.Send(ctx, data)
-	}
-
-	return o.retrier.Execute(ctx, op)
+// NewOutputWithRetry creates a new retry decorator. It keeps the optional
+// interfaces of delegate (see output.Decorate).
+func NewOutputWithRetry(delegate output.OutputStrategy, retrier *Retrier) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithRetry{retrier: retrier})
 }
 
-// Close delegates to the underlying output if it implements Closeable.
-func (o *OutputWithRetry) Close() error {
-	if closer, ok := o.delegate.(api.Closeable); ok {
-		return closer.Close()
+// Intercept retries Send, SendRecords and the opening of a stream. Chunk
+// writes and the end of a stream are not retried: the output may already
+// hold part of the data.
+func (o *OutputWithRetry) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	switch op {
+	case output.OpSend, output.OpSendRecords, output.OpInitialize, output.OpResume:
+		return o.retrier.Execute(ctx, call)
 	}
-	return nil
+	return call(ctx)
 }
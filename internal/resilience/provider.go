@@ -4,30 +4,26 @@ import (
 	"context"
 
 	"github.com/AshishBagdane/go-report-engine/internal/provider"
-	"github.com/AshishBagdane/go-report-engine/pkg/api"
 )
 
-// ProviderWithRetry wraps a ProviderStrategy with retry logic.
+// ProviderWithRetry retries the calls to a ProviderStrategy.
 type ProviderWithRetry struct {
-	delegate provider.ProviderStrategy
-	retrier  *Retrier
+	retrier *Retrier
 }
 
-// NewProviderWithRetry creates a new ProviderWithRetry decorator.
-func NewProviderWithRetry(delegate provider.ProviderStrategy, retrier *Retrier) *ProviderWithRetry {
-	return &ProviderWithRetry{
-		delegate: delegate,
-		retrier:  retrier,
-	}
+// NewProviderWithRetry creates a new retry decorator. It keeps the
+// optional interfaces of delegate (see provider.Decorate).
+func NewProviderWithRetry(delegate provider.ProviderStrategy, retrier *Retrier) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithRetry{retrier: retrier})
 }
 
-// Fetch executes the delegate's Fetch method with retries.
-func (p *ProviderWithRetry) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+// InterceptFetch executes fetch with retries.
+func (p *ProviderWithRetry) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
 	op := func(ctx context.Context) error {
 		var err error
-		results, err = p.delegate.Fetch(ctx)
+		results, err = fetch(ctx)
 		return err
 	}
 
@@ -35,23 +31,17 @@ func (p *ProviderWithRetry) Fetch(ctx context.Context) ([]map[string]interface{}
 	return results, err
 }
 
-// Stream executes the delegate's Stream method **without** retries for the stream itself.
-func (p *ProviderWithRetry) Stream(ctx context.Context) (provider.Iterator, error) {
-	if streamer, ok := p.delegate.(provider.StreamingProviderStrategy); ok {
-		return streamer.Stream(ctx)
-	}
-	// Return nil if not supported, or wrapped error?
-	// Usually the caller checks for interface compliance before calling, or we assume they know.
-	// But if we return nil iterator, caller might panic.
-	// Ideally we only expose Stream if delegate does.
-	// But struct embedding isn't dynamic.
-	return nil, nil // TODO: Proper error "Streaming not supported" or similar.
-}
+// InterceptStream retries opening the stream. Reading it is not retried:
+// the records already read may have been processed.
+func (p *ProviderWithRetry) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	var iter provider.Iterator
 
-// Close delegates cleanup if supported.
-func (p *ProviderWithRetry) Close() error {
-	if closer, ok := p.delegate.(api.Closeable); ok {
-		return closer.Close()
+	openOp := func(ctx context.Context) error {
+		var err error
+		iter, err = open(ctx)
+		return err
 	}
-	return nil
+
+	err := p.retrier.Execute(ctx, openOp)
+	return iter, err
 }
@@ -0,0 +1,112 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter implements a token bucket. Tokens are added at Rate per
+// second up to Burst, and each call takes one, waiting when none is left.
+type RateLimiter struct {
+	name string
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter allowing rate calls per second
+// with bursts of up to burst calls. The bucket starts full.
+func NewRateLimiter(name string, rate float64, burst int) *RateLimiter {
+	l := &RateLimiter{name: name, last: time.Now()}
+	l.SetLimit(rate, burst)
+	l.tokens = l.burst
+	return l
+}
+
+// SetLimit changes the rate and burst, keeping the tokens already in the
+// bucket up to the new burst. A rate of zero or less disables limiting;
+// a burst below 1 is treated as 1.
+func (l *RateLimiter) SetLimit(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	l.rate = rate
+	l.burst = float64(burst)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Limit returns the rate and burst.
+func (l *RateLimiter) Limit() (rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate, int(l.burst)
+}
+
+// Wait blocks until a token is available or ctx is done. Waiting callers
+// are served in arrival order.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	// Take a token now, going into debt if the bucket is empty; the debt
+	// is paid off by the time the delay has passed.
+	l.refill(time.Now())
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Allow takes a token if one is available, without waiting.
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return true
+	}
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// refill adds the tokens earned since the last call. Callers hold mu.
+func (l *RateLimiter) refill(now time.Time) {
+	if l.rate > 0 {
+		l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	}
+	l.last = now
+}
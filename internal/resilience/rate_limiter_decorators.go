@@ -0,0 +1,61 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+)
+
+// ProviderWithRateLimit takes a token from a RateLimiter before each call
+// to a ProviderStrategy.
+type ProviderWithRateLimit struct {
+	limiter *RateLimiter
+}
+
+// NewProviderWithRateLimit creates a new decorator. It keeps the optional
+// interfaces of delegate (see provider.Decorate).
+func NewProviderWithRateLimit(delegate provider.ProviderStrategy, limiter *RateLimiter) provider.ProviderStrategy {
+	return provider.Decorate(delegate, &ProviderWithRateLimit{limiter: limiter})
+}
+
+// InterceptFetch waits for a token, then fetches.
+func (p *ProviderWithRateLimit) InterceptFetch(ctx context.Context, fetch func(context.Context) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return fetch(ctx)
+}
+
+// InterceptStream waits for a token, then opens the stream. Only opening
+// the stream takes a token, not reading it.
+func (p *ProviderWithRateLimit) InterceptStream(ctx context.Context, op string, open func(context.Context) (provider.Iterator, error)) (provider.Iterator, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return open(ctx)
+}
+
+// OutputWithRateLimit takes a token from a RateLimiter before each
+// delivery to an OutputStrategy.
+type OutputWithRateLimit struct {
+	limiter *RateLimiter
+}
+
+// NewOutputWithRateLimit creates a new decorator. It keeps the optional
+// interfaces of delegate (see output.Decorate).
+func NewOutputWithRateLimit(delegate output.OutputStrategy, limiter *RateLimiter) output.OutputStrategy {
+	return output.Decorate(delegate, &OutputWithRateLimit{limiter: limiter})
+}
+
+// Intercept waits for a token before Send, SendRecords and the opening of
+// a stream. Chunk writes and the end of a stream do not take a token.
+func (o *OutputWithRateLimit) Intercept(ctx context.Context, op string, size int, call func(context.Context) error) error {
+	switch op {
+	case output.OpSend, output.OpSendRecords, output.OpInitialize, output.OpResume:
+		if err := o.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return call(ctx)
+}
@@ -0,0 +1,168 @@
+package resilience_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 1, 3)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("expected call %d of the burst to be allowed", i+1)
+		}
+	}
+	if l.Allow() {
+		t.Error("expected the call after the burst to be limited")
+	}
+}
+
+func TestRateLimiter_WaitPacesCalls(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 100, 1) // one call every 10ms
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected 5 calls to take about 40ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 0.1, 1)
+	l.Allow() // empty the bucket; the next token is 10s away
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 0, 1)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatal("expected a zero rate not to limit")
+		}
+	}
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 1, 1)
+	l.Allow()
+	if l.Allow() {
+		t.Fatal("expected the bucket to be empty")
+	}
+
+	l.SetLimit(1000, 1)
+	time.Sleep(5 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("expected the raised rate to refill the bucket")
+	}
+}
+
+func TestProviderWithRateLimit_SharedLimiter(t *testing.T) {
+	limiters := resilience.NewLimiterRegistry()
+	first := &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 1}}}
+	second := &MockProviderStrategy{SuccessContent: []map[string]interface{}{{"id": 2}}}
+
+	l1, err := limiters.RateLimiter("partner-api", 0.1, 1)
+	if err != nil {
+		t.Fatalf("RateLimiter() error = %v", err)
+	}
+	l2, err := limiters.RateLimiter("partner-api", 0.1, 1)
+	if err != nil {
+		t.Fatalf("RateLimiter() error = %v", err)
+	}
+	p1 := resilience.NewProviderWithRateLimit(first, l1)
+	p2 := resilience.NewProviderWithRateLimit(second, l2)
+
+	if _, err := p1.Fetch(context.Background()); err != nil {
+		t.Fatalf("first Fetch() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p2.Fetch(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the shared quota to be used up, got %v", err)
+	}
+	if second.Attempts != 0 {
+		t.Errorf("expected the limited provider not to be called, got %d calls", second.Attempts)
+	}
+}
+
+func TestLimiterRegistry_RejectsMismatchedLimits(t *testing.T) {
+	limiters := resilience.NewLimiterRegistry()
+	if _, err := limiters.RateLimiter("partner-api", 5, 2); err != nil {
+		t.Fatalf("RateLimiter() error = %v", err)
+	}
+	if _, err := limiters.RateLimiter("partner-api", 10, 2); !errors.Is(err, resilience.ErrLimitMismatch) {
+		t.Errorf("expected ErrLimitMismatch for another rate, got %v", err)
+	}
+	l, _ := limiters.RateLimiter("partner-api", 5, 2)
+	if rate, burst := l.Limit(); rate != 5 || burst != 2 {
+		t.Errorf("expected the registered limits to be kept, got rate %g and burst %d", rate, burst)
+	}
+
+	if _, err := limiters.Bulkhead("archive", 2, time.Second); err != nil {
+		t.Fatalf("Bulkhead() error = %v", err)
+	}
+	if _, err := limiters.Bulkhead("archive", 2, 0); !errors.Is(err, resilience.ErrLimitMismatch) {
+		t.Errorf("expected ErrLimitMismatch for another max_wait, got %v", err)
+	}
+}
+
+func TestLimiterRegistry_Clone(t *testing.T) {
+	limiters := resilience.NewLimiterRegistry()
+	shared, _ := limiters.RateLimiter("partner-api", 5, 2)
+
+	clone := limiters.Clone()
+	if l, err := clone.RateLimiter("partner-api", 5, 2); err != nil || l != shared {
+		t.Errorf("expected the clone to hold the registered limiter, got %p, %v", l, err)
+	}
+	if _, err := clone.RateLimiter("partner-api", 1, 2); !errors.Is(err, resilience.ErrLimitMismatch) {
+		t.Errorf("expected the clone to check the registered limits, got %v", err)
+	}
+
+	_, _ = clone.RateLimiter("reporting-db", 1, 1)
+	_, _ = clone.Bulkhead("archive", 1, 0)
+	if _, err := limiters.RateLimiter("reporting-db", 2, 1); err != nil {
+		t.Errorf("expected names used through the clone not to be registered, got %v", err)
+	}
+	if _, err := limiters.Bulkhead("archive", 3, 0); err != nil {
+		t.Errorf("expected names used through the clone not to be registered, got %v", err)
+	}
+}
+
+func TestOutputWithRateLimit_KeepsStreaming(t *testing.T) {
+	l := resilience.NewRateLimiter("test", 0.1, 1)
+	out, ok := resilience.NewOutputWithRateLimit(&streamingOutput{}, l).(output.StreamingOutputStrategy)
+	if !ok {
+		t.Fatal("expected the decorator to keep streaming")
+	}
+	ctx := context.Background()
+
+	if err := out.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	// Chunk writes do not take a token, so the empty bucket does not block
+	for i := 0; i < 3; i++ {
+		if err := out.WriteChunk(ctx, []byte("a")); err != nil {
+			t.Fatalf("WriteChunk() error = %v", err)
+		}
+	}
+	if l.Allow() {
+		t.Error("expected opening the stream to take the only token")
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"math"
 	"math/rand"
 	"time"
-
-	"github.com/AshishBagdane/go-report-engine/internal/errors"
 )
 
 // RetryPolicy defines the configuration for retrying operations.
@@ -16,15 +14,25 @@ type RetryPolicy struct {
 	MaxDelay   time.Duration
 	Factor     float64 // Multiplier for exponential backoff (e.g., 2.0)
 	Jitter     bool    // Whether to add random jitter
+
+	// MaxRetryAfter caps the delay a failing system may request (e.g.
+	// with an HTTP Retry-After header). Zero means MaxDelay.
+	MaxRetryAfter time.Duration
+
+	// Classifiers decide which errors are retried; the first classifier
+	// that recognizes an error wins and unrecognized errors are not
+	// retried. Nil means DefaultClassifiers().
+	Classifiers []ErrorClassifier
 }
 
 // DefaultRetryPolicy provides a sensible default for network operations.
 var DefaultRetryPolicy = RetryPolicy{
-	MaxRetries: 3,
-	BaseDelay:  100 * time.Millisecond,
-	MaxDelay:   2 * time.Second,
-	Factor:     2.0,
-	Jitter:     true,
+	MaxRetries:    3,
+	BaseDelay:     100 * time.Millisecond,
+	MaxDelay:      2 * time.Second,
+	Factor:        2.0,
+	Jitter:        true,
+	MaxRetryAfter: 30 * time.Second,
 }
 
 // Retrier executes operations with retry logic.
@@ -52,7 +60,8 @@ func (r *Retrier) Execute(ctx context.Context, op func(context.Context) error) e
 		}
 
 		// Check if we should retry
-		if !isRetriable(err) {
+		class := r.classify(err)
+		if !class.Retry {
 			return err
 		}
 
@@ -63,6 +72,9 @@ func (r *Retrier) Execute(ctx context.Context, op func(context.Context) error) e
 
 		// Calculate backoff
 		delay := r.calculateBackoff(attempt)
+		if class.After > delay {
+			delay = min(class.After, r.maxRetryAfter())
+		}
 		timer := time.NewTimer(delay)
 
 		select {
@@ -97,15 +109,25 @@ func (r *Retrier) calculateBackoff(attempt int) time.Duration {
 	return time.Duration(delay)
 }
 
-// isRetriable determines if an error is transient.
-func isRetriable(err error) bool {
-	// 1. Check if it implements our error interface
-	if errors.IsRetryable(err) {
-		return true
+// classify returns the decision of the first classifier that recognizes
+// err. Unrecognized errors are not retried.
+func (r *Retrier) classify(err error) Classification {
+	classifiers := r.Policy.Classifiers
+	if classifiers == nil {
+		classifiers = defaultClassifiers
 	}
+	for _, c := range classifiers {
+		if class, ok := c.Classify(err); ok {
+			return class
+		}
+	}
+	return Classification{}
+}
 
-	// 2. Unwrap to check chain
-	// For now, assume unknown errors are NOT retriable unless explicit.
-	// In a real system we might check `net.Error` temporary() here.
-	return false
+// maxRetryAfter returns the cap on requested retry delays.
+func (r *Retrier) maxRetryAfter() time.Duration {
+	if r.Policy.MaxRetryAfter > 0 {
+		return r.Policy.MaxRetryAfter
+	}
+	return r.Policy.MaxDelay
 }
@@ -0,0 +1,104 @@
+package resilience
+
+import (
+	"fmt"
+	"time"
+)
+
+// failureWindow counts call outcomes over a sliding window.
+type failureWindow interface {
+	record(now time.Time, failed bool)
+	counts(now time.Time) (calls, failures int)
+	reset()
+
+	// String describes the window, e.g. "last 20 calls".
+	String() string
+}
+
+// countWindow holds the outcomes of the last len(outcomes) calls.
+type countWindow struct {
+	outcomes []bool // true for a failure
+	next     int
+	filled   int
+	failures int
+}
+
+func (w *countWindow) record(now time.Time, failed bool) {
+	if w.filled == len(w.outcomes) {
+		if w.outcomes[w.next] {
+			w.failures--
+		}
+	} else {
+		w.filled++
+	}
+	w.outcomes[w.next] = failed
+	if failed {
+		w.failures++
+	}
+	w.next = (w.next + 1) % len(w.outcomes)
+}
+
+func (w *countWindow) counts(now time.Time) (calls, failures int) {
+	return w.filled, w.failures
+}
+
+func (w *countWindow) reset() {
+	w.next, w.filled, w.failures = 0, 0, 0
+}
+
+func (w *countWindow) String() string {
+	return fmt.Sprintf("last %d calls", len(w.outcomes))
+}
+
+// timeWindowBuckets is the number of buckets a time window is split into;
+// outcomes expire a bucket at a time.
+const timeWindowBuckets = 10
+
+// timeWindow counts the outcomes of the calls made in the last window,
+// in buckets of window/timeWindowBuckets.
+type timeWindow struct {
+	width   time.Duration
+	buckets [timeWindowBuckets]bucket
+}
+
+// bucket counts the outcomes of one slice of a time window.
+type bucket struct {
+	start    time.Time
+	calls    int
+	failures int
+}
+
+func newTimeWindow(window time.Duration) *timeWindow {
+	return &timeWindow{width: max(window/timeWindowBuckets, 1)}
+}
+
+func (w *timeWindow) String() string {
+	return fmt.Sprintf("last %s", w.width*timeWindowBuckets)
+}
+
+func (w *timeWindow) record(now time.Time, failed bool) {
+	start := now.Truncate(w.width)
+	b := &w.buckets[start.UnixNano()/int64(w.width)%timeWindowBuckets]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+}
+
+func (w *timeWindow) counts(now time.Time) (calls, failures int) {
+	oldest := now.Truncate(w.width).Add(-w.width * (timeWindowBuckets - 1))
+	for _, b := range w.buckets {
+		if !b.start.Before(oldest) {
+			calls += b.calls
+			failures += b.failures
+		}
+	}
+	return calls, failures
+}
+
+func (w *timeWindow) reset() {
+	w.buckets = [timeWindowBuckets]bucket{}
+}
@@ -0,0 +1,232 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes activation times for a job.
+type Schedule interface {
+	// Next returns the first activation time strictly after t, or the zero
+	// time if the schedule never fires again.
+	Next(t time.Time) time.Time
+}
+
+// descriptors maps the supported @-shortcuts to their cron equivalents.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a schedule specification.
+//
+// Supported forms:
+//   - Standard 5-field cron: "minute hour day-of-month month day-of-week",
+//     with "*", lists ("1,15"), ranges ("1-5"), steps ("*/15", "0-30/10")
+//     and month/weekday names ("jan", "mon"). Weekday 7 is Sunday.
+//   - Descriptors: @yearly, @annually, @monthly, @weekly, @daily,
+//     @midnight, @hourly
+//   - Fixed intervals: "@every 90s", "@every 1h30m"
+//
+// As in cron, when both day-of-month and day-of-week are restricted, a day
+// matches if either field matches.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("scheduler: empty schedule")
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: invalid @every interval in %q: %w", spec, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("scheduler: @every interval must be positive, got %s", d)
+		}
+		return everySchedule{interval: d}, nil
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("scheduler: unknown descriptor %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("scheduler: cron expression %q must have 5 fields, got %d", spec, len(fields))
+	}
+
+	var cs cronSchedule
+	var err error
+	if cs.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("scheduler: minute field: %w", err)
+	}
+	if cs.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("scheduler: hour field: %w", err)
+	}
+	if cs.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("scheduler: day-of-month field: %w", err)
+	}
+	if cs.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("scheduler: month field: %w", err)
+	}
+	if cs.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("scheduler: day-of-week field: %w", err)
+	}
+	// Fold Sunday-as-7 onto 0
+	if cs.dow&(1<<7) != 0 {
+		cs.dow = cs.dow&^(1<<7) | 1
+	}
+	// A field is restricted unless it allows every day, however written
+	// ("*", "*/1", "1-31" or "0-6")
+	cs.domRestricted = cs.dom != fieldMask(1, 31)
+	cs.dowRestricted = cs.dow != fieldMask(0, 6)
+
+	return cs, nil
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseField converts one cron field into a bitset of allowed values.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list element in %q", field)
+		}
+
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(bounds[1], names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := parseValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// "5/10" means starting at 5 through max, as in most crons
+			if step > 1 {
+				hi = max
+			} else {
+				hi = v
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range [%d-%d] in %q", min, max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// fieldMask returns the bitset of every value in [min, max].
+func fieldMask(min, max int) uint64 {
+	return (1<<uint(max+1) - 1) &^ (1<<uint(min) - 1)
+}
+
+// parseValue parses a number or a case-insensitive name.
+func parseValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// cronSchedule is a parsed 5-field cron expression stored as bitsets.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+// Next returns the next matching minute after t, in t's location.
+func (c cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	loc := t.Location()
+
+	// Bound the search; an expression such as "0 0 31 2 *" never matches
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day-of-month / day-of-week rules.
+func (c cronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// everySchedule fires at a fixed interval.
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next returns t plus the interval.
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(e.interval)
+}
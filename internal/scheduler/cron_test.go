@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, spec string) Schedule {
+	t.Helper()
+	s, err := ParseSchedule(spec)
+	if err != nil {
+		t.Fatalf("ParseSchedule(%q) error = %v", spec, err)
+	}
+	return s
+}
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday
+	base := time.Date(2024, time.January, 10, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 10, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"30 6 * * *", time.Date(2024, 1, 11, 6, 30, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2024, 1, 13, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2024, 1, 14, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2024, 1, 10, 10, 25, 0, 0, time.UTC)},
+		{"0 0-6/3 * * *", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match (the 15th, or a Friday)
+		{"0 0 15 * fri", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		// A day field that allows every day is not a restriction
+		{"0 0 1 * */1", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 0-6", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */1 * fri", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1-31 * sun", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got := mustParse(t, tt.spec).Next(base)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_NeverFires(t *testing.T) {
+	s := mustParse(t, "0 0 31 2 *")
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestParseSchedule_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	s := mustParse(t, "0 6 * * *")
+
+	got := s.Next(time.Date(2024, 1, 10, 5, 0, 0, 0, time.UTC).In(loc))
+	want := time.Date(2024, 1, 11, 6, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@fortnightly",
+		"@every",
+		"@every soon",
+		"@every -1m",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseSchedule(spec); err == nil {
+				t.Errorf("ParseSchedule(%q) should fail", spec)
+			}
+		})
+	}
+}
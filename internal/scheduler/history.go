@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+)
+
+// RunStatus is the lifecycle state of a scheduled run.
+type RunStatus string
+
+// Run statuses recorded in history.
+const (
+	RunStatusQueued    RunStatus = "queued"
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+	RunStatusSkipped   RunStatus = "skipped"
+	RunStatusCanceled  RunStatus = "canceled"
+)
+
+// Trigger sources for a run.
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// ErrRunNotFound is returned by History.Get for unknown run IDs.
+var ErrRunNotFound = errors.New("scheduler: run not found")
+
+// Run is one execution (or skipped execution) of a job.
+type Run struct {
+	ID          string        `json:"id"`
+	Job         string        `json:"job"`
+	Trigger     string        `json:"trigger"`
+	Status      RunStatus     `json:"status"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	StartedAt   time.Time     `json:"started_at,omitempty"`
+	FinishedAt  time.Time     `json:"finished_at,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+
+	// ConfigHash identifies the config the run used (see ConfigHash).
+	ConfigHash string `json:"config_hash,omitempty"`
+
+	// Result is the engine's report of what the run did. It is set once
+	// the engine has executed, whether or not it succeeded.
+	Result *engine.RunResult `json:"result,omitempty"`
+}
+
+// Done reports whether the run has reached a terminal status.
+func (r Run) Done() bool {
+	switch r.Status {
+	case RunStatusSucceeded, RunStatusFailed, RunStatusSkipped, RunStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ConfigHash returns a stable SHA-256 hash of cfg, so runs can be grouped
+// by the exact config they used.
+func ConfigHash(cfg engine.Config) string {
+	// encoding/json sorts map keys, so equal configs hash equally
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// History stores run records. Save is called every time a run changes
+// status, so implementations must upsert by Run.ID.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type History interface {
+	// Save inserts or replaces the run with the same ID.
+	Save(run Run) error
+
+	// Get returns the run with the given ID or ErrRunNotFound.
+	Get(id string) (Run, error)
+
+	// List returns runs for job (all jobs if empty), newest first.
+	// A limit <= 0 returns every stored run.
+	List(job string, limit int) ([]Run, error)
+}
+
+// DefaultHistoryLimit is the number of runs MemoryHistory keeps per job.
+const DefaultHistoryLimit = 100
+
+// MemoryHistory keeps the most recent runs of each job in memory.
+type MemoryHistory struct {
+	mu     sync.RWMutex
+	limit  int
+	runs   map[string]Run
+	order  map[string][]string // job -> run IDs, oldest first
+	serial map[string]int64    // run ID -> insertion order
+	next   int64
+}
+
+// NewMemoryHistory creates a history that retains up to limit runs per job.
+// A limit <= 0 uses DefaultHistoryLimit.
+func NewMemoryHistory(limit int) *MemoryHistory {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	return &MemoryHistory{
+		limit:  limit,
+		runs:   make(map[string]Run),
+		order:  make(map[string][]string),
+		serial: make(map[string]int64),
+	}
+}
+
+// Save inserts or updates a run, evicting the job's oldest run when full.
+func (h *MemoryHistory) Save(run Run) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.runs[run.ID]; !exists {
+		h.order[run.Job] = append(h.order[run.Job], run.ID)
+		h.serial[run.ID] = h.next
+		h.next++
+
+		if ids := h.order[run.Job]; len(ids) > h.limit {
+			evicted := ids[0]
+			h.order[run.Job] = ids[1:]
+			delete(h.runs, evicted)
+			delete(h.serial, evicted)
+		}
+	}
+	h.runs[run.ID] = run
+	return nil
+}
+
+// Get returns a run by ID.
+func (h *MemoryHistory) Get(id string) (Run, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	run, ok := h.runs[id]
+	if !ok {
+		return Run{}, ErrRunNotFound
+	}
+	return run, nil
+}
+
+// List returns runs newest first.
+func (h *MemoryHistory) List(job string, limit int) ([]Run, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var runs []Run
+	if job != "" {
+		for _, id := range h.order[job] {
+			runs = append(runs, h.runs[id])
+		}
+	} else {
+		for _, run := range h.runs {
+			runs = append(runs, run)
+		}
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return h.serial[runs[i].ID] > h.serial[runs[j].ID]
+	})
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
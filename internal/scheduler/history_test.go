@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+)
+
+func TestMemoryHistory_SaveAndGet(t *testing.T) {
+	h := NewMemoryHistory(0)
+
+	run := Run{ID: "r1", Job: "daily", Status: RunStatusRunning}
+	if err := h.Save(run); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	run.Status = RunStatusSucceeded
+	_ = h.Save(run)
+
+	got, err := h.Get("r1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != RunStatusSucceeded {
+		t.Errorf("Status = %s, want %s", got.Status, RunStatusSucceeded)
+	}
+
+	runs, _ := h.List("daily", 0)
+	if len(runs) != 1 {
+		t.Errorf("List() returned %d runs, want 1 (updates must not duplicate)", len(runs))
+	}
+
+	if _, err := h.Get("missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestMemoryHistory_ListOrderAndLimit(t *testing.T) {
+	h := NewMemoryHistory(3)
+
+	for i := 0; i < 5; i++ {
+		_ = h.Save(Run{ID: fmt.Sprintf("a%d", i), Job: "a"})
+	}
+	_ = h.Save(Run{ID: "b0", Job: "b"})
+
+	runs, _ := h.List("a", 0)
+	if len(runs) != 3 {
+		t.Fatalf("List(a) returned %d runs, want 3 (retention limit)", len(runs))
+	}
+	if runs[0].ID != "a4" || runs[2].ID != "a2" {
+		t.Errorf("List(a) order = %s..%s, want a4..a2", runs[0].ID, runs[2].ID)
+	}
+	if _, err := h.Get("a0"); !errors.Is(err, ErrRunNotFound) {
+		t.Error("evicted run should not be retrievable")
+	}
+
+	all, _ := h.List("", 2)
+	if len(all) != 2 || all[0].ID != "b0" || all[1].ID != "a4" {
+		t.Errorf("List(all, 2) = %v", all)
+	}
+}
+
+func TestRun_Done(t *testing.T) {
+	tests := []struct {
+		status RunStatus
+		want   bool
+	}{
+		{RunStatusQueued, false},
+		{RunStatusRunning, false},
+		{RunStatusSucceeded, true},
+		{RunStatusFailed, true},
+		{RunStatusSkipped, true},
+		{RunStatusCanceled, true},
+	}
+
+	for _, tt := range tests {
+		if got := (Run{Status: tt.status}).Done(); got != tt.want {
+			t.Errorf("Run{Status: %s}.Done() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	cfg := engine.Config{
+		Provider:  engine.ProviderConfig{Type: "csv", Params: map[string]string{"file_path": "a.csv", "delimiter": ","}},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "file", Params: map[string]string{"path": "out.json"}},
+	}
+	same := cfg
+	same.Provider.Params = map[string]string{"delimiter": ",", "file_path": "a.csv"}
+
+	if ConfigHash(cfg) == "" || ConfigHash(cfg) != ConfigHash(same) {
+		t.Errorf("equal configs must hash equally: %q vs %q", ConfigHash(cfg), ConfigHash(same))
+	}
+
+	changed := cfg
+	changed.Output = engine.OutputConfig{Type: "file", Params: map[string]string{"path": "other.json"}}
+	if ConfigHash(cfg) == ConfigHash(changed) {
+		t.Error("different configs must hash differently")
+	}
+}
@@ -0,0 +1,682 @@
+// Package scheduler runs report engines on recurring schedules.
+//
+// Jobs pair an engine.Config with a cron-style schedule. On every
+// activation the scheduler builds a fresh engine from the config, runs it
+// with Execute and records the outcome, including the engine's RunResult,
+// in a History.
+//
+// Example:
+//
+//	s := scheduler.New()
+//	err := s.Add(scheduler.Job{
+//	    Name:     "daily-sales",
+//	    Schedule: "30 6 * * mon-fri",
+//	    Jitter:   2 * time.Minute,
+//	    Overlap:  scheduler.OverlapSkip,
+//	    Config:   cfg,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	s.Start(ctx)
+//	defer s.Stop()
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/factory"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+)
+
+// OverlapPolicy decides what happens when a job is triggered while a
+// previous run of the same job is still in progress.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the new activation and records it as skipped.
+	OverlapSkip OverlapPolicy = "skip"
+
+	// OverlapQueue starts the new activation once the current run finishes.
+	// At most one activation is queued; further ones are recorded as skipped.
+	OverlapQueue OverlapPolicy = "queue"
+
+	// OverlapCancelPrevious cancels the current run and starts the new
+	// activation as soon as it has stopped.
+	OverlapCancelPrevious OverlapPolicy = "cancel_previous"
+)
+
+var (
+	// ErrStopped is returned when using a scheduler after Stop.
+	ErrStopped = errors.New("scheduler: stopped")
+
+	// ErrJobNotFound is returned when a job name is not registered.
+	ErrJobNotFound = errors.New("scheduler: job not found")
+)
+
+// EngineFactory builds the engine for a single run.
+type EngineFactory func(cfg engine.Config) (*engine.ReportEngine, error)
+
+// Job is a report definition with its schedule.
+type Job struct {
+	// Name uniquely identifies the job.
+	Name string
+
+	// Schedule is a cron expression or descriptor (see ParseSchedule).
+	// An empty schedule registers a manual-only job that runs via Trigger.
+	Schedule string
+
+	// Jitter delays each scheduled activation by a random duration in
+	// [0, Jitter) to spread load when many jobs share a schedule.
+	Jitter time.Duration
+
+	// Timeout bounds each run. Zero means no timeout.
+	Timeout time.Duration
+
+	// Overlap selects the overlap policy (default: OverlapSkip).
+	Overlap OverlapPolicy
+
+	// Config defines the report pipeline.
+	Config engine.Config
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithHistory sets the run history store (default: in-memory).
+func WithHistory(h History) Option {
+	return func(s *Scheduler) {
+		s.history = h
+	}
+}
+
+// WithEngineFactory overrides how engines are built from configs
+// (default: factory.NewEngineFromConfig).
+func WithEngineFactory(f EngineFactory) Option {
+	return func(s *Scheduler) {
+		s.newEngine = f
+	}
+}
+
+// WithLogger sets the scheduler logger.
+func WithLogger(l *logging.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = l
+	}
+}
+
+// WithLocation sets the time zone cron expressions are evaluated in
+// (default: time.Local).
+func WithLocation(loc *time.Location) Option {
+	return func(s *Scheduler) {
+		s.location = loc
+	}
+}
+
+// WithTracer traces every run: a "scheduler.run" span tagged with the
+// job, trigger and run ID wraps the engine's "report.run" span. Runs
+// started with TriggerContext are children of the span in that context.
+func WithTracer(t observability.Tracer) Option {
+	return func(s *Scheduler) {
+		s.tracer = t
+	}
+}
+
+// Scheduler triggers jobs on their schedules and applies overlap policies.
+//
+// Thread-safe: Yes. Jobs can be added, removed and triggered while the
+// scheduler is running.
+type Scheduler struct {
+	history   History
+	newEngine EngineFactory
+	logger    *logging.Logger
+	location  *time.Location
+	tracer    observability.Tracer
+
+	// ctx is the parent of every run; Stop cancels it
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	jobs     map[string]*jobState
+	loopCtx  context.Context
+	started  bool
+	stopped  bool
+	loops    sync.WaitGroup
+	inflight sync.WaitGroup
+
+	// unsaved holds runs waiting for the history writer, in the order
+	// their changes happened; wake signals the writer, which closes
+	// written when it exits after Stop
+	unsaved []Run
+	closing bool
+	wake    chan struct{}
+	written chan struct{}
+}
+
+// jobState tracks a job's schedule loop and in-progress runs.
+type jobState struct {
+	job      Job
+	schedule Schedule
+	stop     chan struct{}
+
+	active *activeRun
+	queued *Run
+	// queuedValues holds the trigger context of the queued run
+	queuedValues context.Context
+}
+
+// activeRun is a run in progress.
+type activeRun struct {
+	run        Run
+	job        Job // the job as it was when the run started
+	cancel     context.CancelFunc
+	superseded bool
+}
+
+// New creates a scheduler.
+func New(opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		history:   NewMemoryHistory(DefaultHistoryLimit),
+		newEngine: factory.NewEngineFromConfig,
+		location:  time.Local,
+		ctx:       ctx,
+		cancel:    cancel,
+		jobs:      make(map[string]*jobState),
+		wake:      make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.logger == nil {
+		s.logger = logging.NewLogger(logging.Config{
+			Level:     logging.LevelInfo,
+			Format:    logging.FormatJSON,
+			Component: "scheduler",
+		})
+	}
+	return s
+}
+
+// Add validates and registers a job. If the scheduler is running, the job
+// is scheduled immediately.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("scheduler: job name cannot be empty")
+	}
+	var schedule Schedule
+	if job.Schedule != "" {
+		parsed, err := ParseSchedule(job.Schedule)
+		if err != nil {
+			return fmt.Errorf("scheduler: job %q: %w", job.Name, err)
+		}
+		schedule = parsed
+	}
+	switch job.Overlap {
+	case "":
+		job.Overlap = OverlapSkip
+	case OverlapSkip, OverlapQueue, OverlapCancelPrevious:
+	default:
+		return fmt.Errorf("scheduler: job %q: unknown overlap policy %q", job.Name, job.Overlap)
+	}
+	if job.Jitter < 0 || job.Timeout < 0 {
+		return fmt.Errorf("scheduler: job %q: jitter and timeout must not be negative", job.Name)
+	}
+	if err := job.Config.Validate(); err != nil {
+		return fmt.Errorf("scheduler: job %q: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("scheduler: job %q already exists", job.Name)
+	}
+
+	st := &jobState{job: job, schedule: schedule, stop: make(chan struct{})}
+	s.jobs[job.Name] = st
+	if s.started {
+		s.startLoop(st)
+	}
+	return nil
+}
+
+// Remove unschedules a job. A run in progress is allowed to finish.
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	close(st.stop)
+	delete(s.jobs, name)
+	return nil
+}
+
+// UpdateConfigs replaces the config of the named jobs for their
+// subsequent runs, typically after a config file reload. Runs in progress
+// keep the engine they were started with.
+//
+// The update is all or nothing: if any config is invalid, no job is
+// changed. Configs naming no registered job are skipped with a warning,
+// since a job's schedule is not part of its config.
+func (s *Scheduler) UpdateConfigs(configs map[string]*engine.Config) error {
+	names := make([]string, 0, len(configs))
+	for name, cfg := range configs {
+		if cfg == nil {
+			return fmt.Errorf("scheduler: job %q: config is nil", name)
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("scheduler: job %q: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	for _, name := range names {
+		st, ok := s.jobs[name]
+		if !ok {
+			s.logger.Warn("config update skipped: no such job", "job", name)
+			continue
+		}
+		st.job.Config = *configs[name]
+	}
+	s.logger.Info("job configs updated", "jobs", len(names))
+	return nil
+}
+
+// Jobs returns the registered jobs sorted by name.
+func (s *Scheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, st := range s.jobs {
+		jobs = append(jobs, st.job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// History returns the run history store.
+func (s *Scheduler) History() History {
+	return s.history
+}
+
+// Start begins scheduling all jobs. Scheduling stops when ctx is canceled
+// or Stop is called; runs already in progress are only canceled by Stop.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	if s.started {
+		return fmt.Errorf("scheduler: already started")
+	}
+	s.started = true
+	s.loopCtx = ctx
+
+	for _, st := range s.jobs {
+		s.startLoop(st)
+	}
+	s.logger.Info("scheduler started", "jobs", len(s.jobs))
+	return nil
+}
+
+// Stop stops scheduling, cancels runs in progress and waits for them to
+// record their final status. Stop is idempotent.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	for _, st := range s.jobs {
+		close(st.stop)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+	s.loops.Wait()
+	s.inflight.Wait()
+
+	// Every run has its final status now; wait for history to record it
+	s.mu.Lock()
+	s.closing = true
+	written := s.written
+	s.mu.Unlock()
+	if written != nil {
+		s.wakeWriter()
+		<-written
+	}
+	s.logger.Info("scheduler stopped")
+}
+
+// Trigger starts a manual run of the named job, applying its overlap
+// policy. It returns immediately with the run, whose status is running,
+// queued or skipped; history records it in the background.
+func (s *Scheduler) Trigger(name string) (Run, error) {
+	return s.TriggerContext(context.Background(), name)
+}
+
+// TriggerContext is like Trigger, but the run sees the values of ctx,
+// such as the trace span of the request that triggered it. The run is
+// not canceled with ctx: it outlives the request and is only canceled by
+// its timeout, its overlap policy or Stop.
+func (s *Scheduler) TriggerContext(ctx context.Context, name string) (Run, error) {
+	s.mu.Lock()
+	st, ok := s.jobs[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return Run{}, fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	return s.trigger(context.WithoutCancel(ctx), st, time.Now(), TriggerManual)
+}
+
+// startLoop launches the schedule loop for a job. Manual-only jobs have
+// no loop. Caller holds mu.
+func (s *Scheduler) startLoop(st *jobState) {
+	if st.schedule == nil {
+		return
+	}
+	s.loops.Add(1)
+	go s.loop(s.loopCtx, st)
+}
+
+// loop waits for each activation of the job's schedule and triggers it.
+func (s *Scheduler) loop(ctx context.Context, st *jobState) {
+	defer s.loops.Done()
+
+	for {
+		next := st.schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			s.logger.Warn("schedule has no future activations", "job", st.job.Name)
+			return
+		}
+		fireAt := next.Add(jitter(st.job.Jitter))
+
+		timer := time.NewTimer(time.Until(fireAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-st.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := s.trigger(context.Background(), st, next, TriggerSchedule); err != nil {
+				s.logger.Warn("scheduled trigger failed", "job", st.job.Name, "error", err)
+			}
+		}
+	}
+}
+
+// trigger applies the overlap policy and starts, queues or skips a run.
+// The run sees the values of values.
+func (s *Scheduler) trigger(values context.Context, st *jobState, scheduledAt time.Time, trigger string) (Run, error) {
+	run := Run{
+		ID:          newRunID(),
+		Job:         st.job.Name,
+		Trigger:     trigger,
+		ScheduledAt: scheduledAt,
+	}
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return Run{}, ErrStopped
+	}
+
+	if st.active == nil {
+		run.Status = RunStatusRunning
+		run.StartedAt = time.Now()
+		s.startRun(st, run, values)
+		s.mu.Unlock()
+		return run, nil
+	}
+
+	switch st.job.Overlap {
+	case OverlapQueue:
+		if st.queued == nil {
+			run.Status = RunStatusQueued
+			st.queued = &run
+			st.queuedValues = values
+			s.save(run)
+		} else {
+			s.finishUnstarted(run, RunStatusSkipped)
+		}
+	case OverlapCancelPrevious:
+		st.active.superseded = true
+		st.active.cancel()
+		if st.queued != nil {
+			s.finishUnstarted(*st.queued, RunStatusSkipped)
+		}
+		run.Status = RunStatusQueued
+		st.queued = &run
+		st.queuedValues = values
+		s.save(run)
+	default:
+		s.finishUnstarted(run, RunStatusSkipped)
+	}
+	s.mu.Unlock()
+
+	if run.Status == "" {
+		run.Status = RunStatusSkipped
+		s.logger.Info("run skipped: previous run still in progress", "job", run.Job, "run_id", run.ID)
+	}
+	return run, nil
+}
+
+// finishUnstarted records a run that never executed. Caller holds mu.
+func (s *Scheduler) finishUnstarted(run Run, status RunStatus) {
+	run.Status = status
+	run.FinishedAt = time.Now()
+	s.save(run)
+}
+
+// startRun records the run as running and executes it with the values of
+// values. Caller holds mu.
+func (s *Scheduler) startRun(st *jobState, run Run, values context.Context) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if st.job.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(s.ctx, st.job.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(s.ctx)
+	}
+	ctx = runContext{Context: ctx, values: values}
+	run.ConfigHash = ConfigHash(st.job.Config)
+	active := &activeRun{run: run, job: st.job, cancel: cancel}
+	st.active = active
+
+	s.save(run)
+	s.inflight.Add(1)
+	go s.execute(ctx, st, active)
+}
+
+// execute runs the engine and records the outcome, then starts any queued run.
+func (s *Scheduler) execute(ctx context.Context, st *jobState, active *activeRun) {
+	defer s.inflight.Done()
+	defer active.cancel()
+
+	run := active.run
+	ctx = logging.WithRequestID(ctx, run.ID)
+	s.logger.InfoContext(ctx, "run started", "job", run.Job, "trigger", run.Trigger)
+
+	result, err := s.runEngine(ctx, active.job, run.Trigger)
+
+	run.FinishedAt = time.Now()
+	run.Duration = run.FinishedAt.Sub(run.StartedAt)
+	run.Result = result
+
+	s.mu.Lock()
+	switch {
+	case err == nil:
+		run.Status = RunStatusSucceeded
+	case active.superseded || s.ctx.Err() != nil:
+		run.Status = RunStatusCanceled
+		run.Error = err.Error()
+	default:
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+	}
+	s.save(run)
+
+	st.active = nil
+	if st.queued != nil {
+		next, values := *st.queued, st.queuedValues
+		st.queued, st.queuedValues = nil, nil
+		if s.stopped {
+			s.finishUnstarted(next, RunStatusCanceled)
+		} else {
+			next.Status = RunStatusRunning
+			next.StartedAt = time.Now()
+			s.startRun(st, next, values)
+		}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "run finished", "job", run.Job, "status", run.Status,
+			"duration_ms", run.Duration.Milliseconds(), "error", err)
+	} else {
+		s.logger.InfoContext(ctx, "run finished", "job", run.Job, "status", run.Status,
+			"duration_ms", run.Duration.Milliseconds())
+	}
+}
+
+// runEngine builds a fresh engine for the config, runs it and closes it.
+// The result is nil if the engine could not be built.
+func (s *Scheduler) runEngine(ctx context.Context, job Job, trigger string) (*engine.RunResult, error) {
+	eng, err := s.newEngine(job.Config)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: failed to build engine: %w", err)
+	}
+	defer func() {
+		if err := eng.CloseWithContext(context.WithoutCancel(ctx)); err != nil {
+			s.logger.WarnContext(ctx, "engine cleanup failed", "error", err)
+		}
+	}()
+
+	if s.tracer == nil {
+		return eng.Execute(ctx)
+	}
+
+	ctx, span := s.tracer.StartSpan(ctx, "scheduler.run")
+	defer span.End()
+	span.SetTag("job", job.Name)
+	span.SetTag("trigger", trigger)
+	span.SetTag("run_id", logging.GetRequestID(ctx))
+	eng.WithTracer(s.tracer)
+
+	result, err := eng.Execute(ctx)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+// runContext is a run's context: cancellation comes from the embedded
+// context, while values are looked up in the trigger context first.
+type runContext struct {
+	context.Context
+	values context.Context
+}
+
+// Value returns the value for key from the trigger context, or from the
+// run context if the trigger context has none.
+func (c runContext) Value(key any) any {
+	if v := c.values.Value(key); v != nil {
+		return v
+	}
+	return c.Context.Value(key)
+}
+
+// save queues a run to be written to history. A single writer saves runs
+// in the order they were queued, so a slow History never holds up the
+// scheduler while it holds mu. Caller holds mu.
+func (s *Scheduler) save(run Run) {
+	s.unsaved = append(s.unsaved, run)
+	if s.written == nil {
+		s.written = make(chan struct{})
+		go s.writeHistory()
+	}
+	s.wakeWriter()
+}
+
+// wakeWriter signals the history writer that there is work to do.
+func (s *Scheduler) wakeWriter() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// writeHistory saves queued runs until Stop, logging (not failing) on
+// error.
+func (s *Scheduler) writeHistory() {
+	defer close(s.written)
+	for range s.wake {
+		s.mu.Lock()
+		runs, closing := s.unsaved, s.closing
+		s.unsaved = nil
+		s.mu.Unlock()
+
+		for _, run := range runs {
+			if err := s.history.Save(run); err != nil {
+				s.logger.Warn("failed to record run", "job", run.Job, "run_id", run.ID, "error", err)
+			}
+		}
+		if closing {
+			return
+		}
+	}
+}
+
+// jitter returns a random delay in [0, max).
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(mathrand.Int64N(int64(max)))
+}
+
+// newRunID returns a random run identifier.
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
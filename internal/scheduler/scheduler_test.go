@@ -0,0 +1,623 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+)
+
+// testConfig is a valid config; the engine itself comes from testEngines.
+var testConfig = engine.Config{
+	Provider:  engine.ProviderConfig{Type: "mock"},
+	Formatter: engine.FormatterConfig{Type: "json"},
+	Output:    engine.OutputConfig{Type: "console"},
+}
+
+// gatedProvider blocks each Fetch until released or canceled.
+type gatedProvider struct {
+	gate    chan struct{}
+	started chan struct{}
+	err     error
+}
+
+func (p *gatedProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	p.started <- struct{}{}
+	if p.gate != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-p.gate:
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []map[string]interface{}{{"id": 1}}, nil
+}
+
+// countingOutput counts delivered reports.
+type countingOutput struct {
+	sent atomic.Int32
+}
+
+func (o *countingOutput) Send(ctx context.Context, data []byte) error {
+	o.sent.Add(1)
+	return nil
+}
+
+// testEngines builds engines sharing one gated provider and counting output.
+type testEngines struct {
+	provider *gatedProvider
+	output   *countingOutput
+}
+
+func newTestEngines(gated bool) *testEngines {
+	p := &gatedProvider{started: make(chan struct{}, 100)}
+	if gated {
+		p.gate = make(chan struct{})
+	}
+	return &testEngines{provider: p, output: &countingOutput{}}
+}
+
+func (e *testEngines) factory(cfg engine.Config) (*engine.ReportEngine, error) {
+	eng := &engine.ReportEngine{
+		Provider:  e.provider,
+		Processor: &processor.BaseProcessor{},
+		Formatter: formatter.NewJSONFormatter(""),
+		Output:    e.output,
+	}
+	return eng.WithLogger(quietLogger()), nil
+}
+
+// release lets one blocked Fetch complete.
+func (e *testEngines) release() {
+	e.provider.gate <- struct{}{}
+}
+
+// waitStarted waits for a Fetch to begin.
+func (e *testEngines) waitStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case <-e.provider.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for run to start")
+	}
+}
+
+func quietLogger() *logging.Logger {
+	return logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText})
+}
+
+func newTestScheduler(e *testEngines) *Scheduler {
+	return New(WithEngineFactory(e.factory), WithLogger(quietLogger()))
+}
+
+// waitForStatus polls history until the run reaches status.
+func waitForStatus(t *testing.T, s *Scheduler, id string, status RunStatus) Run {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		run, err := s.History().Get(id)
+		if err == nil && run.Status == status {
+			return run
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	run, _ := s.History().Get(id)
+	t.Fatalf("run %s status = %s, want %s", id, run.Status, status)
+	return Run{}
+}
+
+func TestScheduler_RunsOnSchedule(t *testing.T) {
+	e := newTestEngines(false)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	if err := s.Add(Job{Name: "fast", Schedule: "@every 20ms", Config: testConfig}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for e.output.sent.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := e.output.sent.Load(); got < 2 {
+		t.Fatalf("job ran %d times, want at least 2", got)
+	}
+
+	// Stop waits for history to record the runs
+	s.Stop()
+	runs, _ := s.History().List("fast", 0)
+	var succeeded int
+	for _, run := range runs {
+		if run.Status == RunStatusSucceeded {
+			succeeded++
+			if run.Trigger != TriggerSchedule || run.FinishedAt.Before(run.StartedAt) {
+				t.Errorf("unexpected run record: %+v", run)
+			}
+		}
+	}
+	if succeeded < 2 {
+		t.Errorf("history has %d succeeded runs, want at least 2", succeeded)
+	}
+}
+
+// slowHistory is a History whose saves block until released.
+type slowHistory struct {
+	*MemoryHistory
+	release chan struct{}
+}
+
+func (h *slowHistory) Save(run Run) error {
+	<-h.release
+	return h.MemoryHistory.Save(run)
+}
+
+func TestScheduler_SlowHistoryDoesNotBlock(t *testing.T) {
+	e := newTestEngines(false)
+	h := &slowHistory{MemoryHistory: NewMemoryHistory(0), release: make(chan struct{})}
+	s := New(WithEngineFactory(e.factory), WithLogger(quietLogger()), WithHistory(h))
+
+	_ = s.Add(Job{Name: "sales", Config: testConfig})
+	_ = s.Add(Job{Name: "finance", Config: testConfig})
+	_ = s.Start(context.Background())
+
+	done := make(chan []Run)
+	go func() {
+		var runs []Run
+		for _, name := range []string{"sales", "finance", "sales"} {
+			run, err := s.Trigger(name)
+			if err != nil {
+				t.Errorf("Trigger(%s) error = %v", name, err)
+			}
+			runs = append(runs, run)
+			time.Sleep(20 * time.Millisecond)
+		}
+		_ = s.Jobs()
+		done <- runs
+	}()
+
+	var runs []Run
+	select {
+	case runs = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Trigger and Jobs blocked on a slow history")
+	}
+
+	close(h.release)
+	s.Stop()
+	for _, run := range runs {
+		if got, err := h.Get(run.ID); err != nil || got.Status != RunStatusSucceeded {
+			t.Errorf("run %s = %s, %v; want succeeded once history catches up", run.ID, got.Status, err)
+		}
+	}
+}
+
+func TestScheduler_OverlapSkip(t *testing.T) {
+	e := newTestEngines(true)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "slow", Schedule: "@daily", Config: testConfig})
+
+	first, err := s.Trigger("slow")
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if first.Status != RunStatusRunning || first.Trigger != TriggerManual {
+		t.Errorf("first run = %+v", first)
+	}
+	e.waitStarted(t)
+
+	second, _ := s.Trigger("slow")
+	if second.Status != RunStatusSkipped {
+		t.Errorf("second run status = %s, want %s", second.Status, RunStatusSkipped)
+	}
+	waitForStatus(t, s, second.ID, RunStatusSkipped)
+
+	e.release()
+	done := waitForStatus(t, s, first.ID, RunStatusSucceeded)
+	if done.Duration <= 0 {
+		t.Errorf("Duration = %v, want > 0", done.Duration)
+	}
+}
+
+func TestScheduler_OverlapQueue(t *testing.T) {
+	e := newTestEngines(true)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "slow", Schedule: "@daily", Overlap: OverlapQueue, Config: testConfig})
+
+	first, _ := s.Trigger("slow")
+	e.waitStarted(t)
+
+	second, _ := s.Trigger("slow")
+	third, _ := s.Trigger("slow")
+	if second.Status != RunStatusQueued {
+		t.Errorf("second run status = %s, want %s", second.Status, RunStatusQueued)
+	}
+	if third.Status != RunStatusSkipped {
+		t.Errorf("third run status = %s, want %s", third.Status, RunStatusSkipped)
+	}
+
+	e.release()
+	waitForStatus(t, s, first.ID, RunStatusSucceeded)
+
+	e.waitStarted(t)
+	e.release()
+	waitForStatus(t, s, second.ID, RunStatusSucceeded)
+
+	if got := e.output.sent.Load(); got != 2 {
+		t.Errorf("job ran %d times, want 2", got)
+	}
+}
+
+func TestScheduler_OverlapCancelPrevious(t *testing.T) {
+	e := newTestEngines(true)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "slow", Schedule: "@daily", Overlap: OverlapCancelPrevious, Config: testConfig})
+
+	first, _ := s.Trigger("slow")
+	e.waitStarted(t)
+
+	second, _ := s.Trigger("slow")
+	canceled := waitForStatus(t, s, first.ID, RunStatusCanceled)
+	if canceled.Error == "" {
+		t.Error("canceled run should record the cancellation error")
+	}
+
+	e.waitStarted(t)
+	e.release()
+	waitForStatus(t, s, second.ID, RunStatusSucceeded)
+}
+
+func TestScheduler_RunFailure(t *testing.T) {
+	e := newTestEngines(false)
+	e.provider.err = errors.New("source unavailable")
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "broken", Schedule: "@daily", Config: testConfig})
+
+	run, _ := s.Trigger("broken")
+	failed := waitForStatus(t, s, run.ID, RunStatusFailed)
+	if failed.Error == "" {
+		t.Error("failed run should record the error")
+	}
+}
+
+func TestScheduler_RunTimeout(t *testing.T) {
+	e := newTestEngines(true)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "slow", Schedule: "@daily", Timeout: 20 * time.Millisecond, Config: testConfig})
+
+	run, _ := s.Trigger("slow")
+	waitForStatus(t, s, run.ID, RunStatusFailed)
+}
+
+func TestScheduler_EngineBuildFailure(t *testing.T) {
+	s := New(
+		WithLogger(quietLogger()),
+		WithEngineFactory(func(cfg engine.Config) (*engine.ReportEngine, error) {
+			return nil, errors.New("unknown provider")
+		}),
+	)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "bad", Schedule: "@daily", Config: testConfig})
+
+	run, _ := s.Trigger("bad")
+	waitForStatus(t, s, run.ID, RunStatusFailed)
+}
+
+func TestScheduler_StopCancelsRuns(t *testing.T) {
+	e := newTestEngines(true)
+	s := newTestScheduler(e)
+
+	_ = s.Add(Job{Name: "slow", Schedule: "@daily", Overlap: OverlapQueue, Config: testConfig})
+	_ = s.Start(context.Background())
+
+	first, _ := s.Trigger("slow")
+	e.waitStarted(t)
+	second, _ := s.Trigger("slow")
+
+	s.Stop()
+
+	if run, _ := s.History().Get(first.ID); run.Status != RunStatusCanceled {
+		t.Errorf("in-flight run status = %s, want %s", run.Status, RunStatusCanceled)
+	}
+	if run, _ := s.History().Get(second.ID); run.Status != RunStatusCanceled {
+		t.Errorf("queued run status = %s, want %s", run.Status, RunStatusCanceled)
+	}
+
+	if _, err := s.Trigger("slow"); !errors.Is(err, ErrStopped) {
+		t.Errorf("Trigger() after Stop error = %v, want ErrStopped", err)
+	}
+	if err := s.Start(context.Background()); !errors.Is(err, ErrStopped) {
+		t.Errorf("Start() after Stop error = %v, want ErrStopped", err)
+	}
+	if err := s.Add(Job{Name: "late", Schedule: "@daily", Config: testConfig}); !errors.Is(err, ErrStopped) {
+		t.Errorf("Add() after Stop error = %v, want ErrStopped", err)
+	}
+
+	// Stop is idempotent
+	s.Stop()
+}
+
+func TestScheduler_StartContextStopsScheduling(t *testing.T) {
+	e := newTestEngines(false)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "fast", Schedule: "@every 10ms", Config: testConfig})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = s.Start(ctx)
+	cancel()
+
+	time.Sleep(50 * time.Millisecond)
+	before := e.output.sent.Load()
+	time.Sleep(50 * time.Millisecond)
+	if after := e.output.sent.Load(); after != before {
+		t.Errorf("job kept running after context cancel: %d -> %d", before, after)
+	}
+}
+
+func TestScheduler_AddValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{name: "missing name", job: Job{Schedule: "@daily", Config: testConfig}},
+		{name: "bad schedule", job: Job{Name: "j", Schedule: "every day", Config: testConfig}},
+		{name: "bad overlap", job: Job{Name: "j", Schedule: "@daily", Overlap: "parallel", Config: testConfig}},
+		{name: "negative jitter", job: Job{Name: "j", Schedule: "@daily", Jitter: -time.Second, Config: testConfig}},
+		{name: "invalid config", job: Job{Name: "j", Schedule: "@daily"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(WithLogger(quietLogger()))
+			defer s.Stop()
+			if err := s.Add(tt.job); err == nil {
+				t.Error("Add() should fail")
+			}
+		})
+	}
+}
+
+func TestScheduler_JobManagement(t *testing.T) {
+	s := New(WithLogger(quietLogger()))
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "b", Schedule: "@daily", Config: testConfig})
+	_ = s.Add(Job{Name: "a", Schedule: "@hourly", Config: testConfig})
+
+	if err := s.Add(Job{Name: "a", Schedule: "@daily", Config: testConfig}); err == nil {
+		t.Error("Add() should reject duplicate job names")
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 2 || jobs[0].Name != "a" || jobs[1].Overlap != OverlapSkip {
+		t.Errorf("Jobs() = %+v", jobs)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Error("second Start() should fail")
+	}
+
+	if err := s.Remove("a"); err != nil {
+		t.Errorf("Remove() error = %v", err)
+	}
+	if err := s.Remove("a"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Remove() of unknown job error = %v, want ErrJobNotFound", err)
+	}
+	if _, err := s.Trigger("a"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Trigger() of unknown job error = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestScheduler_ManualOnlyJob(t *testing.T) {
+	e := newTestEngines(false)
+	s := newTestScheduler(e)
+	defer s.Stop()
+
+	if err := s.Add(Job{Name: "adhoc", Config: testConfig}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	run, err := s.Trigger("adhoc")
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	done := waitForStatus(t, s, run.ID, RunStatusSucceeded)
+	if got := e.output.sent.Load(); got != 1 {
+		t.Errorf("output sent %d reports, want 1", got)
+	}
+
+	// The engine result is recorded with the run, under the same ID
+	if done.Result == nil {
+		t.Fatal("run result not recorded")
+	}
+	if done.Result.RunID != run.ID || done.Result.RecordsIn != 1 || !done.Result.Succeeded() {
+		t.Errorf("Result = %+v", done.Result)
+	}
+}
+
+func TestScheduler_RecordsConfigHash(t *testing.T) {
+	e := newTestEngines(false)
+	s := New(WithEngineFactory(e.factory), WithLogger(quietLogger()))
+	defer s.Stop()
+
+	if err := s.Add(Job{Name: "finance", Config: testConfig}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	run, err := s.Trigger("finance")
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	waitForStatus(t, s, run.ID, RunStatusSucceeded)
+
+	got, err := s.History().Get(run.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.ConfigHash == "" || got.ConfigHash != ConfigHash(testConfig) || got.Result.RunID != run.ID {
+		t.Errorf("run = %+v", got)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	if jitter(0) != 0 {
+		t.Error("jitter(0) should be 0")
+	}
+	for i := 0; i < 100; i++ {
+		if d := jitter(time.Second); d < 0 || d >= time.Second {
+			t.Fatalf("jitter() = %v, want [0, 1s)", d)
+		}
+	}
+}
+
+func TestScheduler_UpdateConfigs(t *testing.T) {
+	e := newTestEngines(true)
+
+	// Record the config each run's engine was built from
+	built := make(chan engine.Config, 10)
+	s := New(WithLogger(quietLogger()), WithEngineFactory(func(cfg engine.Config) (*engine.ReportEngine, error) {
+		built <- cfg
+		return e.factory(cfg)
+	}))
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "daily", Schedule: "@daily", Config: testConfig})
+
+	first, _ := s.Trigger("daily")
+	e.waitStarted(t)
+
+	updated := testConfig
+	updated.Output = engine.OutputConfig{Type: "file", Params: map[string]string{"path": "out.json"}}
+	err := s.UpdateConfigs(map[string]*engine.Config{"daily": &updated, "unknown": &updated})
+	if err != nil {
+		t.Fatalf("UpdateConfigs() error = %v", err)
+	}
+
+	// An invalid config rejects the whole update
+	invalid := testConfig
+	invalid.Provider.Type = ""
+	if err := s.UpdateConfigs(map[string]*engine.Config{"daily": &invalid}); err == nil {
+		t.Error("UpdateConfigs() should reject an invalid config")
+	}
+	if err := s.UpdateConfigs(map[string]*engine.Config{"daily": nil}); err == nil {
+		t.Error("UpdateConfigs() should reject a nil config")
+	}
+	if got := s.Jobs()[0].Config.Output.Type; got != "file" {
+		t.Errorf("job output type = %q, want the last valid update", got)
+	}
+
+	// The run in progress keeps the config it started with
+	e.release()
+	waitForStatus(t, s, first.ID, RunStatusSucceeded)
+	if cfg := <-built; cfg.Output.Type != "console" {
+		t.Errorf("in-flight run built from output %q, want console", cfg.Output.Type)
+	}
+
+	second, _ := s.Trigger("daily")
+	e.waitStarted(t)
+	e.release()
+	waitForStatus(t, s, second.ID, RunStatusSucceeded)
+	if cfg := <-built; cfg.Output.Type != "file" {
+		t.Errorf("next run built from output %q, want file", cfg.Output.Type)
+	}
+
+	s.Stop()
+	if err := s.UpdateConfigs(map[string]*engine.Config{"daily": &updated}); !errors.Is(err, ErrStopped) {
+		t.Errorf("UpdateConfigs() after Stop error = %v, want ErrStopped", err)
+	}
+}
+
+type traceKey struct{}
+
+// valueProvider wraps a gatedProvider and records the trace value of each
+// Fetch context.
+type valueProvider struct {
+	*gatedProvider
+	values chan interface{}
+}
+
+func (p *valueProvider) Fetch(ctx context.Context) ([]map[string]interface{}, error) {
+	p.values <- ctx.Value(traceKey{})
+	return p.gatedProvider.Fetch(ctx)
+}
+
+func TestScheduler_TriggerContext(t *testing.T) {
+	e := newTestEngines(true)
+	p := &valueProvider{gatedProvider: e.provider, values: make(chan interface{}, 10)}
+	s := New(WithLogger(quietLogger()), WithEngineFactory(func(cfg engine.Config) (*engine.ReportEngine, error) {
+		eng, _ := e.factory(cfg)
+		eng.Provider = p
+		return eng, nil
+	}))
+	defer s.Stop()
+
+	_ = s.Add(Job{Name: "slow", Overlap: OverlapQueue, Config: testConfig})
+
+	// The trigger context is canceled as soon as the request ends; the run
+	// keeps its values but not its cancellation
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "first"))
+	first, err := s.TriggerContext(ctx, "slow")
+	if err != nil {
+		t.Fatalf("TriggerContext() error = %v", err)
+	}
+	cancel()
+	e.waitStarted(t)
+	if got := <-p.values; got != "first" {
+		t.Errorf("first run saw %v, want first", got)
+	}
+
+	queued, _ := s.TriggerContext(context.WithValue(context.Background(), traceKey{}, "queued"), "slow")
+	if queued.Status != RunStatusQueued {
+		t.Fatalf("queued run status = %s", queued.Status)
+	}
+
+	e.release()
+	waitForStatus(t, s, first.ID, RunStatusSucceeded)
+	e.waitStarted(t)
+	if got := <-p.values; got != "queued" {
+		t.Errorf("queued run saw %v, want queued", got)
+	}
+	e.release()
+	waitForStatus(t, s, queued.ID, RunStatusSucceeded)
+
+	manual, _ := s.Trigger("slow")
+	e.waitStarted(t)
+	if got := <-p.values; got != nil {
+		t.Errorf("Trigger run saw %v, want no value", got)
+	}
+	e.release()
+	waitForStatus(t, s, manual.ID, RunStatusSucceeded)
+}
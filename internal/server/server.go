@@ -0,0 +1,335 @@
+// Package server provides an embeddable HTTP management server for
+// operating the report engine as a service.
+//
+// Endpoints:
+//
+//	GET  /healthz               aggregated component health
+//	GET  /readyz                readiness; fails while the server drains
+//	POST /reports/{name}/run    trigger a run of a configured report
+//	GET  /runs/{id}             status of a run
+//	GET  /metrics               metrics, when a metrics handler is set
+//
+// Run requests carrying W3C trace context (a traceparent header) start
+// runs in the caller's trace; see WithPropagator.
+//
+// Health responses carry the aggregated health.Result. UP and DEGRADED
+// answer 200, DOWN answers 503, so a degraded service stays in rotation.
+//
+// Example:
+//
+//	sched := scheduler.New()
+//	_ = sched.Add(scheduler.Job{Name: "daily-sales", Schedule: "@daily", Config: cfg})
+//	_ = sched.Start(ctx)
+//	defer sched.Stop()
+//
+//	srv := server.New(
+//	    server.WithRunner(sched),
+//	    server.WithChecker("engine", eng),
+//	)
+//	if err := srv.ListenAndServe(ctx, ":8080"); err != nil {
+//	    log.Fatal(err)
+//	}
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// DefaultCheckTimeout bounds a single health or readiness request.
+	DefaultCheckTimeout = 5 * time.Second
+
+	// DefaultShutdownTimeout bounds graceful shutdown in ListenAndServe.
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+// Runner triggers report runs and exposes their history.
+// *scheduler.Scheduler implements Runner.
+type Runner interface {
+	Trigger(name string) (scheduler.Run, error)
+	History() scheduler.History
+}
+
+// ContextRunner is a Runner whose runs see the values of the triggering
+// request's context, such as its trace span. *scheduler.Scheduler
+// implements ContextRunner.
+type ContextRunner interface {
+	Runner
+	TriggerContext(ctx context.Context, name string) (scheduler.Run, error)
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithRunner enables the run endpoints backed by r.
+func WithRunner(r Runner) Option {
+	return func(s *Server) {
+		s.runner = r
+	}
+}
+
+// WithChecker registers a critical health component.
+func WithChecker(name string, c health.Checker) Option {
+	return func(s *Server) {
+		s.health.Register(name, c)
+	}
+}
+
+// WithOptionalChecker registers a health component whose failure only
+// degrades the service.
+func WithOptionalChecker(name string, c health.Checker) Option {
+	return func(s *Server) {
+		s.health.RegisterOptional(name, c)
+	}
+}
+
+// WithMetricsHandler serves h at GET /metrics, e.g. the Handler of an
+// observability.PrometheusCollector.
+func WithMetricsHandler(h http.Handler) Option {
+	return func(s *Server) {
+		s.metrics = h
+	}
+}
+
+// WithPropagator sets how trace context is extracted from the headers of
+// run requests, e.g. otel.GetTextMapPropagator() to follow the
+// application's global setting (default: W3C trace context and baggage).
+// With a ContextRunner, a run triggered by a request carrying a
+// traceparent header joins the caller's trace.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(s *Server) {
+		s.propagator = p
+	}
+}
+
+// WithLogger sets the server logger.
+func WithLogger(l *logging.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// WithCheckTimeout sets the timeout for health checks (default: 5s).
+func WithCheckTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.checkTimeout = d
+	}
+}
+
+// WithShutdownTimeout sets how long ListenAndServe waits for in-flight
+// requests on shutdown (default: 10s).
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = d
+	}
+}
+
+// Server is the HTTP management server.
+//
+// Thread-safe: Yes.
+type Server struct {
+	runner          Runner
+	metrics         http.Handler
+	propagator      propagation.TextMapPropagator
+	health          *health.Composite
+	logger          *logging.Logger
+	checkTimeout    time.Duration
+	shutdownTimeout time.Duration
+	mux             *http.ServeMux
+	draining        atomic.Bool
+}
+
+// New creates a server. Run endpoints are only served when a Runner is
+// configured.
+func New(opts ...Option) *Server {
+	s := &Server{
+		health:          health.NewComposite(),
+		checkTimeout:    DefaultCheckTimeout,
+		shutdownTimeout: DefaultShutdownTimeout,
+		mux:             http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.logger == nil {
+		s.logger = logging.NewLogger(logging.Config{
+			Level:     logging.LevelInfo,
+			Format:    logging.FormatJSON,
+			Component: "server",
+		})
+	}
+	if s.propagator == nil {
+		s.propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+	if s.runner != nil {
+		s.mux.HandleFunc("POST /reports/{name}/run", s.handleRun)
+		s.mux.HandleFunc("GET /runs/{id}", s.handleGetRun)
+	}
+	if s.metrics != nil {
+		s.mux.Handle("GET /metrics", s.metrics)
+	}
+	return s
+}
+
+// Health returns the composite checker so components can be registered
+// after construction.
+func (s *Server) Health() *health.Composite {
+	return s.health
+}
+
+// Handler returns the HTTP handler, for mounting into an existing server.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves on addr until ctx is canceled, then marks the
+// server not ready and shuts down gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("server: failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves on ln until ctx is canceled. See ListenAndServe.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	s.draining.Store(false)
+	s.logger.Info("management server started", "addr", ln.Addr().String())
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.draining.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server: shutdown failed: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server: %w", err)
+	}
+	s.logger.Info("management server stopped")
+	return nil
+}
+
+// handleHealth reports aggregated component health.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	res := s.check(r.Context())
+	writeJSON(w, healthCode(res.Status), res)
+}
+
+// handleReady reports readiness: component health, and not draining.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, health.Result{
+			Status: health.StatusDown,
+			Error:  "server is shutting down",
+		})
+		return
+	}
+	res := s.check(r.Context())
+	writeJSON(w, healthCode(res.Status), res)
+}
+
+// handleRun triggers a run and answers 202 with the run record. The
+// request's trace context is passed on to a ContextRunner.
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var run scheduler.Run
+	var err error
+	if runner, ok := s.runner.(ContextRunner); ok {
+		ctx := s.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		run, err = runner.TriggerContext(ctx, name)
+	} else {
+		run, err = s.runner.Trigger(name)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			writeError(w, http.StatusNotFound, fmt.Sprintf("report %q not found", name))
+		case errors.Is(err, scheduler.ErrStopped):
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			s.logger.ErrorContext(r.Context(), "failed to trigger run", "report", name, "error", err)
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	s.logger.InfoContext(r.Context(), "run triggered", "report", name, "run_id", run.ID, "status", run.Status)
+	w.Header().Set("Location", "/runs/"+run.ID)
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// handleGetRun returns a run from history.
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run, err := s.runner.History().Get(id)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrRunNotFound) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("run %q not found", id))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// check runs the composite health check within the check timeout.
+func (s *Server) check(ctx context.Context) health.Result {
+	ctx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	defer cancel()
+
+	// The error duplicates res.Status == DOWN
+	res, _ := s.health.CheckHealth(ctx)
+	return res
+}
+
+// healthCode maps a status to an HTTP code; only DOWN is unavailable.
+func healthCode(status health.Status) int {
+	if status == health.StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// writeError writes a JSON error body.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,383 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/engine"
+	"github.com/AshishBagdane/go-report-engine/internal/formatter"
+	"github.com/AshishBagdane/go-report-engine/internal/health"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/observability"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/processor"
+	"github.com/AshishBagdane/go-report-engine/internal/provider"
+	"github.com/AshishBagdane/go-report-engine/internal/scheduler"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func quietLogger() *logging.Logger {
+	return logging.NewLogger(logging.Config{Level: logging.LevelError, Format: logging.FormatText})
+}
+
+func checker(status health.Status) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) (health.Result, error) {
+		return health.Result{Status: status}, nil
+	})
+}
+
+// do sends a request to the server handler and decodes the JSON body.
+func do(t *testing.T, s *Server, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	if body != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+			t.Fatalf("%s %s: invalid JSON body %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantStatus health.Status
+		wantCode   int
+	}{
+		{name: "no components", wantStatus: health.StatusUp, wantCode: http.StatusOK},
+		{
+			name:       "all up",
+			opts:       []Option{WithChecker("db", checker(health.StatusUp))},
+			wantStatus: health.StatusUp,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name: "optional down is degraded",
+			opts: []Option{
+				WithChecker("db", checker(health.StatusUp)),
+				WithOptionalChecker("sftp", checker(health.StatusDown)),
+			},
+			wantStatus: health.StatusDegraded,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "critical down",
+			opts:       []Option{WithChecker("db", checker(health.StatusDown))},
+			wantStatus: health.StatusDown,
+			wantCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(append(tt.opts, WithLogger(quietLogger()))...)
+			for _, path := range []string{"/healthz", "/readyz"} {
+				var res health.Result
+				rec := do(t, s, http.MethodGet, path, &res)
+				if rec.Code != tt.wantCode || res.Status != tt.wantStatus {
+					t.Errorf("GET %s = %d %s, want %d %s", path, rec.Code, res.Status, tt.wantCode, tt.wantStatus)
+				}
+				if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %q", ct)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	slow := health.CheckerFunc(func(ctx context.Context) (health.Result, error) {
+		<-ctx.Done()
+		return health.Result{}, ctx.Err()
+	})
+	s := New(WithChecker("slow", slow), WithCheckTimeout(10*time.Millisecond), WithLogger(quietLogger()))
+
+	var res health.Result
+	if rec := do(t, s, http.MethodGet, "/healthz", &res); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /healthz = %d, want 503", rec.Code)
+	}
+}
+
+func TestHealthWithEngine(t *testing.T) {
+	eng, err := engine.NewEngineBuilder().
+		WithProvider(provider.NewMockProvider(nil)).
+		WithProcessor(&processor.BaseProcessor{}).
+		WithFormatter(formatter.NewJSONFormatter("")).
+		WithOutput(&output.ConsoleOutput{}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(WithLogger(quietLogger()))
+	s.Health().Register("engine", eng)
+
+	var res health.Result
+	if rec := do(t, s, http.MethodGet, "/healthz", &res); rec.Code != http.StatusOK || res.Status != health.StatusUp {
+		t.Errorf("GET /healthz = %d %s", rec.Code, res.Status)
+	}
+}
+
+// fakeRunner records triggers and serves runs from a memory history.
+type fakeRunner struct {
+	history *scheduler.MemoryHistory
+	err     error
+}
+
+func (f *fakeRunner) Trigger(name string) (scheduler.Run, error) {
+	if f.err != nil {
+		return scheduler.Run{}, f.err
+	}
+	if name != "sales" {
+		return scheduler.Run{}, fmt.Errorf("%w: %q", scheduler.ErrJobNotFound, name)
+	}
+	run := scheduler.Run{ID: "run-1", Job: name, Trigger: scheduler.TriggerManual, Status: scheduler.RunStatusRunning}
+	_ = f.history.Save(run)
+	return run, nil
+}
+
+func (f *fakeRunner) History() scheduler.History {
+	return f.history
+}
+
+func TestRunEndpoints(t *testing.T) {
+	runner := &fakeRunner{history: scheduler.NewMemoryHistory(10)}
+	s := New(WithRunner(runner), WithLogger(quietLogger()))
+
+	var run scheduler.Run
+	rec := do(t, s, http.MethodPost, "/reports/sales/run", &run)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST run = %d, want 202", rec.Code)
+	}
+	if run.ID != "run-1" || run.Status != scheduler.RunStatusRunning {
+		t.Errorf("run = %+v", run)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/runs/run-1" {
+		t.Errorf("Location = %q", loc)
+	}
+
+	var got scheduler.Run
+	if rec := do(t, s, http.MethodGet, "/runs/run-1", &got); rec.Code != http.StatusOK || got.Job != "sales" {
+		t.Errorf("GET run = %d %+v", rec.Code, got)
+	}
+
+	var errBody map[string]string
+	if rec := do(t, s, http.MethodGet, "/runs/missing", &errBody); rec.Code != http.StatusNotFound || errBody["error"] == "" {
+		t.Errorf("GET missing run = %d %v", rec.Code, errBody)
+	}
+	if rec := do(t, s, http.MethodPost, "/reports/unknown/run", &errBody); rec.Code != http.StatusNotFound {
+		t.Errorf("POST unknown report = %d, want 404", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/reports/sales/run", nil); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET on run endpoint = %d, want 405", rec.Code)
+	}
+
+	runner.err = scheduler.ErrStopped
+	if rec := do(t, s, http.MethodPost, "/reports/sales/run", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST on stopped runner = %d, want 503", rec.Code)
+	}
+	runner.err = errors.New("boom")
+	if rec := do(t, s, http.MethodPost, "/reports/sales/run", nil); rec.Code != http.StatusInternalServerError {
+		t.Errorf("POST on failing runner = %d, want 500", rec.Code)
+	}
+}
+
+func TestRunEndpointsRequireRunner(t *testing.T) {
+	s := New(WithLogger(quietLogger()))
+	if rec := do(t, s, http.MethodPost, "/reports/sales/run", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("POST run without runner = %d, want 404", rec.Code)
+	}
+}
+
+func TestRunWithScheduler(t *testing.T) {
+	newEngine := func(cfg engine.Config) (*engine.ReportEngine, error) {
+		eng := &engine.ReportEngine{
+			Provider:  provider.NewMockProvider([]map[string]interface{}{{"id": 1}}),
+			Processor: &processor.BaseProcessor{},
+			Formatter: formatter.NewJSONFormatter(""),
+			Output:    &discardOutput{},
+		}
+		return eng.WithLogger(quietLogger()), nil
+	}
+	sched := scheduler.New(scheduler.WithEngineFactory(newEngine), scheduler.WithLogger(quietLogger()))
+	defer sched.Stop()
+
+	err := sched.Add(scheduler.Job{Name: "sales", Config: engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(WithRunner(sched), WithLogger(quietLogger()))
+
+	var run scheduler.Run
+	if rec := do(t, s, http.MethodPost, "/reports/sales/run", &run); rec.Code != http.StatusAccepted {
+		t.Fatalf("POST run = %d", rec.Code)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var got scheduler.Run
+		do(t, s, http.MethodGet, "/runs/"+run.ID, &got)
+		if got.Status == scheduler.RunStatusSucceeded {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run status = %s, want succeeded", got.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type discardOutput struct{}
+
+func (discardOutput) Send(ctx context.Context, data []byte) error { return nil }
+
+func TestServeLifecycle(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(WithLogger(quietLogger()))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, ln)
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /readyz = %d, want 200", resp.StatusCode)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve() did not return after cancel")
+	}
+
+	// Once draining, readiness fails while liveness still answers
+	if rec := do(t, s, http.MethodGet, "/readyz", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /readyz after shutdown = %d, want 503", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/healthz", nil); rec.Code != http.StatusOK {
+		t.Errorf("GET /healthz after shutdown = %d, want 200", rec.Code)
+	}
+}
+
+func TestListenAndServeInvalidAddr(t *testing.T) {
+	s := New(WithLogger(quietLogger()))
+	if err := s.ListenAndServe(context.Background(), "not-an-address"); err == nil {
+		t.Error("ListenAndServe() should fail on an invalid address")
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "report_engine_runs_total 3")
+	})
+	s := New(WithLogger(quietLogger()), WithMetricsHandler(metrics))
+
+	rec := do(t, s, http.MethodGet, "/metrics", nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "report_engine_runs_total 3\n" {
+		t.Errorf("GET /metrics = %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Without a handler there is no endpoint
+	rec = do(t, New(WithLogger(quietLogger())), http.MethodGet, "/metrics", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /metrics without handler = %d, want 404", rec.Code)
+	}
+}
+
+func TestRunPropagatesTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := observability.NewOTelTracer(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	newEngine := func(cfg engine.Config) (*engine.ReportEngine, error) {
+		eng := &engine.ReportEngine{
+			Provider:  provider.NewMockProvider([]map[string]interface{}{{"id": 1}}),
+			Processor: &processor.BaseProcessor{},
+			Formatter: formatter.NewJSONFormatter(""),
+			Output:    &discardOutput{},
+		}
+		return eng.WithLogger(quietLogger()), nil
+	}
+	sched := scheduler.New(
+		scheduler.WithEngineFactory(newEngine),
+		scheduler.WithLogger(quietLogger()),
+		scheduler.WithTracer(tracer),
+	)
+	defer sched.Stop()
+	if err := sched.Add(scheduler.Job{Name: "sales", Config: engine.Config{
+		Provider:  engine.ProviderConfig{Type: "mock"},
+		Formatter: engine.FormatterConfig{Type: "json"},
+		Output:    engine.OutputConfig{Type: "console"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(WithRunner(sched), WithLogger(quietLogger()))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/reports/sales/run", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST run = %d", rec.Code)
+	}
+	var run scheduler.Run
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, _ := sched.History().Get(run.ID)
+		if got.Status == scheduler.RunStatusSucceeded {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run status = %s, want succeeded", got.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	spans := exporter.GetSpans()
+	names := make(map[string]bool)
+	for _, span := range spans {
+		names[span.Name] = true
+		if got := span.SpanContext.TraceID().String(); got != traceID {
+			t.Errorf("span %s trace ID = %s, want %s", span.Name, got, traceID)
+		}
+		if span.Name == "scheduler.run" && span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("scheduler.run parent = %s, want the caller's span", span.Parent.SpanID())
+		}
+	}
+	if !names["scheduler.run"] || !names["report.run"] {
+		t.Errorf("spans = %v, want scheduler.run and report.run", names)
+	}
+}
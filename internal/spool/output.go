@@ -0,0 +1,246 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	engerrors "github.com/AshishBagdane/go-report-engine/internal/errors"
+	"github.com/AshishBagdane/go-report-engine/internal/logging"
+	"github.com/AshishBagdane/go-report-engine/internal/output"
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/pkg/api"
+)
+
+// Output spools every report before delivering it to its delegate.
+//
+// Send returns as soon as the report is on disk; delivery runs in the
+// background, retried with the Output's policy. A delivered report is
+// removed from the spool. One that still fails stays there, with the
+// attempt recorded, until Replay delivers it. Every delivery claims its
+// entry first (see Spool.Claim), so a report is never sent twice.
+type Output struct {
+	name     string
+	delegate output.OutputStrategy
+	spool    *Spool
+	retrier  *resilience.Retrier
+	logger   *logging.Logger
+
+	// deliveries tracks background deliveries; stop cancels them. mu
+	// orders new deliveries with Close
+	mu         sync.Mutex
+	closed     bool
+	deliveries sync.WaitGroup
+	ctx        context.Context
+	stop       context.CancelFunc
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// NewOutput creates a decorator that spools reports for delegate in
+// spool. name identifies the output in spool entries; Replay only
+// delivers the entries of its own name.
+func NewOutput(name string, delegate output.OutputStrategy, spool *Spool, policy resilience.RetryPolicy) *Output {
+	ctx, stop := context.WithCancel(context.Background())
+	return &Output{
+		name:     name,
+		delegate: delegate,
+		spool:    spool,
+		retrier:  resilience.NewRetrier(policy),
+		logger: logging.NewLogger(logging.Config{
+			Level:     logging.LevelInfo,
+			Format:    logging.FormatJSON,
+			Component: "spool",
+		}),
+		ctx:  ctx,
+		stop: stop,
+	}
+}
+
+// WithLogger sets the logger deliveries are logged to.
+func (o *Output) WithLogger(logger *logging.Logger) *Output {
+	o.logger = logger
+	return o
+}
+
+// Spool returns the spool reports are kept in.
+func (o *Output) Spool() *Spool {
+	return o.spool
+}
+
+// Send stores data in the spool and starts delivering it. It only fails
+// if the report cannot be spooled.
+func (o *Output) Send(ctx context.Context, data []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return engerrors.NewOutputError("send", engerrors.ErrorTypePermanent, errors.New("spool output is closed")).
+			WithOutputType("spool")
+	}
+
+	entry, err := o.spool.Put(o.name, logging.GetRequestID(ctx), data)
+	if err != nil {
+		return engerrors.ErrOutputWrite("spool", o.spool.Dir(), err)
+	}
+	o.logger.InfoContext(ctx, "report spooled",
+		"spool_id", entry.ID,
+		"output", o.name,
+		"size_bytes", entry.Size,
+	)
+
+	// A replay may have picked the entry up already; it stays spooled
+	// for one otherwise
+	release, err := o.spool.Claim(entry.ID)
+	if err != nil {
+		o.logger.WarnContext(ctx, "spooled report left for replay", "spool_id", entry.ID, "error", err)
+		return nil
+	}
+
+	// Delivery outlives the run: it keeps the run's values, such as the
+	// request ID, but is only canceled by Close
+	deliveryCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopOnClose := context.AfterFunc(o.ctx, cancel)
+	o.deliveries.Add(1)
+	go func() {
+		defer o.deliveries.Done()
+		defer stopOnClose()
+		defer cancel()
+		defer release()
+		_ = o.deliver(deliveryCtx, entry, data)
+	}()
+	return nil
+}
+
+// Replay delivers the spooled reports of this output, oldest first, and
+// returns how many were delivered. Reports that another delivery is
+// working on are skipped. Reports that fail again stay spooled; their
+// errors are joined.
+func (o *Output) Replay(ctx context.Context) (int, error) {
+	entries, err := o.spool.List()
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.Output != o.name {
+			continue
+		}
+		if err := o.ReplayEntry(ctx, entry.ID); err != nil {
+			// Delivered or being delivered by someone else since List
+			if errors.Is(err, ErrClaimed) || errors.Is(err, ErrNotFound) {
+				continue
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		delivered++
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// ReplayEntry delivers the spooled report with the given ID. It fails
+// with ErrClaimed if another delivery is working on the report, and for
+// a report spooled for another output.
+func (o *Output) ReplayEntry(ctx context.Context, id string) error {
+	release, err := o.spool.Claim(id)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	entry, err := o.spool.Get(id)
+	if err != nil {
+		return err
+	}
+	if entry.Output != o.name {
+		return engerrors.NewOutputError("replay", engerrors.ErrorTypeConfiguration,
+			fmt.Errorf("spool entry %s is for output %q, not %q", id, entry.Output, o.name)).
+			WithOutputType("spool")
+	}
+	data, err := o.spool.Payload(id)
+	if err != nil {
+		return err
+	}
+	return o.deliver(ctx, entry, data)
+}
+
+// deliver sends a spooled report with retries, then removes it from the
+// spool or records the failure.
+func (o *Output) deliver(ctx context.Context, entry Entry, data []byte) error {
+	err := o.retrier.Execute(ctx, func(ctx context.Context) error {
+		return o.delegate.Send(ctx, data)
+	})
+	if err != nil {
+		o.logger.ErrorContext(ctx, "spooled report delivery failed",
+			"spool_id", entry.ID,
+			"output", o.name,
+			"error", err,
+		)
+		if recErr := o.spool.recordFailure(entry.ID, err); recErr != nil {
+			o.logger.WarnContext(ctx, "failed to record spool delivery attempt", "spool_id", entry.ID, "error", recErr)
+		}
+		return fmt.Errorf("spool entry %s: %w", entry.ID, err)
+	}
+
+	if err := o.spool.Remove(entry.ID); err != nil {
+		// Delivered but still spooled: a replay would deliver it twice
+		o.logger.WarnContext(ctx, "failed to remove delivered report from spool", "spool_id", entry.ID, "error", err)
+	}
+	o.logger.InfoContext(ctx, "spooled report delivered",
+		"spool_id", entry.ID,
+		"output", o.name,
+		"attempts", entry.Attempts+1,
+	)
+	return nil
+}
+
+// Wait blocks until background deliveries have finished or ctx is done.
+func (o *Output) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		o.deliveries.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseWithContext waits for background deliveries until ctx is done,
+// cancels the ones still running (their reports stay spooled) and closes
+// the delegate.
+func (o *Output) CloseWithContext(ctx context.Context) error {
+	o.closeOnce.Do(func() {
+		o.mu.Lock()
+		o.closed = true
+		o.mu.Unlock()
+
+		waitErr := o.Wait(ctx)
+		o.stop()
+		o.deliveries.Wait()
+
+		var closeErr error
+		if c, ok := o.delegate.(api.CloseableWithContext); ok {
+			closeErr = c.CloseWithContext(ctx)
+		} else if c, ok := o.delegate.(io.Closer); ok {
+			closeErr = c.Close()
+		}
+		o.closeErr = errors.Join(waitErr, closeErr)
+	})
+	return o.closeErr
+}
+
+// Close waits for background deliveries and closes the delegate.
+func (o *Output) Close() error {
+	return o.CloseWithContext(context.Background())
+}
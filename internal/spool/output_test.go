@@ -0,0 +1,239 @@
+package spool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshishBagdane/go-report-engine/internal/resilience"
+	"github.com/AshishBagdane/go-report-engine/internal/spool"
+)
+
+// testPolicy gives up after the first attempt.
+var testPolicy = resilience.RetryPolicy{
+	MaxRetries: 0,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   time.Millisecond,
+	Factor:     1,
+}
+
+// recordingOutput fails with err, if set, and keeps the reports it was
+// sent.
+type recordingOutput struct {
+	mu       sync.Mutex
+	err      error
+	received []string
+	closed   bool
+	block    chan struct{}
+}
+
+func (o *recordingOutput) Send(ctx context.Context, data []byte) error {
+	if o.block != nil {
+		select {
+		case <-o.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.err != nil {
+		return o.err
+	}
+	o.received = append(o.received, string(data))
+	return nil
+}
+
+func (o *recordingOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.closed = true
+	return nil
+}
+
+func (o *recordingOutput) setErr(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.err = err
+}
+
+func (o *recordingOutput) reports() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.received...)
+}
+
+func TestOutput_DeliversAndRemoves(t *testing.T) {
+	delegate := &recordingOutput{}
+	s := spool.New(t.TempDir())
+	out := spool.NewOutput("file", delegate, s, testPolicy)
+
+	if err := out.Send(context.Background(), []byte("report")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := delegate.reports(); len(got) != 1 || got[0] != "report" {
+		t.Errorf("delegate received %q", got)
+	}
+	if !delegate.closed {
+		t.Error("Close should close the delegate")
+	}
+	if entries, _ := s.List(); len(entries) != 0 {
+		t.Errorf("delivered report still spooled: %+v", entries)
+	}
+}
+
+func TestOutput_FailureStaysSpooledUntilReplay(t *testing.T) {
+	delegate := &recordingOutput{err: errors.New("host unreachable")}
+	s := spool.New(t.TempDir())
+	out := spool.NewOutput("sftp", delegate, s, testPolicy)
+
+	// The run succeeds although the output is down
+	if err := out.Send(context.Background(), []byte("report")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := out.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List = %+v, %v; want the failed report", entries, err)
+	}
+	if entries[0].Attempts != 1 || entries[0].LastError != "host unreachable" {
+		t.Errorf("entry = %+v", entries[0])
+	}
+
+	// Entries of other outputs are left alone
+	if _, err := s.Put("other", "", []byte("other report")); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := out.Replay(context.Background()); n != 0 || err == nil {
+		t.Errorf("Replay while down = %d, %v; want a failure", n, err)
+	}
+
+	delegate.setErr(nil)
+	n, err := out.Replay(context.Background())
+	if n != 1 || err != nil {
+		t.Fatalf("Replay = %d, %v", n, err)
+	}
+	if got := delegate.reports(); len(got) != 1 || got[0] != "report" {
+		t.Errorf("delegate received %q", got)
+	}
+	entries, _ = s.List()
+	if len(entries) != 1 || entries[0].Output != "other" {
+		t.Errorf("remaining entries = %+v", entries)
+	}
+}
+
+func TestOutput_ReplayEntryNotFound(t *testing.T) {
+	out := spool.NewOutput("file", &recordingOutput{}, spool.New(t.TempDir()), testPolicy)
+	if err := out.ReplayEntry(context.Background(), "missing"); !errors.Is(err, spool.ErrNotFound) {
+		t.Errorf("ReplayEntry = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOutput_ReplayEntryOtherOutput(t *testing.T) {
+	delegate := &recordingOutput{}
+	s := spool.New(t.TempDir())
+	entry, err := s.Put("other", "", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := spool.NewOutput("file", delegate, s, testPolicy)
+	if err := out.ReplayEntry(context.Background(), entry.ID); err == nil {
+		t.Fatal("ReplayEntry should refuse a report of another output")
+	}
+	if got := delegate.reports(); len(got) != 0 {
+		t.Errorf("delegate received %q", got)
+	}
+	if _, err := s.Get(entry.ID); err != nil {
+		t.Errorf("the report should stay spooled: %v", err)
+	}
+}
+
+func TestOutput_ReplaySkipsClaimedEntries(t *testing.T) {
+	delegate := &recordingOutput{}
+	s := spool.New(t.TempDir())
+	entry, err := s.Put("file", "", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := s.Claim(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := spool.NewOutput("file", delegate, s, testPolicy)
+	if n, err := out.Replay(context.Background()); n != 0 || err != nil {
+		t.Errorf("Replay = %d, %v; want the claimed report skipped", n, err)
+	}
+	if err := out.ReplayEntry(context.Background(), entry.ID); !errors.Is(err, spool.ErrClaimed) {
+		t.Errorf("ReplayEntry = %v, want ErrClaimed", err)
+	}
+
+	release()
+	if n, err := out.Replay(context.Background()); n != 1 || err != nil {
+		t.Errorf("Replay after release = %d, %v", n, err)
+	}
+}
+
+func TestOutput_ConcurrentReplaysDeliverOnce(t *testing.T) {
+	delegate := &recordingOutput{}
+	s := spool.New(t.TempDir())
+	for i := 0; i < 5; i++ {
+		if _, err := s.Put("file", "", []byte("report")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out := spool.NewOutput("file", delegate, s, testPolicy)
+			if _, err := out.Replay(context.Background()); err != nil {
+				t.Errorf("Replay failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := delegate.reports(); len(got) != 5 {
+		t.Errorf("delegate received %d reports, want each of the 5 once", len(got))
+	}
+}
+
+func TestOutput_CloseCancelsPendingDeliveries(t *testing.T) {
+	delegate := &recordingOutput{block: make(chan struct{})}
+	s := spool.New(t.TempDir())
+	out := spool.NewOutput("file", delegate, s, testPolicy)
+
+	// The run's context ending does not stop delivery
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := out.Send(ctx, []byte("report")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	cancel()
+
+	closeCtx, stop := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer stop()
+	if err := out.CloseWithContext(closeCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CloseWithContext = %v, want the deadline", err)
+	}
+
+	if entries, _ := s.List(); len(entries) != 1 {
+		t.Errorf("an undelivered report should stay spooled, got %+v", entries)
+	}
+	if err := out.Send(context.Background(), []byte("late")); err == nil {
+		t.Error("Send after Close should fail")
+	}
+}
@@ -0,0 +1,295 @@
+// Package spool keeps formatted reports on disk until they are delivered.
+//
+// A Spool is a directory of reports waiting for their output. The Output
+// decorator writes every report to the spool before delivering it in the
+// background with retries, so an output outage never forces a report to be
+// generated again: reports that could not be delivered stay in the spool
+// and can be replayed later, e.g. with "report-engine spool replay".
+package spool
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for an entry that is not in the spool.
+var ErrNotFound = errors.New("spool: entry not found")
+
+// ErrClaimed is returned by Claim for an entry that another delivery, in
+// this or another process, is working on.
+var ErrClaimed = errors.New("spool: entry is being delivered")
+
+// File extensions of an entry's metadata, payload and claim.
+const (
+	entryExt   = ".json"
+	payloadExt = ".data"
+	claimExt   = ".lock"
+)
+
+// staleClaim is the age after which a claim is taken to be left behind by
+// a process that crashed while delivering, and may be taken over.
+const staleClaim = time.Hour
+
+// Entry describes a spooled report.
+type Entry struct {
+	ID string `json:"id"`
+
+	// Output is the name of the output the report is for.
+	Output string `json:"output"`
+
+	// RunID is the run that produced the report, if known.
+	RunID string `json:"run_id,omitempty"`
+
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Attempts counts delivery attempts that failed; each attempt may
+	// include several retries.
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+}
+
+// Spool stores reports in a directory, each as a payload file and an
+// entry file named after the entry ID. Files are written to a temporary
+// name, synced and renamed, so a crash never leaves a partial report.
+// The directory is created on the first Put.
+type Spool struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates a spool backed by dir.
+func New(dir string) *Spool {
+	return &Spool{dir: dir}
+}
+
+// Dir returns the spool directory.
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Put stores data as a report for output and returns its entry.
+func (s *Spool) Put(output, runID string, data []byte) (Entry, error) {
+	entry := Entry{
+		ID:        newID(),
+		Output:    output,
+		RunID:     runID,
+		Size:      int64(len(data)),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return Entry{}, fmt.Errorf("spool: failed to create directory: %w", err)
+	}
+	// The payload goes first: an entry file is only written for a
+	// complete payload
+	if err := writeFile(s.path(entry.ID, payloadExt), data); err != nil {
+		return Entry{}, err
+	}
+	if err := s.writeEntry(entry); err != nil {
+		_ = os.Remove(s.path(entry.ID, payloadExt))
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// List returns the spooled entries, oldest first. Entry files that cannot
+// be decoded are skipped.
+func (s *Spool) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to read %s: %w", s.dir, err)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), entryExt) {
+			continue
+		}
+		entry, err := s.readEntry(strings.TrimSuffix(file.Name(), entryExt))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// Get returns the entry with the given ID.
+func (s *Spool) Get(id string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readEntry(id)
+}
+
+// Payload returns the report stored under id.
+func (s *Spool) Payload(id string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(id, payloadExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to read payload %s: %w", id, err)
+	}
+	return data, nil
+}
+
+// Remove deletes the entry and its payload, e.g. once delivered.
+func (s *Spool) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The entry goes first, so a crash leaves an orphan payload rather
+	// than an entry without one
+	if err := os.Remove(s.path(id, entryExt)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("spool: failed to remove %s: %w", id, err)
+	}
+	if err := os.Remove(s.path(id, payloadExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("spool: failed to remove payload %s: %w", id, err)
+	}
+	return nil
+}
+
+// Claim reserves the entry with id for one delivery, so a background
+// delivery and a replay, or two replays, never send the same report
+// twice. The claim is a lock file next to the entry, created exclusively,
+// so it holds across processes sharing the spool directory. It fails
+// with ErrClaimed while another delivery holds the entry; release ends
+// the claim.
+func (s *Spool) Claim(id string) (release func(), err error) {
+	lock := s.path(id, claimExt)
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("spool: failed to claim %s: %w", id, err)
+		}
+
+		info, err := os.Stat(lock)
+		switch {
+		case attempt > 0:
+			return nil, ErrClaimed
+		case errors.Is(err, os.ErrNotExist):
+			// Released in the meantime
+			continue
+		case err != nil || time.Since(info.ModTime()) < staleClaim:
+			return nil, ErrClaimed
+		}
+		// Left behind by a delivery that never finished
+		if err := os.Remove(lock); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("spool: failed to remove stale claim on %s: %w", id, err)
+		}
+	}
+}
+
+// recordFailure adds a failed delivery attempt to the entry with id.
+func (s *Spool) recordFailure(id string, deliveryErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.readEntry(id)
+	if err != nil {
+		return err
+	}
+	entry.Attempts++
+	entry.LastError = deliveryErr.Error()
+	entry.LastAttempt = time.Now().UTC()
+	return s.writeEntry(entry)
+}
+
+// readEntry reads an entry file. Callers hold mu.
+func (s *Spool) readEntry(id string) (Entry, error) {
+	data, err := os.ReadFile(s.path(id, entryExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, ErrNotFound
+	}
+	if err != nil {
+		return Entry{}, fmt.Errorf("spool: failed to read entry %s: %w", id, err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("spool: failed to decode entry %s: %w", id, err)
+	}
+	return entry, nil
+}
+
+// writeEntry writes an entry file. Callers hold mu.
+func (s *Spool) writeEntry(entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("spool: failed to encode entry %s: %w", entry.ID, err)
+	}
+	return writeFile(s.path(entry.ID, entryExt), data)
+}
+
+// path returns the file of entry id with the given extension. IDs are
+// reduced to their base name so they cannot point outside the spool.
+func (s *Spool) path(id, ext string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+ext)
+}
+
+// writeFile writes data to path through a synced temporary file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("spool: failed to create %s: %w", tmp, err)
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("spool: failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("spool: failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// newID returns a unique entry ID that sorts by creation time.
+func newID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return time.Now().UTC().Format("20060102T150405.000000000") + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,149 @@
+package spool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpool_PutListRemove(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "spool"))
+
+	first, err := s.Put("sftp", "run-1", []byte("first"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	second, err := s.Put("sftp", "run-2", []byte("second report"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != first.ID || entries[1].ID != second.ID {
+		t.Fatalf("entries = %+v, want oldest first", entries)
+	}
+	if entries[1].Output != "sftp" || entries[1].RunID != "run-2" || entries[1].Size != int64(len("second report")) {
+		t.Errorf("entry = %+v", entries[1])
+	}
+
+	data, err := s.Payload(first.ID)
+	if err != nil || string(data) != "first" {
+		t.Errorf("Payload = %q, %v", data, err)
+	}
+
+	if err := s.Remove(first.ID); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := s.Get(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Remove = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Payload(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Payload after Remove = %v, want ErrNotFound", err)
+	}
+	if err := s.Remove(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Remove = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSpool_ListMissingDir(t *testing.T) {
+	entries, err := New(filepath.Join(t.TempDir(), "missing")).List()
+	if err != nil || entries != nil {
+		t.Errorf("List = %v, %v; want an empty spool", entries, err)
+	}
+}
+
+func TestSpool_ListSkipsCorruptEntries(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+	if _, err := s.Put("file", "", []byte("ok")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.List()
+	if err != nil || len(entries) != 1 {
+		t.Errorf("List = %+v, %v; want the one valid entry", entries, err)
+	}
+}
+
+func TestSpool_RecordFailure(t *testing.T) {
+	s := New(t.TempDir())
+	entry, err := s.Put("sftp", "", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.recordFailure(entry.ID, errors.New("connection refused")); err != nil {
+			t.Fatalf("recordFailure failed: %v", err)
+		}
+	}
+
+	got, err := s.Get(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Attempts != 2 || got.LastError != "connection refused" || got.LastAttempt.IsZero() {
+		t.Errorf("entry = %+v", got)
+	}
+	if err := s.recordFailure("missing", errors.New("x")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("recordFailure of a missing entry = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSpool_PathStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+	if got := s.path("../../etc/passwd", entryExt); filepath.Dir(got) != dir {
+		t.Errorf("path = %s, want a file in %s", got, dir)
+	}
+}
+
+func TestSpool_Claim(t *testing.T) {
+	s := New(t.TempDir())
+	entry, err := s.Put("sftp", "", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := s.Claim(entry.ID)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if _, err := s.Claim(entry.ID); !errors.Is(err, ErrClaimed) {
+		t.Errorf("second Claim = %v, want ErrClaimed", err)
+	}
+	if entries, _ := s.List(); len(entries) != 1 {
+		t.Errorf("claims should not be listed, got %+v", entries)
+	}
+
+	release()
+	release, err = s.Claim(entry.ID)
+	if err != nil {
+		t.Fatalf("Claim after release failed: %v", err)
+	}
+
+	// A claim left behind by a crashed delivery is taken over
+	old := time.Now().Add(-2 * staleClaim)
+	if err := os.Chtimes(s.path(entry.ID, claimExt), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Claim(entry.ID); err != nil {
+		t.Errorf("Claim of a stale claim failed: %v", err)
+	}
+	release()
+}
+
+func TestSpool_ClaimMissingDir(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "missing"))
+	if _, err := s.Claim("id"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Claim = %v, want ErrNotFound", err)
+	}
+}
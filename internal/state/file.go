@@ -0,0 +1,106 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps state in a JSON object file. Every Set and Delete
+// rewrites the file atomically (write to a temporary file, then rename),
+// so a crash never leaves a half-written state file behind.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a store backed by the file at path. The file and
+// its directory are created on the first Set.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Path returns the backing file path.
+func (f *FileStore) Path() string {
+	return f.path
+}
+
+// Get returns the value stored under key.
+func (f *FileStore) Get(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores value under key.
+func (f *FileStore) Set(key, value string) error {
+	return f.update(func(values map[string]string) {
+		values[key] = value
+	})
+}
+
+// Delete removes key.
+func (f *FileStore) Delete(key string) error {
+	return f.update(func(values map[string]string) {
+		delete(values, key)
+	})
+}
+
+// update applies fn to the stored values and writes them back.
+func (f *FileStore) update(fn func(values map[string]string)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return err
+	}
+	fn(values)
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("state: failed to encode %s: %w", f.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("state: failed to create directory: %w", err)
+	}
+
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("state: failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("state: failed to replace %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// load reads the stored values. A missing file holds no values.
+func (f *FileStore) load() (map[string]string, error) {
+	values := make(map[string]string)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("state: failed to read %s: %w", f.path, err)
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("state: failed to decode %s: %w", f.path, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,45 @@
+package state
+
+import "sync"
+
+// MemoryStore keeps state in memory. State is lost when the process exits,
+// so it is mainly useful for tests and long-running embedded engines.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string]string)}
+}
+
+// Get returns the value stored under key.
+func (m *MemoryStore) Get(key string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, ok := m.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores value under key.
+func (m *MemoryStore) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+	return nil
+}
+
+// Delete removes key.
+func (m *MemoryStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, key)
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package state provides small key-value stores for engine state that
+// must survive between runs, such as incremental-run watermarks.
+//
+// Values are opaque strings; callers encode structured state themselves.
+package state
+
+import "errors"
+
+// ErrNotFound is returned by Store.Get for keys that have no value.
+var ErrNotFound = errors.New("state: key not found")
+
+// Store persists string values by key.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key or ErrNotFound.
+	Get(key string) (string, error)
+
+	// Set stores value under key, replacing any previous value.
+	Set(key, value string) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(key string) error
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testStore runs the Store contract against s.
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+
+	if _, err := s.Get("orders"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Set("orders", "2026-03-01T00:00:00Z"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Set("orders", "2026-03-02T00:00:00Z"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if v, err := s.Get("orders"); err != nil || v != "2026-03-02T00:00:00Z" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+
+	if err := s.Delete("orders"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get("orders"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(deleted) error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("orders"); err != nil {
+		t.Errorf("Delete(missing) error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = s.Set(string(rune('a'+i)), "x")
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		if _, err := s.Get(string(rune('a' + i))); err != nil {
+			t.Errorf("Get(%c) error = %v", 'a'+i, err)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, NewFileStore(filepath.Join(t.TempDir(), "nested", "state.json")))
+}
+
+func TestFileStore_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := NewFileStore(path).Set("orders", "42"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := NewFileStore(path).Get("orders"); err != nil || v != "42" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file should not be left behind")
+	}
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileStore(path)
+	if _, err := s.Get("orders"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() on a corrupt file error = %v, want a decode error", err)
+	}
+	if err := s.Set("orders", "1"); err == nil {
+		t.Error("Set() must not overwrite a corrupt state file")
+	}
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamType is the expected type of a parameter value. Values are always
+// strings in the config; the type says how the component parses them.
+type ParamType string
+
+const (
+	ParamString   ParamType = "string"
+	ParamInt      ParamType = "int"
+	ParamFloat    ParamType = "float"
+	ParamBool     ParamType = "bool"
+	ParamDuration ParamType = "duration" // e.g. "30s", "5m"
+)
+
+// ParamSpec describes one parameter a component accepts.
+//
+// A Name ending in "*" matches every parameter with that prefix, such as
+// "header_*" for "header_Authorization".
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Required    bool      `json:"required,omitempty"`
+	Default     string    `json:"default,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Enum        []string  `json:"enum,omitempty"` // allowed values, if limited
+
+	// Deprecated, when set, marks a parameter that is still accepted but
+	// should no longer be used, and says what to do instead.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// ParamDescriber is implemented by Configurable components that declare
+// their parameters. The factory validates config params against the
+// declaration before calling Configure, so unknown parameters (typically
+// typos) and malformed values are rejected. The declaration also feeds
+// the generated config JSON Schema.
+//
+// A nil result means the component does not declare its parameters, and
+// no validation is done.
+type ParamDescriber interface {
+	ParamSpecs() []ParamSpec
+}
+
+// Matches reports whether the spec applies to the parameter name.
+func (s ParamSpec) Matches(name string) bool {
+	if prefix, ok := strings.CutSuffix(s.Name, "*"); ok {
+		return strings.HasPrefix(name, prefix) && len(name) > len(prefix)
+	}
+	return s.Name == name
+}
+
+// Check validates a value against the spec's type and allowed values.
+func (s ParamSpec) Check(value string) error {
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(v string) bool { return strings.EqualFold(v, value) }) {
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(s.Enum, ", "), value)
+	}
+
+	var err error
+	switch s.Type {
+	case ParamInt:
+		_, err = strconv.Atoi(value)
+	case ParamFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case ParamBool:
+		_, err = strconv.ParseBool(value)
+	case ParamDuration:
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("must be a %s, got %q", s.Type, value)
+	}
+	return nil
+}
+
+// DeprecatedParams returns a warning for each parameter in params that
+// specs declare as deprecated, sorted by name.
+//
+// Example:
+//
+//	api.DeprecatedParams(json.ParamSpecs(), map[string]string{"pretty": "true"})
+//	// parameter "pretty" is deprecated: it has no effect, set indent instead
+func DeprecatedParams(specs []ParamSpec, params map[string]string) []string {
+	var warnings []string
+	for _, spec := range specs {
+		if spec.Deprecated == "" {
+			continue
+		}
+		for name := range params {
+			if spec.Matches(name) {
+				warnings = append(warnings, fmt.Sprintf("parameter %q is deprecated: %s", name, spec.Deprecated))
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// ParamError is a problem with one parameter. Line and Column, when set,
+// locate the parameter in the config file; the factory fills them in for
+// configs loaded from a file.
+type ParamError struct {
+	Name    string
+	Problem string // e.g. unknown parameter "file_pth" (did you mean "file_path"?)
+	Line    int
+	Column  int
+}
+
+func (e *ParamError) Error() string {
+	if e.Line == 0 {
+		return e.Problem
+	}
+	return fmt.Sprintf("%s (line %d, column %d)", e.Problem, e.Line, e.Column)
+}
+
+// ParamErrors is the error ValidateParams returns: every problem it found.
+type ParamErrors []*ParamError
+
+func (e ParamErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, problem := range e {
+		problems[i] = problem.Error()
+	}
+	return "invalid parameters: " + strings.Join(problems, "; ")
+}
+
+// ValidateParams checks params against specs: every required parameter
+// must be present, every parameter must be declared, and values must
+// match their declared type. Unknown names get a suggestion when they
+// look like a typo of a declared one. A nil specs skips validation.
+// Problems are returned as ParamErrors.
+//
+// Example:
+//
+//	err := api.ValidateParams(csv.ParamSpecs(), map[string]string{"file_pth": "x.csv"})
+//	// unknown parameter "file_pth" (did you mean "file_path"?)
+func ValidateParams(specs []ParamSpec, params map[string]string) error {
+	if specs == nil {
+		return nil
+	}
+
+	var problems ParamErrors
+	for _, spec := range specs {
+		if spec.Required {
+			if _, ok := params[spec.Name]; !ok {
+				problems = append(problems, &ParamError{Name: spec.Name, Problem: fmt.Sprintf("missing required parameter %q", spec.Name)})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i := slices.IndexFunc(specs, func(s ParamSpec) bool { return s.Matches(name) })
+		if i < 0 {
+			problem := fmt.Sprintf("unknown parameter %q", name)
+			if suggestion := closestParam(specs, name); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			problems = append(problems, &ParamError{Name: name, Problem: problem})
+			continue
+		}
+		if err := specs[i].Check(params[name]); err != nil {
+			problems = append(problems, &ParamError{Name: name, Problem: fmt.Sprintf("parameter %q %v", name, err)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// closestParam returns the declared name nearest to name, if it is close
+// enough to be a likely typo.
+func closestParam(specs []ParamSpec, name string) string {
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if !strings.HasSuffix(spec.Name, "*") {
+			names = append(names, spec.Name)
+		}
+	}
+	return ClosestName(name, names)
+}
+
+// ClosestName returns the candidate nearest to name by edit distance, or
+// "" if none is close enough to be a likely typo. It is used for "did you
+// mean" hints on unknown parameters and config fields. Ties go to the
+// candidate sharing the longest prefix with name, then alphabetically, so
+// the result does not depend on the order of candidates.
+func ClosestName(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/3+2
+	for _, candidate := range candidates {
+		d := editDistance(name, candidate)
+		if d < bestDistance || (d == bestDistance && best != "" && closer(name, candidate, best)) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// closer reports whether candidate matches name better than best, an
+// equally distant candidate.
+func closer(name, candidate, best string) bool {
+	if c, b := commonPrefix(name, candidate), commonPrefix(name, best); c != b {
+		return c > b
+	}
+	return candidate < best
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+var testSpecs = []ParamSpec{
+	{Name: "file_path", Type: ParamString, Required: true},
+	{Name: "has_header", Type: ParamBool},
+	{Name: "batch_size", Type: ParamInt},
+	{Name: "ratio", Type: ParamFloat},
+	{Name: "timeout", Type: ParamDuration},
+	{Name: "mode", Type: ParamString, Enum: []string{"insert", "upsert"}},
+	{Name: "header_*", Type: ParamString},
+	{Name: "legacy", Type: ParamBool, Deprecated: "set mode instead"},
+}
+
+// TestValidateParams tests accepted and rejected params
+func TestValidateParams(t *testing.T) {
+	valid := map[string]string{
+		"file_path":     "in.csv",
+		"has_header":    "false",
+		"batch_size":    "500",
+		"ratio":         "0.5",
+		"timeout":       "30s",
+		"mode":          "UPSERT",
+		"header_Accept": "application/json",
+	}
+	if err := ValidateParams(testSpecs, valid); err != nil {
+		t.Errorf("ValidateParams() error = %v", err)
+	}
+	if err := ValidateParams(nil, map[string]string{"anything": "x"}); err != nil {
+		t.Errorf("ValidateParams(nil) error = %v, want undeclared params skipped", err)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"missing required", map[string]string{}, `missing required parameter "file_path"`},
+		{"typo", map[string]string{"file_path": "x", "has_heder": "true"}, `unknown parameter "has_heder" (did you mean "has_header"?)`},
+		{"unknown", map[string]string{"file_path": "x", "compression": "gzip"}, `unknown parameter "compression"`},
+		{"bare prefix", map[string]string{"file_path": "x", "header_": "v"}, `unknown parameter "header_"`},
+		{"bad int", map[string]string{"file_path": "x", "batch_size": "many"}, `parameter "batch_size" must be a int`},
+		{"bad bool", map[string]string{"file_path": "x", "has_header": "yes"}, `parameter "has_header" must be a bool`},
+		{"bad float", map[string]string{"file_path": "x", "ratio": "half"}, `parameter "ratio" must be a float`},
+		{"bad duration", map[string]string{"file_path": "x", "timeout": "30"}, `parameter "timeout" must be a duration`},
+		{"bad enum", map[string]string{"file_path": "x", "mode": "merge"}, `must be one of insert, upsert, got "merge"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParams(testSpecs, tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateParams() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateParamsReportsAllProblems tests that problems are collected
+func TestValidateParamsReportsAllProblems(t *testing.T) {
+	err := ValidateParams(testSpecs, map[string]string{"batch_size": "x", "zzz": "1"})
+	if err == nil {
+		t.Fatal("ValidateParams() should fail")
+	}
+	for _, want := range []string{"file_path", "batch_size", "zzz"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+// TestClosestName tests typo suggestions
+func TestValidateParamsProblems(t *testing.T) {
+	err := ValidateParams(testSpecs, map[string]string{"fle_path": "x.csv"})
+	problems, ok := err.(ParamErrors)
+	if !ok || len(problems) != 2 {
+		t.Fatalf("ValidateParams() = %#v, want two ParamErrors", err)
+	}
+	if problems[0].Name != "file_path" || problems[1].Name != "fle_path" {
+		t.Errorf("problem names = %q, %q", problems[0].Name, problems[1].Name)
+	}
+
+	problems[1].Line, problems[1].Column = 4, 7
+	want := `invalid parameters: missing required parameter "file_path"; unknown parameter "fle_path" (did you mean "file_path"?) (line 4, column 7)`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+// TestDeprecatedParams tests warnings for deprecated params
+func TestDeprecatedParams(t *testing.T) {
+	params := map[string]string{"file_path": "x.csv", "legacy": "true"}
+	if err := ValidateParams(testSpecs, params); err != nil {
+		t.Errorf("ValidateParams() error = %v, want deprecated params accepted", err)
+	}
+	want := `parameter "legacy" is deprecated: set mode instead`
+	if got := DeprecatedParams(testSpecs, params); len(got) != 1 || got[0] != want {
+		t.Errorf("DeprecatedParams() = %v, want [%s]", got, want)
+	}
+	if got := DeprecatedParams(testSpecs, map[string]string{"file_path": "x.csv"}); got != nil {
+		t.Errorf("DeprecatedParams() = %v, want none", got)
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	candidates := []string{"provider", "processors", "params", "type"}
+	tests := map[string]string{
+		"parms":      "params",
+		"provder":    "provider",
+		"processor":  "processors",
+		"tpye":       "type",
+		"formatting": "",
+		"x":          "",
+	}
+	for name, want := range tests {
+		if got := ClosestName(name, candidates); got != want {
+			t.Errorf("ClosestName(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	// Equally distant candidates are chosen independent of their order
+	for _, order := range [][]string{{"max_delay", "max_retries"}, {"max_retries", "max_delay"}} {
+		if got := ClosestName("max_retry", order); got != "max_retries" {
+			t.Errorf("ClosestName(max_retry, %v) = %q, want max_retries", order, got)
+		}
+	}
+}